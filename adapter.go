@@ -39,6 +39,19 @@ const (
 	FeatureConst
 	// FeatureDefault provides a default value
 	FeatureDefault
+
+	// FeatureAnnotations is a tool-level feature rather than a schema keyword:
+	// behavioral hints such as read-only or destructive (see ToolAnnotations)
+	FeatureAnnotations
+	// FeatureOutputSchema is a tool-level feature: a schema for tool results
+	FeatureOutputSchema
+	// FeatureTitle is a tool-level feature: a human-readable display name
+	FeatureTitle
+	// FeatureIcons is a tool-level feature: icons for display in clients
+	FeatureIcons
+	// FeatureMetadata is a tool-level feature: Category, Tags, Version,
+	// Timeout and format-specific metadata such as MCP _meta
+	FeatureMetadata
 )
 
 // featureNames maps features to their string representations
@@ -59,6 +72,11 @@ var featureNames = map[SchemaFeature]string{
 	FeatureEnum:                 "enum",
 	FeatureConst:                "const",
 	FeatureDefault:              "default",
+	FeatureAnnotations:          "annotations",
+	FeatureOutputSchema:         "outputSchema",
+	FeatureTitle:                "title",
+	FeatureIcons:                "icons",
+	FeatureMetadata:             "metadata",
 }
 
 // String returns the JSON Schema keyword name for this feature.
@@ -69,6 +87,36 @@ func (f SchemaFeature) String() string {
 	return fmt.Sprintf("SchemaFeature(%d)", f)
 }
 
+// ParseSchemaFeature returns the feature for a JSON Schema keyword name
+// (e.g., "$ref", "anyOf"). Returns an error for unknown keywords.
+func ParseSchemaFeature(name string) (SchemaFeature, error) {
+	for f, n := range featureNames {
+		if n == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown schema feature: %q", name)
+}
+
+// MarshalText encodes the feature as its JSON Schema keyword name.
+func (f SchemaFeature) MarshalText() ([]byte, error) {
+	name, ok := featureNames[f]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema feature: %d", int(f))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a JSON Schema keyword name into a feature.
+func (f *SchemaFeature) UnmarshalText(text []byte) error {
+	parsed, err := ParseSchemaFeature(string(text))
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
 // AllFeatures returns all known schema features in a stable order.
 func AllFeatures() []SchemaFeature {
 	return []SchemaFeature{
@@ -88,6 +136,11 @@ func AllFeatures() []SchemaFeature {
 		FeatureEnum,
 		FeatureConst,
 		FeatureDefault,
+		FeatureAnnotations,
+		FeatureOutputSchema,
+		FeatureTitle,
+		FeatureIcons,
+		FeatureMetadata,
 	}
 }
 
@@ -117,6 +170,63 @@ type Adapter interface {
 	SupportsFeature(feature SchemaFeature) bool
 }
 
+// ModalAdapter is an optional interface for adapters that emit more than one
+// variant of their format, each with its own capability set (e.g., OpenAI
+// strict and non-strict function calling).
+//
+// AdapterRegistry.Convert uses it to pick the mode for each conversion: an
+// explicit mode from the target format ("openai:strict") or WithTargetMode
+// takes precedence, otherwise ModeOf derives the mode from the tool.
+type ModalAdapter interface {
+	Adapter
+
+	// ModeOf returns the mode the adapter would emit the tool in.
+	ModeOf(tool *CanonicalTool) string
+
+	// WithMode returns an adapter bound to the given mode, whose
+	// SupportsFeature and FromCanonical follow that mode's capabilities.
+	// Returns an error for unknown modes.
+	WithMode(mode string) (Adapter, error)
+}
+
+// ProfileBinder is an optional interface for adapters whose FromCanonical
+// restricts schemas to a capability profile, such as the OpenAI strict
+// compiler.
+//
+// AdapterRegistry.Convert binds the target to the profile it resolved for
+// the conversion, so a registry override changes the emitted schema as well
+// as the loss warnings.
+type ProfileBinder interface {
+	// WithProfile returns an adapter whose FromCanonical and SchemaIssues
+	// follow the given profile instead of the built-in one.
+	WithProfile(profile *CapabilityProfile) Adapter
+}
+
+// ToolFeatureReporter is an optional interface for adapters that keep
+// tool-level data in SourceMeta, such as an MCP title or icons.
+//
+// AdapterRegistry.Convert asks the source adapter which tool-level features
+// a tool uses, in addition to those visible in canonical fields, and warns
+// when the target cannot carry them.
+type ToolFeatureReporter interface {
+	// ToolFeatures returns the tool-level features the tool's SourceMeta uses.
+	ToolFeatures(tool *CanonicalTool) []SchemaFeature
+}
+
+// SchemaIssueReporter is an optional interface for adapters whose
+// FromCanonical compiles schemas into a restricted dialect, such as OpenAI
+// strict mode, rather than copying them.
+//
+// AdapterRegistry.Convert asks the target adapter for the issues of the
+// tool it emits and attaches them to the result, so rewrites that a
+// per-feature warning cannot describe are still reported.
+type SchemaIssueReporter interface {
+	// SchemaIssues returns what FromCanonical drops, rewrites or
+	// approximates in the tool's schemas. Paths start with "/inputSchema"
+	// or "/outputSchema".
+	SchemaIssues(tool *CanonicalTool) []SchemaIssue
+}
+
 // ConversionError represents an error during tool format conversion.
 type ConversionError struct {
 	// Adapter is the name of the adapter that encountered the error
@@ -157,3 +267,27 @@ func (w FeatureLossWarning) String() string {
 	return fmt.Sprintf("feature %s lost converting from %s to %s",
 		w.Feature, w.FromAdapter, w.ToAdapter)
 }
+
+// SchemaIssue reports a schema construct that a target cannot express
+// exactly. Unlike FeatureLossWarning, which is predicted per feature, an
+// issue points at the specific location in the schema that was dropped,
+// rewritten, or approximated.
+type SchemaIssue struct {
+	// Path is a JSON Pointer to the affected schema (e.g., "/properties/tags")
+	Path string
+
+	// Keyword is the JSON Schema keyword involved (e.g., "oneOf")
+	Keyword string
+
+	// Message explains what was done with the construct
+	Message string
+}
+
+// String returns a human-readable issue message.
+func (i SchemaIssue) String() string {
+	path := i.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s at %s: %s", i.Keyword, path, i.Message)
+}
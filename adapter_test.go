@@ -72,6 +72,11 @@ func TestAllFeatures(t *testing.T) {
 		FeatureEnum,
 		FeatureConst,
 		FeatureDefault,
+		FeatureAnnotations,
+		FeatureOutputSchema,
+		FeatureTitle,
+		FeatureIcons,
+		FeatureMetadata,
 	}
 
 	for _, known := range knownFeatures {
@@ -269,3 +274,16 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSchemaIssue_String(t *testing.T) {
+	issue := SchemaIssue{Path: "/properties/id", Keyword: "oneOf", Message: "approximated"}
+
+	got := issue.String()
+
+	if got != "oneOf at /properties/id: approximated" {
+		t.Errorf("String() = %q", got)
+	}
+	if root := (SchemaIssue{Keyword: "type", Message: "m"}).String(); !containsString(root, "at /:") {
+		t.Errorf("String() for root = %q, want path /", root)
+	}
+}
@@ -1,7 +1,9 @@
 package adapters
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/jonwraymond/tooladapter"
 )
@@ -9,6 +11,10 @@ import (
 // AnthropicTool represents an Anthropic tool definition.
 // This is a self-contained type that doesn't depend on external SDK.
 type AnthropicTool struct {
+	// Type is empty or "custom" for user-defined tools; built-in tools use
+	// AnthropicBuiltinTool
+	Type string `json:"type,omitempty"`
+
 	// Name is the tool identifier
 	Name string `json:"name"`
 
@@ -17,10 +23,18 @@ type AnthropicTool struct {
 
 	// InputSchema is the JSON Schema for tool input (Anthropic uses input_schema)
 	InputSchema map[string]any `json:"input_schema"`
+
+	// InputExamples are example inputs that validate against InputSchema
+	InputExamples []map[string]any `json:"input_examples,omitempty"`
+
+	// CacheControl sets a prompt caching breakpoint
+	CacheControl *AnthropicCacheControl `json:"cache_control,omitempty"`
 }
 
 // AnthropicAdapter converts between Anthropic tool format and canonical format.
-type AnthropicAdapter struct{}
+type AnthropicAdapter struct {
+	renderAnnotations bool
+}
 
 // NewAnthropicAdapter creates a new Anthropic adapter.
 func NewAnthropicAdapter() *AnthropicAdapter {
@@ -32,8 +46,20 @@ func (a *AnthropicAdapter) Name() string {
 	return "anthropic"
 }
 
+// WithAnnotationsInDescription returns a copy of the adapter that prefixes
+// tool descriptions with the tool's title and behavioral hints, since
+// Anthropic has no annotations field. ToCanonical on the copy parses the
+// prefix back.
+func (a *AnthropicAdapter) WithAnnotationsInDescription() *AnthropicAdapter {
+	copied := *a
+	copied.renderAnnotations = true
+	return &copied
+}
+
 // ToCanonical converts an Anthropic tool to canonical format.
-// Accepts AnthropicTool or *AnthropicTool.
+// Accepts AnthropicTool and AnthropicBuiltinTool (or pointers to them), and
+// raw JSON as json.RawMessage, []byte or map[string]any. Built-in tools
+// become ToolKindHosted tools with provider "anthropic".
 func (a *AnthropicAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
 	var tool AnthropicTool
 
@@ -45,8 +71,29 @@ func (a *AnthropicAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, err
 			return nil, errors.New("nil AnthropicTool pointer")
 		}
 		tool = *v
+	case AnthropicBuiltinTool:
+		return builtinToCanonical(v)
+	case *AnthropicBuiltinTool:
+		if v == nil {
+			return nil, errors.New("nil AnthropicBuiltinTool pointer")
+		}
+		return builtinToCanonical(*v)
+	case json.RawMessage:
+		return a.decodeJSON(v)
+	case []byte:
+		return a.decodeJSON(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.decodeJSON(data)
 	default:
-		return nil, errors.New("expected AnthropicTool or *AnthropicTool")
+		return nil, errors.New("expected AnthropicTool, AnthropicBuiltinTool or raw JSON")
+	}
+
+	if tool.Type != "" && tool.Type != "custom" {
+		return nil, fmt.Errorf("anthropic tool type %q requires AnthropicBuiltinTool", tool.Type)
 	}
 
 	canonical := &tooladapter.CanonicalTool{
@@ -55,6 +102,18 @@ func (a *AnthropicAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, err
 		SourceFormat: "anthropic",
 		SourceMeta:   make(map[string]any),
 	}
+	if a.renderAnnotations {
+		canonical.Description, canonical.Annotations = tooladapter.SplitRenderedAnnotations(canonical.Description)
+	}
+	if len(tool.InputExamples) > 0 {
+		canonical.Examples = append([]map[string]any(nil), tool.InputExamples...)
+	}
+	if tool.Type != "" {
+		canonical.SourceMeta["type"] = tool.Type
+	}
+	if tool.CacheControl != nil {
+		canonical.SourceMeta["cache_control"] = cacheControlMeta(tool.CacheControl)
+	}
 
 	// Convert input schema
 	if tool.InputSchema != nil {
@@ -69,15 +128,42 @@ func (a *AnthropicAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, err
 }
 
 // FromCanonical converts a canonical tool to Anthropic format.
+// Function tools become AnthropicTool; hosted tools with provider
+// "anthropic" become AnthropicBuiltinTool. The type and cache_control
+// recorded in SourceMeta are restored, and Examples are emitted as
+// input_examples after validation against the input schema.
 func (a *AnthropicAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
 	if tool == nil {
 		return nil, errors.New("nil CanonicalTool")
 	}
+	if tool.Kind == tooladapter.ToolKindHosted {
+		return encodeBuiltinTool(tool)
+	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
 
 	anthropicTool := AnthropicTool{
 		Name:        tool.Name,
 		Description: tool.Description,
 	}
+	if a.renderAnnotations {
+		anthropicTool.Description = tooladapter.RenderAnnotations(tool.Description, tool.Annotations)
+	}
+	if len(tool.Examples) > 0 {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
+		}
+		anthropicTool.InputExamples = append([]map[string]any(nil), tool.Examples...)
+	}
+	if typ, ok := tool.SourceMeta["type"].(string); ok && typ == "custom" {
+		anthropicTool.Type = typ
+	}
+	cc, err := cacheControlFromMeta(tool.SourceMeta)
+	if err != nil {
+		return nil, err
+	}
+	anthropicTool.CacheControl = cc
 
 	// Convert input schema to input_schema map
 	if tool.InputSchema != nil {
@@ -87,15 +173,49 @@ func (a *AnthropicAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any,
 	return anthropicTool, nil
 }
 
+// decodeJSON converts a raw JSON tool, telling user-defined tools from
+// built-ins by their type.
+func (a *AnthropicAdapter) decodeJSON(data []byte) (*tooladapter.CanonicalTool, error) {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decode anthropic tool: %w", err)
+	}
+
+	if probe.Type == "" || probe.Type == "custom" {
+		var tool AnthropicTool
+		if err := json.Unmarshal(data, &tool); err != nil {
+			return nil, fmt.Errorf("decode anthropic tool: %w", err)
+		}
+		return a.ToCanonical(tool)
+	}
+
+	var tool AnthropicBuiltinTool
+	if err := json.Unmarshal(data, &tool); err != nil {
+		return nil, fmt.Errorf("decode anthropic built-in tool: %w", err)
+	}
+	return builtinToCanonical(tool)
+}
+
 // SupportsFeature returns whether this adapter supports a schema feature.
-// Anthropic supports most JSON Schema features except $ref.
+// Answers come from the built-in capability profile; Anthropic supports
+// most JSON Schema features except $ref and $defs. Annotations are
+// supported when rendered into descriptions.
 func (a *AnthropicAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
-	switch feature {
-	case tooladapter.FeatureRef:
-		return false // Anthropic doesn't support $ref
-	case tooladapter.FeatureDefs:
-		return false // Anthropic doesn't support $defs
-	default:
-		return true // Other features are generally supported
+	if feature == tooladapter.FeatureAnnotations && a.renderAnnotations {
+		return true
+	}
+	return supportsBuiltin(a.Name(), "", feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Only the default mode ("") is defined. Annotations are marked supported
+// when rendered into descriptions.
+func (a *AnthropicAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	p, ok := builtinProfile(a.Name(), mode)
+	if ok && a.renderAnnotations {
+		p.Features[tooladapter.FeatureAnnotations] = true
 	}
+	return p, ok
 }
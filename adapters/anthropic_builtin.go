@@ -0,0 +1,275 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// AnthropicCacheControl marks a prompt caching breakpoint on a tool.
+type AnthropicCacheControl struct {
+	// Type is the cache type ("ephemeral")
+	Type string `json:"type"`
+
+	// TTL is the cache lifetime (e.g., "5m", "1h"); empty means the default
+	TTL string `json:"ttl,omitempty"`
+}
+
+// AnthropicBuiltinTool is a versioned tool defined by Anthropic, such as
+// bash_20250124, text_editor_20250728, computer_20250124 or
+// web_search_20250305. Client tools (bash, text_editor, computer, memory)
+// are executed by the caller; server tools (web_search, web_fetch,
+// code_execution) are executed by Anthropic. Options sit next to the type
+// and name in JSON.
+type AnthropicBuiltinTool struct {
+	// Type is the versioned tool type (e.g., "bash_20250124")
+	Type string
+
+	// Name is the tool name the model calls (e.g., "bash")
+	Name string
+
+	// CacheControl sets a prompt caching breakpoint
+	CacheControl *AnthropicCacheControl
+
+	// Options holds the tool's remaining fields (e.g., "display_width_px",
+	// "max_uses", "allowed_domains")
+	Options map[string]any
+}
+
+// anthropicToolFamilies records where each built-in tool family runs.
+var anthropicToolFamilies = map[string]string{
+	"bash":           "client",
+	"text_editor":    "client",
+	"computer":       "client",
+	"memory":         "client",
+	"web_search":     "server",
+	"web_fetch":      "server",
+	"code_execution": "server",
+}
+
+// Family returns the tool type without its version suffix
+// (e.g., "text_editor" for "text_editor_20250728").
+func (t AnthropicBuiltinTool) Family() string {
+	family, _ := splitAnthropicType(t.Type)
+	return family
+}
+
+// Version returns the tool type's date version (e.g., "20250124"),
+// or "" if the type is not versioned.
+func (t AnthropicBuiltinTool) Version() string {
+	_, version := splitAnthropicType(t.Type)
+	return version
+}
+
+// Validate checks the fields Anthropic requires: a built-in type, a name,
+// and display dimensions for computer tools.
+func (t AnthropicBuiltinTool) Validate() error {
+	if t.Type == "" || t.Type == "custom" {
+		return fmt.Errorf("invalid anthropic built-in tool type: %q", t.Type)
+	}
+	if t.Name == "" {
+		return fmt.Errorf("anthropic built-in tool %q requires a name", t.Type)
+	}
+	if t.Family() == "computer" {
+		for _, key := range []string{"display_width_px", "display_height_px"} {
+			if !isPositiveNumber(t.Options[key]) {
+				return fmt.Errorf("computer tool %q requires a positive %s", t.Type, key)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON flattens Options next to the type and name fields.
+func (t AnthropicBuiltinTool) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(t.Options)+3)
+	for k, v := range t.Options {
+		m[k] = v
+	}
+	m["type"] = t.Type
+	m["name"] = t.Name
+	if t.CacheControl != nil {
+		m["cache_control"] = t.CacheControl
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON splits the type, name and cache_control fields from the
+// tool's options.
+func (t *AnthropicBuiltinTool) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	var out AnthropicBuiltinTool
+	if raw, ok := m["type"]; ok {
+		if err := json.Unmarshal(raw, &out.Type); err != nil {
+			return fmt.Errorf("decode type: %w", err)
+		}
+	}
+	if raw, ok := m["name"]; ok {
+		if err := json.Unmarshal(raw, &out.Name); err != nil {
+			return fmt.Errorf("decode name: %w", err)
+		}
+	}
+	if raw, ok := m["cache_control"]; ok && string(raw) != "null" {
+		out.CacheControl = &AnthropicCacheControl{}
+		if err := json.Unmarshal(raw, out.CacheControl); err != nil {
+			return fmt.Errorf("decode cache_control: %w", err)
+		}
+	}
+	delete(m, "type")
+	delete(m, "name")
+	delete(m, "cache_control")
+
+	if len(m) > 0 {
+		out.Options = make(map[string]any, len(m))
+		for k, raw := range m {
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return fmt.Errorf("decode %s: %w", k, err)
+			}
+			out.Options[k] = v
+		}
+	}
+	*t = out
+	return nil
+}
+
+// splitAnthropicType splits a versioned type such as "computer_20250124"
+// into its family and eight-digit date version.
+func splitAnthropicType(typ string) (family, version string) {
+	i := strings.LastIndexByte(typ, '_')
+	if i < 0 || len(typ)-i-1 != 8 {
+		return typ, ""
+	}
+	for _, c := range typ[i+1:] {
+		if c < '0' || c > '9' {
+			return typ, ""
+		}
+	}
+	return typ[:i], typ[i+1:]
+}
+
+// builtinToCanonical converts a built-in tool into a hosted canonical tool.
+// The execution side ("client" or "server") is recorded in SourceMeta for
+// known families.
+func builtinToCanonical(t AnthropicBuiltinTool) (*tooladapter.CanonicalTool, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	canonical := &tooladapter.CanonicalTool{
+		Kind: tooladapter.ToolKindHosted,
+		Name: t.Name,
+		Hosted: &tooladapter.HostedToolConfig{
+			Provider: "anthropic",
+			Type:     t.Type,
+			Options:  copyOptions(t.Options),
+		},
+		SourceFormat: "anthropic",
+		SourceMeta:   make(map[string]any),
+	}
+	if execution, ok := anthropicToolFamilies[t.Family()]; ok {
+		canonical.SourceMeta["execution"] = execution
+	}
+	if t.CacheControl != nil {
+		canonical.SourceMeta["cache_control"] = cacheControlMeta(t.CacheControl)
+	}
+	return canonical, nil
+}
+
+// encodeBuiltinTool emits a hosted canonical tool as an Anthropic built-in.
+// Hosted tools of other providers have no Anthropic equivalent.
+func encodeBuiltinTool(tool *tooladapter.CanonicalTool) (any, error) {
+	if tool.Hosted == nil || tool.Hosted.Provider != "anthropic" {
+		return nil, unsupportedKindError("anthropic", tool)
+	}
+
+	out := AnthropicBuiltinTool{
+		Type:    tool.Hosted.Type,
+		Name:    tool.Name,
+		Options: copyOptions(tool.Hosted.Options),
+	}
+	cc, err := cacheControlFromMeta(tool.SourceMeta)
+	if err != nil {
+		return nil, err
+	}
+	out.CacheControl = cc
+
+	if err := out.Validate(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// cacheControlMeta stores a cache_control value in SourceMeta as a plain
+// map, so that it survives JSON serialization of the canonical tool.
+func cacheControlMeta(cc *AnthropicCacheControl) map[string]any {
+	m := map[string]any{"type": cc.Type}
+	if cc.TTL != "" {
+		m["ttl"] = cc.TTL
+	}
+	return m
+}
+
+// cacheControlFromMeta reads a cache_control value recorded in SourceMeta.
+// A Bedrock cache point marks the same caching boundary and becomes an
+// ephemeral breakpoint. Returns nil if neither is recorded.
+func cacheControlFromMeta(meta map[string]any) (*AnthropicCacheControl, error) {
+	switch v := meta["cache_control"].(type) {
+	case nil:
+		if typ, ok := meta["cachePoint"].(string); ok && typ != "" {
+			return &AnthropicCacheControl{Type: "ephemeral"}, nil
+		}
+		return nil, nil
+	case AnthropicCacheControl:
+		return &v, nil
+	case *AnthropicCacheControl:
+		return v, nil
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var cc AnthropicCacheControl
+		if err := json.Unmarshal(data, &cc); err != nil {
+			return nil, fmt.Errorf("decode cache_control: %w", err)
+		}
+		return &cc, nil
+	default:
+		return nil, errors.New("SourceMeta[cache_control] must be an object")
+	}
+}
+
+// isPositiveNumber reports whether v is a number greater than zero.
+func isPositiveNumber(v any) bool {
+	switch n := v.(type) {
+	case float64:
+		return n > 0
+	case int:
+		return n > 0
+	case int64:
+		return n > 0
+	case json.Number:
+		f, err := n.Float64()
+		return err == nil && f > 0
+	}
+	return false
+}
+
+// copyOptions returns a shallow copy of a tool options map, or nil if empty.
+func copyOptions(options map[string]any) map[string]any {
+	if len(options) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(options))
+	for k, v := range options {
+		out[k] = v
+	}
+	return out
+}
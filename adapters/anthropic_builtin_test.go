@@ -0,0 +1,264 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestAnthropicBuiltinTool_FamilyAndVersion(t *testing.T) {
+	tests := []struct {
+		typ         string
+		wantFamily  string
+		wantVersion string
+	}{
+		{"bash_20250124", "bash", "20250124"},
+		{"text_editor_20250728", "text_editor", "20250728"},
+		{"web_search_20250305", "web_search", "20250305"},
+		{"computer_20250124", "computer", "20250124"},
+		{"mystery", "mystery", ""},
+		{"tool_v2", "tool_v2", ""},
+	}
+
+	for _, tt := range tests {
+		tool := AnthropicBuiltinTool{Type: tt.typ}
+		if got := tool.Family(); got != tt.wantFamily {
+			t.Errorf("Family(%q) = %q, want %q", tt.typ, got, tt.wantFamily)
+		}
+		if got := tool.Version(); got != tt.wantVersion {
+			t.Errorf("Version(%q) = %q, want %q", tt.typ, got, tt.wantVersion)
+		}
+	}
+}
+
+func TestAnthropicBuiltinTool_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    AnthropicBuiltinTool
+		wantErr bool
+	}{
+		{"bash", AnthropicBuiltinTool{Type: "bash_20250124", Name: "bash"}, false},
+		{"computer", AnthropicBuiltinTool{Type: "computer_20250124", Name: "computer", Options: map[string]any{"display_width_px": 1024, "display_height_px": 768.0}}, false},
+		{"computer without height", AnthropicBuiltinTool{Type: "computer_20250124", Name: "computer", Options: map[string]any{"display_width_px": 1024}}, true},
+		{"computer zero width", AnthropicBuiltinTool{Type: "computer_20241022", Name: "computer", Options: map[string]any{"display_width_px": 0, "display_height_px": 768}}, true},
+		{"missing name", AnthropicBuiltinTool{Type: "bash_20250124"}, true},
+		{"missing type", AnthropicBuiltinTool{Name: "bash"}, true},
+		{"custom type", AnthropicBuiltinTool{Type: "custom", Name: "bash"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tool.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnthropicBuiltinTool_JSON(t *testing.T) {
+	input := `{"cache_control":{"type":"ephemeral"},"display_height_px":768,"display_number":1,"display_width_px":1024,"name":"computer","type":"computer_20250124"}`
+
+	var tool AnthropicBuiltinTool
+	if err := json.Unmarshal([]byte(input), &tool); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := AnthropicBuiltinTool{
+		Type:         "computer_20250124",
+		Name:         "computer",
+		CacheControl: &AnthropicCacheControl{Type: "ephemeral"},
+		Options:      map[string]any{"display_width_px": 1024.0, "display_height_px": 768.0, "display_number": 1.0},
+	}
+	if !reflect.DeepEqual(tool, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", tool, want)
+	}
+
+	data, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("Marshal() = %s, want %s", data, input)
+	}
+}
+
+func TestAnthropicAdapter_ToCanonical_BuiltinTools(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	tests := []struct {
+		name          string
+		raw           any
+		wantName      string
+		wantType      string
+		wantExecution string
+	}{
+		{"bash struct", AnthropicBuiltinTool{Type: "bash_20250124", Name: "bash"}, "bash", "bash_20250124", "client"},
+		{"text editor json", []byte(`{"type":"text_editor_20250728","name":"str_replace_based_edit_tool","max_characters":10000}`), "str_replace_based_edit_tool", "text_editor_20250728", "client"},
+		{"web search map", map[string]any{"type": "web_search_20250305", "name": "web_search", "max_uses": 5}, "web_search", "web_search_20250305", "server"},
+		{"unknown family", &AnthropicBuiltinTool{Type: "future_20300101", Name: "future"}, "future", "future_20300101", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.ToCanonical(tt.raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Kind != tooladapter.ToolKindHosted {
+				t.Errorf("Kind = %q, want %q", got.Kind, tooladapter.ToolKindHosted)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", got.Name, tt.wantName)
+			}
+			if got.Hosted == nil || got.Hosted.Provider != "anthropic" || got.Hosted.Type != tt.wantType {
+				t.Errorf("Hosted = %+v, want anthropic %s", got.Hosted, tt.wantType)
+			}
+			if execution, _ := got.SourceMeta["execution"].(string); execution != tt.wantExecution {
+				t.Errorf("SourceMeta[execution] = %q, want %q", execution, tt.wantExecution)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAnthropicAdapter_ToCanonical_BuiltinErrors(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	tests := map[string]any{
+		"computer without display": []byte(`{"type":"computer_20250124","name":"computer"}`),
+		"missing name":             AnthropicBuiltinTool{Type: "bash_20250124"},
+		"nil pointer":              (*AnthropicBuiltinTool)(nil),
+		"built-in type on tool":    AnthropicTool{Type: "bash_20250124", Name: "bash"},
+		"malformed json":           []byte(`{"type":`),
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := adapter.ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestAnthropicAdapter_CacheControlRoundTrip(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	original := AnthropicTool{
+		Type:         "custom",
+		Name:         "get_weather",
+		InputSchema:  map[string]any{"type": "object"},
+		CacheControl: &AnthropicCacheControl{Type: "ephemeral", TTL: "1h"},
+	}
+
+	canonical, err := adapter.ToCanonical(original)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if canonical.SourceMeta["type"] != "custom" {
+		t.Errorf("SourceMeta[type] = %v, want custom", canonical.SourceMeta["type"])
+	}
+
+	// SourceMeta survives a JSON round trip of the canonical tool
+	data, err := json.Marshal(canonical.SourceMeta)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	canonical.SourceMeta = nil
+	if err := json.Unmarshal(data, &canonical.SourceMeta); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	got, ok := out.(AnthropicTool)
+	if !ok {
+		t.Fatalf("FromCanonical() returned %T, want AnthropicTool", out)
+	}
+	if got.Type != "custom" {
+		t.Errorf("Type = %q, want custom", got.Type)
+	}
+	if got.CacheControl == nil || *got.CacheControl != *original.CacheControl {
+		t.Errorf("CacheControl = %+v, want %+v", got.CacheControl, original.CacheControl)
+	}
+}
+
+func TestAnthropicAdapter_BuiltinRoundTrip(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	inputs := []string{
+		`{"display_height_px":768,"display_width_px":1024,"name":"computer","type":"computer_20250124"}`,
+		`{"cache_control":{"type":"ephemeral"},"name":"bash","type":"bash_20250124"}`,
+		`{"allowed_domains":["example.com"],"max_uses":3,"name":"web_search","type":"web_search_20250305"}`,
+	}
+
+	for _, input := range inputs {
+		canonical, err := adapter.ToCanonical([]byte(input))
+		if err != nil {
+			t.Fatalf("ToCanonical(%s) error = %v", input, err)
+		}
+		out, err := adapter.FromCanonical(canonical)
+		if err != nil {
+			t.Fatalf("FromCanonical() error = %v", err)
+		}
+		data, err := json.Marshal(out)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if string(data) != input {
+			t.Errorf("round trip = %s, want %s", data, input)
+		}
+	}
+}
+
+func TestAnthropicAdapter_FromCanonical_BuiltinErrors(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	tests := map[string]*tooladapter.CanonicalTool{
+		"computer without display": {
+			Kind:   tooladapter.ToolKindHosted,
+			Name:   "computer",
+			Hosted: &tooladapter.HostedToolConfig{Provider: "anthropic", Type: "computer_20250124"},
+		},
+		"bad cache control": {
+			Kind:       tooladapter.ToolKindHosted,
+			Name:       "bash",
+			Hosted:     &tooladapter.HostedToolConfig{Provider: "anthropic", Type: "bash_20250124"},
+			SourceMeta: map[string]any{"cache_control": "ephemeral"},
+		},
+		"no provider": {
+			Kind:   tooladapter.ToolKindHosted,
+			Name:   "bash",
+			Hosted: &tooladapter.HostedToolConfig{Type: "bash_20250124"},
+		},
+	}
+
+	for name, tool := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := adapter.FromCanonical(tool); err == nil {
+				t.Error("FromCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRegistry_Convert_AnthropicBuiltinToOpenAI(t *testing.T) {
+	r := tooladapter.NewRegistry()
+	_ = r.Register(NewAnthropicAdapter())
+	_ = r.Register(NewOpenAIAdapter())
+
+	_, err := r.Convert(AnthropicBuiltinTool{Type: "bash_20250124", Name: "bash"}, "anthropic", "openai")
+
+	var convErr *tooladapter.ConversionError
+	if !errors.As(err, &convErr) || convErr.Direction != "from_canonical" {
+		t.Fatalf("Convert() error = %v, want from_canonical ConversionError", err)
+	}
+}
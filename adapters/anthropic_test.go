@@ -337,3 +337,71 @@ func TestAnthropicAdapter_ToCanonical_EmptySchema(t *testing.T) {
 		t.Error("InputSchema should be nil when input_schema is empty")
 	}
 }
+
+func TestAnthropicAdapter_InputExamples(t *testing.T) {
+	adapter := NewAnthropicAdapter()
+
+	original := AnthropicTool{
+		Name: "get_weather",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+			"required":   []any{"city"},
+		},
+		InputExamples: []map[string]any{{"city": "Paris"}, {"city": "Tokyo"}},
+	}
+
+	canonical, err := adapter.ToCanonical(original)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if len(canonical.Examples) != 2 || canonical.Examples[1]["city"] != "Tokyo" {
+		t.Errorf("Examples = %v, want Paris and Tokyo", canonical.Examples)
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if got := out.(AnthropicTool).InputExamples; len(got) != 2 || got[0]["city"] != "Paris" {
+		t.Errorf("InputExamples = %v, want Paris and Tokyo", got)
+	}
+
+	canonical.Examples = append(canonical.Examples, map[string]any{"town": "Lyon"})
+	if _, err := adapter.FromCanonical(canonical); err == nil {
+		t.Error("FromCanonical() with invalid example error = nil, want error")
+	}
+}
+
+func TestAnthropicAdapter_AnnotationsInDescription(t *testing.T) {
+	adapter := NewAnthropicAdapter().WithAnnotationsInDescription()
+	yes := true
+	tool := &tooladapter.CanonicalTool{
+		Name:        "drop_table",
+		Description: "Drop a table.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Annotations: &tooladapter.ToolAnnotations{DestructiveHint: &yes},
+	}
+
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if got := out.(AnthropicTool).Description; got != "[destructive] Drop a table." {
+		t.Errorf("Description = %q, want %q", got, "[destructive] Drop a table.")
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(tool, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+
+	// Without the option the hints are not rendered
+	out, _ = NewAnthropicAdapter().FromCanonical(tool)
+	if got := out.(AnthropicTool).Description; got != "Drop a table." {
+		t.Errorf("Description without rendering = %q, want %q", got, "Drop a table.")
+	}
+}
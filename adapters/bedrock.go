@@ -0,0 +1,352 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// BedrockToolSpec is the tool definition of the AWS Bedrock Converse API.
+// This is a self-contained type that doesn't depend on external SDK.
+type BedrockToolSpec struct {
+	// Name is the tool identifier; see IsBedrockToolName
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// InputSchema wraps the JSON Schema for tool input
+	InputSchema BedrockInputSchema `json:"inputSchema"`
+}
+
+// BedrockInputSchema wraps a tool's input schema as {"json": {...}}.
+type BedrockInputSchema struct {
+	// JSON is the JSON Schema document
+	JSON map[string]any `json:"json"`
+}
+
+// BedrockCachePoint marks a prompt caching checkpoint: everything before it
+// in the request is cached.
+type BedrockCachePoint struct {
+	// Type is the cache point type ("default")
+	Type string `json:"type"`
+}
+
+// BedrockTool is an entry of the toolConfig.tools array. Exactly one of
+// ToolSpec and CachePoint is set.
+type BedrockTool struct {
+	// ToolSpec is a tool definition
+	ToolSpec *BedrockToolSpec `json:"toolSpec,omitempty"`
+
+	// CachePoint is a caching checkpoint after the preceding tools
+	CachePoint *BedrockCachePoint `json:"cachePoint,omitempty"`
+}
+
+// BedrockToolConfig is the toolConfig of a Converse request.
+type BedrockToolConfig struct {
+	// Tools lists tool definitions and cache points
+	Tools []BedrockTool `json:"tools"`
+
+	// ToolChoice is passed through as is ({"auto": {}}, {"any": {}} or
+	// {"tool": {"name": ...}})
+	ToolChoice map[string]any `json:"toolChoice,omitempty"`
+}
+
+// maxBedrockToolNameLength is the longest tool name Bedrock accepts.
+const maxBedrockToolNameLength = 64
+
+// IsBedrockToolName reports whether a name matches ^[a-zA-Z][a-zA-Z0-9_]*$
+// and is at most 64 characters, the Bedrock tool name rule. It is stricter
+// than the OpenAI and Anthropic rule: names must start with a letter and
+// cannot contain hyphens.
+func IsBedrockToolName(name string) bool {
+	if name == "" || len(name) > maxBedrockToolNameLength {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		if i == 0 && !letter {
+			return false
+		}
+		if !letter && !(c >= '0' && c <= '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// bedrockNamePrefix starts sanitized Bedrock names that would otherwise
+// not start with a letter.
+const bedrockNamePrefix = "tool_"
+
+// BedrockAdapter converts between Bedrock Converse tool specs and canonical
+// format.
+type BedrockAdapter struct {
+	renderExamples bool
+}
+
+// NewBedrockAdapter creates a new Bedrock adapter.
+func NewBedrockAdapter() *BedrockAdapter {
+	return &BedrockAdapter{}
+}
+
+// Name returns the adapter identifier.
+func (a *BedrockAdapter) Name() string {
+	return "bedrock"
+}
+
+// WithExamplesInDescription returns a copy of the adapter that renders tool
+// examples into tool spec descriptions, since Bedrock tool specs have no
+// examples field. ToCanonical on the copy splits rendered examples back
+// out.
+func (a *BedrockAdapter) WithExamplesInDescription() *BedrockAdapter {
+	copied := *a
+	copied.renderExamples = true
+	return &copied
+}
+
+// ToCanonical converts a Bedrock tool to canonical format.
+// Accepts BedrockTool and BedrockToolSpec (or pointers to them), and raw
+// JSON of either as json.RawMessage, []byte or map[string]any. A cache
+// point entry is not a tool; use ToCanonicalAll to read a tools array with
+// cache points.
+func (a *BedrockAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
+	var spec BedrockToolSpec
+
+	switch v := raw.(type) {
+	case BedrockToolSpec:
+		spec = v
+	case *BedrockToolSpec:
+		if v == nil {
+			return nil, errors.New("nil BedrockToolSpec pointer")
+		}
+		spec = *v
+	case BedrockTool:
+		return a.toolToCanonical(v)
+	case *BedrockTool:
+		if v == nil {
+			return nil, errors.New("nil BedrockTool pointer")
+		}
+		return a.toolToCanonical(*v)
+	case json.RawMessage:
+		return a.decodeJSON(v)
+	case []byte:
+		return a.decodeJSON(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.decodeJSON(data)
+	default:
+		return nil, errors.New("expected BedrockTool, BedrockToolSpec or raw JSON")
+	}
+
+	canonical := &tooladapter.CanonicalTool{
+		Name:         spec.Name,
+		Description:  spec.Description,
+		SourceFormat: "bedrock",
+		SourceMeta:   make(map[string]any),
+	}
+	if a.renderExamples {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(spec.Description)
+	}
+	if spec.InputSchema.JSON != nil {
+		schema, err := mapToJSONSchema(spec.InputSchema.JSON)
+		if err != nil {
+			return nil, err
+		}
+		canonical.InputSchema = schema
+	}
+	return canonical, nil
+}
+
+// SanitizeToolName converts a provider-safe name into one satisfying
+// IsBedrockToolName: hyphens and other disallowed characters become
+// underscores, and names not starting with a letter get a "tool_" prefix,
+// dropping leading characters beyond the length limit. AdapterRegistry
+// uses it for Convert with WithNameMapping.
+func (a *BedrockAdapter) SanitizeToolName(name string) string {
+	if IsBedrockToolName(name) {
+		return name
+	}
+	b := []byte(name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	sanitized := string(b)
+	if sanitized == "" || !(b[0] >= 'a' && b[0] <= 'z' || b[0] >= 'A' && b[0] <= 'Z') {
+		// Keep the end of long names, where a shortening hash sits
+		if limit := maxBedrockToolNameLength - len(bedrockNamePrefix); len(sanitized) > limit {
+			sanitized = sanitized[len(sanitized)-limit:]
+		}
+		sanitized = bedrockNamePrefix + sanitized
+	}
+	if len(sanitized) > maxBedrockToolNameLength {
+		sanitized = sanitized[:maxBedrockToolNameLength]
+	}
+	return sanitized
+}
+
+// FromCanonical converts a canonical tool to a BedrockTool wrapping a tool
+// spec. Returns an error if the name does not satisfy IsBedrockToolName;
+// Convert with WithNameMapping assigns names that do. A tool without an
+// input schema gets an empty object schema, since Bedrock requires one.
+// Use FromCanonicalAll to also emit cache points.
+func (a *BedrockAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
+	if tool == nil {
+		return nil, errors.New("nil CanonicalTool")
+	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
+	if !IsBedrockToolName(tool.Name) {
+		return nil, fmt.Errorf("bedrock tool name %q must match ^[a-zA-Z][a-zA-Z0-9_]*$ and be at most %d characters", tool.Name, maxBedrockToolNameLength)
+	}
+
+	spec := &BedrockToolSpec{
+		Name:        tool.Name,
+		Description: tool.Description,
+	}
+	if a.renderExamples {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
+		}
+		spec.Description = tooladapter.RenderExamples(tool.Description, tool.Examples)
+	}
+	if tool.InputSchema != nil {
+		spec.InputSchema.JSON = tool.InputSchema.ToMap()
+	} else {
+		spec.InputSchema.JSON = map[string]any{"type": "object"}
+	}
+	return BedrockTool{ToolSpec: spec}, nil
+}
+
+// ToCanonicalAll converts every tool in a raw Bedrock request body.
+// The body may be a Converse request with a "toolConfig", a toolConfig
+// object, or a bare tools array. A cache point entry is recorded on the
+// tool before it as SourceMeta["cachePoint"], holding the cache point type.
+func (a *BedrockAdapter) ToCanonicalAll(body []byte) ([]*tooladapter.CanonicalTool, error) {
+	var entries []BedrockTool
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("decode bedrock tools: %w", err)
+		}
+	} else {
+		var req struct {
+			BedrockToolConfig
+			ToolConfig *BedrockToolConfig `json:"toolConfig"`
+		}
+		if err := json.Unmarshal(trimmed, &req); err != nil {
+			return nil, fmt.Errorf("decode bedrock request: %w", err)
+		}
+		entries = req.Tools
+		if req.ToolConfig != nil {
+			entries = req.ToolConfig.Tools
+		}
+	}
+
+	tools := make([]*tooladapter.CanonicalTool, 0, len(entries))
+	for i, entry := range entries {
+		if entry.CachePoint != nil && entry.ToolSpec == nil {
+			if len(tools) == 0 {
+				return nil, fmt.Errorf("tool %d: cachePoint before any tool", i)
+			}
+			tools[len(tools)-1].SourceMeta["cachePoint"] = entry.CachePoint.Type
+			continue
+		}
+		tool, err := a.ToCanonical(entry)
+		if err != nil {
+			return nil, fmt.Errorf("tool %d: %w", i, err)
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// FromCanonicalAll converts tools to a toolConfig. A cache point follows
+// each tool with SourceMeta["cachePoint"] set, or with an Anthropic
+// cache_control breakpoint, which marks the same caching boundary.
+func (a *BedrockAdapter) FromCanonicalAll(tools []*tooladapter.CanonicalTool) (*BedrockToolConfig, error) {
+	config := &BedrockToolConfig{Tools: make([]BedrockTool, 0, len(tools))}
+	for i, tool := range tools {
+		out, err := a.FromCanonical(tool)
+		if err != nil {
+			return nil, fmt.Errorf("tool %d: %w", i, err)
+		}
+		config.Tools = append(config.Tools, out.(BedrockTool))
+		if cp := cachePointFromMeta(tool.SourceMeta); cp != nil {
+			config.Tools = append(config.Tools, BedrockTool{CachePoint: cp})
+		}
+	}
+	return config, nil
+}
+
+// toolToCanonical converts a tools array entry that holds a tool spec.
+func (a *BedrockAdapter) toolToCanonical(tool BedrockTool) (*tooladapter.CanonicalTool, error) {
+	if tool.ToolSpec == nil {
+		if tool.CachePoint != nil {
+			return nil, errors.New("bedrock cachePoint entry is not a tool")
+		}
+		return nil, errors.New("bedrock tool has no toolSpec")
+	}
+	return a.ToCanonical(*tool.ToolSpec)
+}
+
+// decodeJSON converts a raw tools array entry or bare tool spec.
+func (a *BedrockAdapter) decodeJSON(data []byte) (*tooladapter.CanonicalTool, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decode bedrock tool: %w", err)
+	}
+	_, hasSpec := probe["toolSpec"]
+	_, hasCachePoint := probe["cachePoint"]
+	if hasSpec || hasCachePoint {
+		var tool BedrockTool
+		if err := json.Unmarshal(data, &tool); err != nil {
+			return nil, fmt.Errorf("decode bedrock tool: %w", err)
+		}
+		return a.toolToCanonical(tool)
+	}
+
+	var spec BedrockToolSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("decode bedrock tool spec: %w", err)
+	}
+	return a.ToCanonical(spec)
+}
+
+// cachePointFromMeta returns the cache point recorded in SourceMeta, or one
+// standing in for an Anthropic cache_control breakpoint. Returns nil if
+// neither is recorded.
+func cachePointFromMeta(meta map[string]any) *BedrockCachePoint {
+	if typ, ok := meta["cachePoint"].(string); ok && typ != "" {
+		return &BedrockCachePoint{Type: typ}
+	}
+	if meta["cache_control"] != nil {
+		return &BedrockCachePoint{Type: "default"}
+	}
+	return nil
+}
+
+// SupportsFeature returns whether this adapter supports a schema feature.
+// Answers come from the built-in capability profile, which describes
+// Anthropic models on Bedrock: most JSON Schema features except $ref and
+// $defs.
+func (a *BedrockAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
+	return supportsBuiltin(a.Name(), "", feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Only the default mode ("") is defined.
+func (a *BedrockAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	return builtinProfile(a.Name(), mode)
+}
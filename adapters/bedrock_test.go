@@ -0,0 +1,337 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const bedrockConverseRequest = `{
+	"modelId": "anthropic.claude-sonnet-4",
+	"toolConfig": {
+		"tools": [
+			{"toolSpec": {"name": "get_weather", "description": "Get the weather.",
+				"inputSchema": {"json": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}}}},
+			{"toolSpec": {"name": "get_time", "inputSchema": {"json": {"type": "object"}}}},
+			{"cachePoint": {"type": "default"}}
+		],
+		"toolChoice": {"auto": {}}
+	}
+}`
+
+func TestBedrockAdapter_Name(t *testing.T) {
+	if got := NewBedrockAdapter().Name(); got != "bedrock" {
+		t.Errorf("Name() = %q, want %q", got, "bedrock")
+	}
+}
+
+func TestBedrockAdapter_ToCanonical(t *testing.T) {
+	spec := BedrockToolSpec{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: BedrockInputSchema{JSON: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		}},
+	}
+
+	inputs := map[string]any{
+		"spec":         spec,
+		"spec pointer": &spec,
+		"tool":         BedrockTool{ToolSpec: &spec},
+		"tool pointer": &BedrockTool{ToolSpec: &spec},
+		"wrapped JSON": json.RawMessage(`{"toolSpec":{"name":"get_weather","description":"Get the weather.","inputSchema":{"json":{"type":"object","properties":{"city":{"type":"string"}}}}}}`),
+		"bare JSON":    []byte(`{"name":"get_weather","description":"Get the weather.","inputSchema":{"json":{"type":"object","properties":{"city":{"type":"string"}}}}}`),
+		"map":          map[string]any{"toolSpec": map[string]any{"name": "get_weather", "description": "Get the weather.", "inputSchema": map[string]any{"json": spec.InputSchema.JSON}}},
+	}
+
+	for name, raw := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewBedrockAdapter().ToCanonical(raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Name != "get_weather" || got.Description != "Get the weather." || got.SourceFormat != "bedrock" {
+				t.Errorf("tool = %+v, want get_weather from bedrock", got)
+			}
+			if got.InputSchema == nil || got.InputSchema.Properties["city"].Type != "string" {
+				t.Errorf("InputSchema = %+v, want city string", got.InputSchema)
+			}
+		})
+	}
+}
+
+func TestBedrockAdapter_ToCanonical_Errors(t *testing.T) {
+	tests := map[string]any{
+		"nil spec pointer": (*BedrockToolSpec)(nil),
+		"nil tool pointer": (*BedrockTool)(nil),
+		"cache point":      BedrockTool{CachePoint: &BedrockCachePoint{Type: "default"}},
+		"empty entry":      BedrockTool{},
+		"cache point JSON": []byte(`{"cachePoint":{"type":"default"}}`),
+		"malformed JSON":   []byte(`{"toolSpec":`),
+		"unsupported":      42,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewBedrockAdapter().ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBedrockAdapter_FromCanonical(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+	}
+
+	out, err := NewBedrockAdapter().FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	data, _ := json.Marshal(out)
+	want := `{"toolSpec":{"name":"get_weather","description":"Get the weather.","inputSchema":{"json":{"type":"object"}}}}`
+	if string(data) != want {
+		t.Errorf("FromCanonical() = %s, want %s", data, want)
+	}
+
+	out, err = NewBedrockAdapter().FromCanonical(&tooladapter.CanonicalTool{Name: "ping"})
+	if err != nil {
+		t.Fatalf("FromCanonical(no schema) error = %v", err)
+	}
+	data, _ = json.Marshal(out)
+	if want := `{"toolSpec":{"name":"ping","inputSchema":{"json":{"type":"object"}}}}`; string(data) != want {
+		t.Errorf("FromCanonical(no schema) = %s, want %s", data, want)
+	}
+
+	if _, err := NewBedrockAdapter().FromCanonical(nil); err == nil {
+		t.Error("FromCanonical(nil) error = nil, want error")
+	}
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+	if _, err := NewBedrockAdapter().FromCanonical(custom); err == nil {
+		t.Error("FromCanonical(custom) error = nil, want error")
+	}
+}
+
+func TestBedrockAdapter_ExamplesInDescription(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}}},
+		Examples:    []map[string]any{{"city": "Paris"}},
+	}
+
+	adapter := NewBedrockAdapter().WithExamplesInDescription()
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}"
+	if got := out.(BedrockTool).ToolSpec.Description; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(tool, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+
+	invalid := *tool
+	invalid.Examples = []map[string]any{{"city": 42}}
+	if _, err := adapter.FromCanonical(&invalid); err == nil {
+		t.Error("FromCanonical() with invalid example error = nil, want error")
+	}
+}
+
+func TestIsBedrockToolName(t *testing.T) {
+	tests := map[string]bool{
+		"get_weather":             true,
+		"GetWeather2":             true,
+		"get-weather":             false,
+		"2fa":                     false,
+		"_private":                false,
+		"":                        false,
+		"github.create":           false,
+		strings.Repeat("a", 64):   true,
+		strings.Repeat("a", 65):   false,
+		"github_create_issue_now": true,
+	}
+	for name, want := range tests {
+		if got := IsBedrockToolName(name); got != want {
+			t.Errorf("IsBedrockToolName(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	_, err := NewBedrockAdapter().FromCanonical(&tooladapter.CanonicalTool{
+		Name:        "get-weather",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "get-weather") {
+		t.Errorf("FromCanonical() error = %v, want it to name the tool", err)
+	}
+}
+
+func TestBedrockAdapter_SanitizeToolName(t *testing.T) {
+	long := strings.Repeat("a", 55) + "_0123abcd"
+	tests := map[string]string{
+		"get_weather":  "get_weather",
+		"get-weather":  "get_weather",
+		"2fa_verify":   "tool_2fa_verify",
+		"_private":     "tool__private",
+		"9" + long[1:]: "tool_" + long[5:],
+	}
+	adapter := NewBedrockAdapter()
+	for name, want := range tests {
+		got := adapter.SanitizeToolName(name)
+		if got != want {
+			t.Errorf("SanitizeToolName(%q) = %q, want %q", name, got, want)
+		}
+		if !IsBedrockToolName(got) {
+			t.Errorf("SanitizeToolName(%q) = %q, not a Bedrock name", name, got)
+		}
+	}
+}
+
+func TestRegistry_Convert_BedrockNameMapping(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewBedrockAdapter())
+
+	for name, want := range map[string]string{"get-weather": "get_weather", "2fa.verify": "tool_2fa_verify"} {
+		result, err := registry.Convert(mcp.Tool{Name: name, InputSchema: map[string]any{"type": "object"}}, "mcp", "bedrock", tooladapter.WithNameMapping())
+		if err != nil {
+			t.Fatalf("Convert(%q) error = %v", name, err)
+		}
+		if got := result.Tool.(BedrockTool).ToolSpec.Name; got != want {
+			t.Errorf("Convert(%q) name = %q, want %q", name, got, want)
+		}
+		if id, ok := registry.ResolveToolName(want); !ok || id != name {
+			t.Errorf("ResolveToolName(%q) = %q, %v; want %q", want, id, ok, name)
+		}
+	}
+}
+
+func TestBedrockAdapter_ToCanonicalAll(t *testing.T) {
+	adapter := NewBedrockAdapter()
+
+	bodies := map[string]string{
+		"converse request": bedrockConverseRequest,
+		"tool config":      `{"tools":[{"toolSpec":{"name":"get_weather","inputSchema":{"json":{}}}},{"toolSpec":{"name":"get_time","inputSchema":{"json":{}}}},{"cachePoint":{"type":"default"}}]}`,
+		"bare array":       `[{"toolSpec":{"name":"get_weather","inputSchema":{"json":{}}}},{"toolSpec":{"name":"get_time","inputSchema":{"json":{}}}},{"cachePoint":{"type":"default"}}]`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			tools, err := adapter.ToCanonicalAll([]byte(body))
+			if err != nil {
+				t.Fatalf("ToCanonicalAll() error = %v", err)
+			}
+			if len(tools) != 2 || tools[0].Name != "get_weather" || tools[1].Name != "get_time" {
+				t.Fatalf("ToCanonicalAll() = %v, want get_weather and get_time", tools)
+			}
+			if _, ok := tools[0].SourceMeta["cachePoint"]; ok {
+				t.Error("get_weather has a cache point, want none")
+			}
+			if tools[1].SourceMeta["cachePoint"] != "default" {
+				t.Errorf("get_time SourceMeta[cachePoint] = %v, want default", tools[1].SourceMeta["cachePoint"])
+			}
+		})
+	}
+
+	for name, body := range map[string]string{
+		"leading cache point": `[{"cachePoint":{"type":"default"}}]`,
+		"malformed":           `{"toolConfig":`,
+		"bad entry":           `[{"toolSpec":{"name":"x","inputSchema":{"json":"object"}}}]`,
+	} {
+		if _, err := adapter.ToCanonicalAll([]byte(body)); err == nil {
+			t.Errorf("ToCanonicalAll(%s) error = nil, want error", name)
+		}
+	}
+}
+
+func TestBedrockAdapter_FromCanonicalAll(t *testing.T) {
+	adapter := NewBedrockAdapter()
+	tools, err := adapter.ToCanonicalAll([]byte(bedrockConverseRequest))
+	if err != nil {
+		t.Fatalf("ToCanonicalAll() error = %v", err)
+	}
+
+	config, err := adapter.FromCanonicalAll(tools)
+	if err != nil {
+		t.Fatalf("FromCanonicalAll() error = %v", err)
+	}
+
+	var req struct {
+		ToolConfig map[string]any `json:"toolConfig"`
+	}
+	_ = json.Unmarshal([]byte(bedrockConverseRequest), &req)
+	delete(req.ToolConfig, "toolChoice")
+
+	gotJSON, _ := json.Marshal(config)
+	var got any
+	_ = json.Unmarshal(gotJSON, &got)
+	wantJSON, _ := json.Marshal(req.ToolConfig)
+	var want any
+	_ = json.Unmarshal(wantJSON, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromCanonicalAll() =\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+
+	bad := []*tooladapter.CanonicalTool{{Name: "bad-name", InputSchema: &tooladapter.JSONSchema{Type: "object"}}}
+	if _, err := adapter.FromCanonicalAll(bad); err == nil {
+		t.Error("FromCanonicalAll() with an invalid name error = nil, want error")
+	}
+}
+
+func TestBedrockAdapter_AnthropicCacheBreakpoints(t *testing.T) {
+	anthropicTool := AnthropicTool{
+		Name:         "get_weather",
+		InputSchema:  map[string]any{"type": "object"},
+		CacheControl: &AnthropicCacheControl{Type: "ephemeral"},
+	}
+	canonical, err := NewAnthropicAdapter().ToCanonical(anthropicTool)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	// An Anthropic breakpoint becomes a Bedrock cache point
+	config, err := NewBedrockAdapter().FromCanonicalAll([]*tooladapter.CanonicalTool{canonical})
+	if err != nil {
+		t.Fatalf("FromCanonicalAll() error = %v", err)
+	}
+	if len(config.Tools) != 2 || config.Tools[1].CachePoint == nil || config.Tools[1].CachePoint.Type != "default" {
+		t.Errorf("Tools = %+v, want the tool followed by a cache point", config.Tools)
+	}
+
+	// And a Bedrock cache point becomes an Anthropic breakpoint
+	tools, err := NewBedrockAdapter().ToCanonicalAll([]byte(bedrockConverseRequest))
+	if err != nil {
+		t.Fatalf("ToCanonicalAll() error = %v", err)
+	}
+	out, err := NewAnthropicAdapter().FromCanonical(tools[1])
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if cc := out.(AnthropicTool).CacheControl; cc == nil || cc.Type != "ephemeral" {
+		t.Errorf("CacheControl = %+v, want ephemeral", cc)
+	}
+}
+
+func TestBedrockAdapter_SupportsFeature(t *testing.T) {
+	adapter := NewBedrockAdapter()
+	anthropic := NewAnthropicAdapter()
+	for _, f := range tooladapter.AllFeatures() {
+		if got, want := adapter.SupportsFeature(f), anthropic.SupportsFeature(f); got != want {
+			t.Errorf("SupportsFeature(%s) = %v, want %v as for Anthropic", f, got, want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// CohereTool represents a Cohere v2 tool, a function wrapped in an
+// OpenAI-like envelope. This is a self-contained type that doesn't depend
+// on external SDK.
+type CohereTool struct {
+	// Type is always "function"
+	Type string `json:"type"`
+
+	// Function is the function definition
+	Function CohereFunction `json:"function"`
+}
+
+// CohereFunction is the function definition of a Cohere v2 tool.
+type CohereFunction struct {
+	// Name is the function identifier
+	Name string `json:"name"`
+
+	// Description explains what the function does
+	Description string `json:"description,omitempty"`
+
+	// Parameters is the JSON Schema for function arguments
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// CohereV1Tool represents a Cohere v1 tool, whose parameters are a flat map
+// of Python-typed definitions instead of a JSON Schema.
+type CohereV1Tool struct {
+	// Name is the tool identifier
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// ParameterDefinitions maps parameter names to their definitions
+	ParameterDefinitions map[string]CohereParameterDefinition `json:"parameter_definitions,omitempty"`
+}
+
+// CohereModeV1 is the Cohere adapter mode that emits v1 tools with
+// parameter_definitions. The default mode ("") emits v2 tools.
+const CohereModeV1 = "v1"
+
+// CohereAdapter converts between Cohere tools and canonical format.
+//
+// The adapter has two modes, one per API version. An adapter in the
+// default mode emits v1 tools only for tools whose SourceMeta["apiVersion"]
+// is "v1", which ToCanonical records for v1 tools; an adapter bound to
+// CohereModeV1 via WithMode emits every tool as v1.
+type CohereAdapter struct {
+	mode           string
+	profile        *tooladapter.CapabilityProfile
+	renderExamples bool
+}
+
+// NewCohereAdapter creates a new Cohere adapter in the default (v2) mode.
+func NewCohereAdapter() *CohereAdapter {
+	return &CohereAdapter{}
+}
+
+// Name returns the adapter identifier.
+func (a *CohereAdapter) Name() string {
+	return "cohere"
+}
+
+// ModeOf returns the mode a tool is emitted in: the bound mode if set,
+// otherwise CohereModeV1 when SourceMeta["apiVersion"] is "v1".
+func (a *CohereAdapter) ModeOf(tool *tooladapter.CanonicalTool) string {
+	if a.mode != "" {
+		return a.mode
+	}
+	if tool != nil && tool.SourceMeta != nil {
+		if version, ok := tool.SourceMeta["apiVersion"].(string); ok && version == CohereModeV1 {
+			return CohereModeV1
+		}
+	}
+	return ""
+}
+
+// WithMode returns a Cohere adapter bound to the given mode.
+// Returns an error if no capability profile exists for the mode.
+func (a *CohereAdapter) WithMode(mode string) (tooladapter.Adapter, error) {
+	if _, ok := builtinProfile(a.Name(), mode); !ok {
+		return nil, fmt.Errorf("cohere adapter: unknown mode %q", mode)
+	}
+	copied := *a
+	copied.mode = mode
+	copied.profile = nil
+	return &copied, nil
+}
+
+// WithProfile returns a Cohere adapter whose v2 parameters follow the given
+// profile. Only a default-mode profile changes the output, since v1
+// parameter definitions have a fixed shape.
+func (a *CohereAdapter) WithProfile(profile *tooladapter.CapabilityProfile) tooladapter.Adapter {
+	copied := *a
+	copied.profile = profile.Clone()
+	return &copied
+}
+
+// WithExamplesInDescription returns a copy of the adapter that renders tool
+// examples into tool descriptions, since Cohere tools have no examples
+// field in either API version. ToCanonical on the copy splits rendered
+// examples back out.
+func (a *CohereAdapter) WithExamplesInDescription() *CohereAdapter {
+	copied := *a
+	copied.renderExamples = true
+	return &copied
+}
+
+// ToCanonical converts a Cohere tool to canonical format.
+// Accepts CohereTool, CohereFunction and CohereV1Tool (or pointers to
+// them), and raw JSON of any of them as json.RawMessage, []byte or
+// map[string]any.
+//
+// v1 parameter definitions are re-nested: dotted names such as
+// "address.city" become properties of nested objects. v1 tools are
+// recorded as SourceMeta["apiVersion"] = "v1" so that they are emitted as
+// v1 again.
+func (a *CohereAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
+	switch v := raw.(type) {
+	case CohereTool:
+		return a.functionToCanonical(v.Function)
+	case *CohereTool:
+		if v == nil {
+			return nil, errors.New("nil CohereTool pointer")
+		}
+		return a.functionToCanonical(v.Function)
+	case CohereFunction:
+		return a.functionToCanonical(v)
+	case *CohereFunction:
+		if v == nil {
+			return nil, errors.New("nil CohereFunction pointer")
+		}
+		return a.functionToCanonical(*v)
+	case CohereV1Tool:
+		return a.v1ToCanonical(v)
+	case *CohereV1Tool:
+		if v == nil {
+			return nil, errors.New("nil CohereV1Tool pointer")
+		}
+		return a.v1ToCanonical(*v)
+	case json.RawMessage:
+		return a.decodeJSON(v)
+	case []byte:
+		return a.decodeJSON(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.decodeJSON(data)
+	default:
+		return nil, errors.New("expected CohereTool, CohereFunction, CohereV1Tool or raw JSON")
+	}
+}
+
+// FromCanonical converts a canonical tool to a CohereTool, or to a
+// CohereV1Tool in CohereModeV1. v1 parameter definitions are compiled by
+// CompileCohereParameters, which flattens nested objects and drops what
+// the definitions cannot express. v2 parameters keep the input schema
+// without the keywords the v2 capability profile does not support.
+func (a *CohereAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
+	if tool == nil {
+		return nil, errors.New("nil CanonicalTool")
+	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
+
+	description := tool.Description
+	if a.renderExamples {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
+		}
+		description = tooladapter.RenderExamples(tool.Description, tool.Examples)
+	}
+
+	if a.ModeOf(tool) == CohereModeV1 {
+		v1 := CohereV1Tool{
+			Name:        tool.Name,
+			Description: description,
+		}
+		if tool.InputSchema != nil {
+			params, _ := CompileCohereParameters(tool.InputSchema)
+			if len(params) > 0 {
+				v1.ParameterDefinitions = params
+			}
+		}
+		return v1, nil
+	}
+
+	fn := CohereFunction{
+		Name:        tool.Name,
+		Description: description,
+	}
+	if tool.InputSchema != nil {
+		params, _ := restrictSchema(tool.InputSchema, a.v2Profile())
+		fn.Parameters = params.ToMap()
+	}
+	return CohereTool{Type: "function", Function: fn}, nil
+}
+
+// functionToCanonical converts a v2 function definition.
+func (a *CohereAdapter) functionToCanonical(fn CohereFunction) (*tooladapter.CanonicalTool, error) {
+	canonical := &tooladapter.CanonicalTool{
+		Name:         fn.Name,
+		Description:  fn.Description,
+		SourceFormat: "cohere",
+		SourceMeta:   make(map[string]any),
+	}
+	if a.renderExamples {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(fn.Description)
+	}
+	if fn.Parameters != nil {
+		schema, err := mapToJSONSchema(fn.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		canonical.InputSchema = schema
+	}
+	return canonical, nil
+}
+
+// v1ToCanonical converts a v1 tool. The input schema is always an object,
+// since v1 tools without parameters still take an (empty) argument object.
+func (a *CohereAdapter) v1ToCanonical(tool CohereV1Tool) (*tooladapter.CanonicalTool, error) {
+	schema, err := cohereParametersToJSONSchema(tool.ParameterDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("cohere tool %q: %w", tool.Name, err)
+	}
+	canonical := &tooladapter.CanonicalTool{
+		Name:         tool.Name,
+		Description:  tool.Description,
+		InputSchema:  schema,
+		SourceFormat: "cohere",
+		SourceMeta:   map[string]any{"apiVersion": CohereModeV1},
+	}
+	if a.renderExamples {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(tool.Description)
+	}
+	return canonical, nil
+}
+
+// decodeJSON converts a raw tool, telling the versions apart by their
+// fields: v2 tools have a "function" envelope or bare "parameters", and
+// anything else is read as a v1 tool.
+func (a *CohereAdapter) decodeJSON(data []byte) (*tooladapter.CanonicalTool, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decode cohere tool: %w", err)
+	}
+
+	if _, ok := probe["function"]; ok {
+		var tool CohereTool
+		if err := json.Unmarshal(data, &tool); err != nil {
+			return nil, fmt.Errorf("decode cohere tool: %w", err)
+		}
+		return a.functionToCanonical(tool.Function)
+	}
+	if _, ok := probe["parameters"]; ok {
+		var fn CohereFunction
+		if err := json.Unmarshal(data, &fn); err != nil {
+			return nil, fmt.Errorf("decode cohere function: %w", err)
+		}
+		return a.functionToCanonical(fn)
+	}
+
+	var tool CohereV1Tool
+	if err := json.Unmarshal(data, &tool); err != nil {
+		return nil, fmt.Errorf("decode cohere v1 tool: %w", err)
+	}
+	return a.v1ToCanonical(tool)
+}
+
+// SchemaIssues returns what FromCanonical drops or rewrites in a function
+// tool's input schema: the losses of CompileCohereParameters in
+// CohereModeV1, and the keywords the v2 profile does not support otherwise.
+func (a *CohereAdapter) SchemaIssues(tool *tooladapter.CanonicalTool) []tooladapter.SchemaIssue {
+	if tool == nil || !tool.IsFunction() || tool.InputSchema == nil {
+		return nil
+	}
+	var issues []tooladapter.SchemaIssue
+	if a.ModeOf(tool) == CohereModeV1 {
+		_, issues = CompileCohereParameters(tool.InputSchema)
+	} else {
+		_, issues = restrictSchema(tool.InputSchema, a.v2Profile())
+	}
+	return schemaIssuesAt("/inputSchema", issues)
+}
+
+// v2Profile returns the profile v2 parameters are restricted to: the bound
+// default-mode profile, or the built-in one.
+func (a *CohereAdapter) v2Profile() *tooladapter.CapabilityProfile {
+	if a.profile != nil && a.profile.Mode == "" {
+		return a.profile
+	}
+	p, _ := builtinProfile(a.Name(), "")
+	return p
+}
+
+// SupportsFeature returns whether this adapter supports a schema feature.
+// Answers come from the built-in capability profile of the bound mode.
+func (a *CohereAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
+	return supportsBuiltin(a.Name(), a.mode, feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Supported modes are "" (default, v2) and "v1".
+func (a *CohereAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	return builtinProfile(a.Name(), mode)
+}
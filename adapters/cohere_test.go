@@ -0,0 +1,326 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const cohereV1WeatherJSON = `{
+	"name": "get_weather",
+	"description": "Get the weather.",
+	"parameter_definitions": {
+		"city": {"description": "City name", "type": "str", "required": true},
+		"days": {"type": "int"},
+		"address.street": {"type": "str", "required": true},
+		"address.zip": {"type": "str"}
+	}
+}`
+
+func TestCohereAdapter_Name(t *testing.T) {
+	if got := NewCohereAdapter().Name(); got != "cohere" {
+		t.Errorf("Name() = %q, want %q", got, "cohere")
+	}
+}
+
+func TestCohereAdapter_ToCanonical_V2(t *testing.T) {
+	fn := CohereFunction{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		},
+	}
+
+	inputs := map[string]any{
+		"tool":         CohereTool{Type: "function", Function: fn},
+		"tool pointer": &CohereTool{Type: "function", Function: fn},
+		"function":     fn,
+		"fn pointer":   &fn,
+		"wrapped JSON": json.RawMessage(`{"type":"function","function":{"name":"get_weather","description":"Get the weather.","parameters":{"type":"object","properties":{"city":{"type":"string"}}}}}`),
+		"bare JSON":    []byte(`{"name":"get_weather","description":"Get the weather.","parameters":{"type":"object","properties":{"city":{"type":"string"}}}}`),
+		"map":          map[string]any{"type": "function", "function": map[string]any{"name": "get_weather", "description": "Get the weather.", "parameters": fn.Parameters}},
+	}
+
+	for name, raw := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewCohereAdapter().ToCanonical(raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Name != "get_weather" || got.Description != "Get the weather." || got.SourceFormat != "cohere" {
+				t.Errorf("tool = %+v, want get_weather from cohere", got)
+			}
+			if got.InputSchema == nil || got.InputSchema.Properties["city"].Type != "string" {
+				t.Errorf("InputSchema = %+v, want city string", got.InputSchema)
+			}
+			if _, ok := got.SourceMeta["apiVersion"]; ok {
+				t.Errorf("SourceMeta[apiVersion] = %v, want unset for v2", got.SourceMeta["apiVersion"])
+			}
+		})
+	}
+}
+
+func TestCohereAdapter_ToCanonical_V1(t *testing.T) {
+	got, err := NewCohereAdapter().ToCanonical(json.RawMessage(cohereV1WeatherJSON))
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if got.SourceMeta["apiVersion"] != "v1" {
+		t.Errorf("SourceMeta[apiVersion] = %v, want v1", got.SourceMeta["apiVersion"])
+	}
+
+	in := got.InputSchema
+	if in.Type != "object" || !reflect.DeepEqual(in.Required, []string{"address", "city"}) {
+		t.Errorf("InputSchema = %+v, want object requiring address and city", in)
+	}
+	if street := in.Properties["address"].Properties["street"]; street == nil || street.Type != "string" {
+		t.Errorf("address.street = %+v, want a nested string", street)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	// A tool without parameters still takes an object
+	empty, err := NewCohereAdapter().ToCanonical(CohereV1Tool{Name: "now"})
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if empty.InputSchema == nil || empty.InputSchema.Type != "object" {
+		t.Errorf("InputSchema = %+v, want object", empty.InputSchema)
+	}
+}
+
+func TestCohereAdapter_ToCanonical_Errors(t *testing.T) {
+	tests := map[string]any{
+		"nil tool pointer":     (*CohereTool)(nil),
+		"nil function pointer": (*CohereFunction)(nil),
+		"nil v1 pointer":       (*CohereV1Tool)(nil),
+		"unsupported":          42,
+		"malformed JSON":       []byte(`{"name":`),
+		"unknown v1 type":      []byte(`{"name":"x","parameter_definitions":{"a":{"type":"complex"}}}`),
+		"conflicting names":    CohereV1Tool{Name: "x", ParameterDefinitions: map[string]CohereParameterDefinition{"a": {Type: "str"}, "a.b": {Type: "str"}}},
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCohereAdapter().ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCohereAdapter_FromCanonical(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+	}
+
+	out, err := NewCohereAdapter().FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	data, _ := json.Marshal(out)
+	want := `{"type":"function","function":{"name":"get_weather","description":"Get the weather.","parameters":{"type":"object"}}}`
+	if string(data) != want {
+		t.Errorf("FromCanonical() = %s, want %s", data, want)
+	}
+
+	if _, err := NewCohereAdapter().FromCanonical(nil); err == nil {
+		t.Error("FromCanonical(nil) error = nil, want error")
+	}
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+	if _, err := NewCohereAdapter().FromCanonical(custom); err == nil {
+		t.Error("FromCanonical(custom) error = nil, want error")
+	}
+}
+
+func TestCohereAdapter_ExamplesInDescription(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}}},
+		Examples:    []map[string]any{{"city": "Paris"}},
+	}
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}"
+
+	v2 := NewCohereAdapter().WithExamplesInDescription()
+	v1, err := v2.WithMode(CohereModeV1)
+	if err != nil {
+		t.Fatalf("WithMode() error = %v", err)
+	}
+	for _, adapter := range []tooladapter.Adapter{v2, v1} {
+		out, err := adapter.FromCanonical(tool)
+		if err != nil {
+			t.Fatalf("FromCanonical() error = %v", err)
+		}
+		var got string
+		switch out := out.(type) {
+		case CohereTool:
+			got = out.Function.Description
+		case CohereV1Tool:
+			got = out.Description
+		}
+		if got != want {
+			t.Errorf("%T Description = %q, want %q", out, got, want)
+		}
+
+		back, err := adapter.ToCanonical(out)
+		if err != nil {
+			t.Fatalf("ToCanonical() error = %v", err)
+		}
+		if back.Description != tool.Description || !reflect.DeepEqual(back.Examples, tool.Examples) {
+			t.Errorf("%T ToCanonical() = %q with examples %v, want the original description and examples", out, back.Description, back.Examples)
+		}
+	}
+
+	invalid := *tool
+	invalid.Examples = []map[string]any{{"city": 42}}
+	for _, adapter := range []tooladapter.Adapter{v2, v1} {
+		if _, err := adapter.FromCanonical(&invalid); err == nil {
+			t.Error("FromCanonical() with invalid example error = nil, want error")
+		}
+	}
+}
+
+func TestCohereAdapter_RoundTrip_V1(t *testing.T) {
+	adapter := NewCohereAdapter()
+	canonical, err := adapter.ToCanonical([]byte(cohereV1WeatherJSON))
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if _, ok := out.(CohereV1Tool); !ok {
+		t.Fatalf("FromCanonical() = %T, want CohereV1Tool", out)
+	}
+
+	gotJSON, _ := json.Marshal(out)
+	var got, want any
+	_ = json.Unmarshal(gotJSON, &got)
+	_ = json.Unmarshal([]byte(cohereV1WeatherJSON), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip =\n%s\nwant\n%s", gotJSON, cohereV1WeatherJSON)
+	}
+}
+
+func TestCohereAdapter_WithMode(t *testing.T) {
+	adapter, err := NewCohereAdapter().WithMode(CohereModeV1)
+	if err != nil {
+		t.Fatalf("WithMode() error = %v", err)
+	}
+	if adapter.SupportsFeature(tooladapter.FeatureEnum) {
+		t.Error("v1 SupportsFeature(enum) = true, want false")
+	}
+
+	out, err := adapter.FromCanonical(&tooladapter.CanonicalTool{
+		Name: "t",
+		InputSchema: &tooladapter.JSONSchema{
+			Type:       "object",
+			Properties: map[string]*tooladapter.JSONSchema{"q": {Type: "string"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if v1 := out.(CohereV1Tool); v1.ParameterDefinitions["q"].Type != "str" {
+		t.Errorf("FromCanonical() = %+v, want q as str", v1)
+	}
+
+	if _, err := NewCohereAdapter().WithMode("v3"); err == nil {
+		t.Error("WithMode(v3) error = nil, want error")
+	}
+}
+
+func TestCohereAdapter_SupportsFeature(t *testing.T) {
+	adapter := NewCohereAdapter()
+	tests := map[tooladapter.SchemaFeature]bool{
+		tooladapter.FeatureRef:          true,
+		tooladapter.FeatureAnyOf:        true,
+		tooladapter.FeatureOneOf:        false,
+		tooladapter.FeatureEnum:         true,
+		tooladapter.FeatureMinimum:      false,
+		tooladapter.FeatureOutputSchema: false,
+	}
+	for feature, want := range tests {
+		if got := adapter.SupportsFeature(feature); got != want {
+			t.Errorf("SupportsFeature(%s) = %v, want %v", feature, got, want)
+		}
+	}
+}
+
+func TestRegistry_Convert_MCPToCohereV1(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewCohereAdapter())
+
+	tool := mcp.Tool{
+		Name: "search",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"q":      map[string]any{"type": "string", "pattern": "^[a-z]+$"},
+				"filter": map[string]any{"type": "object", "properties": map[string]any{"lang": map[string]any{"type": "string"}}},
+				"limit":  map[string]any{"type": "integer", "maximum": 50},
+			},
+		},
+	}
+
+	result, err := registry.Convert(tool, "mcp", "cohere:v1")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	v1 := result.Tool.(CohereV1Tool)
+	if v1.ParameterDefinitions["filter.lang"].Type != "str" {
+		t.Errorf("Convert() = %+v, want filter.lang flattened", v1)
+	}
+	var features []string
+	for _, w := range result.Warnings {
+		features = append(features, w.Feature.String())
+	}
+	sort.Strings(features)
+	if want := []string{"maximum", "pattern"}; !reflect.DeepEqual(features, want) {
+		t.Errorf("warnings = %v, want %v", features, want)
+	}
+	wantIssues := []tooladapter.SchemaIssue{
+		{Path: "/inputSchema/properties/limit", Keyword: "maximum", Message: "dropped; parameter definitions only have a type and description"},
+		{Path: "/inputSchema/properties/q", Keyword: "pattern", Message: "dropped; parameter definitions only have a type and description"},
+	}
+	if !reflect.DeepEqual(result.Issues, wantIssues) {
+		t.Errorf("issues = %v, want %v", result.Issues, wantIssues)
+	}
+
+	// v2 keeps the pattern and drops the maximum it does not support
+	result, err = registry.Convert(tool, "mcp", "cohere")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	v2, ok := result.Tool.(CohereTool)
+	if !ok {
+		t.Fatalf("Convert() = %T, want CohereTool", result.Tool)
+	}
+	props := v2.Function.Parameters["properties"].(map[string]any)
+	if _, ok := props["limit"].(map[string]any)["maximum"]; ok {
+		t.Errorf("limit = %v, want maximum removed", props["limit"])
+	}
+	if props["q"].(map[string]any)["pattern"] != "^[a-z]+$" {
+		t.Errorf("q = %v, want pattern kept", props["q"])
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Feature != tooladapter.FeatureMaximum {
+		t.Errorf("warnings = %v, want maximum", result.Warnings)
+	}
+	wantIssues = []tooladapter.SchemaIssue{
+		{Path: "/inputSchema/properties/limit", Keyword: "maximum", Message: "removed; not supported"},
+	}
+	if !reflect.DeepEqual(result.Issues, wantIssues) {
+		t.Errorf("issues = %v, want %v", result.Issues, wantIssues)
+	}
+}
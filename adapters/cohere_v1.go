@@ -0,0 +1,336 @@
+package adapters
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// CohereParameterDefinition describes one parameter of a Cohere v1 tool.
+type CohereParameterDefinition struct {
+	// Description explains the parameter
+	Description string `json:"description,omitempty"`
+
+	// Type is a Python type name such as "str", "int", "float", "bool",
+	// "List[str]" or "Dict"
+	Type string `json:"type"`
+
+	// Required marks the parameter as mandatory
+	Required bool `json:"required,omitempty"`
+}
+
+// CompileCohereParameters rewrites a canonical object schema into Cohere
+// v1 parameter_definitions, which only have a Python type, a description
+// and a required flag per parameter.
+//
+// Nested object properties are flattened into dotted parameter names
+// ("address.city"), required only if every enclosing property is required.
+// Arrays become List[T] and objects without properties become Dict. Local
+// $ref values are resolved against the root $defs, and nullable schemas
+// lose their null branch. Properties that cannot be typed, such as other
+// combinators, are removed, and constraint keywords (enum, const, default,
+// pattern, format, bounds, additionalProperties, not) are dropped. Each
+// removal is reported as an issue.
+//
+// The input schema is not modified. Returns nil if schema is nil or not an
+// object schema.
+func CompileCohereParameters(schema *tooladapter.JSONSchema) (map[string]CohereParameterDefinition, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+	c := &cohereCompiler{root: schema, params: make(map[string]CohereParameterDefinition)}
+	if schema.Type != "object" && len(schema.Properties) == 0 {
+		c.issue("", "type", "root schema must be an object")
+		return nil, c.issues
+	}
+	c.compileObject(schema, "", "", true)
+	return c.params, c.issues
+}
+
+// cohereCompiler collects parameters and issues through a single
+// compilation.
+type cohereCompiler struct {
+	root   *tooladapter.JSONSchema
+	params map[string]CohereParameterDefinition
+	issues []tooladapter.SchemaIssue
+
+	// resolving holds the $ref values being expanded, to stop recursion
+	resolving []string
+}
+
+func (c *cohereCompiler) issue(path, keyword, message string) {
+	c.issues = append(c.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// compileObject adds the parameters for every property of an object
+// schema, in name order.
+func (c *cohereCompiler) compileObject(s *tooladapter.JSONSchema, path, prefix string, required bool) {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "/properties/" + escapePointer(name)
+		listed := slices.Contains(s.Required, name)
+		if listed && !required {
+			c.issue(propPath, "required", "dropped; properties of an optional object are optional")
+		}
+		c.compileProperty(s.Properties[name], propPath, prefix+name, required && listed)
+	}
+}
+
+// compileProperty adds the parameter, or parameters for a nested object,
+// for one property schema.
+func (c *cohereCompiler) compileProperty(s *tooladapter.JSONSchema, path, name string, required bool) {
+	s, pushed := c.resolve(s, path)
+	defer c.pop(pushed)
+	if s == nil {
+		return
+	}
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 || len(s.AllOf) > 0 {
+		c.issue(path, combinatorKeyword(s), "removed; combinators have no parameter type")
+		return
+	}
+
+	if len(s.Properties) > 0 {
+		if s.Description != "" {
+			c.issue(path, "description", "dropped; flattened objects have no description")
+		}
+		c.dropConstraints(s, path)
+		c.compileObject(s, path, name+".", required)
+		return
+	}
+
+	typ, ok := c.pythonType(s, path)
+	if !ok {
+		return
+	}
+	c.dropConstraints(s, path)
+	if _, exists := c.params[name]; exists {
+		c.issue(path, "properties", fmt.Sprintf("removed; flattened name %q is already used", name))
+		return
+	}
+	c.params[name] = CohereParameterDefinition{
+		Description: s.Description,
+		Type:        typ,
+		Required:    required,
+	}
+}
+
+// pythonType returns the Python type name for a schema that is not
+// flattened. Reports an issue and returns false if it has none.
+func (c *cohereCompiler) pythonType(s *tooladapter.JSONSchema, path string) (string, bool) {
+	switch s.Type {
+	case "string":
+		return "str", true
+	case "integer":
+		return "int", true
+	case "number":
+		return "float", true
+	case "boolean":
+		return "bool", true
+	case "object":
+		return "Dict", true
+	case "array":
+		items, pushed := c.resolve(s.Items, path+"/items")
+		defer c.pop(pushed)
+		if items == nil {
+			return "List", true
+		}
+		if len(items.Properties) > 0 {
+			c.issue(path+"/items", "properties", "dropped; list items are typed as Dict")
+			return "List[Dict]", true
+		}
+		inner, ok := c.pythonType(items, path+"/items")
+		if !ok {
+			return "List", true
+		}
+		return "List[" + inner + "]", true
+	case "":
+		if _, ok := stringValues(s.Enum); ok && len(s.Enum) > 0 {
+			return "str", true
+		}
+		if _, ok := s.Const.(string); ok {
+			return "str", true
+		}
+		c.issue(path, "type", "removed; untyped values have no parameter type")
+	default:
+		c.issue(path, "type", fmt.Sprintf("removed; type %q has no parameter type", s.Type))
+	}
+	return "", false
+}
+
+// resolve follows local references and strips a null branch. It returns
+// the number of references it entered, which the caller must pop once the
+// schema is compiled. Returns nil, after reporting an issue, for
+// references that cannot be followed.
+func (c *cohereCompiler) resolve(s *tooladapter.JSONSchema, path string) (*tooladapter.JSONSchema, int) {
+	pushed := 0
+	for s != nil && s.Ref != "" {
+		if slices.Contains(c.resolving, s.Ref) {
+			c.issue(path, "$ref", "removed; recursive references cannot be flattened")
+			return nil, pushed
+		}
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		target := c.root.Defs[strings.NewReplacer("~1", "/", "~0", "~").Replace(name)]
+		if !ok || target == nil {
+			c.issue(path, "$ref", fmt.Sprintf("removed; cannot resolve %q", s.Ref))
+			return nil, pushed
+		}
+		c.resolving = append(c.resolving, s.Ref)
+		pushed++
+		resolved := *target
+		if s.Description != "" {
+			resolved.Description = s.Description
+		}
+		s = &resolved
+	}
+	if s == nil {
+		return nil, pushed
+	}
+	if inner, ok := nullBranchOf(s); ok {
+		return inner, pushed
+	}
+	return s, pushed
+}
+
+// pop leaves the last n references entered by resolve.
+func (c *cohereCompiler) pop(n int) {
+	c.resolving = c.resolving[:len(c.resolving)-n]
+}
+
+// dropConstraints reports the keywords a parameter definition cannot carry.
+func (c *cohereCompiler) dropConstraints(s *tooladapter.JSONSchema, path string) {
+	drop := func(keyword string, present bool) {
+		if present {
+			c.issue(path, keyword, "dropped; parameter definitions only have a type and description")
+		}
+	}
+	drop("enum", len(s.Enum) > 0)
+	drop("const", s.Const != nil)
+	drop("default", s.Default != nil)
+	drop("pattern", s.Pattern != "")
+	drop("format", s.Format != "")
+	drop("minimum", s.Minimum != nil)
+	drop("maximum", s.Maximum != nil)
+	drop("minLength", s.MinLength != nil)
+	drop("maxLength", s.MaxLength != nil)
+	drop("minItems", s.MinItems != nil)
+	drop("maxItems", s.MaxItems != nil)
+	drop("additionalProperties", s.AdditionalProperties != nil)
+	drop("not", s.Not != nil)
+	for _, k := range extraKeywords(s) {
+		drop(k, true)
+	}
+}
+
+// combinatorKeyword names the first combinator a schema uses.
+func combinatorKeyword(s *tooladapter.JSONSchema) string {
+	switch {
+	case len(s.AnyOf) > 0:
+		return "anyOf"
+	case len(s.OneOf) > 0:
+		return "oneOf"
+	default:
+		return "allOf"
+	}
+}
+
+// cohereParametersToJSONSchema converts v1 parameter_definitions to an
+// object schema, re-nesting dotted names into nested objects. A nested
+// object is required if any of its parameters is.
+func cohereParametersToJSONSchema(params map[string]CohereParameterDefinition) (*tooladapter.JSONSchema, error) {
+	root := &tooladapter.JSONSchema{Type: "object"}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	leaves := make(map[*tooladapter.JSONSchema]bool)
+	for _, name := range names {
+		def := params[name]
+		leaf, err := parsePythonType(def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", name, err)
+		}
+		leaf.Description = def.Description
+
+		parts := strings.Split(name, ".")
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent.Properties[part]
+			if !ok {
+				child = &tooladapter.JSONSchema{Type: "object"}
+				setProperty(parent, part, child)
+			} else if leaves[child] {
+				return nil, fmt.Errorf("parameter %q conflicts with parameter %q", name, strings.Join(parts[:len(parts)-1], "."))
+			}
+			if def.Required && !slices.Contains(parent.Required, part) {
+				parent.Required = append(parent.Required, part)
+			}
+			parent = child
+		}
+
+		last := parts[len(parts)-1]
+		if _, exists := parent.Properties[last]; exists {
+			return nil, fmt.Errorf("parameter %q conflicts with a nested parameter", name)
+		}
+		setProperty(parent, last, leaf)
+		leaves[leaf] = true
+		if def.Required {
+			parent.Required = append(parent.Required, last)
+		}
+	}
+	return root, nil
+}
+
+func setProperty(s *tooladapter.JSONSchema, name string, prop *tooladapter.JSONSchema) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*tooladapter.JSONSchema)
+	}
+	s.Properties[name] = prop
+}
+
+// parsePythonType converts a Python type name used by Cohere v1 to a
+// schema. Names are case-insensitive; List and Dict may be parameterized.
+func parsePythonType(typ string) (*tooladapter.JSONSchema, error) {
+	t := strings.TrimSpace(typ)
+	base, arg, generic := strings.Cut(t, "[")
+	if generic {
+		if !strings.HasSuffix(arg, "]") {
+			return nil, fmt.Errorf("malformed parameter type %q", typ)
+		}
+		arg = strings.TrimSuffix(arg, "]")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(base)) {
+	case "str", "string":
+		return &tooladapter.JSONSchema{Type: "string"}, nil
+	case "int", "integer":
+		return &tooladapter.JSONSchema{Type: "integer"}, nil
+	case "float", "number":
+		return &tooladapter.JSONSchema{Type: "number"}, nil
+	case "bool", "boolean":
+		return &tooladapter.JSONSchema{Type: "boolean"}, nil
+	case "dict", "object":
+		return &tooladapter.JSONSchema{Type: "object"}, nil
+	case "list", "array":
+		schema := &tooladapter.JSONSchema{Type: "array"}
+		if generic {
+			items, err := parsePythonType(arg)
+			if err != nil {
+				return nil, err
+			}
+			schema.Items = items
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("unknown parameter type %q", typ)
+}
@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestCompileCohereParameters(t *testing.T) {
+	minLen := 1
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"city": {Type: "string", Description: "City name", MinLength: &minLen},
+			"days": {AnyOf: []*tooladapter.JSONSchema{{Type: "integer"}, {Type: "null"}}},
+			"tags": {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}},
+			"unit": {Enum: []any{"c", "f"}},
+			"address": {
+				Type:        "object",
+				Description: "Postal address",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"street": {Type: "string"},
+					"zip":    {Type: "string"},
+				},
+				Required: []string{"street"},
+			},
+			"owner": {Ref: "#/$defs/Person"},
+			"extra": {Type: "object"},
+			"value": {OneOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "number"}}},
+		},
+		Required: []string{"city", "address"},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Person": {
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"name":   {Type: "string"},
+					"parent": {Ref: "#/$defs/Person"},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+
+	got, issues := CompileCohereParameters(schema)
+
+	want := map[string]CohereParameterDefinition{
+		"city":           {Description: "City name", Type: "str", Required: true},
+		"days":           {Type: "int"},
+		"tags":           {Type: "List[str]"},
+		"unit":           {Type: "str"},
+		"address.street": {Type: "str", Required: true},
+		"address.zip":    {Type: "str"},
+		"owner.name":     {Type: "str"},
+		"extra":          {Type: "Dict"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompileCohereParameters() =\n%v\nwant\n%v", got, want)
+	}
+
+	var gotIssues []string
+	for _, issue := range issues {
+		gotIssues = append(gotIssues, issue.Keyword+" "+issue.Path)
+	}
+	wantIssues := []string{
+		"description /properties/address",
+		"minLength /properties/city",
+		"required /properties/owner/properties/name",
+		"$ref /properties/owner/properties/parent",
+		"enum /properties/unit",
+		"oneOf /properties/value",
+	}
+	if !reflect.DeepEqual(gotIssues, wantIssues) {
+		t.Errorf("issues = %q, want %q", gotIssues, wantIssues)
+	}
+}
+
+func TestCompileCohereParameters_Root(t *testing.T) {
+	if got, issues := CompileCohereParameters(nil); got != nil || issues != nil {
+		t.Errorf("CompileCohereParameters(nil) = %v, %v, want nil", got, issues)
+	}
+	got, issues := CompileCohereParameters(&tooladapter.JSONSchema{Type: "string"})
+	if got != nil || len(issues) != 1 || issues[0].Keyword != "type" {
+		t.Errorf("CompileCohereParameters(string) = %v, %v, want a type issue", got, issues)
+	}
+}
+
+func TestCompileCohereParameters_RecursiveItems(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"tree": {Ref: "#/$defs/Tree"},
+		},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Tree": {Type: "array", Items: &tooladapter.JSONSchema{Ref: "#/$defs/Tree"}},
+		},
+	}
+	got, issues := CompileCohereParameters(schema)
+	if got["tree"].Type != "List" {
+		t.Errorf("tree = %+v, want an untyped List", got["tree"])
+	}
+	if len(issues) != 1 || issues[0].Keyword != "$ref" || issues[0].Path != "/properties/tree/items" {
+		t.Errorf("issues = %v, want one $ref issue at the items", issues)
+	}
+}
+
+func TestCohereParametersToJSONSchema(t *testing.T) {
+	got, err := cohereParametersToJSONSchema(map[string]CohereParameterDefinition{
+		"city":           {Type: "str", Description: "City name", Required: true},
+		"tags":           {Type: "List[str]"},
+		"address.street": {Type: "str", Required: true},
+		"address.zip":    {Type: "str"},
+		"meta":           {Type: "Dict"},
+	})
+	if err != nil {
+		t.Fatalf("cohereParametersToJSONSchema() error = %v", err)
+	}
+
+	if got.Type != "object" || !reflect.DeepEqual(got.Required, []string{"address", "city"}) {
+		t.Errorf("root = %+v, want object requiring address and city", got)
+	}
+	address := got.Properties["address"]
+	if address.Type != "object" || !reflect.DeepEqual(address.Required, []string{"street"}) || len(address.Properties) != 2 {
+		t.Errorf("address = %+v, want object with street and zip, requiring street", address)
+	}
+	if tags := got.Properties["tags"]; tags.Type != "array" || tags.Items.Type != "string" {
+		t.Errorf("tags = %+v, want array of string", tags)
+	}
+	if city := got.Properties["city"]; city.Description != "City name" {
+		t.Errorf("city.Description = %q, want %q", city.Description, "City name")
+	}
+
+	conflicts := []map[string]CohereParameterDefinition{
+		{"a": {Type: "str"}, "a.b": {Type: "str"}},
+		{"a.b": {Type: "str"}, "a.b.c": {Type: "int"}},
+		{"a": {Type: "complex"}},
+	}
+	for _, params := range conflicts {
+		if _, err := cohereParametersToJSONSchema(params); err == nil {
+			t.Errorf("cohereParametersToJSONSchema(%v) error = nil, want error", params)
+		}
+	}
+}
+
+func TestParsePythonType(t *testing.T) {
+	tests := map[string]string{
+		"str":             `{"type":"string"}`,
+		"INT":             `{"type":"integer"}`,
+		"float":           `{"type":"number"}`,
+		"bool":            `{"type":"boolean"}`,
+		"Dict":            `{"type":"object"}`,
+		"dict[str, int]":  `{"type":"object"}`,
+		"list":            `{"type":"array"}`,
+		"List[str]":       `{"items":{"type":"string"},"type":"array"}`,
+		"List[List[int]]": `{"items":{"items":{"type":"integer"},"type":"array"},"type":"array"}`,
+	}
+	for typ, want := range tests {
+		got, err := parsePythonType(typ)
+		if err != nil {
+			t.Errorf("parsePythonType(%q) error = %v", typ, err)
+			continue
+		}
+		if js, _ := json.Marshal(got.ToMap()); string(js) != want {
+			t.Errorf("parsePythonType(%q) = %s, want %s", typ, js, want)
+		}
+	}
+
+	for _, typ := range []string{"complex", "List[str", "List[tuple]", ""} {
+		if _, err := parsePythonType(typ); err == nil {
+			t.Errorf("parsePythonType(%q) error = nil, want error", typ)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// GeminiFunctionDeclaration represents a Gemini / Vertex AI function
+// declaration. This is a self-contained type that doesn't depend on
+// external SDK.
+//
+// Parameters are given either as Parameters, an OpenAPI 3.0 subset, or as
+// ParametersJSONSchema; the API rejects declarations that set both. The
+// same holds for Response and ResponseJSONSchema.
+type GeminiFunctionDeclaration struct {
+	// Name is the function identifier
+	Name string `json:"name"`
+
+	// Description explains what the function does
+	Description string `json:"description,omitempty"`
+
+	// Behavior is BLOCKING or NON_BLOCKING (Live API only)
+	Behavior string `json:"behavior,omitempty"`
+
+	// Parameters is the OpenAPI schema for function arguments
+	Parameters *GeminiSchema `json:"parameters,omitempty"`
+
+	// ParametersJSONSchema is the JSON Schema for function arguments
+	ParametersJSONSchema map[string]any `json:"parametersJsonSchema,omitempty"`
+
+	// Response is the OpenAPI schema for the function result
+	Response *GeminiSchema `json:"response,omitempty"`
+
+	// ResponseJSONSchema is the JSON Schema for the function result
+	ResponseJSONSchema map[string]any `json:"responseJsonSchema,omitempty"`
+}
+
+// GeminiModeJSONSchema is the Gemini adapter mode that emits
+// parametersJsonSchema and responseJsonSchema. The default mode ("") emits
+// the OpenAPI parameters and response fields.
+const GeminiModeJSONSchema = "jsonschema"
+
+// GeminiAdapter converts between Gemini function declarations and
+// canonical format.
+//
+// The adapter has two modes with different capability sets, one per schema
+// form. An adapter in the default mode emits JSON Schema only for tools
+// whose SourceMeta["jsonSchema"] is set, which ToCanonical records for
+// declarations read from parametersJsonSchema; an adapter bound to
+// GeminiModeJSONSchema via WithMode emits JSON Schema for every tool.
+type GeminiAdapter struct {
+	mode           string
+	profile        *tooladapter.CapabilityProfile
+	renderExamples bool
+}
+
+// NewGeminiAdapter creates a new Gemini adapter in the default mode.
+func NewGeminiAdapter() *GeminiAdapter {
+	return &GeminiAdapter{}
+}
+
+// Name returns the adapter identifier.
+func (a *GeminiAdapter) Name() string {
+	return "gemini"
+}
+
+// ModeOf returns the mode a tool is emitted in: the bound mode if set,
+// otherwise GeminiModeJSONSchema when SourceMeta["jsonSchema"] is true.
+func (a *GeminiAdapter) ModeOf(tool *tooladapter.CanonicalTool) string {
+	if a.mode != "" {
+		return a.mode
+	}
+	if tool != nil && tool.SourceMeta != nil {
+		if js, ok := tool.SourceMeta["jsonSchema"].(bool); ok && js {
+			return GeminiModeJSONSchema
+		}
+	}
+	return ""
+}
+
+// WithMode returns a Gemini adapter bound to the given mode.
+// Returns an error if no capability profile exists for the mode.
+func (a *GeminiAdapter) WithMode(mode string) (tooladapter.Adapter, error) {
+	if _, ok := builtinProfile(a.Name(), mode); !ok {
+		return nil, fmt.Errorf("gemini adapter: unknown mode %q", mode)
+	}
+	copied := *a
+	copied.mode = mode
+	copied.profile = nil
+	return &copied, nil
+}
+
+// WithProfile returns a Gemini adapter whose JSON Schema output follows the
+// given profile. Only a GeminiModeJSONSchema profile changes the output,
+// since OpenAPI schemas are compiled by CompileGeminiSchema.
+func (a *GeminiAdapter) WithProfile(profile *tooladapter.CapabilityProfile) tooladapter.Adapter {
+	copied := *a
+	copied.profile = profile.Clone()
+	return &copied
+}
+
+// WithExamplesInDescription returns a copy of the adapter that renders tool
+// examples into function descriptions, since Gemini function declarations
+// have no examples field. ToCanonical on the copy splits rendered examples
+// back out.
+func (a *GeminiAdapter) WithExamplesInDescription() *GeminiAdapter {
+	copied := *a
+	copied.renderExamples = true
+	return &copied
+}
+
+// ToCanonical converts a Gemini function declaration to canonical format.
+// Accepts GeminiFunctionDeclaration (or a pointer to it) and raw JSON as
+// json.RawMessage, []byte or map[string]any.
+//
+// OpenAPI schemas are converted with lowercase types, and nullable
+// schemas become anyOf with a null branch. propertyOrdering lists are kept
+// in SourceMeta["propertyOrdering"], keyed by JSON Pointer, and the
+// behavior in SourceMeta["behavior"].
+func (a *GeminiAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
+	var decl GeminiFunctionDeclaration
+
+	switch v := raw.(type) {
+	case GeminiFunctionDeclaration:
+		decl = v
+	case *GeminiFunctionDeclaration:
+		if v == nil {
+			return nil, errors.New("nil GeminiFunctionDeclaration pointer")
+		}
+		decl = *v
+	case json.RawMessage:
+		if err := json.Unmarshal(v, &decl); err != nil {
+			return nil, fmt.Errorf("decode gemini function declaration: %w", err)
+		}
+	case []byte:
+		if err := json.Unmarshal(v, &decl); err != nil {
+			return nil, fmt.Errorf("decode gemini function declaration: %w", err)
+		}
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &decl); err != nil {
+			return nil, fmt.Errorf("decode gemini function declaration: %w", err)
+		}
+	default:
+		return nil, errors.New("expected GeminiFunctionDeclaration or raw JSON")
+	}
+
+	if decl.Parameters != nil && decl.ParametersJSONSchema != nil {
+		return nil, errors.New("gemini function declaration sets both parameters and parametersJsonSchema")
+	}
+	if decl.Response != nil && decl.ResponseJSONSchema != nil {
+		return nil, errors.New("gemini function declaration sets both response and responseJsonSchema")
+	}
+
+	canonical := &tooladapter.CanonicalTool{
+		Name:         decl.Name,
+		Description:  decl.Description,
+		SourceFormat: "gemini",
+		SourceMeta:   make(map[string]any),
+	}
+	if a.renderExamples {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(decl.Description)
+	}
+	if decl.Behavior != "" {
+		canonical.SourceMeta["behavior"] = decl.Behavior
+	}
+
+	orderings := make(map[string]any)
+	switch {
+	case decl.ParametersJSONSchema != nil:
+		schema, err := mapToJSONSchema(decl.ParametersJSONSchema)
+		if err != nil {
+			return nil, err
+		}
+		canonical.InputSchema = schema
+		canonical.SourceMeta["jsonSchema"] = true
+	case decl.Parameters != nil:
+		canonical.InputSchema = geminiToJSONSchema(decl.Parameters)
+		geminiPropertyOrdering(decl.Parameters, "/parameters", orderings)
+	}
+
+	switch {
+	case decl.ResponseJSONSchema != nil:
+		schema, err := mapToJSONSchema(decl.ResponseJSONSchema)
+		if err != nil {
+			return nil, err
+		}
+		canonical.OutputSchema = schema
+	case decl.Response != nil:
+		canonical.OutputSchema = geminiToJSONSchema(decl.Response)
+		geminiPropertyOrdering(decl.Response, "/response", orderings)
+	}
+	if len(orderings) > 0 {
+		canonical.SourceMeta["propertyOrdering"] = orderings
+	}
+
+	return canonical, nil
+}
+
+// FromCanonical converts a canonical tool to a GeminiFunctionDeclaration.
+// In the default mode, schemas are compiled by CompileGeminiSchema and
+// recorded propertyOrdering lists are restored; in GeminiModeJSONSchema
+// they are emitted as JSON Schema without the keywords the jsonschema
+// capability profile does not support.
+func (a *GeminiAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
+	if tool == nil {
+		return nil, errors.New("nil CanonicalTool")
+	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
+
+	decl := GeminiFunctionDeclaration{
+		Name:        tool.Name,
+		Description: tool.Description,
+	}
+	if a.renderExamples {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
+		}
+		decl.Description = tooladapter.RenderExamples(tool.Description, tool.Examples)
+	}
+	if behavior, ok := tool.SourceMeta["behavior"].(string); ok {
+		decl.Behavior = behavior
+	}
+
+	if a.ModeOf(tool) == GeminiModeJSONSchema {
+		if tool.InputSchema != nil {
+			params, _ := restrictSchema(tool.InputSchema, a.jsonSchemaProfile())
+			decl.ParametersJSONSchema = params.ToMap()
+		}
+		if tool.OutputSchema != nil {
+			response, _ := restrictSchema(tool.OutputSchema, a.jsonSchemaProfile())
+			decl.ResponseJSONSchema = response.ToMap()
+		}
+		return decl, nil
+	}
+
+	orderings, _ := tool.SourceMeta["propertyOrdering"].(map[string]any)
+	if tool.InputSchema != nil {
+		decl.Parameters, _ = CompileGeminiSchema(tool.InputSchema)
+		applyGeminiPropertyOrdering(decl.Parameters, "/parameters", orderings)
+	}
+	if tool.OutputSchema != nil {
+		decl.Response, _ = CompileGeminiSchema(tool.OutputSchema)
+		applyGeminiPropertyOrdering(decl.Response, "/response", orderings)
+	}
+	return decl, nil
+}
+
+// SchemaIssues returns what FromCanonical drops or rewrites in a function
+// tool's input and output schemas: the losses of CompileGeminiSchema in the
+// default mode, and the keywords the jsonschema profile does not support
+// in GeminiModeJSONSchema.
+func (a *GeminiAdapter) SchemaIssues(tool *tooladapter.CanonicalTool) []tooladapter.SchemaIssue {
+	if tool == nil || !tool.IsFunction() {
+		return nil
+	}
+	compile := func(schema *tooladapter.JSONSchema) []tooladapter.SchemaIssue {
+		if a.ModeOf(tool) == GeminiModeJSONSchema {
+			_, found := restrictSchema(schema, a.jsonSchemaProfile())
+			return found
+		}
+		_, found := CompileGeminiSchema(schema)
+		return found
+	}
+	var issues []tooladapter.SchemaIssue
+	if tool.InputSchema != nil {
+		issues = append(issues, schemaIssuesAt("/inputSchema", compile(tool.InputSchema))...)
+	}
+	if tool.OutputSchema != nil {
+		issues = append(issues, schemaIssuesAt("/outputSchema", compile(tool.OutputSchema))...)
+	}
+	return issues
+}
+
+// jsonSchemaProfile returns the profile JSON Schema output is restricted
+// to: the bound GeminiModeJSONSchema profile, or the built-in one.
+func (a *GeminiAdapter) jsonSchemaProfile() *tooladapter.CapabilityProfile {
+	if a.profile != nil && a.profile.Mode == GeminiModeJSONSchema {
+		return a.profile
+	}
+	p, _ := builtinProfile(a.Name(), GeminiModeJSONSchema)
+	return p
+}
+
+// SupportsFeature returns whether this adapter supports a schema feature.
+// Answers come from the built-in capability profile of the bound mode.
+func (a *GeminiAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
+	return supportsBuiltin(a.Name(), a.mode, feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Supported modes are "" (default) and "jsonschema".
+func (a *GeminiAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	return builtinProfile(a.Name(), mode)
+}
@@ -0,0 +1,426 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// GeminiSchema is the OpenAPI 3.0 subset Gemini and Vertex AI accept in
+// FunctionDeclaration.parameters and response.
+type GeminiSchema struct {
+	// Type is an uppercase type name: STRING, NUMBER, INTEGER, BOOLEAN,
+	// ARRAY, OBJECT or NULL
+	Type string `json:"type,omitempty"`
+
+	// Format is a semantic format (e.g., "date-time", "int64")
+	Format string `json:"format,omitempty"`
+
+	// Description explains the schema
+	Description string `json:"description,omitempty"`
+
+	// Nullable allows null in addition to Type
+	Nullable bool `json:"nullable,omitempty"`
+
+	// Enum restricts a STRING to a fixed set of values
+	Enum []string `json:"enum,omitempty"`
+
+	// Default is the default value
+	Default any `json:"default,omitempty"`
+
+	// Items is the schema for ARRAY elements
+	Items *GeminiSchema `json:"items,omitempty"`
+
+	// MinItems is the minimum ARRAY length
+	MinItems *int64 `json:"minItems,omitempty"`
+
+	// MaxItems is the maximum ARRAY length
+	MaxItems *int64 `json:"maxItems,omitempty"`
+
+	// Properties maps property names to their schemas for OBJECT
+	Properties map[string]*GeminiSchema `json:"properties,omitempty"`
+
+	// PropertyOrdering lists the property names in the order the model
+	// should produce them
+	PropertyOrdering []string `json:"propertyOrdering,omitempty"`
+
+	// Required lists property names that must be present
+	Required []string `json:"required,omitempty"`
+
+	// MinLength is the minimum STRING length
+	MinLength *int64 `json:"minLength,omitempty"`
+
+	// MaxLength is the maximum STRING length
+	MaxLength *int64 `json:"maxLength,omitempty"`
+
+	// Pattern is a regex pattern for STRING validation
+	Pattern string `json:"pattern,omitempty"`
+
+	// Minimum is the minimum numeric value
+	Minimum *float64 `json:"minimum,omitempty"`
+
+	// Maximum is the maximum numeric value
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// AnyOf allows any of the listed schemas
+	AnyOf []*GeminiSchema `json:"anyOf,omitempty"`
+}
+
+// UnmarshalJSON decodes a schema. The API encodes int64 fields such as
+// minLength and minItems as JSON strings; both strings and numbers are
+// accepted.
+func (s *GeminiSchema) UnmarshalJSON(data []byte) error {
+	type plain GeminiSchema
+	var v struct {
+		plain
+		MinLength *json.Number `json:"minLength,omitempty"`
+		MaxLength *json.Number `json:"maxLength,omitempty"`
+		MinItems  *json.Number `json:"minItems,omitempty"`
+		MaxItems  *json.Number `json:"maxItems,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = GeminiSchema(v.plain)
+	var err error
+	if s.MinLength, err = parseGeminiInt(v.MinLength); err != nil {
+		return fmt.Errorf("minLength: %w", err)
+	}
+	if s.MaxLength, err = parseGeminiInt(v.MaxLength); err != nil {
+		return fmt.Errorf("maxLength: %w", err)
+	}
+	if s.MinItems, err = parseGeminiInt(v.MinItems); err != nil {
+		return fmt.Errorf("minItems: %w", err)
+	}
+	if s.MaxItems, err = parseGeminiInt(v.MaxItems); err != nil {
+		return fmt.Errorf("maxItems: %w", err)
+	}
+	return nil
+}
+
+func parseGeminiInt(n *json.Number) (*int64, error) {
+	if n == nil {
+		return nil, nil
+	}
+	i, err := n.Int64()
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// CompileGeminiSchema rewrites a canonical schema into the OpenAPI 3.0
+// subset used by Gemini FunctionDeclaration.parameters.
+//
+// Types become uppercase. A nullable schema, written as anyOf with a null
+// branch, becomes its non-null branch with nullable: true. Local $ref
+// values are inlined from the root $defs, oneOf is approximated by anyOf,
+// and a const string becomes a single-value enum. Keywords the subset
+// lacks (additionalProperties, allOf, not, non-string enums and consts,
+// recursive references) are removed. Each such change is reported as an
+// issue.
+//
+// The input schema is not modified. Returns nil if schema is nil.
+func CompileGeminiSchema(schema *tooladapter.JSONSchema) (*GeminiSchema, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+	c := &geminiCompiler{root: schema}
+	return c.compile(schema, ""), c.issues
+}
+
+// geminiCompiler carries the root schema, for resolving references, and
+// collected issues through a single compilation.
+type geminiCompiler struct {
+	root   *tooladapter.JSONSchema
+	issues []tooladapter.SchemaIssue
+
+	// inlining holds the $ref values being expanded, to stop recursion
+	inlining []string
+}
+
+func (c *geminiCompiler) issue(path, keyword, message string) {
+	c.issues = append(c.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+func (c *geminiCompiler) compile(s *tooladapter.JSONSchema, path string) *GeminiSchema {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		return c.inline(s, path)
+	}
+
+	nullable := false
+	if inner, ok := nullBranchOf(s); ok {
+		s, nullable = inner, true
+	}
+
+	out := &GeminiSchema{
+		Type:        strings.ToUpper(s.Type),
+		Format:      s.Format,
+		Description: s.Description,
+		Nullable:    nullable,
+		Default:     s.Default,
+		Pattern:     s.Pattern,
+		Minimum:     copyFloat(s.Minimum),
+		Maximum:     copyFloat(s.Maximum),
+		Required:    slices.Clone(s.Required),
+	}
+	if s.MinLength != nil {
+		n := int64(*s.MinLength)
+		out.MinLength = &n
+	}
+	if s.MaxLength != nil {
+		n := int64(*s.MaxLength)
+		out.MaxLength = &n
+	}
+	if s.MinItems != nil {
+		n := int64(*s.MinItems)
+		out.MinItems = &n
+	}
+	if s.MaxItems != nil {
+		n := int64(*s.MaxItems)
+		out.MaxItems = &n
+	}
+
+	if len(s.Enum) > 0 {
+		if values, ok := stringValues(s.Enum); ok {
+			out.Enum = values
+		} else {
+			c.issue(path, "enum", "removed; only string enums are supported")
+		}
+	}
+	if s.Const != nil {
+		if v, ok := s.Const.(string); ok && out.Enum == nil {
+			out.Enum = []string{v}
+			if out.Type == "" {
+				out.Type = "STRING"
+			}
+		} else {
+			c.issue(path, "const", "removed; only string constants can be expressed as an enum")
+		}
+	}
+
+	if len(s.Properties) > 0 {
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out.Properties = make(map[string]*GeminiSchema, len(names))
+		for _, name := range names {
+			out.Properties[name] = c.compile(s.Properties[name], path+"/properties/"+escapePointer(name))
+		}
+		if out.Type == "" {
+			out.Type = "OBJECT"
+		}
+	}
+	if s.Items != nil {
+		out.Items = c.compile(s.Items, path+"/items")
+	}
+
+	for i, sub := range s.AnyOf {
+		out.AnyOf = append(out.AnyOf, c.compile(sub, fmt.Sprintf("%s/anyOf/%d", path, i)))
+	}
+	if len(s.OneOf) > 0 {
+		c.issue(path, "oneOf", "approximated as anyOf; exclusivity is not enforced")
+		for i, sub := range s.OneOf {
+			out.AnyOf = append(out.AnyOf, c.compile(sub, fmt.Sprintf("%s/oneOf/%d", path, i)))
+		}
+	}
+	if len(s.AllOf) > 0 {
+		c.issue(path, "allOf", "removed; not supported")
+	}
+	if s.Not != nil {
+		c.issue(path, "not", "removed; not supported")
+	}
+	if s.AdditionalProperties != nil {
+		c.issue(path, "additionalProperties", "removed; not supported")
+	}
+	if len(s.Defs) > 0 && path != "" {
+		c.issue(path, "$defs", "removed; definitions are only read from the root")
+	}
+	for _, k := range extraKeywords(s) {
+		c.issue(path, k, "removed; not supported")
+	}
+	return out
+}
+
+// inline compiles the root $defs entry a reference points to in place of
+// the reference.
+func (c *geminiCompiler) inline(s *tooladapter.JSONSchema, path string) *GeminiSchema {
+	if slices.Contains(c.inlining, s.Ref) {
+		c.issue(path, "$ref", "replaced by an unconstrained OBJECT; recursive references cannot be inlined")
+		return &GeminiSchema{Type: "OBJECT", Description: s.Description}
+	}
+	name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+	target := c.root.Defs[strings.NewReplacer("~1", "/", "~0", "~").Replace(name)]
+	if !ok || target == nil {
+		c.issue(path, "$ref", fmt.Sprintf("removed; cannot resolve %q", s.Ref))
+		return &GeminiSchema{Description: s.Description}
+	}
+
+	c.inlining = append(c.inlining, s.Ref)
+	defer func() { c.inlining = c.inlining[:len(c.inlining)-1] }()
+	out := c.compile(target, path)
+	if s.Description != "" {
+		out.Description = s.Description
+	}
+	return out
+}
+
+// nullBranchOf reports whether s is nullable, written as an anyOf with a
+// null branch and one other branch, and returns the schema without null.
+// A bare type branch is merged into s, matching how type lists are read.
+func nullBranchOf(s *tooladapter.JSONSchema) (*tooladapter.JSONSchema, bool) {
+	if len(s.AnyOf) != 2 || s.Type != "" {
+		return nil, false
+	}
+	for i, b := range s.AnyOf {
+		other := s.AnyOf[1-i]
+		if b == nil || other == nil || !reflect.DeepEqual(*b, tooladapter.JSONSchema{Type: "null"}) {
+			continue
+		}
+		if reflect.DeepEqual(*other, tooladapter.JSONSchema{Type: other.Type}) {
+			merged := *s
+			merged.AnyOf = nil
+			merged.Type = other.Type
+			return &merged, true
+		}
+		return stripNullBranch(s), true
+	}
+	return nil, false
+}
+
+// stringValues returns the values as strings if they all are strings.
+func stringValues(values []any) ([]string, bool) {
+	out := make([]string, len(values))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		out[i] = s
+	}
+	return out, true
+}
+
+// geminiToJSONSchema converts a Gemini schema to canonical form. Nullable
+// schemas become anyOf with a null branch, as type lists do, and the
+// "enum" format Gemini uses for string enums is dropped.
+func geminiToJSONSchema(s *GeminiSchema) *tooladapter.JSONSchema {
+	if s == nil {
+		return nil
+	}
+	out := &tooladapter.JSONSchema{
+		Description: s.Description,
+		Format:      s.Format,
+		Default:     s.Default,
+		Pattern:     s.Pattern,
+		Minimum:     copyFloat(s.Minimum),
+		Maximum:     copyFloat(s.Maximum),
+		Required:    slices.Clone(s.Required),
+		Items:       geminiToJSONSchema(s.Items),
+	}
+	if s.MinLength != nil {
+		n := int(*s.MinLength)
+		out.MinLength = &n
+	}
+	if s.MaxLength != nil {
+		n := int(*s.MaxLength)
+		out.MaxLength = &n
+	}
+	if s.MinItems != nil {
+		n := int(*s.MinItems)
+		out.MinItems = &n
+	}
+	if s.MaxItems != nil {
+		n := int(*s.MaxItems)
+		out.MaxItems = &n
+	}
+	if len(s.Enum) > 0 {
+		out.Enum = make([]any, len(s.Enum))
+		for i, v := range s.Enum {
+			out.Enum[i] = v
+		}
+		if out.Format == "enum" {
+			out.Format = ""
+		}
+	}
+	if len(s.Properties) > 0 {
+		out.Properties = make(map[string]*tooladapter.JSONSchema, len(s.Properties))
+		for name, prop := range s.Properties {
+			out.Properties[name] = geminiToJSONSchema(prop)
+		}
+	}
+	for _, sub := range s.AnyOf {
+		out.AnyOf = append(out.AnyOf, geminiToJSONSchema(sub))
+	}
+
+	typ := strings.ToLower(s.Type)
+	if typ == "type_unspecified" {
+		typ = ""
+	}
+	if s.Nullable && typ != "" && typ != "null" {
+		applyTypeList(out, []string{typ, "null"})
+	} else {
+		out.Type = typ
+	}
+	return out
+}
+
+// geminiPropertyOrdering collects the propertyOrdering lists of a schema,
+// keyed by the JSON Pointer of the object they belong to.
+func geminiPropertyOrdering(s *GeminiSchema, path string, into map[string]any) {
+	if s == nil {
+		return
+	}
+	if len(s.PropertyOrdering) > 0 {
+		into[path] = slices.Clone(s.PropertyOrdering)
+	}
+	for name, prop := range s.Properties {
+		geminiPropertyOrdering(prop, path+"/properties/"+escapePointer(name), into)
+	}
+	geminiPropertyOrdering(s.Items, path+"/items", into)
+	for i, sub := range s.AnyOf {
+		geminiPropertyOrdering(sub, fmt.Sprintf("%s/anyOf/%d", path, i), into)
+	}
+}
+
+// applyGeminiPropertyOrdering restores recorded propertyOrdering lists.
+// Names that are no longer properties are dropped, and new properties are
+// appended in name order.
+func applyGeminiPropertyOrdering(s *GeminiSchema, path string, orderings map[string]any) {
+	if s == nil {
+		return
+	}
+	if recorded := stringList(orderings[path]); len(recorded) > 0 && len(s.Properties) > 0 {
+		order := make([]string, 0, len(s.Properties))
+		for _, name := range recorded {
+			if _, ok := s.Properties[name]; ok && !slices.Contains(order, name) {
+				order = append(order, name)
+			}
+		}
+		var rest []string
+		for name := range s.Properties {
+			if !slices.Contains(order, name) {
+				rest = append(rest, name)
+			}
+		}
+		sort.Strings(rest)
+		s.PropertyOrdering = append(order, rest...)
+	}
+	for name, prop := range s.Properties {
+		applyGeminiPropertyOrdering(prop, path+"/properties/"+escapePointer(name), orderings)
+	}
+	applyGeminiPropertyOrdering(s.Items, path+"/items", orderings)
+	for i, sub := range s.AnyOf {
+		applyGeminiPropertyOrdering(sub, fmt.Sprintf("%s/anyOf/%d", path, i), orderings)
+	}
+}
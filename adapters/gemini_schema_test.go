@@ -0,0 +1,149 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestCompileGeminiSchema(t *testing.T) {
+	minLen := 1
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"city":  {Type: "string", MinLength: &minLen},
+			"unit":  {Type: "string", Enum: []any{"c", "f"}},
+			"kind":  {Const: "fixed"},
+			"days":  {AnyOf: []*tooladapter.JSONSchema{{Type: "integer"}, {Type: "null"}}, Description: "Forecast days"},
+			"owner": {Ref: "#/$defs/Person"},
+			"level": {Type: "integer", Enum: []any{1, 2}},
+		},
+		Required:             []string{"city"},
+		AdditionalProperties: boolPtr(false),
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Person": {
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"name":   {Type: "string"},
+					"parent": {Ref: "#/$defs/Person"},
+				},
+			},
+		},
+	}
+
+	got, issues := CompileGeminiSchema(schema)
+
+	if got.Type != "OBJECT" || !reflect.DeepEqual(got.Required, []string{"city"}) {
+		t.Errorf("root = %+v, want OBJECT requiring city", got)
+	}
+	props := got.Properties
+	if c := props["city"]; c.Type != "STRING" || c.MinLength == nil || *c.MinLength != 1 {
+		t.Errorf("city = %+v, want STRING with minLength 1", c)
+	}
+	if u := props["unit"]; !reflect.DeepEqual(u.Enum, []string{"c", "f"}) {
+		t.Errorf("unit.Enum = %v, want [c f]", u.Enum)
+	}
+	if k := props["kind"]; k.Type != "STRING" || !reflect.DeepEqual(k.Enum, []string{"fixed"}) {
+		t.Errorf("kind = %+v, want a single-value STRING enum", k)
+	}
+	if d := props["days"]; d.Type != "INTEGER" || !d.Nullable || d.Description != "Forecast days" || d.AnyOf != nil {
+		t.Errorf("days = %+v, want nullable INTEGER", d)
+	}
+	owner := props["owner"]
+	if owner.Type != "OBJECT" || owner.Properties["name"].Type != "STRING" {
+		t.Errorf("owner = %+v, want the inlined Person", owner)
+	}
+	if parent := owner.Properties["parent"]; parent.Type != "OBJECT" || parent.Properties != nil {
+		t.Errorf("owner.parent = %+v, want an unconstrained OBJECT", parent)
+	}
+
+	var gotIssues []string
+	for _, issue := range issues {
+		gotIssues = append(gotIssues, issue.Keyword+" "+issue.Path)
+	}
+	want := []string{
+		"enum /properties/level",
+		"$ref /properties/owner/properties/parent",
+		"additionalProperties ",
+	}
+	if !reflect.DeepEqual(gotIssues, want) {
+		t.Errorf("issues = %q, want %q", gotIssues, want)
+	}
+}
+
+func TestCompileGeminiSchema_Approximations(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		OneOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}},
+		AllOf: []*tooladapter.JSONSchema{{Type: "object"}},
+		Not:   &tooladapter.JSONSchema{Type: "null"},
+		Const: 3,
+	}
+
+	got, issues := CompileGeminiSchema(schema)
+	if len(got.AnyOf) != 2 || got.AnyOf[0].Type != "STRING" {
+		t.Errorf("AnyOf = %+v, want the oneOf branches", got.AnyOf)
+	}
+	var keywords []string
+	for _, issue := range issues {
+		keywords = append(keywords, issue.Keyword)
+	}
+	if want := []string{"const", "oneOf", "allOf", "not"}; !reflect.DeepEqual(keywords, want) {
+		t.Errorf("issue keywords = %v, want %v", keywords, want)
+	}
+	if got, _ := CompileGeminiSchema(nil); got != nil {
+		t.Errorf("CompileGeminiSchema(nil) = %+v, want nil", got)
+	}
+}
+
+func TestGeminiSchema_UnmarshalJSON(t *testing.T) {
+	var s GeminiSchema
+	data := `{"type":"STRING","minLength":"2","maxLength":10,"nullable":true,"enum":["a","b"],"format":"enum"}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if s.MinLength == nil || *s.MinLength != 2 || s.MaxLength == nil || *s.MaxLength != 10 {
+		t.Errorf("lengths = %v, %v, want 2 and 10", s.MinLength, s.MaxLength)
+	}
+
+	var arr GeminiSchema
+	if err := json.Unmarshal([]byte(`{"type":"ARRAY","minItems":"1","maxItems":3}`), &arr); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if arr.MinItems == nil || *arr.MinItems != 1 || arr.MaxItems == nil || *arr.MaxItems != 3 {
+		t.Errorf("item counts = %v, %v, want 1 and 3", arr.MinItems, arr.MaxItems)
+	}
+	if !s.Nullable || s.Type != "STRING" {
+		t.Errorf("schema = %+v, want nullable STRING", s)
+	}
+
+	if err := json.Unmarshal([]byte(`{"minLength":"two"}`), &s); err == nil {
+		t.Error("Unmarshal() of a non-numeric minLength succeeded, want error")
+	}
+}
+
+func TestGeminiToJSONSchema_Nullable(t *testing.T) {
+	minLen := int64(1)
+	got := geminiToJSONSchema(&GeminiSchema{
+		Type:        "STRING",
+		Nullable:    true,
+		Description: "A name",
+		MinLength:   &minLen,
+		Enum:        []string{"a"},
+		Format:      "enum",
+	})
+
+	if got.Type != "" || len(got.AnyOf) != 2 || got.AnyOf[0].Type != "string" || got.AnyOf[1].Type != "null" {
+		t.Errorf("schema = %+v, want anyOf string|null", got)
+	}
+	if got.Format != "" || got.MinLength == nil || *got.MinLength != 1 || got.Description != "A name" {
+		t.Errorf("schema = %+v, want keywords kept and format enum dropped", got)
+	}
+
+	// Compiling back restores the nullable form
+	back, issues := CompileGeminiSchema(got)
+	if len(issues) != 0 || back.Type != "STRING" || !back.Nullable || *back.MinLength != 1 {
+		t.Errorf("CompileGeminiSchema() = %+v, %v, want nullable STRING", back, issues)
+	}
+}
@@ -0,0 +1,335 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const geminiWeatherJSON = `{
+	"name": "get_weather",
+	"description": "Get the weather.",
+	"behavior": "NON_BLOCKING",
+	"parameters": {
+		"type": "OBJECT",
+		"properties": {
+			"city": {"type": "STRING", "minLength": "1"},
+			"days": {"type": "INTEGER", "nullable": true, "minimum": 1},
+			"unit": {"type": "STRING", "format": "enum", "enum": ["c", "f"]}
+		},
+		"propertyOrdering": ["unit", "city", "days"],
+		"required": ["city"]
+	},
+	"response": {
+		"type": "OBJECT",
+		"properties": {"temp": {"type": "NUMBER"}}
+	}
+}`
+
+func TestGeminiAdapter_Name(t *testing.T) {
+	if got := NewGeminiAdapter().Name(); got != "gemini" {
+		t.Errorf("Name() = %q, want %q", got, "gemini")
+	}
+}
+
+func TestGeminiAdapter_ToCanonical_OpenAPI(t *testing.T) {
+	got, err := NewGeminiAdapter().ToCanonical(json.RawMessage(geminiWeatherJSON))
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	if got.Name != "get_weather" || got.SourceFormat != "gemini" {
+		t.Errorf("tool = %s/%s, want get_weather/gemini", got.Name, got.SourceFormat)
+	}
+	if got.SourceMeta["behavior"] != "NON_BLOCKING" {
+		t.Errorf("SourceMeta[behavior] = %v, want NON_BLOCKING", got.SourceMeta["behavior"])
+	}
+	in := got.InputSchema
+	if in.Type != "object" || !reflect.DeepEqual(in.Required, []string{"city"}) {
+		t.Errorf("InputSchema = %+v, want object requiring city", in)
+	}
+	if city := in.Properties["city"]; city.Type != "string" || city.MinLength == nil || *city.MinLength != 1 {
+		t.Errorf("city = %+v, want string with minLength 1", city)
+	}
+	if days := in.Properties["days"]; len(days.AnyOf) != 2 || days.Minimum == nil {
+		t.Errorf("days = %+v, want anyOf integer|null with minimum", days)
+	}
+	if unit := in.Properties["unit"]; unit.Format != "" || len(unit.Enum) != 2 {
+		t.Errorf("unit = %+v, want a plain enum", unit)
+	}
+	if got.OutputSchema == nil || got.OutputSchema.Properties["temp"].Type != "number" {
+		t.Errorf("OutputSchema = %+v, want temp number", got.OutputSchema)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestGeminiAdapter_ToCanonical_JSONSchema(t *testing.T) {
+	decl := GeminiFunctionDeclaration{
+		Name: "lookup",
+		ParametersJSONSchema: map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"id": map[string]any{"$ref": "#/$defs/ID"}},
+			"$defs":                map[string]any{"ID": map[string]any{"type": "string"}},
+			"additionalProperties": false,
+		},
+		ResponseJSONSchema: map[string]any{"type": "object"},
+	}
+
+	got, err := NewGeminiAdapter().ToCanonical(&decl)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if got.SourceMeta["jsonSchema"] != true {
+		t.Errorf("SourceMeta[jsonSchema] = %v, want true", got.SourceMeta["jsonSchema"])
+	}
+	if got.InputSchema.Properties["id"].Ref != "#/$defs/ID" || got.InputSchema.AdditionalProperties == nil {
+		t.Errorf("InputSchema = %+v, want $ref and additionalProperties kept", got.InputSchema)
+	}
+	if got.OutputSchema == nil || got.OutputSchema.Type != "object" {
+		t.Errorf("OutputSchema = %+v, want object", got.OutputSchema)
+	}
+
+	// The JSON Schema form is restored on the way out
+	out, err := NewGeminiAdapter().FromCanonical(got)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	back := out.(GeminiFunctionDeclaration)
+	if back.Parameters != nil || back.ParametersJSONSchema["$defs"] == nil || back.ResponseJSONSchema == nil {
+		t.Errorf("FromCanonical() = %+v, want parametersJsonSchema and responseJsonSchema", back)
+	}
+}
+
+func TestGeminiAdapter_ToCanonical_Errors(t *testing.T) {
+	tests := map[string]any{
+		"nil pointer":     (*GeminiFunctionDeclaration)(nil),
+		"unsupported":     42,
+		"malformed JSON":  []byte(`{"name":`),
+		"both parameters": GeminiFunctionDeclaration{Name: "x", Parameters: &GeminiSchema{Type: "OBJECT"}, ParametersJSONSchema: map[string]any{}},
+		"both responses":  GeminiFunctionDeclaration{Name: "x", Response: &GeminiSchema{Type: "OBJECT"}, ResponseJSONSchema: map[string]any{}},
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewGeminiAdapter().ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestGeminiAdapter_RoundTrip(t *testing.T) {
+	adapter := NewGeminiAdapter()
+	canonical, err := adapter.ToCanonical(json.RawMessage(geminiWeatherJSON))
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+
+	gotJSON, _ := json.Marshal(out)
+	var got, want any
+	_ = json.Unmarshal(gotJSON, &got)
+	_ = json.Unmarshal([]byte(geminiWeatherJSON), &want)
+	// Lengths are emitted as numbers and the enum format is not restored
+	props := want.(map[string]any)["parameters"].(map[string]any)["properties"].(map[string]any)
+	props["city"].(map[string]any)["minLength"] = 1.0
+	delete(props["unit"].(map[string]any), "format")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip =\n%s\nwant\n%s", gotJSON, geminiWeatherJSON)
+	}
+}
+
+func TestGeminiAdapter_ExamplesInDescription(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}}},
+		Examples:    []map[string]any{{"city": "Paris"}},
+	}
+
+	adapter := NewGeminiAdapter().WithExamplesInDescription()
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}"
+	if got := out.(GeminiFunctionDeclaration).Description; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if back.Description != tool.Description || !reflect.DeepEqual(back.Examples, tool.Examples) {
+		t.Errorf("ToCanonical() = %q with examples %v, want the original description and examples", back.Description, back.Examples)
+	}
+
+	out, _ = NewGeminiAdapter().FromCanonical(tool)
+	if got := out.(GeminiFunctionDeclaration).Description; got != tool.Description {
+		t.Errorf("default Description = %q, want examples dropped", got)
+	}
+
+	invalid := *tool
+	invalid.Examples = []map[string]any{{"city": 42}}
+	if _, err := adapter.FromCanonical(&invalid); err == nil {
+		t.Error("FromCanonical() with invalid example error = nil, want error")
+	}
+}
+
+func TestGeminiAdapter_WithMode(t *testing.T) {
+	adapter, err := NewGeminiAdapter().WithMode(GeminiModeJSONSchema)
+	if err != nil {
+		t.Fatalf("WithMode() error = %v", err)
+	}
+	if !adapter.SupportsFeature(tooladapter.FeatureRef) {
+		t.Error("jsonschema SupportsFeature($ref) = false, want true")
+	}
+
+	out, err := adapter.FromCanonical(&tooladapter.CanonicalTool{
+		Name:        "t",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+	})
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if decl := out.(GeminiFunctionDeclaration); decl.Parameters != nil || decl.ParametersJSONSchema["type"] != "object" {
+		t.Errorf("FromCanonical() = %+v, want parametersJsonSchema", decl)
+	}
+
+	if _, err := NewGeminiAdapter().WithMode("strict"); err == nil {
+		t.Error("WithMode(strict) error = nil, want error")
+	}
+}
+
+func TestGeminiAdapter_JSONSchemaMode_RestrictsToProfile(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name: "lookup",
+		InputSchema: &tooladapter.JSONSchema{
+			Type:       "object",
+			Properties: map[string]*tooladapter.JSONSchema{"code": {Type: "string", Pattern: "^[A-Z]+$"}},
+		},
+		OutputSchema: &tooladapter.JSONSchema{Type: "string", Default: "none"},
+		SourceMeta:   map[string]any{"jsonSchema": true},
+	}
+
+	adapter := NewGeminiAdapter()
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	decl := out.(GeminiFunctionDeclaration)
+	code := decl.ParametersJSONSchema["properties"].(map[string]any)["code"].(map[string]any)
+	if _, ok := code["pattern"]; ok {
+		t.Errorf("parametersJsonSchema code = %v, want pattern removed", code)
+	}
+	if _, ok := decl.ResponseJSONSchema["default"]; ok {
+		t.Errorf("responseJsonSchema = %v, want default removed", decl.ResponseJSONSchema)
+	}
+	issues := adapter.SchemaIssues(tool)
+	if len(issues) != 2 || issues[0].Path != "/inputSchema/properties/code" || issues[1].Path != "/outputSchema" {
+		t.Errorf("SchemaIssues() = %v, want pattern and default issues", issues)
+	}
+
+	// A registry override that allows pattern keeps it in the output
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(adapter)
+	p, _ := registry.Profile("gemini", GeminiModeJSONSchema)
+	p.Features[tooladapter.FeaturePattern] = true
+	_ = registry.SetProfile(*p)
+	decl.ParametersJSONSchema["properties"].(map[string]any)["code"].(map[string]any)["pattern"] = "^[A-Z]+$"
+	result, err := registry.Convert(decl, "gemini", "gemini:jsonschema")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	code = result.Tool.(GeminiFunctionDeclaration).ParametersJSONSchema["properties"].(map[string]any)["code"].(map[string]any)
+	if code["pattern"] != "^[A-Z]+$" || len(result.Warnings) != 0 || len(result.Issues) != 0 {
+		t.Errorf("Convert() code = %v, warnings = %v, issues = %v, want pattern kept", code, result.Warnings, result.Issues)
+	}
+}
+
+func TestGeminiAdapter_SupportsFeature(t *testing.T) {
+	adapter := NewGeminiAdapter()
+	tests := map[tooladapter.SchemaFeature]bool{
+		tooladapter.FeatureAnyOf:                true,
+		tooladapter.FeatureOneOf:                false,
+		tooladapter.FeatureRef:                  false,
+		tooladapter.FeatureAdditionalProperties: false,
+		tooladapter.FeatureConst:                false,
+		tooladapter.FeatureMinLength:            true,
+		tooladapter.FeatureOutputSchema:         true,
+		tooladapter.FeatureAnnotations:          false,
+	}
+	for feature, want := range tests {
+		if got := adapter.SupportsFeature(feature); got != want {
+			t.Errorf("SupportsFeature(%s) = %v, want %v", feature, got, want)
+		}
+	}
+}
+
+func TestGeminiAdapter_FromCanonical_Kinds(t *testing.T) {
+	adapter := NewGeminiAdapter()
+	if _, err := adapter.FromCanonical(nil); err == nil {
+		t.Error("FromCanonical(nil) error = nil, want error")
+	}
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+	if _, err := adapter.FromCanonical(custom); err == nil {
+		t.Error("FromCanonical(custom) error = nil, want error")
+	}
+}
+
+func TestRegistry_Convert_MCPToGemini(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewGeminiAdapter())
+
+	tool := mcp.Tool{
+		Name: "search",
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{"q": map[string]any{"type": "string"}},
+			"additionalProperties": false,
+		},
+		OutputSchema: map[string]any{"type": "object", "additionalProperties": true},
+	}
+
+	result, err := registry.Convert(tool, "mcp", "gemini")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	decl := result.Tool.(GeminiFunctionDeclaration)
+	if decl.Parameters.Type != "OBJECT" || decl.Response == nil {
+		t.Errorf("Convert() = %+v, want OBJECT parameters and a response", decl)
+	}
+
+	var features []string
+	for _, w := range result.Warnings {
+		features = append(features, w.Feature.String())
+	}
+	if want := []string{"additionalProperties", "additionalProperties"}; !reflect.DeepEqual(features, want) {
+		t.Errorf("warnings = %v, want %v", features, want)
+	}
+	wantIssues := []tooladapter.SchemaIssue{
+		{Path: "/inputSchema", Keyword: "additionalProperties", Message: "removed; not supported"},
+		{Path: "/outputSchema", Keyword: "additionalProperties", Message: "removed; not supported"},
+	}
+	if !reflect.DeepEqual(result.Issues, wantIssues) {
+		t.Errorf("issues = %v, want %v", result.Issues, wantIssues)
+	}
+
+	// The JSON Schema mode keeps additionalProperties
+	result, err = registry.Convert(tool, "mcp", "gemini:jsonschema")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(result.Warnings) != 0 || len(result.Issues) != 0 {
+		t.Errorf("jsonschema warnings = %v, issues = %v, want none", result.Warnings, result.Issues)
+	}
+}
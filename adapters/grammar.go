@@ -0,0 +1,731 @@
+package adapters
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// CompileGBNF compiles a canonical schema into a GBNF grammar for
+// llama.cpp. The root rule matches the JSON documents the schema accepts,
+// so sampling with the grammar yields valid tool arguments.
+//
+// Object properties are written in name order, required ones first, and
+// optional ones may be left out. Other keys are only allowed when
+// additionalProperties is true, or when an object declares no properties.
+// enum and const match the JSON encoding of their values. Arrays honor
+// minItems and maxItems. Strings honor minLength and maxLength, or a
+// pattern when it can be translated, and the date, time, date-time and
+// uuid formats. References into the root $defs, and to the root itself,
+// become rule references, so recursive schemas are supported. Whitespace
+// between tokens is limited to a space or a line break and indentation.
+//
+// Keywords the grammar can only approximate are reported as issues: oneOf
+// exclusivity, not, numeric bounds other than a minimum of 0, other
+// formats, patterns using constructs such as word boundaries, and
+// conflicting allOf branches.
+//
+// The input schema is not modified. Returns "" if schema is nil.
+func CompileGBNF(schema *tooladapter.JSONSchema) (string, []tooladapter.SchemaIssue) {
+	g, issues := compileGrammar(schema)
+	if g == nil {
+		return "", issues
+	}
+	return g.gbnf(), issues
+}
+
+// CompileLark compiles a canonical schema into a Lark grammar, as used by
+// OpenAI custom tools and vLLM guided decoding. The grammar accepts the
+// same documents as CompileGBNF, with "start" as the root rule and each
+// JSON string or number written as one regular expression terminal.
+//
+// The input schema is not modified. Returns "" if schema is nil.
+func CompileLark(schema *tooladapter.JSONSchema) (string, []tooladapter.SchemaIssue) {
+	g, issues := compileGrammar(schema)
+	if g == nil {
+		return "", issues
+	}
+	return g.lark(), issues
+}
+
+// grammarPrimitives are the shared rules for JSON values, built on first
+// use. Their names are reserved.
+var grammarPrimitives = []string{
+	"space", "char", "string", "number", "integer", "boolean", "null",
+	"value", "object", "array", "date", "time", "date-time", "uuid",
+}
+
+// Regular expressions for the number and format primitives. Integers are
+// limited to 16 digits, as in llama.cpp.
+const (
+	grammarIntegerPattern  = `^-?(?:0|[1-9][0-9]{0,15})$`
+	grammarUnsignedPattern = `^(?:0|[1-9][0-9]{0,15})$`
+	grammarFractionPattern = `^(?:\.[0-9]+)?(?:[eE][-+]?[0-9]+)?$`
+	grammarDatePattern     = `^[0-9]{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12][0-9]|3[01])$`
+	grammarTimePattern     = `^(?:[01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9](?:\.[0-9]+)?(?:Z|[-+](?:[01][0-9]|2[0-3]):[0-5][0-9])$`
+	grammarUUIDPattern     = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+)
+
+func compileGrammar(schema *tooladapter.JSONSchema) (*grammar, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+	c := &grammarCompiler{
+		root: schema,
+		g:    &grammar{rules: make(map[string]grammarExpr)},
+		refs: make(map[string]string),
+		used: map[string]bool{"start": true},
+	}
+	for _, name := range grammarPrimitives {
+		c.used[name] = true
+	}
+
+	root := c.newRule("root")
+	c.refs["#"] = root
+	c.g.rules[root] = c.body(schema, "", root)
+	return c.g, c.issues
+}
+
+// grammarCompiler collects rules and issues through a single compilation.
+type grammarCompiler struct {
+	root   *tooladapter.JSONSchema
+	g      *grammar
+	issues []tooladapter.SchemaIssue
+
+	// refs maps the $ref values compiled so far to their rules
+	refs map[string]string
+
+	// used holds the rule names taken, including reserved ones
+	used map[string]bool
+
+	// merging holds the $ref values being merged into an allOf, to stop
+	// recursion
+	merging []string
+}
+
+func (c *grammarCompiler) issue(path, keyword, message string) {
+	c.issues = append(c.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// newRule allocates a rule name derived from base. Names use lowercase
+// letters, digits and dashes, which both notations accept.
+func (c *grammarCompiler) newRule(base string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "r-" + name
+	}
+
+	unique := name
+	for i := 2; c.used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	c.used[unique] = true
+	c.g.names = append(c.g.names, unique)
+	return unique
+}
+
+// compile returns an expression for the values a schema accepts, followed
+// by whitespace. Schemas that need more than a shared rule get their own
+// rule, named after name.
+func (c *grammarCompiler) compile(s *tooladapter.JSONSchema, path, name string) grammarExpr {
+	rule := c.newRule(name)
+	body := c.body(s, path, rule)
+	if ref, ok := body.(grammarRef); ok {
+		// Use the shared rule instead
+		c.g.names = slices.DeleteFunc(c.g.names, func(n string) bool { return n == rule })
+		delete(c.used, rule)
+		return ref
+	}
+	c.g.rules[rule] = body
+	return grammarRef(rule)
+}
+
+// body returns the expression for a schema; name is the rule it belongs to.
+func (c *grammarCompiler) body(s *tooladapter.JSONSchema, path, name string) grammarExpr {
+	if s == nil {
+		return c.primitive("value")
+	}
+	c.extra(s, path)
+	if s.Ref != "" {
+		return c.ref(s.Ref, path)
+	}
+	if len(s.AllOf) > 0 {
+		return c.body(c.mergeAllOf(s, path), path, name)
+	}
+	if s.Not != nil {
+		c.issue(path, "not", "not enforced")
+	}
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		return c.alternatives(s, path, name)
+	}
+	if s.Const != nil {
+		return c.literals([]any{s.Const}, path, "const")
+	}
+	if len(s.Enum) > 0 {
+		return c.literals(s.Enum, path, "enum")
+	}
+
+	switch grammarType(s) {
+	case "object":
+		return c.object(s, path, name)
+	case "array":
+		return c.array(s, path, name)
+	case "string":
+		return c.string(s, path)
+	case "integer":
+		return c.number(s, path, true)
+	case "number":
+		return c.number(s, path, false)
+	case "boolean", "null":
+		return c.primitive(s.Type)
+	case "":
+		return c.primitive("value")
+	}
+	c.issue(path, "type", fmt.Sprintf("unknown type %q; any value is accepted", s.Type))
+	return c.primitive("value")
+}
+
+// grammarType returns the type of a schema, inferred from its keywords if
+// it has none.
+func grammarType(s *tooladapter.JSONSchema) string {
+	switch {
+	case s.Type != "":
+		return s.Type
+	case len(s.Properties) > 0 || len(s.Required) > 0 || s.AdditionalProperties != nil:
+		return "object"
+	case s.Items != nil || s.MinItems != nil || s.MaxItems != nil:
+		return "array"
+	case s.Pattern != "" || s.Format != "" || s.MinLength != nil || s.MaxLength != nil:
+		return "string"
+	case s.Minimum != nil || s.Maximum != nil:
+		return "number"
+	}
+	return ""
+}
+
+// ref returns the rule for a local reference, compiling its target on
+// first use.
+func (c *grammarCompiler) ref(ref, path string) grammarExpr {
+	if rule, ok := c.refs[ref]; ok {
+		return grammarRef(rule)
+	}
+	name, def := c.resolveRef(ref)
+	if def == nil {
+		c.issue(path, "$ref", fmt.Sprintf("cannot resolve %q; any value is accepted", ref))
+		return c.primitive("value")
+	}
+	rule := c.newRule(name)
+	c.refs[ref] = rule
+	c.g.rules[rule] = c.body(def, "/$defs/"+escapePointer(name), rule)
+	return grammarRef(rule)
+}
+
+// resolveRef returns the root $defs entry a local reference points to.
+func (c *grammarCompiler) resolveRef(ref string) (string, *tooladapter.JSONSchema) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return "", nil
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	return name, c.root.Defs[name]
+}
+
+// alternatives compiles anyOf and oneOf branches, each merged with the
+// keywords of the schema around them.
+func (c *grammarCompiler) alternatives(s *tooladapter.JSONSchema, path, name string) grammarExpr {
+	if len(s.OneOf) > 0 {
+		c.issue(path, "oneOf", "approximated as anyOf; exclusivity is not enforced")
+	}
+	base := *s
+	base.AnyOf, base.OneOf, base.Extra = nil, nil, nil
+	bare := isBareSchema(&base)
+
+	var alt grammarAlt
+	add := func(keyword string, branches []*tooladapter.JSONSchema) {
+		for i, branch := range branches {
+			branchPath := fmt.Sprintf("%s/%s/%d", path, keyword, i)
+			if !bare {
+				resolved := c.resolveBranch(branch, branchPath)
+				c.extra(resolved, branchPath)
+				merged := base
+				mergeGrammarSchema(&merged, resolved)
+				branch = &merged
+			}
+			alt = append(alt, c.compile(branch, branchPath, fmt.Sprintf("%s-%d", name, len(alt)+1)))
+		}
+	}
+	add("anyOf", s.AnyOf)
+	add("oneOf", s.OneOf)
+	return alt
+}
+
+// mergeAllOf merges the allOf branches of a schema into it. Where branches
+// set a keyword to different values, the last one wins; bounds are
+// intersected and required lists joined.
+func (c *grammarCompiler) mergeAllOf(s *tooladapter.JSONSchema, path string) *tooladapter.JSONSchema {
+	merged := *s
+	merged.AllOf, merged.Extra = nil, nil
+	conflict := false
+	for i, branch := range s.AllOf {
+		branchPath := fmt.Sprintf("%s/allOf/%d", path, i)
+		pushed := branch != nil && branch.Ref != ""
+		if pushed {
+			if slices.Contains(c.merging, branch.Ref) {
+				c.issue(branchPath, "$ref", "not enforced; recursive references cannot be merged")
+				continue
+			}
+			c.merging = append(c.merging, branch.Ref)
+		}
+		resolved := c.resolveBranch(branch, branchPath)
+		c.extra(resolved, branchPath)
+		if resolved != nil && len(resolved.AllOf) > 0 {
+			resolved = c.mergeAllOf(resolved, branchPath)
+		}
+		if mergeGrammarSchema(&merged, resolved) {
+			conflict = true
+		}
+		if pushed {
+			c.merging = c.merging[:len(c.merging)-1]
+		}
+	}
+	if conflict {
+		c.issue(path, "allOf", "approximated; where branches conflict, the last one wins")
+	}
+	return &merged
+}
+
+// grammarAnnotations are the Extra keywords that describe values without
+// constraining them.
+var grammarAnnotations = map[string]bool{
+	"$comment": true, "$id": true, "$schema": true, "deprecated": true,
+	"examples": true, "readOnly": true, "title": true, "writeOnly": true,
+}
+
+// extra reports the keywords kept in a schema's Extra map that constrain
+// values, none of which the grammar enforces.
+func (c *grammarCompiler) extra(s *tooladapter.JSONSchema, path string) {
+	if s == nil {
+		return
+	}
+	for _, k := range extraKeywords(s) {
+		if !grammarAnnotations[k] {
+			c.issue(path, k, "not enforced")
+		}
+	}
+}
+
+// resolveBranch follows a reference in a branch that is merged with other
+// schemas, reporting references that cannot be followed.
+func (c *grammarCompiler) resolveBranch(s *tooladapter.JSONSchema, path string) *tooladapter.JSONSchema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	if s.Ref == "#" {
+		return c.root
+	}
+	_, def := c.resolveRef(s.Ref)
+	if def == nil {
+		c.issue(path, "$ref", fmt.Sprintf("cannot resolve %q; not enforced", s.Ref))
+	}
+	return def
+}
+
+// isBareSchema reports whether a schema only carries annotations, so that
+// its combinator branches can be compiled as they are.
+func isBareSchema(s *tooladapter.JSONSchema) bool {
+	bare := *s
+	bare.Description, bare.Default, bare.Defs = "", nil, nil
+	return reflect.DeepEqual(bare, tooladapter.JSONSchema{})
+}
+
+// mergeGrammarSchema merges src into dst, which must be a copy whose maps
+// and slices are not modified. It reports whether a keyword had two
+// different values.
+func mergeGrammarSchema(dst, src *tooladapter.JSONSchema) bool {
+	if src == nil {
+		return false
+	}
+	conflict := false
+	take := func(set bool, differ bool, apply func()) {
+		if set {
+			conflict = conflict || differ
+			apply()
+		}
+	}
+
+	if src.Type != "" && dst.Type != src.Type {
+		switch {
+		case dst.Type == "number" && src.Type == "integer":
+			dst.Type = "integer"
+		case dst.Type == "integer" && src.Type == "number":
+		default:
+			conflict = conflict || dst.Type != ""
+			dst.Type = src.Type
+		}
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	take(src.Pattern != "", dst.Pattern != "" && dst.Pattern != src.Pattern, func() { dst.Pattern = src.Pattern })
+	take(src.Format != "", dst.Format != "" && dst.Format != src.Format, func() { dst.Format = src.Format })
+	take(src.Const != nil, dst.Const != nil && !reflect.DeepEqual(dst.Const, src.Const), func() { dst.Const = src.Const })
+	take(len(src.Enum) > 0, len(dst.Enum) > 0 && !reflect.DeepEqual(dst.Enum, src.Enum), func() { dst.Enum = src.Enum })
+	take(src.Items != nil, dst.Items != nil && !reflect.DeepEqual(dst.Items, src.Items), func() { dst.Items = src.Items })
+	take(len(src.AnyOf) > 0, len(dst.AnyOf) > 0, func() { dst.AnyOf = src.AnyOf })
+	take(len(src.OneOf) > 0, len(dst.OneOf) > 0, func() { dst.OneOf = src.OneOf })
+	take(src.Not != nil, dst.Not != nil, func() { dst.Not = src.Not })
+
+	if len(src.Properties) > 0 {
+		props := maps.Clone(dst.Properties)
+		if props == nil {
+			props = make(map[string]*tooladapter.JSONSchema, len(src.Properties))
+		}
+		for name, prop := range src.Properties {
+			if existing, ok := props[name]; ok && !reflect.DeepEqual(existing, prop) {
+				conflict = true
+			}
+			props[name] = prop
+		}
+		dst.Properties = props
+	}
+	if len(src.Required) > 0 {
+		required := slices.Clone(dst.Required)
+		for _, name := range src.Required {
+			if !slices.Contains(required, name) {
+				required = append(required, name)
+			}
+		}
+		dst.Required = required
+	}
+	if src.AdditionalProperties != nil && (dst.AdditionalProperties == nil || !*src.AdditionalProperties) {
+		dst.AdditionalProperties = src.AdditionalProperties
+	}
+
+	dst.Minimum = tighterBound(dst.Minimum, src.Minimum, false)
+	dst.Maximum = tighterBound(dst.Maximum, src.Maximum, true)
+	dst.MinLength = tighterBound(dst.MinLength, src.MinLength, false)
+	dst.MaxLength = tighterBound(dst.MaxLength, src.MaxLength, true)
+	dst.MinItems = tighterBound(dst.MinItems, src.MinItems, false)
+	dst.MaxItems = tighterBound(dst.MaxItems, src.MaxItems, true)
+	return conflict
+}
+
+// tighterBound returns the stricter of two optional bounds: the larger
+// lower bound, or the smaller upper bound.
+func tighterBound[T cmp.Ordered](a, b *T, upper bool) *T {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		v := *b
+		return &v
+	case b == nil:
+		v := *a
+		return &v
+	}
+	v := max(*a, *b)
+	if upper {
+		v = min(*a, *b)
+	}
+	return &v
+}
+
+// literals matches the JSON encoding of each value.
+func (c *grammarCompiler) literals(values []any, path, keyword string) grammarExpr {
+	var alt grammarAlt
+	for _, v := range values {
+		text, err := promptJSON(v, "")
+		if err != nil {
+			c.issue(path, keyword, fmt.Sprintf("value %v dropped: %v", v, err))
+			continue
+		}
+		alt = append(alt, grammarLiteral(text))
+	}
+	if len(alt) == 1 {
+		return grammarSeq{alt[0], c.primitive("space")}
+	}
+	return grammarSeq{alt, c.primitive("space")}
+}
+
+// object compiles an object schema. Required properties come first, then
+// optional ones, each in name order.
+func (c *grammarCompiler) object(s *tooladapter.JSONSchema, path, name string) grammarExpr {
+	space := c.primitive("space")
+	comma := func(e grammarExpr) grammarExpr {
+		return grammarSeq{grammarLiteral(","), space, e}
+	}
+
+	var required, optional []string
+	for _, prop := range s.Required {
+		if !slices.Contains(required, prop) {
+			required = append(required, prop)
+		}
+	}
+	for prop := range s.Properties {
+		if !slices.Contains(required, prop) {
+			optional = append(optional, prop)
+		}
+	}
+	sort.Strings(required)
+	sort.Strings(optional)
+
+	kv := func(prop string) grammarExpr {
+		rule := c.newRule(name + "-" + prop + "-kv")
+		key, _ := promptJSON(prop, "")
+		value := c.compile(s.Properties[prop], path+"/properties/"+escapePointer(prop), name+"-"+prop)
+		c.g.rules[rule] = grammarSeq{grammarLiteral(key), space, grammarLiteral(":"), space, value}
+		return grammarRef(rule)
+	}
+	requiredKVs := make([]grammarExpr, len(required))
+	for i, prop := range required {
+		requiredKVs[i] = kv(prop)
+	}
+	optionalKVs := make([]grammarExpr, len(optional))
+	for i, prop := range optional {
+		optionalKVs[i] = kv(prop)
+	}
+
+	var extra grammarExpr
+	if additional := s.AdditionalProperties; additional != nil && *additional ||
+		additional == nil && len(s.Properties) == 0 && len(s.Required) == 0 {
+		extra = grammarSeq{c.primitive("string"), grammarLiteral(":"), space, c.primitive("value")}
+	}
+	extras := func(items grammarSeq) grammarSeq {
+		if extra != nil {
+			items = append(items, grammarRepeat{expr: comma(extra), min: 0, max: -1})
+		}
+		return items
+	}
+
+	out := grammarSeq{grammarLiteral("{"), space}
+	if len(requiredKVs) > 0 {
+		for i, kv := range requiredKVs {
+			if i > 0 {
+				out = append(out, grammarLiteral(","), space)
+			}
+			out = append(out, kv)
+		}
+		for _, kv := range optionalKVs {
+			out = append(out, grammarRepeat{expr: comma(kv), min: 0, max: 1})
+		}
+		out = extras(out)
+	} else if len(optionalKVs) > 0 || extra != nil {
+		// The first property present has no comma before it
+		var first grammarAlt
+		for i, kv := range optionalKVs {
+			items := grammarSeq{kv}
+			for _, later := range optionalKVs[i+1:] {
+				items = append(items, grammarRepeat{expr: comma(later), min: 0, max: 1})
+			}
+			first = append(first, extras(items))
+		}
+		if extra != nil {
+			first = append(first, extras(grammarSeq{extra}))
+		}
+		out = append(out, grammarRepeat{expr: first, min: 0, max: 1})
+	}
+	return append(out, grammarLiteral("}"), space)
+}
+
+// array compiles an array schema with its item count bounds.
+func (c *grammarCompiler) array(s *tooladapter.JSONSchema, path, name string) grammarExpr {
+	space := c.primitive("space")
+	minItems, maxItems := 0, -1
+	if s.MinItems != nil {
+		minItems = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		maxItems = *s.MaxItems
+		if minItems > maxItems {
+			c.issue(path, "minItems", fmt.Sprintf("lowered to maxItems (%d); no array can match", maxItems))
+			minItems = maxItems
+		}
+	}
+
+	out := grammarSeq{grammarLiteral("["), space}
+	if maxItems != 0 {
+		item := c.compile(s.Items, path+"/items", name+"-item")
+		list := grammarSeq{item}
+		if maxItems != 1 {
+			restMax := -1
+			if maxItems > 0 {
+				restMax = maxItems - 1
+			}
+			list = append(list, grammarRepeat{
+				expr: grammarSeq{grammarLiteral(","), space, item},
+				min:  max(minItems-1, 0),
+				max:  restMax,
+			})
+		}
+		if minItems == 0 {
+			out = append(out, grammarRepeat{expr: list, min: 0, max: 1})
+		} else {
+			out = append(out, list)
+		}
+	}
+	return append(out, grammarLiteral("]"), space)
+}
+
+// string compiles a string schema: a pattern, if it can be translated, or
+// else a supported format, or else length bounds.
+func (c *grammarCompiler) string(s *tooladapter.JSONSchema, path string) grammarExpr {
+	var content grammarExpr
+	if s.Pattern != "" {
+		expr, err := patternExpr(s.Pattern, c.primitive("char"))
+		if err != nil {
+			c.issue(path, "pattern", fmt.Sprintf("not enforced: %v", err))
+		} else {
+			content = expr
+		}
+	}
+	if s.Format != "" {
+		switch {
+		case content != nil:
+			c.issue(path, "format", "not enforced; the pattern is used instead")
+		case slices.Contains([]string{"date", "time", "date-time", "uuid"}, s.Format):
+			content = c.primitive(s.Format)
+		default:
+			c.issue(path, "format", fmt.Sprintf("%q is not enforced", s.Format))
+		}
+	}
+	if s.MinLength != nil || s.MaxLength != nil {
+		if content != nil {
+			if s.MinLength != nil {
+				c.issue(path, "minLength", "not enforced together with a pattern or format")
+			}
+			if s.MaxLength != nil {
+				c.issue(path, "maxLength", "not enforced together with a pattern or format")
+			}
+		} else {
+			rep := grammarRepeat{expr: c.primitive("char"), min: 0, max: -1}
+			if s.MinLength != nil {
+				rep.min = *s.MinLength
+			}
+			if s.MaxLength != nil {
+				rep.max = *s.MaxLength
+			}
+			content = rep
+		}
+	}
+	if content == nil {
+		return c.primitive("string")
+	}
+	quote := grammarLiteral(`"`)
+	return grammarSeq{grammarToken{grammarSeq{quote, content, quote}}, c.primitive("space")}
+}
+
+// number compiles a number or integer schema. Only a non-negative minimum
+// can be expressed, as a number without a sign.
+func (c *grammarCompiler) number(s *tooladapter.JSONSchema, path string, integer bool) grammarExpr {
+	signed := true
+	if s.Minimum != nil {
+		signed = *s.Minimum < 0
+		switch {
+		case *s.Minimum > 0:
+			c.issue(path, "minimum", "approximated as non-negative")
+		case *s.Minimum < 0:
+			c.issue(path, "minimum", "not enforced")
+		}
+	}
+	if s.Maximum != nil {
+		c.issue(path, "maximum", "not enforced")
+	}
+
+	switch {
+	case signed && integer:
+		return c.primitive("integer")
+	case signed:
+		return c.primitive("number")
+	}
+	expr := grammarSeq{mustPattern(grammarUnsignedPattern)}
+	if !integer {
+		expr = append(expr, mustPattern(grammarFractionPattern))
+	}
+	return grammarSeq{grammarToken{expr}, c.primitive("space")}
+}
+
+// primitive returns the shared rule for a JSON value or format, defining
+// it on first use.
+func (c *grammarCompiler) primitive(name string) grammarRef {
+	if _, ok := c.g.rules[name]; ok {
+		return grammarRef(name)
+	}
+	// Define the name first, since value refers back to itself
+	c.g.names = append(c.g.names, name)
+	c.g.rules[name] = nil
+
+	var body grammarExpr
+	switch name {
+	case "space":
+		indent := grammarRepeat{expr: grammarClass{'\t', '\t', ' ', ' '}, min: 0, max: 20}
+		body = grammarRepeat{
+			expr: grammarToken{grammarAlt{grammarLiteral(" "), grammarSeq{grammarLiteral("\n"), indent}}},
+			min:  0,
+			max:  1,
+		}
+	case "char":
+		hex := grammarClass{'0', '9', 'A', 'F', 'a', 'f'}
+		escapes := grammarClass{'"', '"', '/', '/', '\\', '\\', 'b', 'b', 'f', 'f', 'n', 'n', 'r', 'r', 't', 't'}
+		body = grammarAlt{
+			grammarClass(jsonCharRanges),
+			grammarSeq{grammarLiteral(`\`), grammarAlt{escapes, grammarSeq{grammarLiteral("u"), grammarRepeat{expr: hex, min: 4, max: 4}}}},
+		}
+	case "string":
+		quote := grammarLiteral(`"`)
+		chars := grammarRepeat{expr: c.primitive("char"), min: 0, max: -1}
+		body = grammarSeq{grammarToken{grammarSeq{quote, chars, quote}}, c.primitive("space")}
+	case "integer":
+		body = grammarSeq{grammarToken{mustPattern(grammarIntegerPattern)}, c.primitive("space")}
+	case "number":
+		number := grammarSeq{mustPattern(grammarIntegerPattern), mustPattern(grammarFractionPattern)}
+		body = grammarSeq{grammarToken{number}, c.primitive("space")}
+	case "boolean":
+		body = grammarSeq{grammarAlt{grammarLiteral("true"), grammarLiteral("false")}, c.primitive("space")}
+	case "null":
+		body = grammarSeq{grammarLiteral("null"), c.primitive("space")}
+	case "value":
+		body = grammarAlt{
+			c.primitive("object"), c.primitive("array"), c.primitive("string"),
+			c.primitive("number"), c.primitive("boolean"), c.primitive("null"),
+		}
+	case "object":
+		body = c.object(&tooladapter.JSONSchema{Type: "object"}, "", name)
+	case "array":
+		body = c.array(&tooladapter.JSONSchema{Type: "array"}, "", name)
+	case "date":
+		body = mustPattern(grammarDatePattern)
+	case "time":
+		body = mustPattern(grammarTimePattern)
+	case "date-time":
+		body = grammarSeq{c.primitive("date"), grammarLiteral("T"), c.primitive("time")}
+	case "uuid":
+		body = mustPattern(grammarUUIDPattern)
+	}
+	c.g.rules[name] = body
+	return grammarRef(name)
+}
+
+// mustPattern translates one of the anchored patterns above, which do not
+// use ".".
+func mustPattern(pattern string) grammarExpr {
+	expr, err := patternExpr(pattern, nil)
+	if err != nil {
+		panic(fmt.Sprintf("grammar pattern %q: %v", pattern, err))
+	}
+	return expr
+}
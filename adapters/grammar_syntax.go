@@ -0,0 +1,582 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// grammarExpr is a node of the grammar the schema compiler builds before
+// it is written out as GBNF or Lark. It is one of the types below.
+type grammarExpr any
+
+type (
+	// grammarLiteral matches its text exactly.
+	grammarLiteral string
+
+	// grammarClass matches one character in the ranges, given as lo, hi
+	// pairs as in regexp/syntax.
+	grammarClass []rune
+
+	// grammarRef matches the rule with this name.
+	grammarRef string
+
+	// grammarSeq matches its items one after another; empty, it matches
+	// the empty string.
+	grammarSeq []grammarExpr
+
+	// grammarAlt matches any one of its items.
+	grammarAlt []grammarExpr
+
+	// grammarRepeat matches expr between min and max times; max < 0 means
+	// no upper bound.
+	grammarRepeat struct {
+		expr     grammarExpr
+		min, max int
+	}
+
+	// grammarToken marks an expression that forms a single terminal, such
+	// as a JSON string or number. Lark writes it as one regular expression,
+	// inlining the rules it references.
+	grammarToken struct {
+		expr grammarExpr
+	}
+)
+
+// grammar is a set of named rules. The first rule is the root.
+type grammar struct {
+	names []string
+	rules map[string]grammarExpr
+}
+
+// Precedence levels for writing expressions: an expression written at a
+// level binds at least that tightly, or is parenthesized.
+const (
+	precAlt  = iota // a | b
+	precSeq         // a b
+	precAtom        // a*
+)
+
+// gbnf writes the grammar in llama.cpp GBNF notation.
+func (g *grammar) gbnf() string {
+	var b strings.Builder
+	for _, name := range g.names {
+		fmt.Fprintf(&b, "%s ::= %s\n", name, g.gbnfExpr(g.rules[name], precAlt))
+	}
+	return b.String()
+}
+
+func (g *grammar) gbnfExpr(e grammarExpr, prec int) string {
+	switch e := e.(type) {
+	case grammarLiteral:
+		return quoteLiteral(string(e))
+	case grammarClass:
+		return classString(e, true)
+	case grammarRef:
+		return string(e)
+	case grammarToken:
+		return g.gbnfExpr(e.expr, prec)
+	case grammarSeq:
+		if len(e) == 0 {
+			return `""`
+		}
+		if len(e) == 1 {
+			return g.gbnfExpr(e[0], prec)
+		}
+		parts := make([]string, len(e))
+		for i, item := range e {
+			parts[i] = g.gbnfExpr(item, precSeq)
+		}
+		return group(strings.Join(parts, " "), prec > precSeq)
+	case grammarAlt:
+		if len(e) == 1 {
+			return g.gbnfExpr(e[0], prec)
+		}
+		parts := make([]string, len(e))
+		for i, item := range e {
+			parts[i] = g.gbnfExpr(item, precAlt)
+		}
+		return group(strings.Join(parts, " | "), prec > precAlt)
+	case grammarRepeat:
+		return g.gbnfExpr(e.expr, precAtom) + repeatSuffix(e.min, e.max)
+	}
+	panic(fmt.Sprintf("unknown grammar node %T", e))
+}
+
+// lark writes the grammar in Lark notation, with "start" as the root rule.
+// Rules referenced only from inside tokens are inlined there and not
+// written.
+func (g *grammar) lark() string {
+	reachable := make(map[string]bool)
+	var visit func(e grammarExpr)
+	visit = func(e grammarExpr) {
+		switch e := e.(type) {
+		case grammarRef:
+			if !reachable[string(e)] {
+				reachable[string(e)] = true
+				visit(g.rules[string(e)])
+			}
+		case grammarSeq:
+			for _, item := range e {
+				visit(item)
+			}
+		case grammarAlt:
+			for _, item := range e {
+				visit(item)
+			}
+		case grammarRepeat:
+			visit(e.expr)
+		}
+	}
+	if len(g.names) > 0 {
+		visit(grammarRef(g.names[0]))
+	}
+
+	var b strings.Builder
+	for _, name := range g.names {
+		if reachable[name] {
+			fmt.Fprintf(&b, "%s: %s\n", larkName(name, g.names[0]), g.larkExpr(g.rules[name], precAlt))
+		}
+	}
+	return b.String()
+}
+
+func (g *grammar) larkExpr(e grammarExpr, prec int) string {
+	switch e := e.(type) {
+	case grammarLiteral:
+		return quoteLiteral(string(e))
+	case grammarClass:
+		return "/" + classString(e, false) + "/"
+	case grammarRef:
+		return larkName(string(e), g.names[0])
+	case grammarToken:
+		return "/" + g.regex(e.expr, precAlt) + "/"
+	case grammarSeq:
+		if len(e) == 1 {
+			return g.larkExpr(e[0], prec)
+		}
+		parts := make([]string, len(e))
+		for i, item := range e {
+			parts[i] = g.larkExpr(item, precSeq)
+		}
+		return group(strings.Join(parts, " "), prec > precSeq)
+	case grammarAlt:
+		if len(e) == 1 {
+			return g.larkExpr(e[0], prec)
+		}
+		parts := make([]string, len(e))
+		for i, item := range e {
+			parts[i] = g.larkExpr(item, precAlt)
+		}
+		return group(strings.Join(parts, " | "), prec > precAlt)
+	case grammarRepeat:
+		inner := g.larkExpr(e.expr, precAtom)
+		switch {
+		case e.min == 0 && (e.max == 1 || e.max < 0), e.min == 1 && e.max < 0:
+			return inner + repeatSuffix(e.min, e.max)
+		case e.max < 0:
+			// Lark has no open-ended range: x{m,} is x ~ m followed by x*
+			return group(fmt.Sprintf("%s ~ %d %s*", inner, e.min, inner), prec > precSeq)
+		case e.min == e.max:
+			return group(fmt.Sprintf("%s ~ %d", inner, e.min), prec > precSeq)
+		default:
+			return group(fmt.Sprintf("%s ~ %d..%d", inner, e.min, e.max), prec > precSeq)
+		}
+	}
+	panic(fmt.Sprintf("unknown grammar node %T", e))
+}
+
+// regex writes an expression as a regular expression in the syntax Python
+// and Go share, inlining referenced rules. It is used for Lark tokens,
+// so the rules it inlines must not be recursive.
+func (g *grammar) regex(e grammarExpr, prec int) string {
+	switch e := e.(type) {
+	case grammarLiteral:
+		var b strings.Builder
+		for _, r := range string(e) {
+			b.WriteString(regexRune(r))
+		}
+		return regexGroup(b.String(), prec == precAtom && len([]rune(string(e))) != 1)
+	case grammarClass:
+		return classString(e, false)
+	case grammarRef:
+		return g.regex(g.rules[string(e)], prec)
+	case grammarToken:
+		return g.regex(e.expr, prec)
+	case grammarSeq:
+		if len(e) == 1 {
+			return g.regex(e[0], prec)
+		}
+		var b strings.Builder
+		for _, item := range e {
+			b.WriteString(g.regex(item, precSeq))
+		}
+		return regexGroup(b.String(), prec > precSeq)
+	case grammarAlt:
+		if len(e) == 1 {
+			return g.regex(e[0], prec)
+		}
+		parts := make([]string, len(e))
+		for i, item := range e {
+			parts[i] = g.regex(item, precAlt)
+		}
+		return regexGroup(strings.Join(parts, "|"), prec > precAlt)
+	case grammarRepeat:
+		return g.regex(e.expr, precAtom) + repeatSuffix(e.min, e.max)
+	}
+	panic(fmt.Sprintf("unknown grammar node %T", e))
+}
+
+// group parenthesizes a grammar expression if needed.
+func group(s string, needed bool) string {
+	if needed {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// regexGroup parenthesizes a regular expression if needed.
+func regexGroup(s string, needed bool) string {
+	if needed {
+		return "(?:" + s + ")"
+	}
+	return s
+}
+
+// repeatSuffix writes a repetition in the notation GBNF and regular
+// expressions share.
+func repeatSuffix(min, max int) string {
+	switch {
+	case min == 0 && max == 1:
+		return "?"
+	case min == 0 && max < 0:
+		return "*"
+	case min == 1 && max < 0:
+		return "+"
+	case max < 0:
+		return fmt.Sprintf("{%d,}", min)
+	case min == max:
+		return fmt.Sprintf("{%d}", min)
+	default:
+		return fmt.Sprintf("{%d,%d}", min, max)
+	}
+}
+
+// larkName turns a rule name into a Lark rule name, which uses
+// underscores and calls the root rule "start".
+func larkName(name, root string) string {
+	if name == root {
+		return "start"
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// quoteLiteral writes a string literal with the escapes GBNF and Lark,
+// which reads literals as Python strings, both accept.
+func quoteLiteral(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteString(escapeRune(r))
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// classString writes a character class, as its complement if it runs to
+// the last code point. GBNF escapes '-' and '^' in hex, since it has no
+// escape for them.
+func classString(ranges []rune, gbnf bool) string {
+	negated := len(ranges) > 0 && ranges[len(ranges)-1] == unicode.MaxRune
+	if negated {
+		ranges = complementRanges(ranges)
+	}
+	var b strings.Builder
+	b.WriteByte('[')
+	if negated {
+		b.WriteByte('^')
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		b.WriteString(classRune(lo, gbnf))
+		if hi > lo {
+			if hi > lo+1 {
+				b.WriteByte('-')
+			}
+			b.WriteString(classRune(hi, gbnf))
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func classRune(r rune, gbnf bool) string {
+	switch r {
+	case '\\', ']', '[':
+		return `\` + string(r)
+	case '-', '^':
+		if gbnf {
+			return fmt.Sprintf(`\x%02X`, r)
+		}
+		return `\` + string(r)
+	case '/':
+		if !gbnf {
+			return `\/`
+		}
+	}
+	return escapeRune(r)
+}
+
+// regexRune writes a rune that stands for itself in a regular expression.
+func regexRune(r rune) string {
+	if strings.ContainsRune(`\.+*?()|[]{}^$/`, r) {
+		return `\` + string(r)
+	}
+	return escapeRune(r)
+}
+
+// escapeRune writes control and unprintable characters as escapes, and
+// other characters as they are.
+func escapeRune(r rune) string {
+	switch {
+	case r < 0x20 || r == 0x7F:
+		return fmt.Sprintf(`\x%02X`, r)
+	case r < 0x80 || unicode.IsPrint(r):
+		return string(r)
+	case r <= 0xFFFF:
+		return fmt.Sprintf(`\u%04X`, r)
+	default:
+		return fmt.Sprintf(`\U%08X`, r)
+	}
+}
+
+// complementRanges returns the characters not in ranges.
+func complementRanges(ranges []rune) []rune {
+	var out []rune
+	next := rune(0)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] > next {
+			out = append(out, next, ranges[i]-1)
+		}
+		next = ranges[i+1] + 1
+	}
+	if next <= unicode.MaxRune {
+		out = append(out, next, unicode.MaxRune)
+	}
+	return out
+}
+
+// jsonCharRanges are the characters a JSON string may contain unescaped.
+var jsonCharRanges = []rune{0x20, 0x21, 0x23, 0x5B, 0x5D, 0x7E, 0x80, unicode.MaxRune}
+
+// errUnsupportedPattern is returned for patterns with constructs a
+// grammar cannot express, such as word boundaries.
+var errUnsupportedPattern = errors.New("unsupported construct")
+
+// patternExpr translates a regular expression into an expression over the
+// JSON-encoded text of the strings it matches, without the quotes. char
+// is the expression for any encoded character. A pattern not anchored at
+// either end is padded with char*, since patterns match anywhere in the
+// string.
+//
+// Characters are matched in their JSON encoding: '"' as \" and control
+// characters as escapes. Character classes match printable characters
+// only, and "." matches any encoded character.
+func patternExpr(pattern string, char grammarExpr) (grammarExpr, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	anyText := grammarRepeat{expr: char, min: 0, max: -1}
+	var out grammarSeq
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	} else {
+		out = append(out, anyText)
+	}
+	anchoredEnd := len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText
+	if anchoredEnd {
+		subs = subs[:len(subs)-1]
+	}
+	for _, sub := range subs {
+		e, err := regexpExpr(sub, char)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	if !anchoredEnd {
+		out = append(out, anyText)
+	}
+	if len(out) == 1 {
+		return out[0], nil
+	}
+	return out, nil
+}
+
+func regexpExpr(re *syntax.Regexp, char grammarExpr) (grammarExpr, error) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return grammarSeq{}, nil
+	case syntax.OpLiteral:
+		var out grammarSeq
+		var text strings.Builder
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && unicode.SimpleFold(r) != r {
+				if text.Len() > 0 {
+					out = append(out, grammarLiteral(text.String()))
+					text.Reset()
+				}
+				out = append(out, foldClass(r))
+				continue
+			}
+			text.WriteString(jsonEncodeRune(r))
+		}
+		if text.Len() > 0 {
+			out = append(out, grammarLiteral(text.String()))
+		}
+		if len(out) == 1 {
+			return out[0], nil
+		}
+		return out, nil
+	case syntax.OpCharClass:
+		return jsonClass(re.Rune)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return char, nil
+	case syntax.OpCapture:
+		return regexpExpr(re.Sub[0], char)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		inner, err := regexpExpr(re.Sub[0], char)
+		if err != nil {
+			return nil, err
+		}
+		rep := grammarRepeat{expr: inner, min: re.Min, max: re.Max}
+		switch re.Op {
+		case syntax.OpStar:
+			rep.min, rep.max = 0, -1
+		case syntax.OpPlus:
+			rep.min, rep.max = 1, -1
+		case syntax.OpQuest:
+			rep.min, rep.max = 0, 1
+		}
+		return rep, nil
+	case syntax.OpConcat, syntax.OpAlternate:
+		items := make([]grammarExpr, len(re.Sub))
+		for i, sub := range re.Sub {
+			e, err := regexpExpr(sub, char)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = e
+		}
+		if re.Op == syntax.OpConcat {
+			return grammarSeq(items), nil
+		}
+		return grammarAlt(items), nil
+	}
+	return nil, fmt.Errorf("%w %q", errUnsupportedPattern, re.String())
+}
+
+// foldClass matches a rune in any case. Runes with case need no escaping
+// in JSON.
+func foldClass(r rune) grammarExpr {
+	runes := []rune{r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		runes = append(runes, f)
+	}
+	slices.Sort(runes)
+	class := make(grammarClass, 0, 2*len(runes))
+	for _, f := range runes {
+		class = append(class, f, f)
+	}
+	return class
+}
+
+// jsonClass restricts a character class to what it matches in JSON text:
+// printable characters unescaped, and '"' and '\' escaped.
+func jsonClass(ranges []rune) (grammarExpr, error) {
+	var alt grammarAlt
+	if plain := intersectRanges(ranges, jsonCharRanges); len(plain) > 0 {
+		alt = append(alt, grammarClass(plain))
+	}
+	for _, r := range []rune{'"', '\\'} {
+		if inRanges(ranges, r) {
+			alt = append(alt, grammarLiteral(jsonEncodeRune(r)))
+		}
+	}
+	switch len(alt) {
+	case 0:
+		return nil, fmt.Errorf("%w: class of control characters", errUnsupportedPattern)
+	case 1:
+		return alt[0], nil
+	}
+	return alt, nil
+}
+
+// jsonEncodeRune returns the text of a rune inside a JSON string.
+func jsonEncodeRune(r rune) string {
+	switch r {
+	case '"':
+		return `\"`
+	case '\\':
+		return `\\`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	}
+	if r < 0x20 {
+		return `\u00` + strconv.FormatInt(int64(r)+0x100, 16)[1:]
+	}
+	return string(r)
+}
+
+func inRanges(ranges []rune, r rune) bool {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if r >= ranges[i] && r <= ranges[i+1] {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectRanges returns the characters in both sorted range lists.
+func intersectRanges(a, b []rune) []rune {
+	var out []rune
+	for i, j := 0, 0; i+1 < len(a) && j+1 < len(b); {
+		lo, hi := max(a[i], b[j]), min(a[i+1], b[j+1])
+		if lo <= hi {
+			out = append(out, lo, hi)
+		}
+		if a[i+1] < b[j+1] {
+			i += 2
+		} else {
+			j += 2
+		}
+	}
+	return out
+}
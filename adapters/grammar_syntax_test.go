@@ -0,0 +1,128 @@
+package adapters
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"unicode"
+)
+
+func TestPatternExpr(t *testing.T) {
+	tests := []struct {
+		pattern string
+		gbnf    string
+		regex   string
+	}{
+		{`^abc$`, `"abc"`, `abc`},
+		{`ab`, `char* "ab" char*`, ``},
+		{`^a"b\\$`, `"a\\\"b\\\\"`, `a\\"b\\\\`},
+		{`^[a-c"]+$`, `([a-c] | "\\\"")+`, `(?:[a-c]|\\")+`},
+		{`(?i)^id$`, `[Ii] [Dd]`, `[Ii][Dd]`},
+		{`^\d{2,4}(-x)?$`, `[0-9]{2,4} "-x"?`, `[0-9]{2,4}(?:-x)?`},
+		{`^[^0-9]$`, `[^\x00-\x1F"0-9\\\x7F] | "\\\"" | "\\\\"`, ``},
+		{`^a.b$`, `"a" char "b"`, ``},
+		{`^(red|green)$`, `"red" | "green"`, `red|green`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			e, err := patternExpr(tt.pattern, grammarRef("char"))
+			if err != nil {
+				t.Fatalf("patternExpr() error = %v", err)
+			}
+			g := &grammar{names: []string{"root"}, rules: map[string]grammarExpr{}}
+			if got := g.gbnfExpr(e, precAlt); got != tt.gbnf {
+				t.Errorf("gbnf = %s, want %s", got, tt.gbnf)
+			}
+			if tt.regex == "" {
+				return
+			}
+			if got := g.regex(e, precAlt); got != tt.regex {
+				t.Errorf("regex = %s, want %s", got, tt.regex)
+			}
+		})
+	}
+}
+
+func TestPatternExpr_Unsupported(t *testing.T) {
+	for _, pattern := range []string{`\bword`, `^a$|^b$`, `^[\x00-\x1F]$`} {
+		if _, err := patternExpr(pattern, grammarRef("char")); !errors.Is(err, errUnsupportedPattern) {
+			t.Errorf("patternExpr(%q) error = %v, want errUnsupportedPattern", pattern, err)
+		}
+	}
+	if _, err := patternExpr(`(`, grammarRef("char")); err == nil || errors.Is(err, errUnsupportedPattern) {
+		t.Errorf("patternExpr(%q) error = %v, want a syntax error", `(`, err)
+	}
+}
+
+func TestClassString(t *testing.T) {
+	tests := []struct {
+		ranges []rune
+		gbnf   bool
+		want   string
+	}{
+		{[]rune{'a', 'z', '0', '9'}, true, `[a-z0-9]`},
+		{[]rune{'+', '+', '-', '-'}, true, `[+\x2D]`},
+		{[]rune{'+', '+', '-', '-'}, false, `[+\-]`},
+		{[]rune{'/', '/', ']', ']'}, false, `[\/\]]`},
+		{[]rune{'a', 'b'}, false, `[ab]`},
+		{[]rune{0x20, '!', '#', unicode.MaxRune}, true, `[^\x00-\x1F"]`},
+		{[]rune{0x2028, 0x2028}, false, `[\u2028]`},
+	}
+	for _, tt := range tests {
+		if got := classString(tt.ranges, tt.gbnf); got != tt.want {
+			t.Errorf("classString(%q, %v) = %s, want %s", tt.ranges, tt.gbnf, got, tt.want)
+		}
+	}
+}
+
+func TestComplementRanges(t *testing.T) {
+	tests := []struct {
+		ranges []rune
+		want   []rune
+	}{
+		{nil, []rune{0, unicode.MaxRune}},
+		{[]rune{0, 'a'}, []rune{'b', unicode.MaxRune}},
+		{[]rune{'b', 'c', 'x', unicode.MaxRune}, []rune{0, 'a', 'd', 'w'}},
+	}
+	for _, tt := range tests {
+		if got := complementRanges(tt.ranges); !slices.Equal(got, tt.want) {
+			t.Errorf("complementRanges(%q) = %q, want %q", tt.ranges, got, tt.want)
+		}
+	}
+}
+
+func TestGrammarRepeat(t *testing.T) {
+	tests := []struct {
+		min, max int
+		gbnf     string
+		lark     string
+	}{
+		{0, 1, `x?`, `x?`},
+		{0, -1, `x*`, `x*`},
+		{1, -1, `x+`, `x+`},
+		{2, -1, `x{2,}`, `x ~ 2 x*`},
+		{3, 3, `x{3}`, `x ~ 3`},
+		{1, 4, `x{1,4}`, `x ~ 1..4`},
+	}
+	g := &grammar{names: []string{"root"}, rules: map[string]grammarExpr{}}
+	for _, tt := range tests {
+		e := grammarSeq{grammarRepeat{expr: grammarRef("x"), min: tt.min, max: tt.max}, grammarRef("y")}
+		if got := g.gbnfExpr(e, precAtom); got != "("+tt.gbnf+" y)" {
+			t.Errorf("gbnf {%d,%d} = %s, want (%s y)", tt.min, tt.max, got, tt.gbnf)
+		}
+		if got := g.larkExpr(e, precAlt); got != tt.lark+" y" {
+			t.Errorf("lark {%d,%d} = %s, want %s y", tt.min, tt.max, got, tt.lark)
+		}
+	}
+
+	nested := grammarRepeat{expr: grammarRepeat{expr: grammarRef("x"), min: 2, max: 3}, min: 0, max: 1}
+	if got, want := g.larkExpr(nested, precAlt), `(x ~ 2..3)?`; got != want {
+		t.Errorf("lark nested = %s, want %s", got, want)
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	if got, want := quoteLiteral("a\"b\\\n\x01é"), `"a\"b\\\n\x01é"`; got != want {
+		t.Errorf("quoteLiteral() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,348 @@
+package adapters
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// grammarRegexp compiles a grammar for a non-recursive schema into a
+// regular expression, with every rule inlined, to check what it accepts.
+func grammarRegexp(t *testing.T, schema *tooladapter.JSONSchema) *regexp.Regexp {
+	t.Helper()
+	g, _ := compileGrammar(schema)
+	return regexp.MustCompile(`^(?:` + g.regex(grammarRef(g.names[0]), precAlt) + `)$`)
+}
+
+func TestCompileGBNF(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"city": {Type: "string"},
+			"unit": {Enum: []any{"c", "f"}},
+		},
+		Required: []string{"city"},
+	}
+
+	got, issues := CompileGBNF(schema)
+	if len(issues) != 0 {
+		t.Errorf("issues = %v, want none", issues)
+	}
+	want := `root ::= "{" space root-city-kv ("," space root-unit-kv)? "}" space
+space ::= (" " | "\n" [\x09 ]{0,20})?
+root-city-kv ::= "\"city\"" space ":" space string
+string ::= "\"" char* "\"" space
+char ::= [^\x00-\x1F"\\\x7F] | "\\" (["/\\bfnrt] | "u" [0-9A-Fa-f]{4})
+root-unit-kv ::= "\"unit\"" space ":" space root-unit
+root-unit ::= ("\"c\"" | "\"f\"") space
+`
+	if got != want {
+		t.Errorf("CompileGBNF() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCompileLark(t *testing.T) {
+	one := 1
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"tags": {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}, MinItems: &one},
+		},
+		Required: []string{"tags"},
+	}
+
+	got, _ := CompileLark(schema)
+	want := `start: "{" space root_tags_kv "}" space
+space: / |\x0A[\x09 ]{0,20}/?
+root_tags_kv: "\"tags\"" space ":" space root_tags
+root_tags: "[" space string ("," space string)* "]" space
+string: /"(?:[^\x00-\x1F"\\\x7F]|\\(?:["\/\\bfnrt]|u[0-9A-Fa-f]{4}))*"/ space
+`
+	if got != want {
+		t.Errorf("CompileLark() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCompileGrammar_Accepts(t *testing.T) {
+	zero := 0.0
+	one, three := 1, 3
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"city": {Type: "string"},
+			"days": {Type: "integer", Minimum: &zero},
+			"unit": {Enum: []any{"c", "f"}},
+			"tags": {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}, MinItems: &one, MaxItems: &three},
+			"code": {Type: "string", Pattern: `^[A-Z]{3}-\d+$`},
+			"when": {Type: "string", Format: "date-time"},
+			"note": {AnyOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "null"}}},
+		},
+		Required: []string{"city"},
+	}
+
+	re := grammarRegexp(t, schema)
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{`{"city":"Paris"}`, true},
+		{"{\n  \"city\": \"Paris\",\n  \"days\": 3\n}", true},
+		{`{"city":"Pa\"ris é","tags":["a","b"],"unit":"c"}`, true},
+		{`{"city":"Paris","code":"ABC-12","when":"2026-10-18T12:00:00Z"}`, true},
+		{`{"city":"Paris","note":null}`, true},
+		{`{"city":"Paris","note":"rain"}`, true},
+		{`{"days":3}`, false},
+		{`{"days":3,"city":"Paris"}`, false},
+		{`{"city":"Paris","days":-1}`, false},
+		{`{"city":"Paris","days":1.5}`, false},
+		{`{"city":"Paris","unit":"k"}`, false},
+		{`{"city":"Paris","tags":[]}`, false},
+		{`{"city":"Paris","tags":["a","b","c","d"]}`, false},
+		{`{"city":"Paris","code":"AB-1"}`, false},
+		{`{"city":"Paris","when":"2026-13-01T00:00:00Z"}`, false},
+		{`{"city":"Paris","extra":1}`, false},
+		{`{"city":"Par` + "\n" + `is"}`, false},
+	}
+	for _, tt := range tests {
+		if got := re.MatchString(tt.text); got != tt.want {
+			t.Errorf("grammar accepts %s = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCompileGrammar_Values(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *tooladapter.JSONSchema
+		accept []string
+		reject []string
+	}{
+		{
+			name:   "const",
+			schema: &tooladapter.JSONSchema{Const: map[string]any{"b": 1, "a": []any{true}}},
+			accept: []string{`{"a":[true],"b":1}`},
+			reject: []string{`{"b":1,"a":[true]}`},
+		},
+		{
+			name:   "string length",
+			schema: &tooladapter.JSONSchema{MinLength: intPtr(2), MaxLength: intPtr(3)},
+			accept: []string{`"ab"`, `"a\nc"`},
+			reject: []string{`"a"`, `"abcd"`},
+		},
+		{
+			name:   "exact items",
+			schema: &tooladapter.JSONSchema{Type: "array", Items: &tooladapter.JSONSchema{Type: "boolean"}, MinItems: intPtr(2), MaxItems: intPtr(2)},
+			accept: []string{`[true, false]`},
+			reject: []string{`[true]`, `[true,false,true]`},
+		},
+		{
+			name:   "no items",
+			schema: &tooladapter.JSONSchema{Type: "array", MaxItems: intPtr(0)},
+			accept: []string{`[]`},
+			reject: []string{`[1]`},
+		},
+		{
+			name:   "uuid",
+			schema: &tooladapter.JSONSchema{Type: "string", Format: "uuid"},
+			accept: []string{`"123e4567-e89b-12d3-a456-426614174000"`},
+			reject: []string{`"123e4567"`},
+		},
+		{
+			name: "allOf",
+			schema: &tooladapter.JSONSchema{AllOf: []*tooladapter.JSONSchema{
+				{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"a": {Type: "integer"}}, Required: []string{"a"}},
+				{Ref: "#/$defs/B"},
+			}, Defs: map[string]*tooladapter.JSONSchema{
+				"B": {Properties: map[string]*tooladapter.JSONSchema{"b": {Type: "string"}}, Required: []string{"b"}},
+			}},
+			accept: []string{`{"a":1,"b":"x"}`},
+			reject: []string{`{"a":1}`},
+		},
+		{
+			name: "anyOf with shared keywords",
+			schema: &tooladapter.JSONSchema{
+				Type:       "object",
+				Properties: map[string]*tooladapter.JSONSchema{"a": {Type: "integer"}, "b": {Type: "integer"}},
+				AnyOf:      []*tooladapter.JSONSchema{{Required: []string{"a"}}, {Required: []string{"b"}}},
+			},
+			accept: []string{`{"a":1}`, `{"b":2}`, `{"a":1,"b":2}`},
+			reject: []string{`{}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := grammarRegexp(t, tt.schema)
+			for _, text := range tt.accept {
+				if !re.MatchString(text) {
+					t.Errorf("grammar rejects %s, want accepted", text)
+				}
+			}
+			for _, text := range tt.reject {
+				if re.MatchString(text) {
+					t.Errorf("grammar accepts %s, want rejected", text)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileGrammar_OpenObjects(t *testing.T) {
+	open := true
+	tests := []struct {
+		name   string
+		schema *tooladapter.JSONSchema
+		want   string
+	}{
+		{
+			name:   "any value",
+			schema: &tooladapter.JSONSchema{},
+			want:   "root ::= value\n",
+		},
+		{
+			name:   "no properties",
+			schema: &tooladapter.JSONSchema{Type: "object"},
+			want:   `root ::= "{" space (string ":" space value ("," space string ":" space value)*)? "}" space` + "\n",
+		},
+		{
+			name: "additional properties",
+			schema: &tooladapter.JSONSchema{
+				Type:                 "object",
+				Properties:           map[string]*tooladapter.JSONSchema{"id": {Type: "integer"}},
+				AdditionalProperties: &open,
+			},
+			want: `root ::= "{" space (root-id-kv ("," space string ":" space value)* | string ":" space value ("," space string ":" space value)*)? "}" space` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := CompileGBNF(tt.schema)
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("CompileGBNF() =\n%s\nwant it to start with %s", got, tt.want)
+			}
+			if !strings.Contains(got, "\nvalue ::= object | array | string | number | boolean | null\n") {
+				t.Errorf("CompileGBNF() =\n%s\nwant the value rule", got)
+			}
+		})
+	}
+}
+
+func TestCompileGrammar_Recursive(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*tooladapter.JSONSchema{"tree": {Ref: "#/$defs/Node"}},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Node": {
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"children": {Type: "array", Items: &tooladapter.JSONSchema{Ref: "#/$defs/Node"}},
+					"parent":   {Ref: "#"},
+				},
+			},
+		},
+	}
+
+	got, issues := CompileGBNF(schema)
+	if len(issues) != 0 {
+		t.Errorf("issues = %v, want none", issues)
+	}
+	for _, want := range []string{
+		`root-tree-kv ::= "\"tree\"" space ":" space node` + "\n",
+		`node-children ::= "[" space (node ("," space node)*)? "]" space` + "\n",
+		`node-parent-kv ::= "\"parent\"" space ":" space root` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CompileGBNF() =\n%s\nwant it to contain %s", got, want)
+		}
+	}
+
+	lark, _ := CompileLark(schema)
+	if !strings.Contains(lark, `node_parent_kv: "\"parent\"" space ":" space start`) {
+		t.Errorf("CompileLark() =\n%s\nwant the root reference as start", lark)
+	}
+}
+
+func TestCompileGrammar_Issues(t *testing.T) {
+	one, ten := 1.0, 10.0
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"count": {Type: "integer", Minimum: &one, Maximum: &ten},
+			"email": {Type: "string", Format: "email"},
+			"word":  {Type: "string", Pattern: `\bword\b`, MaxLength: intPtr(5)},
+			"code":  {Type: "string", Pattern: `^[a-z]+$`, MinLength: intPtr(2)},
+			"pick":  {OneOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			"other": {Not: &tooladapter.JSONSchema{Type: "null"}},
+			"ref":   {Ref: "#/components/schemas/X"},
+			"both":  {AllOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			"kind":  {Type: "date"},
+			"step":  {Type: "number", Extra: map[string]any{"multipleOf": 0.5, "exclusiveMinimum": 0}},
+			"set":   {Type: "array", Extra: map[string]any{"uniqueItems": true, "prefixItems": []any{map[string]any{"type": "string"}}}},
+			"named": {Type: "object", Extra: map[string]any{"minProperties": 1, "title": "Named"}},
+			"merge": {AllOf: []*tooladapter.JSONSchema{{Type: "integer", Extra: map[string]any{"multipleOf": 2}}}},
+		},
+	}
+
+	_, issues := CompileGBNF(schema)
+	got := make(map[string]bool)
+	for _, issue := range issues {
+		got[issue.Keyword+" at "+issue.Path] = true
+	}
+	for _, want := range []string{
+		"minimum at /properties/count",
+		"maximum at /properties/count",
+		"format at /properties/email",
+		"pattern at /properties/word",
+		"minLength at /properties/code",
+		"oneOf at /properties/pick",
+		"not at /properties/other",
+		"$ref at /properties/ref",
+		"allOf at /properties/both",
+		"type at /properties/kind",
+		"multipleOf at /properties/step",
+		"exclusiveMinimum at /properties/step",
+		"uniqueItems at /properties/set",
+		"prefixItems at /properties/set",
+		"minProperties at /properties/named",
+		"multipleOf at /properties/merge/allOf/0",
+	} {
+		if !got[want] {
+			t.Errorf("issues = %v, want %s", issues, want)
+		}
+	}
+	if got["title at /properties/named"] {
+		t.Error("title reported, want annotations ignored")
+	}
+	if got["maxLength at /properties/word"] {
+		t.Error("maxLength reported for an untranslatable pattern, want it enforced")
+	}
+}
+
+func TestCompileGrammar_Nil(t *testing.T) {
+	if got, issues := CompileGBNF(nil); got != "" || issues != nil {
+		t.Errorf("CompileGBNF(nil) = %q, %v, want empty", got, issues)
+	}
+	if got, issues := CompileLark(nil); got != "" || issues != nil {
+		t.Errorf("CompileLark(nil) = %q, %v, want empty", got, issues)
+	}
+}
+
+func TestCompileGrammar_RuleNames(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"First Name": {Type: "string", MinLength: intPtr(1)},
+			"2fa":        {Type: "string", MinLength: intPtr(1)},
+		},
+		Required: []string{"First Name", "2fa"},
+	}
+	got, _ := CompileGBNF(schema)
+	for _, want := range []string{"root-first-name ::= ", "root-2fa ::= "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CompileGBNF() =\n%s\nwant rule %q", got, want)
+		}
+	}
+}
+
+func intPtr(i int) *int { return &i }
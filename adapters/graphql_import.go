@@ -0,0 +1,474 @@
+package adapters
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// GraphQLImportOptions configures ImportGraphQL.
+type GraphQLImportOptions struct {
+	// Fields selects the query and mutation fields to import by name; all
+	// of them are imported when it is empty
+	Fields []string
+
+	// Depth is the number of nested selection sets the output selects,
+	// counting the field's own; it defaults to 2. Object fields below that
+	// depth are left out.
+	Depth int
+}
+
+// graphQLDefaultDepth is the selection depth used when none is given.
+const graphQLDefaultDepth = 2
+
+// graphQLIntBounds are the bounds of the 32-bit GraphQL Int scalar.
+var graphQLIntBounds = [2]float64{-1 << 31, 1<<31 - 1}
+
+// ImportGraphQL reads a GraphQL schema in SDL and returns one canonical
+// tool per query or mutation field, queries first, each in definition
+// order.
+//
+// Each tool is built from its field:
+//   - Name is the field name
+//   - Description is the field description
+//   - InputSchema is an object with a property per argument. Non-null
+//     arguments without a default are required; nullable ones are
+//     optional rather than null-typed. Input objects and enums are
+//     referenced from $defs, and @oneOf input objects become a oneOf of
+//     their fields
+//   - OutputSchema is the "data" object of the response, holding the field
+//     result as selected up to opts.Depth. Nullable results are typed as
+//     the value or null, and unions carry their __typename
+//   - Annotations.Deprecated is set for @deprecated fields
+//
+// Deprecated arguments, input fields and output fields carry the JSON
+// Schema "deprecated" keyword, with the reason, if any, added to their
+// description. Deprecated enum values stay in the enum and are listed in
+// its description.
+//
+// Int is bounded to 32 bits, ID is a string, and custom scalars accept any
+// value. The query document that runs the selection, with one variable per
+// argument, is recorded in SourceMeta["graphql"]; see the design notes for
+// its layout.
+//
+// Returns an error if the SDL does not parse, references an undefined
+// type, or a selected field does not exist.
+func ImportGraphQL(data []byte, opts GraphQLImportOptions) ([]*tooladapter.CanonicalTool, error) {
+	schema, err := parseGraphQLSchema(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse GraphQL schema: %w", err)
+	}
+	depth := opts.Depth
+	if depth <= 0 {
+		depth = graphQLDefaultDepth
+	}
+	imp := &graphQLImporter{schema: schema, depth: depth}
+
+	filter := len(opts.Fields) > 0
+	selected := make(map[string]bool, len(opts.Fields))
+	for _, name := range opts.Fields {
+		selected[name] = false
+	}
+
+	var tools []*tooladapter.CanonicalTool
+	seen := make(map[string]string)
+	for _, operation := range []string{"query", "mutation"} {
+		typeName := schema.roots[operation]
+		if typeName == "" {
+			typeName = strings.ToUpper(operation[:1]) + operation[1:]
+		}
+		root, ok := schema.types[typeName]
+		if !ok {
+			if _, declared := schema.roots[operation]; declared {
+				return nil, fmt.Errorf("%s type %q is not defined", operation, typeName)
+			}
+			continue
+		}
+		if root.kind != "type" {
+			return nil, fmt.Errorf("%s type %q is not an object type", operation, typeName)
+		}
+
+		for _, field := range root.fields {
+			if _, ok := selected[field.name]; filter && !ok {
+				continue
+			}
+			selected[field.name] = true
+			endpoint := typeName + "." + field.name
+			tool, err := imp.field(operation, field)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", endpoint, err)
+			}
+			if prev, ok := seen[tool.Name]; ok {
+				return nil, fmt.Errorf("%s: field name %q is already used by %s", endpoint, tool.Name, prev)
+			}
+			seen[tool.Name] = endpoint
+			tools = append(tools, tool)
+		}
+	}
+
+	for _, name := range opts.Fields {
+		if !selected[name] {
+			return nil, fmt.Errorf("query or mutation field %q not found", name)
+		}
+	}
+	return tools, nil
+}
+
+// graphQLImporter holds the schema-wide state of an import.
+type graphQLImporter struct {
+	schema *graphQLSchema
+	depth  int
+}
+
+// field converts one root field.
+func (imp *graphQLImporter) field(operation string, field *graphQLField) (*tooladapter.CanonicalTool, error) {
+	tool := &tooladapter.CanonicalTool{
+		Name:         field.name,
+		Description:  field.description,
+		SourceFormat: "graphql",
+		SourceMeta:   make(map[string]any),
+	}
+	if field.deprecated {
+		tool.Annotations = &tooladapter.ToolAnnotations{Deprecated: true}
+	}
+
+	defs := make(map[string]*tooladapter.JSONSchema)
+	input, err := imp.inputObject(field.args, false, defs)
+	if err != nil {
+		return nil, err
+	}
+	if len(defs) > 0 {
+		input.Defs = defs
+	}
+	tool.InputSchema = input
+
+	defs = make(map[string]*tooladapter.JSONSchema)
+	result, selection, err := imp.output(field.typ, 1, defs)
+	if err != nil {
+		return nil, err
+	}
+	if result.Description == "" {
+		result.Description = field.description
+	}
+	tool.OutputSchema = &tooladapter.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*tooladapter.JSONSchema{field.name: result},
+		Required:   []string{field.name},
+	}
+	if len(defs) > 0 {
+		tool.OutputSchema.Defs = defs
+	}
+
+	var variables, args []string
+	for _, arg := range field.args {
+		variables = append(variables, "$"+arg.name+": "+arg.typ.String())
+		args = append(args, arg.name+": $"+arg.name)
+	}
+	var document strings.Builder
+	document.WriteString(operation + " " + field.name)
+	if len(variables) > 0 {
+		document.WriteString("(" + strings.Join(variables, ", ") + ")")
+	}
+	document.WriteString(" { " + field.name)
+	if len(args) > 0 {
+		document.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	document.WriteString(selection + " }")
+
+	tool.SourceMeta["graphql"] = map[string]any{
+		"operation": operation,
+		"field":     field.name,
+		"document":  document.String(),
+	}
+	return tool, nil
+}
+
+// inputObject converts arguments or input fields into an object schema.
+// With oneOf, exactly one field must be given.
+func (imp *graphQLImporter) inputObject(fields []*graphQLField, oneOf bool, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	schema := &tooladapter.JSONSchema{
+		Type:                 "object",
+		AdditionalProperties: boolPtr(false),
+	}
+	for _, f := range fields {
+		prop, err := imp.input(f.typ, defs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		if f.description != "" {
+			prop.Description = f.description
+		}
+		if f.hasDef {
+			prop.Default = f.def
+		}
+		if f.deprecated {
+			graphQLDeprecate(prop, f.deprecationReason)
+		}
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]*tooladapter.JSONSchema, len(fields))
+		}
+		schema.Properties[f.name] = prop
+		switch {
+		case oneOf:
+			schema.OneOf = append(schema.OneOf, &tooladapter.JSONSchema{Required: []string{f.name}})
+		case f.typ.nonNull && !f.hasDef:
+			schema.Required = append(schema.Required, f.name)
+		}
+	}
+	return schema, nil
+}
+
+// input converts an input type reference.
+func (imp *graphQLImporter) input(t *graphQLTypeRef, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	if t.list != nil {
+		items, err := imp.input(t.list, defs)
+		if err != nil {
+			return nil, err
+		}
+		return &tooladapter.JSONSchema{Type: "array", Items: items}, nil
+	}
+	if schema, ok := graphQLScalar(t.name); ok {
+		return schema, nil
+	}
+
+	def, ok := imp.schema.types[t.name]
+	if !ok {
+		return nil, fmt.Errorf("undefined type %q", t.name)
+	}
+	switch def.kind {
+	case "scalar":
+		return &tooladapter.JSONSchema{Description: def.description}, nil
+	case "enum":
+		imp.enumDef(def, defs)
+	case "input":
+		if _, ok := defs[def.name]; !ok {
+			// Input objects may nest themselves (input Filter { and: [Filter!] }),
+			// so the placeholder stops the recursion at the second visit
+			defs[def.name] = &tooladapter.JSONSchema{}
+			schema, err := imp.inputObject(def.fields, def.oneOf, defs)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%w", def.name, err)
+			}
+			schema.Description = def.description
+			defs[def.name] = schema
+		}
+	default:
+		return nil, fmt.Errorf("%s %q is not an input type", def.kind, def.name)
+	}
+	return &tooladapter.JSONSchema{Ref: "#/$defs/" + def.name}, nil
+}
+
+// enumDef adds an enum to defs, once.
+func (imp *graphQLImporter) enumDef(def *graphQLTypeDef, defs map[string]*tooladapter.JSONSchema) {
+	if _, ok := defs[def.name]; ok {
+		return
+	}
+	values := make([]any, len(def.values))
+	var deprecated []string
+	for i, v := range def.values {
+		values[i] = v.name
+		if v.deprecated {
+			note := v.name
+			if v.deprecationReason != "" {
+				note += " (" + strings.TrimSuffix(v.deprecationReason, ".") + ")"
+			}
+			deprecated = append(deprecated, note)
+		}
+	}
+	description := def.description
+	if len(deprecated) > 0 {
+		description = appendSentence(description, "Deprecated values: "+strings.Join(deprecated, ", ")+".")
+	}
+	defs[def.name] = &tooladapter.JSONSchema{Type: "string", Enum: values, Description: description}
+}
+
+// graphQLDeprecate marks a schema with the "deprecated" keyword and adds
+// the deprecation reason, if any, to its description.
+func graphQLDeprecate(schema *tooladapter.JSONSchema, reason string) {
+	markDeprecated(schema)
+	if reason != "" {
+		schema.Description = appendSentence(schema.Description, "Deprecated: "+reason)
+	}
+}
+
+// markDeprecated sets the JSON Schema "deprecated" keyword, which is kept
+// in Extra.
+func markDeprecated(schema *tooladapter.JSONSchema) {
+	if schema.Extra == nil {
+		schema.Extra = make(map[string]any, 1)
+	}
+	schema.Extra["deprecated"] = true
+}
+
+// appendSentence appends a sentence to a description, if any.
+func appendSentence(description, sentence string) string {
+	if description == "" {
+		return sentence
+	}
+	return description + " " + sentence
+}
+
+// output converts an output type reference at a selection depth, and
+// returns the schema and the selection set to request it with ("" for
+// leaf types).
+func (imp *graphQLImporter) output(t *graphQLTypeRef, depth int, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, string, error) {
+	var schema *tooladapter.JSONSchema
+	var selection string
+	scalar, builtin := graphQLScalar(t.name)
+	if t.list != nil {
+		items, sel, err := imp.output(t.list, depth, defs)
+		if err != nil {
+			return nil, "", err
+		}
+		schema, selection = &tooladapter.JSONSchema{Type: "array", Items: items}, sel
+	} else if builtin {
+		schema = scalar
+	} else {
+		def, ok := imp.schema.types[t.name]
+		if !ok {
+			return nil, "", fmt.Errorf("undefined type %q", t.name)
+		}
+		switch def.kind {
+		case "scalar":
+			schema = &tooladapter.JSONSchema{Description: def.description}
+		case "enum":
+			imp.enumDef(def, defs)
+			schema = &tooladapter.JSONSchema{Ref: "#/$defs/" + def.name}
+		case "type", "interface":
+			var err error
+			schema, selection, err = imp.selectFields(def, depth, defs)
+			if err != nil {
+				return nil, "", err
+			}
+		case "union":
+			var err error
+			schema, selection, err = imp.selectUnion(def, depth, defs)
+			if err != nil {
+				return nil, "", err
+			}
+		default:
+			return nil, "", fmt.Errorf("%s %q is not an output type", def.kind, def.name)
+		}
+	}
+
+	switch {
+	case t.nonNull:
+		return schema, selection, nil
+	case builtin && t.list == nil:
+		applyTypeList(schema, []string{schema.Type, "null"})
+		schema.Type = ""
+		return schema, selection, nil
+	case schema.Type == "" && schema.Ref == "" && len(schema.AnyOf) == 0:
+		// Custom scalars already accept null
+		return schema, selection, nil
+	case len(schema.AnyOf) > 0:
+		// Unions take null as another branch
+		schema.AnyOf = append(schema.AnyOf, &tooladapter.JSONSchema{Type: "null"})
+		return schema, selection, nil
+	}
+	return &tooladapter.JSONSchema{
+		AnyOf: []*tooladapter.JSONSchema{schema, {Type: "null"}},
+	}, selection, nil
+}
+
+// selectFields selects the fields of an object or interface type. Fields
+// of object, interface and union types are selected while depth allows,
+// and fields with a required argument, which the document has no value
+// for, are left out; when a type has no other fields to select,
+// __typename is selected.
+func (imp *graphQLImporter) selectFields(def *graphQLTypeDef, depth int, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, string, error) {
+	schema := &tooladapter.JSONSchema{
+		Type:        "object",
+		Description: def.description,
+		Properties:  make(map[string]*tooladapter.JSONSchema),
+	}
+	var selections []string
+	for _, f := range def.fields {
+		if imp.composite(f.typ.named()) && depth >= imp.depth || requiresArgs(f) {
+			continue
+		}
+		prop, sel, err := imp.output(f.typ, depth+1, defs)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s.%s: %w", def.name, f.name, err)
+		}
+		if f.description != "" {
+			prop.Description = f.description
+		}
+		if f.deprecated {
+			graphQLDeprecate(prop, f.deprecationReason)
+		}
+		schema.Properties[f.name] = prop
+		schema.Required = append(schema.Required, f.name)
+		selections = append(selections, f.name+sel)
+	}
+	if len(selections) == 0 {
+		schema.Properties["__typename"] = &tooladapter.JSONSchema{Type: "string"}
+		schema.Required = []string{"__typename"}
+		selections = []string{"__typename"}
+	}
+	return schema, " { " + strings.Join(selections, " ") + " }", nil
+}
+
+// requiresArgs reports whether a field has a non-null argument without a
+// default, which a selection must give a value for.
+func requiresArgs(f *graphQLField) bool {
+	for _, arg := range f.args {
+		if arg.typ.nonNull && !arg.hasDef {
+			return true
+		}
+	}
+	return false
+}
+
+// selectUnion selects __typename and the fields of each member type with
+// an inline fragment.
+func (imp *graphQLImporter) selectUnion(def *graphQLTypeDef, depth int, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, string, error) {
+	schema := &tooladapter.JSONSchema{Description: def.description}
+	selections := []string{"__typename"}
+	for _, name := range def.members {
+		member, ok := imp.schema.types[name]
+		if !ok {
+			return nil, "", fmt.Errorf("undefined type %q", name)
+		}
+		if member.kind != "type" {
+			return nil, "", fmt.Errorf("union member %q is not an object type", name)
+		}
+		branch, sel, err := imp.selectFields(member, depth, defs)
+		if err != nil {
+			return nil, "", err
+		}
+		if slices.Equal(branch.Required, []string{"__typename"}) {
+			sel = ""
+		} else {
+			branch.Required = append([]string{"__typename"}, branch.Required...)
+			selections = append(selections, "... on "+name+sel)
+		}
+		branch.Properties["__typename"] = &tooladapter.JSONSchema{Const: name}
+		schema.AnyOf = append(schema.AnyOf, branch)
+	}
+	return schema, " { " + strings.Join(selections, " ") + " }", nil
+}
+
+// composite reports whether a named type has fields to select.
+func (imp *graphQLImporter) composite(name string) bool {
+	def, ok := imp.schema.types[name]
+	return ok && (def.kind == "type" || def.kind == "interface" || def.kind == "union")
+}
+
+// graphQLScalar returns the schema of a built-in scalar.
+func graphQLScalar(name string) (*tooladapter.JSONSchema, bool) {
+	switch name {
+	case "Int":
+		minimum, maximum := graphQLIntBounds[0], graphQLIntBounds[1]
+		return &tooladapter.JSONSchema{Type: "integer", Minimum: &minimum, Maximum: &maximum}, true
+	case "Float":
+		return &tooladapter.JSONSchema{Type: "number"}, true
+	case "String", "ID":
+		return &tooladapter.JSONSchema{Type: "string"}, true
+	case "Boolean":
+		return &tooladapter.JSONSchema{Type: "boolean"}, true
+	}
+	return nil, false
+}
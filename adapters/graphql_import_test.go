@@ -0,0 +1,388 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+const librarySDL = `
+schema {
+  query: Library
+  mutation: Desk
+}
+
+"""
+A book in the catalog.
+"""
+type Book implements Node {
+  id: ID!
+  title: String!
+  pages: Int
+  genre: Genre!
+  author: Author!
+  related: [Item!]
+}
+
+type Author {
+  name: String!
+  books: [Book!]!
+}
+
+interface Node {
+  id: ID!
+}
+
+union Item = Book | Author
+
+enum Genre {
+  FICTION
+  "Non-fiction books"
+  NONFICTION
+}
+
+scalar Date
+
+input BookFilter {
+  "Words in the title"
+  title: String
+  genres: [Genre!] = [FICTION]
+  published: DateRange
+  and: [BookFilter!]
+}
+
+input DateRange {
+  from: Date!
+  to: Date
+}
+
+input BookRef @oneOf {
+  id: ID
+  isbn: String
+}
+
+type Library {
+  "Find books matching a filter."
+  books(filter: BookFilter, first: Int = 10): [Book!]!
+  book(ref: BookRef!): Book
+  search(text: String!): [Item!]! @deprecated(reason: "Use books.")
+}
+
+type Desk {
+  "Lend a book."
+  lend(bookId: ID!, until: Date!): Boolean!
+}
+
+extend type Library {
+  today: Date!
+}
+`
+
+func TestImportGraphQL(t *testing.T) {
+	tools, err := ImportGraphQL([]byte(librarySDL), GraphQLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportGraphQL() error = %v", err)
+	}
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+		if err := tool.Validate(); err != nil {
+			t.Errorf("%s: Validate() error = %v", tool.Name, err)
+		}
+		if tool.SourceFormat != "graphql" {
+			t.Errorf("%s: SourceFormat = %q, want graphql", tool.Name, tool.SourceFormat)
+		}
+	}
+	// Query fields first, extensions after the fields they extend
+	if want := []string{"books", "book", "search", "today", "lend"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	books := tools[0]
+	if books.Description != "Find books matching a filter." {
+		t.Errorf("Description = %q", books.Description)
+	}
+	input := books.InputSchema
+	if input.Type != "object" || len(input.Required) != 0 {
+		t.Errorf("input type %q, required %v, want an object with nothing required", input.Type, input.Required)
+	}
+	if input.AdditionalProperties == nil || *input.AdditionalProperties {
+		t.Error("input additionalProperties should be false")
+	}
+	if got := input.Properties["first"]; got.Type != "integer" || got.Default != 10.0 || *got.Maximum != 1<<31-1 {
+		t.Errorf("first = %+v, want a 32-bit integer defaulting to 10", got)
+	}
+	if got := input.Properties["filter"].Ref; got != "#/$defs/BookFilter" {
+		t.Errorf("filter $ref = %q", got)
+	}
+	var defNames []string
+	for name := range input.Defs {
+		defNames = append(defNames, name)
+	}
+	if len(defNames) != 3 || input.Defs["BookFilter"] == nil || input.Defs["DateRange"] == nil || input.Defs["Genre"] == nil {
+		t.Errorf("$defs = %v, want BookFilter, DateRange and Genre", defNames)
+	}
+
+	filter := input.Defs["BookFilter"]
+	if got := filter.Properties["title"].Description; got != "Words in the title" {
+		t.Errorf("title description = %q", got)
+	}
+	genres := filter.Properties["genres"]
+	if genres.Type != "array" || genres.Items.Ref != "#/$defs/Genre" || !reflect.DeepEqual(genres.Default, []any{"FICTION"}) {
+		t.Errorf("genres = %+v", genres)
+	}
+	if got := filter.Properties["and"].Items.Ref; got != "#/$defs/BookFilter" {
+		t.Errorf("recursive and $ref = %q", got)
+	}
+	if got := input.Defs["DateRange"].Required; !reflect.DeepEqual(got, []string{"from"}) {
+		t.Errorf("DateRange required = %v, want [from]", got)
+	}
+	if got := input.Defs["DateRange"].Properties["from"]; got.Type != "" || got.Ref != "" {
+		t.Errorf("custom scalar = %+v, want any value", got)
+	}
+	if got := input.Defs["Genre"].Enum; !reflect.DeepEqual(got, []any{"FICTION", "NONFICTION"}) {
+		t.Errorf("Genre enum = %v", got)
+	}
+
+	meta := books.SourceMeta["graphql"].(map[string]any)
+	wantDoc := "query books($filter: BookFilter, $first: Int) { books(filter: $filter, first: $first) " +
+		"{ id title pages genre author { name } related { __typename ... on Book { id title pages genre } ... on Author { name } } } }"
+	if meta["document"] != wantDoc {
+		t.Errorf("document =\n%s\nwant\n%s", meta["document"], wantDoc)
+	}
+	if meta["operation"] != "query" || meta["field"] != "books" {
+		t.Errorf("graphql meta = %v", meta)
+	}
+	if books.Annotations != nil {
+		t.Errorf("books Annotations = %+v, want nil", books.Annotations)
+	}
+
+	if a := tools[2].Annotations; a == nil || !a.Deprecated {
+		t.Error("search should be deprecated")
+	}
+
+	ref := tools[1].InputSchema.Defs["BookRef"]
+	if len(ref.OneOf) != 2 || !reflect.DeepEqual(ref.OneOf[0].Required, []string{"id"}) || len(ref.Required) != 0 {
+		t.Errorf("@oneOf input = %+v, want a oneOf of single required fields", ref)
+	}
+
+	lend := tools[4]
+	if got := lend.SourceMeta["graphql"].(map[string]any)["document"]; got != "mutation lend($bookId: ID!, $until: Date!) { lend(bookId: $bookId, until: $until) }" {
+		t.Errorf("lend document = %s", got)
+	}
+	if got := lend.OutputSchema.Properties["lend"]; got.Type != "boolean" || got.Description != "Lend a book." {
+		t.Errorf("lend output = %+v", got)
+	}
+}
+
+func TestImportGraphQL_DeprecatedMembers(t *testing.T) {
+	sdl := `
+enum Size {
+  SMALL
+  BIG @deprecated(reason: "Too big.")
+  HUGE @deprecated
+}
+
+input Order {
+  size: Size
+  "Gift wrap"
+  wrap: Boolean @deprecated(reason: "Always wrapped.")
+}
+
+type Shirt {
+  id: ID!
+  color: String @deprecated
+}
+
+type Query {
+  shirt(order: Order!, legacy: Int @deprecated): Shirt
+}
+`
+	tools, err := ImportGraphQL([]byte(sdl), GraphQLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportGraphQL() error = %v", err)
+	}
+	input := tools[0].InputSchema
+	if a := tools[0].Annotations; a != nil {
+		t.Errorf("Annotations = %+v, want nil", a)
+	}
+
+	legacy := input.Properties["legacy"]
+	if legacy.Extra["deprecated"] != true || legacy.Description != "" {
+		t.Errorf("legacy = %+v, want deprecated without a reason", legacy)
+	}
+	if _, ok := input.Properties["order"].Extra["deprecated"]; ok {
+		t.Error("order should not be deprecated")
+	}
+	wrap := input.Defs["Order"].Properties["wrap"]
+	if wrap.Extra["deprecated"] != true || wrap.Description != "Gift wrap Deprecated: Always wrapped." {
+		t.Errorf("wrap = %+v, want deprecated with the reason in the description", wrap)
+	}
+
+	size := input.Defs["Size"]
+	if !reflect.DeepEqual(size.Enum, []any{"SMALL", "BIG", "HUGE"}) || size.Description != "Deprecated values: BIG (Too big), HUGE." {
+		t.Errorf("Size = %+v, want all values with the deprecated ones listed", size)
+	}
+
+	shirt := tools[0].OutputSchema.Properties["shirt"].AnyOf[0]
+	if shirt.Properties["color"].Extra["deprecated"] != true {
+		t.Errorf("color = %+v, want deprecated", shirt.Properties["color"])
+	}
+	if got := shirt.ToMap()["properties"].(map[string]any)["color"].(map[string]any)["deprecated"]; got != true {
+		t.Errorf("color ToMap deprecated = %v, want true", got)
+	}
+}
+func TestImportGraphQL_OutputSchema(t *testing.T) {
+	tools, err := ImportGraphQL([]byte(librarySDL), GraphQLImportOptions{Fields: []string{"book"}})
+	if err != nil {
+		t.Fatalf("ImportGraphQL() error = %v", err)
+	}
+	if len(tools) != 1 {
+		t.Fatalf("got %d tools, want 1", len(tools))
+	}
+	out := tools[0].OutputSchema
+	if out.Type != "object" || !reflect.DeepEqual(out.Required, []string{"book"}) {
+		t.Fatalf("output = %+v, want the data object", out)
+	}
+
+	// A nullable object is the object or null
+	book := out.Properties["book"]
+	if len(book.AnyOf) != 2 || book.AnyOf[1].Type != "null" {
+		t.Fatalf("book = %+v, want anyOf object, null", book)
+	}
+	obj := book.AnyOf[0]
+	if obj.Description != "A book in the catalog." {
+		t.Errorf("book description = %q", obj.Description)
+	}
+	if want := []string{"id", "title", "pages", "genre", "author", "related"}; !reflect.DeepEqual(obj.Required, want) {
+		t.Errorf("book required = %v, want %v", obj.Required, want)
+	}
+	pages := obj.Properties["pages"]
+	if len(pages.AnyOf) != 2 || pages.AnyOf[0].Type != "integer" || pages.AnyOf[1].Type != "null" {
+		t.Errorf("pages = %+v, want integer or null", pages)
+	}
+	if got := obj.Properties["genre"].Ref; got != "#/$defs/Genre" || out.Defs["Genre"] == nil {
+		t.Errorf("genre $ref = %q, $defs = %v", got, out.Defs)
+	}
+
+	// Depth 2 stops at the fields of the author
+	author := obj.Properties["author"]
+	if _, ok := author.Properties["books"]; ok || !reflect.DeepEqual(author.Required, []string{"name"}) {
+		t.Errorf("author = %+v, want only name", author)
+	}
+
+	// Unions carry __typename, and a nullable list is the array or null
+	related := obj.Properties["related"]
+	if len(related.AnyOf) != 2 || related.AnyOf[0].Type != "array" {
+		t.Fatalf("related = %+v, want anyOf array, null", related)
+	}
+	items := related.AnyOf[0].Items
+	if len(items.AnyOf) != 2 {
+		t.Fatalf("related items = %+v, want anyOf Book, Author", items)
+	}
+	if got := items.AnyOf[1].Properties["__typename"].Const; got != "Author" {
+		t.Errorf("Author __typename = %v", got)
+	}
+	if got := items.AnyOf[1].Required; !reflect.DeepEqual(got, []string{"__typename", "name"}) {
+		t.Errorf("Author required = %v", got)
+	}
+}
+
+func TestImportGraphQL_Depth(t *testing.T) {
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		{1, "query book($ref: BookRef!) { book(ref: $ref) { id title pages genre } }"},
+		{3, "query book($ref: BookRef!) { book(ref: $ref) { id title pages genre author { name books { id title pages genre } } " +
+			"related { __typename ... on Book { id title pages genre author { name } related { __typename ... on Book { id title pages genre } ... on Author { name } } } ... on Author { name books { id title pages genre } } } } }"},
+	}
+	for _, tt := range tests {
+		tools, err := ImportGraphQL([]byte(librarySDL), GraphQLImportOptions{Fields: []string{"book"}, Depth: tt.depth})
+		if err != nil {
+			t.Fatalf("ImportGraphQL() error = %v", err)
+		}
+		if got := tools[0].SourceMeta["graphql"].(map[string]any)["document"]; got != tt.want {
+			t.Errorf("depth %d document =\n%s\nwant\n%s", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestImportGraphQL_TypenameOnly(t *testing.T) {
+	sdl := `
+type Query { wrapper: Wrapper! }
+type Wrapper { inner: Inner! }
+type Inner { value: String }
+`
+	tools, err := ImportGraphQL([]byte(sdl), GraphQLImportOptions{Depth: 1})
+	if err != nil {
+		t.Fatalf("ImportGraphQL() error = %v", err)
+	}
+	if got := tools[0].SourceMeta["graphql"].(map[string]any)["document"]; got != "query wrapper { wrapper { __typename } }" {
+		t.Errorf("document = %s", got)
+	}
+	want := &tooladapter.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*tooladapter.JSONSchema{"__typename": {Type: "string"}},
+		Required:   []string{"__typename"},
+	}
+	if got := tools[0].OutputSchema.Properties["wrapper"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrapper = %+v, want %+v", got, want)
+	}
+}
+
+func TestImportGraphQL_NestedArguments(t *testing.T) {
+	sdl := `
+type Query { user(id: ID!): User }
+type User {
+  name: String!
+  posts(first: Int!): [Post!]!
+  recent(first: Int! = 10, after: String): [Post!]!
+}
+type Post { title: String! }
+`
+	tools, err := ImportGraphQL([]byte(sdl), GraphQLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportGraphQL() error = %v", err)
+	}
+	want := "query user($id: ID!) { user(id: $id) { name recent { title } } }"
+	if got := tools[0].SourceMeta["graphql"].(map[string]any)["document"]; got != want {
+		t.Errorf("document = %s, want %s", got, want)
+	}
+	user := tools[0].OutputSchema.Properties["user"].AnyOf[0]
+	if _, ok := user.Properties["posts"]; ok {
+		t.Error("posts, with a required argument, should not be selected")
+	}
+	if got := user.Required; !reflect.DeepEqual(got, []string{"name", "recent"}) {
+		t.Errorf("user required = %v", got)
+	}
+}
+
+func TestImportGraphQL_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		sdl  string
+		opts GraphQLImportOptions
+		want string
+	}{
+		{"syntax", "type Query {\n  a: String\n  b(: Int): String }", GraphQLImportOptions{}, "line 3"},
+		{"undefined type", "type Query { a: Missing }", GraphQLImportOptions{}, `Query.a: undefined type "Missing"`},
+		{"output as input", "type Query { a(b: Query): String }", GraphQLImportOptions{}, "not an input type"},
+		{"input as output", "input In { a: Int } type Query { a: In }", GraphQLImportOptions{}, "not an output type"},
+		{"unknown field", "type Query { a: String }", GraphQLImportOptions{Fields: []string{"b"}}, `field "b" not found`},
+		{"duplicate name", "type Query { a: String } type Mutation { a: String }", GraphQLImportOptions{}, "already used by Query.a"},
+		{"undefined root", "schema { query: Root }", GraphQLImportOptions{}, `query type "Root" is not defined`},
+		{"executable", "query { a }", GraphQLImportOptions{}, "executable definitions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportGraphQL([]byte(tt.sdl), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ImportGraphQL() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,874 @@
+package adapters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// graphQLSchema is a parsed GraphQL type system document.
+type graphQLSchema struct {
+	// types maps type names to their definitions, with extensions applied
+	types map[string]*graphQLTypeDef
+
+	// roots maps "query", "mutation" and "subscription" to the root
+	// operation type names declared by a schema definition
+	roots map[string]string
+}
+
+// graphQLTypeDef is a named type definition.
+type graphQLTypeDef struct {
+	// kind is "scalar", "type", "interface", "union", "enum" or "input"
+	kind        string
+	name        string
+	description string
+
+	// fields are the fields of an object or interface type, or the input
+	// fields of an input type
+	fields []*graphQLField
+
+	// members are the member types of a union
+	members []string
+
+	// values are the values of an enum
+	values []graphQLEnumValue
+
+	// oneOf is set for input types with the @oneOf directive
+	oneOf bool
+}
+
+// graphQLField is a field, an argument or an input field.
+type graphQLField struct {
+	name        string
+	description string
+	args        []*graphQLField
+	typ         *graphQLTypeRef
+
+	// def is the default value of an argument or input field, if hasDef
+	def    any
+	hasDef bool
+
+	// deprecated is set by the @deprecated directive, with its reason
+	deprecated        bool
+	deprecationReason string
+}
+
+// graphQLTypeRef is a type reference: a named type or a list, possibly
+// non-null.
+type graphQLTypeRef struct {
+	name    string
+	list    *graphQLTypeRef
+	nonNull bool
+}
+
+// String writes the reference in GraphQL notation.
+func (t *graphQLTypeRef) String() string {
+	s := t.name
+	if t.list != nil {
+		s = "[" + t.list.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// named returns the named type at the core of the reference.
+func (t *graphQLTypeRef) named() string {
+	for t.list != nil {
+		t = t.list
+	}
+	return t.name
+}
+
+type graphQLEnumValue struct {
+	name        string
+	description string
+
+	// deprecated is set by the @deprecated directive, with its reason
+	deprecated        bool
+	deprecationReason string
+}
+
+// graphQLDirective is a directive applied to a definition.
+type graphQLDirective struct {
+	name string
+	args map[string]any
+}
+
+type graphQLTokenKind int
+
+const (
+	graphQLEOF graphQLTokenKind = iota
+	graphQLPunct
+	graphQLName
+	graphQLInt
+	graphQLFloat
+	graphQLString
+)
+
+type graphQLToken struct {
+	kind graphQLTokenKind
+
+	// value is the text of the token, with strings decoded
+	value string
+
+	pos int
+}
+
+// graphQLParser parses type system documents. Executable definitions
+// (operations and fragments) are rejected.
+type graphQLParser struct {
+	src string
+	pos int
+	tok graphQLToken
+
+	schema     *graphQLSchema
+	extensions []*graphQLTypeDef
+}
+
+// parseGraphQLSchema parses a GraphQL SDL document. Type extensions are
+// merged into the types they extend.
+func parseGraphQLSchema(src string) (*graphQLSchema, error) {
+	p := &graphQLParser{
+		src: src,
+		schema: &graphQLSchema{
+			types: make(map[string]*graphQLTypeDef),
+			roots: make(map[string]string),
+		},
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	for p.tok.kind != graphQLEOF {
+		if err := p.definition(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ext := range p.extensions {
+		def, ok := p.schema.types[ext.name]
+		if !ok {
+			return nil, fmt.Errorf("extension of undefined type %q", ext.name)
+		}
+		if def.kind != ext.kind {
+			return nil, fmt.Errorf("extension of %s %q as %s", def.kind, ext.name, ext.kind)
+		}
+		def.fields = append(def.fields, ext.fields...)
+		def.members = append(def.members, ext.members...)
+		def.values = append(def.values, ext.values...)
+		def.oneOf = def.oneOf || ext.oneOf
+	}
+	return p.schema, nil
+}
+
+// errorf reports an error at the current token.
+func (p *graphQLParser) errorf(format string, args ...any) error {
+	line := 1 + strings.Count(p.src[:p.tok.pos], "\n")
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// describe names the current token for error messages.
+func (p *graphQLParser) describe() string {
+	switch p.tok.kind {
+	case graphQLEOF:
+		return "end of document"
+	case graphQLString:
+		return "string"
+	}
+	return strconv.Quote(p.tok.value)
+}
+
+func (p *graphQLParser) definition() error {
+	description, err := p.description()
+	if err != nil {
+		return err
+	}
+	if p.tok.kind != graphQLName {
+		return p.errorf("unexpected %s", p.describe())
+	}
+
+	keyword := p.tok.value
+	extend := keyword == "extend"
+	if extend {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if p.tok.kind != graphQLName {
+			return p.errorf("unexpected %s", p.describe())
+		}
+		keyword = p.tok.value
+	}
+
+	switch keyword {
+	case "schema":
+		return p.schemaDefinition()
+	case "directive":
+		if extend {
+			return p.errorf("unexpected %s", p.describe())
+		}
+		return p.directiveDefinition()
+	case "scalar", "type", "interface", "union", "enum", "input":
+		def, err := p.typeDefinition(keyword)
+		if err != nil {
+			return err
+		}
+		def.description = description
+		if extend {
+			p.extensions = append(p.extensions, def)
+			return nil
+		}
+		if _, ok := p.schema.types[def.name]; ok {
+			return fmt.Errorf("type %q is defined twice", def.name)
+		}
+		p.schema.types[def.name] = def
+		return nil
+	case "query", "mutation", "subscription", "fragment":
+		return p.errorf("executable definitions are not supported")
+	}
+	return p.errorf("unexpected %s", p.describe())
+}
+
+// description reads an optional description string.
+func (p *graphQLParser) description() (string, error) {
+	if p.tok.kind != graphQLString {
+		return "", nil
+	}
+	description := p.tok.value
+	return description, p.next()
+}
+
+func (p *graphQLParser) schemaDefinition() error {
+	if err := p.next(); err != nil {
+		return err
+	}
+	if _, err := p.directives(); err != nil {
+		return err
+	}
+	if !p.peek("{") {
+		return nil
+	}
+	if err := p.next(); err != nil {
+		return err
+	}
+	for !p.peek("}") {
+		op, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		typ, err := p.name()
+		if err != nil {
+			return err
+		}
+		p.schema.roots[op] = typ
+	}
+	return p.next()
+}
+
+// directiveDefinition skips a directive definition.
+func (p *graphQLParser) directiveDefinition() error {
+	if err := p.next(); err != nil {
+		return err
+	}
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.peek("(") {
+		if _, err := p.inputValues("(", ")"); err != nil {
+			return err
+		}
+	}
+	if p.peekName("repeatable") {
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+	if !p.peekName("on") {
+		return p.errorf(`expected "on", found %s`, p.describe())
+	}
+	if err := p.next(); err != nil {
+		return err
+	}
+	_, err := p.nameList("|")
+	return err
+}
+
+func (p *graphQLParser) typeDefinition(kind string) (*graphQLTypeDef, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	def := &graphQLTypeDef{kind: kind, name: name}
+
+	if (kind == "type" || kind == "interface") && p.peekName("implements") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if _, err := p.nameList("&"); err != nil {
+			return nil, err
+		}
+	}
+	directives, err := p.directives()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directives {
+		if d.name == "oneOf" {
+			def.oneOf = true
+		}
+	}
+
+	switch kind {
+	case "type", "interface":
+		if p.peek("{") {
+			def.fields, err = p.fields()
+		}
+	case "input":
+		if p.peek("{") {
+			def.fields, err = p.inputValues("{", "}")
+		}
+	case "union":
+		if p.peek("=") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			def.members, err = p.nameList("|")
+		}
+	case "enum":
+		if p.peek("{") {
+			def.values, err = p.enumValues()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// nameList reads names separated by sep, which may also lead the list.
+func (p *graphQLParser) nameList(sep string) ([]string, error) {
+	if p.peek(sep) {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.peek(sep) {
+			return names, nil
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *graphQLParser) fields() ([]*graphQLField, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var fields []*graphQLField
+	for !p.peek("}") {
+		description, err := p.description()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		field := &graphQLField{name: name, description: description}
+		if p.peek("(") {
+			if field.args, err = p.inputValues("(", ")"); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if field.typ, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if err := p.deprecation(field); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, p.next()
+}
+
+// inputValues reads argument or input field definitions between open and
+// close.
+func (p *graphQLParser) inputValues(open, close string) ([]*graphQLField, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+	var values []*graphQLField
+	for !p.peek(close) {
+		description, err := p.description()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		value := &graphQLField{name: name, description: description}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if value.typ, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if p.peek("=") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if value.def, err = p.value(); err != nil {
+				return nil, err
+			}
+			value.hasDef = true
+		}
+		if err := p.deprecation(value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, p.next()
+}
+
+// deprecation reads the directives of a field and records @deprecated.
+func (p *graphQLParser) deprecation(field *graphQLField) error {
+	var err error
+	field.deprecated, field.deprecationReason, err = p.deprecatedDirective()
+	return err
+}
+
+// deprecatedDirective reads directives and reports whether they include
+// @deprecated, with its reason.
+func (p *graphQLParser) deprecatedDirective() (deprecated bool, reason string, err error) {
+	directives, err := p.directives()
+	if err != nil {
+		return false, "", err
+	}
+	for _, d := range directives {
+		if d.name == "deprecated" {
+			deprecated = true
+			reason, _ = d.args["reason"].(string)
+		}
+	}
+	return deprecated, reason, nil
+}
+
+func (p *graphQLParser) enumValues() ([]graphQLEnumValue, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var values []graphQLEnumValue
+	for !p.peek("}") {
+		description, err := p.description()
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		value := graphQLEnumValue{name: name, description: description}
+		if value.deprecated, value.deprecationReason, err = p.deprecatedDirective(); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, p.next()
+}
+
+func (p *graphQLParser) directives() ([]graphQLDirective, error) {
+	var directives []graphQLDirective
+	for p.peek("@") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		d := graphQLDirective{name: name, args: make(map[string]any)}
+		if p.peek("(") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			for !p.peek(")") {
+				arg, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if d.args[arg], err = p.value(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+func (p *graphQLParser) typeRef() (*graphQLTypeRef, error) {
+	t := &graphQLTypeRef{}
+	if p.peek("[") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		t.list = inner
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		t.name = name
+	}
+	if p.peek("!") {
+		t.nonNull = true
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// value reads a constant value as a JSON value. Enum values become their
+// names.
+func (p *graphQLParser) value() (any, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == graphQLInt || tok.kind == graphQLFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %s", tok.value)
+		}
+		return f, p.next()
+	case tok.kind == graphQLString:
+		return tok.value, p.next()
+	case tok.kind == graphQLName:
+		var v any = tok.value
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		}
+		return v, p.next()
+	case p.peek("["):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		list := []any{}
+		for !p.peek("]") {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.next()
+	case p.peek("{"):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		obj := map[string]any{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if obj[name], err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.next()
+	}
+	return nil, p.errorf("expected a value, found %s", p.describe())
+}
+
+func (p *graphQLParser) peek(punct string) bool {
+	return p.tok.kind == graphQLPunct && p.tok.value == punct
+}
+
+func (p *graphQLParser) peekName(name string) bool {
+	return p.tok.kind == graphQLName && p.tok.value == name
+}
+
+func (p *graphQLParser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.errorf("expected %q, found %s", punct, p.describe())
+	}
+	return p.next()
+}
+
+func (p *graphQLParser) name() (string, error) {
+	if p.tok.kind != graphQLName {
+		return "", p.errorf("expected a name, found %s", p.describe())
+	}
+	name := p.tok.value
+	return name, p.next()
+}
+
+// next reads the next token, skipping whitespace, commas and comments.
+func (p *graphQLParser) next() error {
+	src := p.src
+	for p.pos < len(src) {
+		switch c := src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(src) && src[p.pos] != '\n' && src[p.pos] != '\r' {
+				p.pos++
+			}
+		case strings.HasPrefix(src[p.pos:], "\uFEFF"):
+			p.pos += len("\uFEFF")
+		default:
+			return p.token()
+		}
+	}
+	p.tok = graphQLToken{kind: graphQLEOF, pos: p.pos}
+	return nil
+}
+
+func (p *graphQLParser) token() error {
+	src, start := p.src, p.pos
+	p.tok = graphQLToken{pos: start}
+	c := src[start]
+	switch {
+	case strings.HasPrefix(src[start:], "..."):
+		p.pos += 3
+		p.tok.kind, p.tok.value = graphQLPunct, "..."
+	case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+		p.pos++
+		p.tok.kind, p.tok.value = graphQLPunct, string(c)
+	case c == '_' || isASCIILetter(c):
+		for p.pos < len(src) && (src[p.pos] == '_' || isASCIILetter(src[p.pos]) || isASCIIDigit(src[p.pos])) {
+			p.pos++
+		}
+		p.tok.kind, p.tok.value = graphQLName, src[start:p.pos]
+	case c == '-' || isASCIIDigit(c):
+		return p.number()
+	case strings.HasPrefix(src[start:], `"""`):
+		return p.blockString()
+	case c == '"':
+		return p.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(src[start:])
+		return p.errorf("unexpected character %q", r)
+	}
+	return nil
+}
+
+func (p *graphQLParser) number() error {
+	src, start := p.src, p.pos
+	digits := func() int {
+		n := 0
+		for p.pos < len(src) && isASCIIDigit(src[p.pos]) {
+			p.pos++
+			n++
+		}
+		return n
+	}
+	kind := graphQLInt
+	if src[p.pos] == '-' {
+		p.pos++
+	}
+	if digits() == 0 {
+		return p.errorf("invalid number")
+	}
+	if p.pos < len(src) && src[p.pos] == '.' {
+		p.pos++
+		kind = graphQLFloat
+		if digits() == 0 {
+			return p.errorf("invalid number")
+		}
+	}
+	if p.pos < len(src) && (src[p.pos] == 'e' || src[p.pos] == 'E') {
+		p.pos++
+		kind = graphQLFloat
+		if p.pos < len(src) && (src[p.pos] == '+' || src[p.pos] == '-') {
+			p.pos++
+		}
+		if digits() == 0 {
+			return p.errorf("invalid number")
+		}
+	}
+	p.tok.kind, p.tok.value = kind, src[start:p.pos]
+	return nil
+}
+
+// string reads a quoted string, decoding its escapes.
+func (p *graphQLParser) string() error {
+	src := p.src
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(src) || src[p.pos] == '\n' || src[p.pos] == '\r' {
+			return p.errorf("unterminated string")
+		}
+		c := src[p.pos]
+		if c == '"' {
+			p.pos++
+			break
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		if p.pos+1 >= len(src) {
+			return p.errorf("unterminated string")
+		}
+		esc := src[p.pos+1]
+		p.pos += 2
+		switch esc {
+		case '"', '\\', '/':
+			b.WriteByte(esc)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r, err := p.unicodeEscape()
+			if err != nil {
+				return err
+			}
+			b.WriteRune(r)
+		default:
+			return p.errorf("invalid escape \\%c", esc)
+		}
+	}
+	p.tok.kind, p.tok.value = graphQLString, b.String()
+	return nil
+}
+
+// unicodeEscape reads the code point of a \u escape, as four hex digits
+// or in braces, combining a surrogate pair into one rune.
+func (p *graphQLParser) unicodeEscape() (rune, error) {
+	src := p.src
+	var hex string
+	if strings.HasPrefix(src[p.pos:], "{") {
+		end := strings.IndexByte(src[p.pos:], '}')
+		if end < 0 {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		hex = src[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else {
+		if p.pos+4 > len(src) {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		hex = src[p.pos : p.pos+4]
+		p.pos += 4
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || n > utf8.MaxRune {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	r := rune(n)
+	if r >= 0xD800 && r < 0xDC00 && strings.HasPrefix(src[p.pos:], `\u`) && p.pos+6 <= len(src) {
+		if low, err := strconv.ParseUint(src[p.pos+2:p.pos+6], 16, 32); err == nil && low >= 0xDC00 && low < 0xE000 {
+			p.pos += 6
+			return (r-0xD800)<<10 + (rune(low) - 0xDC00) + 0x10000, nil
+		}
+	}
+	return r, nil
+}
+
+// blockString reads a """ block string, removing the common indentation
+// and the leading and trailing blank lines as the specification requires.
+func (p *graphQLParser) blockString() error {
+	src := p.src
+	p.pos += 3
+	var raw strings.Builder
+	for {
+		if p.pos >= len(src) {
+			return p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(src[p.pos:], `\"""`) {
+			raw.WriteString(`"""`)
+			p.pos += 4
+			continue
+		}
+		if strings.HasPrefix(src[p.pos:], `"""`) {
+			p.pos += 3
+			break
+		}
+		raw.WriteByte(src[p.pos])
+		p.pos++
+	}
+
+	text := strings.ReplaceAll(raw.String(), "\r\n", "\n")
+	lines := strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if n < len(line) && (indent < 0 || n < indent) {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			lines[i] = lines[i][min(indent, len(lines[i])):]
+		}
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	p.tok.kind, p.tok.value = graphQLString, strings.Join(lines, "\n")
+	return nil
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGraphQLSchema(t *testing.T) {
+	sdl := "\uFEFF" + `
+# Comments and commas are ignored
+directive @auth(role: String = "user") repeatable on FIELD_DEFINITION | OBJECT
+
+schema @auth { query: Root, mutation: Writes }
+
+"""
+    Block strings drop the common indentation,
+
+      keep relative indentation, and unescape \"""quotes\""".
+"""
+type Root implements A & B @auth(role: "admin") {
+  "Escapes: \"é\u{1F600}😀\t"
+  thing(
+    limit: Int = -5
+    ratio: Float = 1.5e2
+    where: Where = {tags: ["a", "b"], flag: true, none: null, kind: BIG}
+  ): [[Thing!]]! @deprecated
+}
+
+union Thing = | One | Two
+enum Kind { SMALL BIG @deprecated(reason: "too big") }
+input Where { tags: [String!], flag: Boolean, none: Int, kind: Kind }
+extend enum Kind { HUGE }
+extend union Thing = Three
+extend input Where @oneOf
+`
+	schema, err := parseGraphQLSchema(sdl)
+	if err != nil {
+		t.Fatalf("parseGraphQLSchema() error = %v", err)
+	}
+
+	if want := map[string]string{"query": "Root", "mutation": "Writes"}; !reflect.DeepEqual(schema.roots, want) {
+		t.Errorf("roots = %v, want %v", schema.roots, want)
+	}
+
+	root := schema.types["Root"]
+	wantDesc := "Block strings drop the common indentation,\n\n  keep relative indentation, and unescape \"\"\"quotes\"\"\"."
+	if root.description != wantDesc {
+		t.Errorf("description =\n%q\nwant\n%q", root.description, wantDesc)
+	}
+
+	thing := root.fields[0]
+	if want := "Escapes: \"é😀😀\t"; thing.description != want {
+		t.Errorf("field description = %q, want %q", thing.description, want)
+	}
+	if got := thing.typ.String(); got != "[[Thing!]]!" {
+		t.Errorf("type = %s, want [[Thing!]]!", got)
+	}
+	if thing.typ.named() != "Thing" || !thing.deprecated || thing.deprecationReason != "" {
+		t.Errorf("field = %+v", thing)
+	}
+	wantDefaults := []any{
+		-5.0,
+		150.0,
+		map[string]any{"tags": []any{"a", "b"}, "flag": true, "none": nil, "kind": "BIG"},
+	}
+	for i, arg := range thing.args {
+		if !arg.hasDef || !reflect.DeepEqual(arg.def, wantDefaults[i]) {
+			t.Errorf("%s default = %#v, want %#v", arg.name, arg.def, wantDefaults[i])
+		}
+	}
+
+	if got := schema.types["Thing"].members; !reflect.DeepEqual(got, []string{"One", "Two", "Three"}) {
+		t.Errorf("union members = %v", got)
+	}
+	var values []string
+	for _, v := range schema.types["Kind"].values {
+		values = append(values, v.name)
+	}
+	if !reflect.DeepEqual(values, []string{"SMALL", "BIG", "HUGE"}) {
+		t.Errorf("enum values = %v", values)
+	}
+	if big := schema.types["Kind"].values[1]; !big.deprecated || big.deprecationReason != "too big" {
+		t.Errorf("BIG = %+v, want deprecated because too big", big)
+	}
+	if !schema.types["Where"].oneOf {
+		t.Error("extension should apply @oneOf")
+	}
+}
+
+func TestParseGraphQLSchema_Errors(t *testing.T) {
+	tests := []struct {
+		sdl  string
+		want string
+	}{
+		{`type A { a: String`, "end of document"},
+		{"type A {\n a: \"x\n\" }", "line 2: unterminated string"},
+		{`type A { "\q" a: Int }`, `invalid escape \q`},
+		{`type A { a(b: Int = 1.): Int }`, "invalid number"},
+		{`type A { a: Int } type A { b: Int }`, `type "A" is defined twice`},
+		{`extend type B { a: Int }`, `extension of undefined type "B"`},
+		{`enum E { X } extend type E { a: Int }`, `extension of enum "E" as type`},
+		{`type A { a: Int % }`, `unexpected character '%'`},
+		{`fragment F on A { a }`, "executable definitions"},
+		{`directive @d on`, "expected a name"},
+	}
+	for _, tt := range tests {
+		_, err := parseGraphQLSchema(tt.sdl)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseGraphQLSchema(%q) error = %v, want it to mention %q", tt.sdl, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// unsupportedKindError explains why an adapter cannot emit a tool of a
+// non-function kind. Hosted tools name their provider and type so callers
+// can tell which built-in has no equivalent in the target format.
+func unsupportedKindError(adapter string, tool *tooladapter.CanonicalTool) error {
+	switch tool.Kind {
+	case tooladapter.ToolKindHosted:
+		if tool.Hosted == nil || tool.Hosted.Type == "" {
+			return errors.New("hosted tool type is required")
+		}
+		provider := tool.Hosted.Provider
+		if provider == "" {
+			provider = "unknown"
+		}
+		return fmt.Errorf("%s hosted tool %q has no %s equivalent", provider, tool.Hosted.Type, adapter)
+	case tooladapter.ToolKindCustom:
+		return fmt.Errorf("custom tools are not supported by the %s adapter", adapter)
+	default:
+		return fmt.Errorf("unsupported tool kind: %q", tool.Kind)
+	}
+}
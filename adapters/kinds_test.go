@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestUnsupportedKindError(t *testing.T) {
+	tests := []struct {
+		name string
+		tool *tooladapter.CanonicalTool
+		want string
+	}{
+		{
+			name: "hosted",
+			tool: &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindHosted, Hosted: &tooladapter.HostedToolConfig{Provider: "anthropic", Type: "bash_20250124"}},
+			want: `anthropic hosted tool "bash_20250124" has no mcp equivalent`,
+		},
+		{
+			name: "hosted without type",
+			tool: &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindHosted},
+			want: "hosted tool type is required",
+		},
+		{
+			name: "custom",
+			tool: &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom},
+			want: "custom tools are not supported by the mcp adapter",
+		},
+		{
+			name: "unknown",
+			tool: &tooladapter.CanonicalTool{Kind: "mystery"},
+			want: `unsupported tool kind: "mystery"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unsupportedKindError("mcp", tt.tool).Error(); got != tt.want {
+				t.Errorf("unsupportedKindError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromCanonical_NonFunctionKindsRejected(t *testing.T) {
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+
+	for _, adapter := range []tooladapter.Adapter{NewMCPAdapter(), NewAnthropicAdapter()} {
+		if _, err := adapter.FromCanonical(custom); err == nil {
+			t.Errorf("%s FromCanonical() error = nil, want error", adapter.Name())
+		}
+	}
+}
+
+func TestFromCanonical_ForeignHostedTools(t *testing.T) {
+	anthropicBash := &tooladapter.CanonicalTool{
+		Kind:   tooladapter.ToolKindHosted,
+		Name:   "bash",
+		Hosted: &tooladapter.HostedToolConfig{Provider: "anthropic", Type: "bash_20250124"},
+	}
+	openaiSearch := &tooladapter.CanonicalTool{
+		Kind:   tooladapter.ToolKindHosted,
+		Name:   "web_search",
+		Hosted: &tooladapter.HostedToolConfig{Provider: "openai", Type: "web_search"},
+	}
+
+	tests := []struct {
+		adapter tooladapter.Adapter
+		tool    *tooladapter.CanonicalTool
+	}{
+		{NewMCPAdapter(), anthropicBash},
+		{NewOpenAIAdapter(), anthropicBash},
+		{NewMCPAdapter(), openaiSearch},
+		{NewAnthropicAdapter(), openaiSearch},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.adapter.FromCanonical(tt.tool)
+		if err == nil {
+			t.Errorf("%s FromCanonical(%s) error = nil, want error", tt.adapter.Name(), tt.tool.Hosted.Type)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.tool.Hosted.Type) {
+			t.Errorf("%s FromCanonical() error = %q, want it to name %q", tt.adapter.Name(), err, tt.tool.Hosted.Type)
+		}
+	}
+}
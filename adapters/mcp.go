@@ -2,13 +2,35 @@ package adapters
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/jonwraymond/tooladapter"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// MCP protocol revisions with built-in capability profiles. They are the
+// modes of MCPAdapter; the default mode ("") emits every field the go-sdk
+// supports.
+const (
+	// MCPProtocol20241105 has no annotations, title, output schema or _meta
+	MCPProtocol20241105 = "2024-11-05"
+
+	// MCPProtocol20250326 adds tool annotations
+	MCPProtocol20250326 = "2025-03-26"
+
+	// MCPProtocol20250618 adds title, outputSchema, _meta and structured content
+	MCPProtocol20250618 = "2025-06-18"
+
+	// MCPProtocol20251125 adds icons
+	MCPProtocol20251125 = "2025-11-25"
+)
+
 // MCPAdapter converts between MCP tool format and canonical format.
-type MCPAdapter struct{}
+type MCPAdapter struct {
+	mode           string
+	renderExamples bool
+	typedSchemas   bool
+}
 
 // NewMCPAdapter creates a new MCP adapter.
 func NewMCPAdapter() *MCPAdapter {
@@ -20,8 +42,40 @@ func (a *MCPAdapter) Name() string {
 	return "mcp"
 }
 
+// ModeOf returns the protocol revision the adapter is bound to, or "" for
+// the latest.
+func (a *MCPAdapter) ModeOf(*tooladapter.CanonicalTool) string {
+	return a.mode
+}
+
+// WithMode returns an MCP adapter bound to a protocol revision (e.g.,
+// MCPProtocol20250326). FromCanonical on the result emits only the fields
+// that revision defines, and ToCanonical records the revision in
+// SourceMeta["protocolVersion"]. Returns an error for unknown revisions.
+func (a *MCPAdapter) WithMode(mode string) (tooladapter.Adapter, error) {
+	if _, ok := builtinProfile(a.Name(), mode); !ok {
+		return nil, fmt.Errorf("mcp adapter: unknown protocol version %q", mode)
+	}
+	copied := *a
+	copied.mode = mode
+	return &copied, nil
+}
+
+// WithExamplesInDescription returns a copy of the adapter that renders tool
+// examples into tool descriptions, since MCP tools have no examples field.
+// ToCanonical on the copy splits rendered examples back out.
+func (a *MCPAdapter) WithExamplesInDescription() *MCPAdapter {
+	copied := *a
+	copied.renderExamples = true
+	return &copied
+}
+
 // ToCanonical converts an MCP tool to canonical format.
-// Accepts mcp.Tool or *mcp.Tool.
+// Accepts mcp.Tool or *mcp.Tool. Schemas may be map[string]any,
+// *jsonschema.Schema or json.RawMessage.
+//
+// Title, icons and _meta keys outside MCPMetaKey are kept in SourceMeta;
+// the MCPMetaKey object populates Category, Tags, Version and Timeout.
 func (a *MCPAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
 	var tool mcp.Tool
 
@@ -43,29 +97,42 @@ func (a *MCPAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
 		SourceFormat: "mcp",
 		SourceMeta:   make(map[string]any),
 	}
+	if a.mode != "" {
+		canonical.SourceMeta["protocolVersion"] = a.mode
+	}
+
+	if a.renderExamples {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(tool.Description)
+	}
+
+	if tool.Annotations != nil {
+		canonical.Annotations = annotationsFromMCP(tool.Annotations)
+	}
 
 	// Store MCP-specific fields in SourceMeta for round-trip
 	if tool.Title != "" {
 		canonical.SourceMeta["title"] = tool.Title
 	}
+	if len(tool.Icons) > 0 {
+		canonical.SourceMeta["icons"] = copyIcons(tool.Icons)
+	}
+	if err := metaToCanonical(tool.Meta, canonical); err != nil {
+		return nil, err
+	}
 
 	// Convert input schema
-	if tool.InputSchema != nil {
-		schema, err := mapToJSONSchema(tool.InputSchema)
-		if err != nil {
-			return nil, err
-		}
-		canonical.InputSchema = schema
+	inputSchema, err := schemaFromMCP(tool.InputSchema)
+	if err != nil {
+		return nil, err
 	}
+	canonical.InputSchema = inputSchema
 
 	// Convert output schema
-	if tool.OutputSchema != nil {
-		schema, err := mapToJSONSchema(tool.OutputSchema)
-		if err != nil {
-			return nil, err
-		}
-		canonical.OutputSchema = schema
+	outputSchema, err := schemaFromMCP(tool.OutputSchema)
+	if err != nil {
+		return nil, err
 	}
+	canonical.OutputSchema = outputSchema
 
 	return canonical, nil
 }
@@ -75,39 +142,166 @@ func (a *MCPAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error)
 	if tool == nil {
 		return nil, errors.New("nil CanonicalTool")
 	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
 
 	mcpTool := mcp.Tool{
 		Name:        tool.Name,
 		Description: tool.Description,
 	}
+	if a.renderExamples {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
+		}
+		mcpTool.Description = tooladapter.RenderExamples(tool.Description, tool.Examples)
+	}
 
-	// Restore MCP-specific fields from SourceMeta
+	// Restore MCP-specific fields from SourceMeta, as far as the protocol
+	// revision allows
 	if tool.SourceMeta != nil {
-		if title, ok := tool.SourceMeta["title"].(string); ok {
+		if title, ok := tool.SourceMeta["title"].(string); ok && a.SupportsFeature(tooladapter.FeatureTitle) {
 			mcpTool.Title = title
 		}
+		if a.SupportsFeature(tooladapter.FeatureIcons) {
+			icons, err := iconsFromMeta(tool.SourceMeta)
+			if err != nil {
+				return nil, err
+			}
+			mcpTool.Icons = icons
+		}
+	}
+
+	if a.SupportsFeature(tooladapter.FeatureMetadata) {
+		meta, err := metaFromCanonical(tool)
+		if err != nil {
+			return nil, err
+		}
+		mcpTool.Meta = meta
+	}
+
+	if hasMCPAnnotations(tool.Annotations) && a.SupportsFeature(tooladapter.FeatureAnnotations) {
+		mcpTool.Annotations = annotationsToMCP(tool.Annotations)
 	}
 
-	// Convert input schema to map
+	// Convert input schema
 	if tool.InputSchema != nil {
-		mcpTool.InputSchema = tool.InputSchema.ToMap()
+		schema, err := a.schemaToMCP(tool.InputSchema)
+		if err != nil {
+			return nil, err
+		}
+		mcpTool.InputSchema = schema
 	}
 
-	// Convert output schema to map
-	if tool.OutputSchema != nil {
-		mcpTool.OutputSchema = tool.OutputSchema.ToMap()
+	// Convert output schema
+	if tool.OutputSchema != nil && a.SupportsFeature(tooladapter.FeatureOutputSchema) {
+		schema, err := a.schemaToMCP(tool.OutputSchema)
+		if err != nil {
+			return nil, err
+		}
+		mcpTool.OutputSchema = schema
 	}
 
 	return mcpTool, nil
 }
 
-// SupportsFeature returns true for all features since MCP supports the full
-// JSON Schema specification.
+// SupportsFeature returns whether this adapter supports a schema feature.
+// Every JSON Schema feature is supported; tool-level features depend on the
+// protocol revision the adapter is bound to.
 func (a *MCPAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
-	return true
+	return supportsBuiltin(a.Name(), a.mode, feature)
+}
+
+// Profile returns the built-in capability profile for a mode. Modes are
+// "" (latest) and the MCPProtocol revisions.
+func (a *MCPAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	return builtinProfile(a.Name(), mode)
+}
+
+// ToolFeatures reports the tool-level features stored in SourceMeta by
+// ToCanonical: title, icons and _meta.
+func (a *MCPAdapter) ToolFeatures(tool *tooladapter.CanonicalTool) []tooladapter.SchemaFeature {
+	var features []tooladapter.SchemaFeature
+	if title, _ := tool.SourceMeta["title"].(string); title != "" {
+		features = append(features, tooladapter.FeatureTitle)
+	}
+	if tool.SourceMeta["icons"] != nil {
+		features = append(features, tooladapter.FeatureIcons)
+	}
+	if tool.SourceMeta["_meta"] != nil {
+		features = append(features, tooladapter.FeatureMetadata)
+	}
+	return features
+}
+
+// annotationsFromMCP copies MCP tool annotations into canonical form.
+func annotationsFromMCP(a *mcp.ToolAnnotations) *tooladapter.ToolAnnotations {
+	return &tooladapter.ToolAnnotations{
+		Title:           a.Title,
+		ReadOnlyHint:    a.ReadOnlyHint,
+		DestructiveHint: copyBool(a.DestructiveHint),
+		IdempotentHint:  a.IdempotentHint,
+		OpenWorldHint:   copyBool(a.OpenWorldHint),
+	}
 }
 
-// mapToJSONSchema converts a map[string]any schema to JSONSchema.
+// hasMCPAnnotations reports whether annotations have an MCP form: they are
+// set and hold more than Deprecated, which is carried in _meta instead.
+func hasMCPAnnotations(a *tooladapter.ToolAnnotations) bool {
+	return a != nil && *a != (tooladapter.ToolAnnotations{Deprecated: true})
+}
+
+// annotationsToMCP copies canonical annotations into MCP form.
+func annotationsToMCP(a *tooladapter.ToolAnnotations) *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{
+		Title:           a.Title,
+		ReadOnlyHint:    a.ReadOnlyHint,
+		DestructiveHint: copyBool(a.DestructiveHint),
+		IdempotentHint:  a.IdempotentHint,
+		OpenWorldHint:   copyBool(a.OpenWorldHint),
+	}
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	v := *b
+	return &v
+}
+
+// schemaKeywords are the keywords JSONSchema has a field for. Others are
+// kept in JSONSchema.Extra.
+var schemaKeywords = map[string]bool{
+	"type": true, "description": true, "pattern": true, "format": true,
+	"$ref": true, "minimum": true, "maximum": true, "minLength": true,
+	"maxLength": true, "minItems": true, "maxItems": true, "const": true,
+	"default": true, "additionalProperties": true, "enum": true,
+	"required": true, "properties": true, "items": true, "$defs": true,
+	"anyOf": true, "oneOf": true, "allOf": true, "not": true,
+}
+
+// schemaExtra returns the keywords of a schema map that JSONSchema has no
+// field for, or nil. An additionalProperties schema other than true or
+// false is included, since only the boolean form is modelled.
+func schemaExtra(m map[string]any) map[string]any {
+	var extra map[string]any
+	for k, v := range m {
+		if schemaKeywords[k] {
+			if _, ok := v.(bool); k != "additionalProperties" || ok {
+				continue
+			}
+		}
+		if extra == nil {
+			extra = make(map[string]any)
+		}
+		extra[k] = v
+	}
+	return extra
+}
+
+// mapToJSONSchema converts a map[string]any schema to JSONSchema. Keywords
+// without a JSONSchema field are kept in Extra.
 func mapToJSONSchema(raw any) (*tooladapter.JSONSchema, error) {
 	m, ok := raw.(map[string]any)
 	if !ok {
@@ -116,9 +310,19 @@ func mapToJSONSchema(raw any) (*tooladapter.JSONSchema, error) {
 
 	schema := &tooladapter.JSONSchema{}
 
-	// Type
-	if v, ok := m["type"].(string); ok {
+	// Type (a type list is expanded after the other keywords are read)
+	var types []string
+	switch v := m["type"].(type) {
+	case string:
 		schema.Type = v
+	case []any:
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	case []string:
+		types = v
 	}
 
 	// Description
@@ -173,6 +377,22 @@ func mapToJSONSchema(raw any) (*tooladapter.JSONSchema, error) {
 		schema.MaxLength = &v
 	}
 
+	// MinItems
+	if v, ok := m["minItems"].(float64); ok {
+		i := int(v)
+		schema.MinItems = &i
+	} else if v, ok := m["minItems"].(int); ok {
+		schema.MinItems = &v
+	}
+
+	// MaxItems
+	if v, ok := m["maxItems"].(float64); ok {
+		i := int(v)
+		schema.MaxItems = &i
+	} else if v, ok := m["maxItems"].(int); ok {
+		schema.MaxItems = &v
+	}
+
 	// Const
 	if v, ok := m["const"]; ok {
 		schema.Const = v
@@ -189,18 +409,28 @@ func mapToJSONSchema(raw any) (*tooladapter.JSONSchema, error) {
 	}
 
 	// Enum
-	if v, ok := m["enum"].([]any); ok {
+	switch v := m["enum"].(type) {
+	case []any:
 		schema.Enum = v
+	case []string:
+		schema.Enum = make([]any, len(v))
+		for i, e := range v {
+			schema.Enum[i] = e
+		}
 	}
 
-	// Required
-	if v, ok := m["required"].([]any); ok {
+	// Required (decoded JSON yields []any, ToMap yields []string)
+	switch v := m["required"].(type) {
+	case []any:
 		schema.Required = make([]string, 0, len(v))
 		for _, r := range v {
 			if s, ok := r.(string); ok {
 				schema.Required = append(schema.Required, s)
 			}
 		}
+	case []string:
+		schema.Required = make([]string, len(v))
+		copy(schema.Required, v)
 	}
 
 	// Properties
@@ -281,5 +511,10 @@ func mapToJSONSchema(raw any) (*tooladapter.JSONSchema, error) {
 		schema.Not = notSchema
 	}
 
+	if len(types) > 0 {
+		applyTypeList(schema, types)
+	}
+	schema.Extra = schemaExtra(m)
+
 	return schema, nil
 }
@@ -0,0 +1,468 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// elicitationFormats lists the string formats an MCP elicitation schema
+// may use.
+var elicitationFormats = map[string]bool{
+	"email":     true,
+	"uri":       true,
+	"date":      true,
+	"date-time": true,
+}
+
+// CompileElicitationSchema rewrites a canonical object schema into an MCP
+// elicitation requestedSchema, which only allows a flat object of string,
+// number, integer and boolean properties.
+//
+// Nested object properties are flattened into top-level properties with
+// dotted names ("address.city"), required only if every enclosing property
+// is required. Local $ref values are resolved against the root $defs.
+// Nullable schemas lose their null branch. Enums and consts become string
+// enums, with non-string values encoded as JSON text, and a oneOf or anyOf
+// whose branches are all consts becomes an enum whose enumNames are the
+// branch descriptions. Properties that cannot be expressed, such as arrays,
+// free-form objects and other combinators, are removed, and keywords or
+// formats the form does not accept are dropped. Each removal is reported as
+// an issue.
+//
+// The input schema is not modified. Returns nil if schema is nil or not an
+// object schema. Use ExpandElicitationContent to turn the submitted form
+// back into arguments for the original schema.
+func CompileElicitationSchema(schema *tooladapter.JSONSchema) (map[string]any, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+	c := compileElicitation(schema)
+	return c.form(), c.issues
+}
+
+// compileElicitation runs the compiler of CompileElicitationSchema over a
+// non-nil schema.
+func compileElicitation(schema *tooladapter.JSONSchema) *elicitCompiler {
+	c := &elicitCompiler{root: schema, names: make(map[string]bool)}
+	if schema.Type != "object" && len(schema.Properties) == 0 {
+		c.issue("", "type", "root schema must be an object")
+		c.failed = true
+		return c
+	}
+	c.compileObject(schema, "", "", nil, true)
+	return c
+}
+
+// form returns the requestedSchema built from the compiled fields, or nil
+// if the root schema was not an object.
+func (c *elicitCompiler) form() map[string]any {
+	if c.failed {
+		return nil
+	}
+
+	props := make(map[string]any, len(c.fields))
+	var required []string
+	for _, f := range c.fields {
+		props[f.name] = f.schema
+		if f.required {
+			required = append(required, f.name)
+		}
+	}
+	out := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+// ExpandElicitationContent turns the content of an accepted elicitation
+// result, for a form compiled from schema by CompileElicitationSchema, back
+// into arguments for schema. Dotted properties are re-nested, and enum
+// answers are converted back to their original values. Content keys that
+// are not part of the form are ignored.
+//
+// The content map is not modified.
+func ExpandElicitationContent(schema *tooladapter.JSONSchema, content map[string]any) map[string]any {
+	if schema == nil || content == nil {
+		return nil
+	}
+	c := &elicitCompiler{root: schema, names: make(map[string]bool)}
+	c.compileObject(schema, "", "", nil, true)
+
+	args := make(map[string]any)
+	for _, f := range c.fields {
+		v, ok := content[f.name]
+		if !ok {
+			continue
+		}
+		if f.enum != nil {
+			if s, ok := v.(string); ok {
+				for _, orig := range f.enum {
+					if enumLabel(orig) == s {
+						v = orig
+						break
+					}
+				}
+			}
+		}
+		setPath(args, f.path, v)
+	}
+	return args
+}
+
+// elicitField is one top-level property of a compiled elicitation form.
+type elicitField struct {
+	// name is the flattened property name
+	name string
+
+	// path lists the property names from the root of the original schema
+	path []string
+
+	// enum holds the original enum values when they were not all strings
+	enum []any
+
+	required bool
+	schema   map[string]any
+}
+
+// elicitCompiler collects fields and issues through a single compilation.
+type elicitCompiler struct {
+	root   *tooladapter.JSONSchema
+	fields []elicitField
+	names  map[string]bool
+	issues []tooladapter.SchemaIssue
+
+	// lost holds the issues that removed a required field
+	lost []tooladapter.SchemaIssue
+
+	// failed is set if the root schema is not an object
+	failed bool
+
+	// visiting holds the $ref values being expanded, to stop recursion
+	visiting []string
+}
+
+func (c *elicitCompiler) issue(path, keyword, message string) {
+	c.issues = append(c.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// remove records an issue that removes a field, which is required if the
+// field is.
+func (c *elicitCompiler) remove(path, keyword, message string, required bool) {
+	c.issue(path, keyword, message)
+	if required {
+		c.lost = append(c.lost, c.issues[len(c.issues)-1])
+	}
+}
+
+// compileObject adds a field for every property of an object schema, in
+// name order. required reports whether the object itself is always present.
+func (c *elicitCompiler) compileObject(s *tooladapter.JSONSchema, path, prefix string, propPath []string, required bool) {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.compileProperty(
+			s.Properties[name],
+			path+"/properties/"+escapePointer(name),
+			prefix+name,
+			append(slices.Clone(propPath), name),
+			required && slices.Contains(s.Required, name),
+		)
+	}
+}
+
+// compileProperty adds the field, or fields for a nested object, for one
+// property schema.
+func (c *elicitCompiler) compileProperty(s *tooladapter.JSONSchema, path, name string, propPath []string, required bool) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		if slices.Contains(c.visiting, s.Ref) {
+			c.remove(path, "$ref", "removed; recursive references cannot be flattened", required)
+			return
+		}
+		target := c.resolveRef(s.Ref)
+		if target == nil {
+			c.remove(path, "$ref", fmt.Sprintf("removed; cannot resolve %q", s.Ref), required)
+			return
+		}
+		resolved := *target
+		if s.Description != "" {
+			resolved.Description = s.Description
+		}
+		c.visiting = append(c.visiting, s.Ref)
+		defer func() { c.visiting = c.visiting[:len(c.visiting)-1] }()
+		c.compileProperty(&resolved, path, name, propPath, required)
+		return
+	}
+
+	s = stripNullBranch(s)
+	if s.Not != nil {
+		c.issue(path, "not", "dropped; negation is not supported")
+	}
+	if len(s.AllOf) > 0 {
+		c.remove(path, "allOf", "removed; combinators are not supported", required)
+		return
+	}
+	if enum, names, ok := constChoices(s); ok {
+		c.addEnum(s, path, name, propPath, required, enum, names)
+		return
+	}
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		keyword := "anyOf"
+		if len(s.OneOf) > 0 {
+			keyword = "oneOf"
+		}
+		c.remove(path, keyword, "removed; only choices between constants are supported", required)
+		return
+	}
+
+	switch {
+	case s.Type == "object" || len(s.Properties) > 0:
+		if len(s.Properties) == 0 {
+			c.remove(path, "type", "removed; objects without properties cannot be flattened", required)
+			return
+		}
+		c.compileObject(s, path, name+".", propPath, required)
+	case s.Enum != nil:
+		c.addEnum(s, path, name, propPath, required, s.Enum, nil)
+	case s.Const != nil:
+		c.addEnum(s, path, name, propPath, required, []any{s.Const}, nil)
+	case s.Type == "string", s.Type == "number", s.Type == "integer", s.Type == "boolean":
+		c.addPrimitive(s, path, name, propPath, required)
+	case s.Type == "":
+		c.remove(path, "type", "removed; untyped values are not supported", required)
+	default:
+		c.remove(path, "type", fmt.Sprintf("removed; type %q is not supported", s.Type), required)
+	}
+}
+
+// addPrimitive adds a string, number, integer or boolean field.
+func (c *elicitCompiler) addPrimitive(s *tooladapter.JSONSchema, path, name string, propPath []string, required bool) {
+	m := map[string]any{"type": s.Type}
+	if s.Description != "" {
+		m["description"] = s.Description
+	}
+
+	switch s.Type {
+	case "string":
+		if s.MinLength != nil {
+			m["minLength"] = *s.MinLength
+		}
+		if s.MaxLength != nil {
+			m["maxLength"] = *s.MaxLength
+		}
+		if s.Format != "" {
+			if elicitationFormats[s.Format] {
+				m["format"] = s.Format
+			} else {
+				c.issue(path, "format", fmt.Sprintf("dropped; format %q is not supported", s.Format))
+			}
+		}
+		if s.Pattern != "" {
+			c.issue(path, "pattern", "dropped; not supported")
+		}
+	case "number", "integer":
+		if s.Minimum != nil {
+			m["minimum"] = *s.Minimum
+		}
+		if s.Maximum != nil {
+			m["maximum"] = *s.Maximum
+		}
+	}
+
+	if s.Default != nil {
+		if defaultMatches(s.Type, s.Default) {
+			m["default"] = s.Default
+		} else {
+			c.issue(path, "default", fmt.Sprintf("dropped; not a valid %s", s.Type))
+		}
+	}
+	c.add(elicitField{name: name, path: propPath, required: required, schema: m}, path)
+}
+
+// addEnum adds a string enum field. names, if given, are display names
+// for the values.
+func (c *elicitCompiler) addEnum(s *tooladapter.JSONSchema, path, name string, propPath []string, required bool, values []any, names []string) {
+	labels := make([]any, 0, len(values))
+	var kept []any
+	var keptNames []any
+	converted := false
+	for i, v := range values {
+		if v == nil {
+			continue // the null choice of a nullable enum
+		}
+		if _, ok := v.(string); !ok {
+			converted = true
+		}
+		labels = append(labels, enumLabel(v))
+		kept = append(kept, v)
+		if names != nil {
+			keptNames = append(keptNames, names[i])
+		}
+	}
+	if len(labels) == 0 {
+		c.remove(path, "enum", "removed; no non-null values", required)
+		return
+	}
+
+	m := map[string]any{"type": "string", "enum": labels}
+	if keptNames != nil {
+		m["enumNames"] = keptNames
+	}
+	if s.Description != "" {
+		m["description"] = s.Description
+	}
+	if s.Default != nil {
+		if slices.Contains(labels, any(enumLabel(s.Default))) {
+			m["default"] = enumLabel(s.Default)
+		} else {
+			c.issue(path, "default", "dropped; not one of the enum values")
+		}
+	}
+
+	f := elicitField{name: name, path: propPath, required: required, schema: m}
+	if converted {
+		f.enum = kept
+	}
+	c.add(f, path)
+}
+
+func (c *elicitCompiler) add(f elicitField, path string) {
+	if c.names[f.name] {
+		c.remove(path, "properties", fmt.Sprintf("removed; flattened name %q is already used", f.name), f.required)
+		return
+	}
+	c.names[f.name] = true
+	c.fields = append(c.fields, f)
+}
+
+// resolveRef returns the root $defs entry a local reference points to.
+func (c *elicitCompiler) resolveRef(ref string) *tooladapter.JSONSchema {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	return c.root.Defs[name]
+}
+
+// stripNullBranch returns the non-null branch of an anyOf or oneOf with
+// exactly one null branch, or s itself.
+func stripNullBranch(s *tooladapter.JSONSchema) *tooladapter.JSONSchema {
+	for _, branches := range [][]*tooladapter.JSONSchema{s.AnyOf, s.OneOf} {
+		if len(branches) != 2 {
+			continue
+		}
+		for i, b := range branches {
+			if b != nil && b.Type == "null" && branches[1-i] != nil {
+				inner := *branches[1-i]
+				if inner.Description == "" {
+					inner.Description = s.Description
+				}
+				if inner.Default == nil {
+					inner.Default = s.Default
+				}
+				return &inner
+			}
+		}
+	}
+	return s
+}
+
+// constChoices returns the values and descriptions of an anyOf or oneOf
+// whose branches are all consts. names is nil unless every branch has a
+// description.
+func constChoices(s *tooladapter.JSONSchema) (values []any, names []string, ok bool) {
+	branches := s.OneOf
+	if len(branches) == 0 {
+		branches = s.AnyOf
+	}
+	if len(branches) == 0 {
+		return nil, nil, false
+	}
+	named := true
+	for _, b := range branches {
+		if b == nil || b.Const == nil {
+			return nil, nil, false
+		}
+		values = append(values, b.Const)
+		names = append(names, b.Description)
+		named = named && b.Description != ""
+	}
+	if !named {
+		names = nil
+	}
+	return values, names, true
+}
+
+// enumLabel returns the string used for an enum value in a form: strings
+// as is and other values as JSON text.
+func enumLabel(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// defaultMatches reports whether a default value has the given JSON type.
+func defaultMatches(typ string, v any) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number", "integer":
+		var f float64
+		switch n := v.(type) {
+		case float64:
+			f = n
+		case float32:
+			f = float64(n)
+		case int:
+			f = float64(n)
+		case int64:
+			f = float64(n)
+		case json.Number:
+			var err error
+			if f, err = n.Float64(); err != nil {
+				return false
+			}
+		default:
+			return false
+		}
+		return typ == "number" || f == math.Trunc(f)
+	}
+	return false
+}
+
+// setPath stores v in m at the nested property path, creating objects as
+// needed.
+func setPath(m map[string]any, path []string, v any) {
+	for _, name := range path[:len(path)-1] {
+		next, ok := m[name].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[name] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
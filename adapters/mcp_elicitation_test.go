@@ -0,0 +1,298 @@
+package adapters
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func bookingSchema() *tooladapter.JSONSchema {
+	minLen := 1
+	return &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"guest": {Ref: "#/$defs/Person", Description: "Who is staying"},
+			"nights": {
+				AnyOf: []*tooladapter.JSONSchema{{Type: "integer"}, {Type: "null"}},
+			},
+			"room": {
+				Description: "Room type",
+				OneOf: []*tooladapter.JSONSchema{
+					{Const: "std", Description: "Standard"},
+					{Const: "ste", Description: "Suite"},
+				},
+			},
+			"floor": {Type: "integer", Enum: []any{1, 2, 3}, Default: 2},
+			"code":  {Type: "string", MinLength: &minLen, Pattern: "^[A-Z]+$", Format: "uuid"},
+			"tags":  {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}},
+			"notes": {Type: "object"},
+		},
+		Required: []string{"guest", "room"},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Person": {
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"email":  {Type: "string", Format: "email"},
+					"name":   {Type: "string"},
+					"friend": {Ref: "#/$defs/Person"},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func TestCompileElicitationSchema(t *testing.T) {
+	schema := bookingSchema()
+	got, issues := CompileElicitationSchema(schema)
+
+	props := got["properties"].(map[string]any)
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	if len(names) != 6 {
+		t.Errorf("properties = %v, want 6", names)
+	}
+	if want := []string{"guest.name", "room"}; !reflect.DeepEqual(got["required"], want) {
+		t.Errorf("required = %v, want %v", got["required"], want)
+	}
+
+	tests := map[string]map[string]any{
+		"guest.email": {"type": "string", "format": "email"},
+		"guest.name":  {"type": "string"},
+		"nights":      {"type": "integer"},
+		"room":        {"type": "string", "description": "Room type", "enum": []any{"std", "ste"}, "enumNames": []any{"Standard", "Suite"}},
+		"floor":       {"type": "string", "enum": []any{"1", "2", "3"}, "default": "2"},
+		"code":        {"type": "string", "minLength": 1},
+	}
+	for name, want := range tests {
+		if !reflect.DeepEqual(props[name], want) {
+			t.Errorf("%s = %v, want %v", name, props[name], want)
+		}
+	}
+
+	var gotIssues []string
+	for _, issue := range issues {
+		gotIssues = append(gotIssues, issue.Keyword+" "+issue.Path)
+	}
+	wantIssues := []string{
+		"format /properties/code",
+		"pattern /properties/code",
+		"$ref /properties/guest/properties/friend",
+		"type /properties/notes",
+		"type /properties/tags",
+	}
+	if !reflect.DeepEqual(gotIssues, wantIssues) {
+		t.Errorf("issues = %v, want %v", gotIssues, wantIssues)
+	}
+
+	// The input must not be modified
+	if !reflect.DeepEqual(schema, bookingSchema()) {
+		t.Error("CompileElicitationSchema() modified its input")
+	}
+}
+
+func TestCompileElicitationSchema_Rejects(t *testing.T) {
+	tests := map[string]struct {
+		schema  *tooladapter.JSONSchema
+		keyword string
+	}{
+		"non-object root": {
+			schema:  &tooladapter.JSONSchema{Type: "string"},
+			keyword: "type",
+		},
+		"mixed anyOf": {
+			schema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{
+				"v": {AnyOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			}},
+			keyword: "anyOf",
+		},
+		"external ref": {
+			schema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{
+				"v": {Ref: "https://example.com/schema"},
+			}},
+			keyword: "$ref",
+		},
+		"bad default": {
+			schema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{
+				"v": {Type: "integer", Default: 1.5},
+			}},
+			keyword: "default",
+		},
+		"name collision": {
+			schema: &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{
+				"a.b": {Type: "string"},
+				"a":   {Type: "object", Properties: map[string]*tooladapter.JSONSchema{"b": {Type: "string"}}},
+			}},
+			keyword: "properties",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, issues := CompileElicitationSchema(tt.schema)
+			if len(issues) != 1 || issues[0].Keyword != tt.keyword {
+				t.Errorf("issues = %v, want one %s issue", issues, tt.keyword)
+			}
+		})
+	}
+}
+
+func TestExpandElicitationContent(t *testing.T) {
+	content := map[string]any{
+		"guest.name": "Ada",
+		"room":       "ste",
+		"floor":      "3",
+		"unknown":    true,
+	}
+
+	got := ExpandElicitationContent(bookingSchema(), content)
+	want := map[string]any{
+		"guest": map[string]any{"name": "Ada"},
+		"room":  "ste",
+		"floor": 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandElicitationContent() = %v, want %v", got, want)
+	}
+	if issues := bookingSchema().ValidateValue(got); len(issues) != 0 {
+		t.Errorf("expanded arguments are invalid: %v", issues)
+	}
+}
+
+func TestMCPServer_ElicitMissing(t *testing.T) {
+	s := newTestMCPServer()
+	s.SetElicitMissing(true)
+	tool := &tooladapter.CanonicalTool{Name: "book", InputSchema: bookingSchema()}
+
+	var called map[string]any
+	if err := s.AddTool(tool, func(_ context.Context, _ *mcp.CallToolRequest, args map[string]any) (any, error) {
+		called = args
+		return "booked", nil
+	}); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	action := "accept"
+	var requested *mcp.ElicitParams
+	session := connectRawMCPServer(t, s.Server(), &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			requested = req.Params
+			if action != "accept" {
+				return &mcp.ElicitResult{Action: action}, nil
+			}
+			return &mcp.ElicitResult{Action: action, Content: map[string]any{"guest.name": "Ada"}}, nil
+		},
+	})
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "book",
+		Arguments: map[string]any{"room": "std"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("CallTool() returned an error result: %v", res.Content)
+	}
+	// Only the missing argument is requested
+	props := requested.RequestedSchema.(map[string]any)["properties"].(map[string]any)
+	if _, ok := props["room"]; ok || props["guest.name"] == nil {
+		t.Errorf("requested properties = %v, want guest fields only", props)
+	}
+	if want := map[string]any{"room": "std", "guest": map[string]any{"name": "Ada"}}; !reflect.DeepEqual(called, want) {
+		t.Errorf("handler args = %v, want %v", called, want)
+	}
+
+	action = "decline"
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "book",
+		Arguments: map[string]any{"room": "std"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "decline") {
+		t.Errorf("declined CallTool() = %+v, want an error result", res)
+	}
+}
+
+func TestMCPServer_ElicitMissing_UnsupportedClient(t *testing.T) {
+	s := newTestMCPServer()
+	s.SetElicitMissing(true)
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return nil, nil }
+	if err := s.AddTool(weatherTool(), handler); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	session := connectMCPServer(t, s)
+	_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_weather", Arguments: map[string]any{}})
+	if err == nil || !strings.Contains(err.Error(), "invalid arguments") {
+		t.Errorf("CallTool() error = %v, want invalid arguments", err)
+	}
+}
+
+func TestMCPServer_ElicitMissing_Unrepresentable(t *testing.T) {
+	s := newTestMCPServer()
+	tool := &tooladapter.CanonicalTool{Name: "book", InputSchema: bookingSchema()}
+	tool.InputSchema.Required = append(tool.InputSchema.Required, "tags")
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return "booked", nil }
+	if err := s.AddTool(tool, handler); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	// Enabled after AddTool, which applies to the tool's next call
+	s.SetElicitMissing(true)
+
+	elicited := false
+	session := connectRawMCPServer(t, s.Server(), &mcp.ClientOptions{
+		ElicitationHandler: func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			elicited = true
+			return &mcp.ElicitResult{Action: "cancel"}, nil
+		},
+	})
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "book",
+		Arguments: map[string]any{"room": "std"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if elicited {
+		t.Error("an incomplete form was elicited")
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "type at /properties/tags") {
+		t.Errorf("CallTool() = %+v, want an error result naming tags", res)
+	}
+}
+
+func TestMCPServer_SetElicitMissing_Concurrent(t *testing.T) {
+	s := newTestMCPServer()
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return "ok", nil }
+	if err := s.AddTool(weatherTool(), handler); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	session := connectMCPServer(t, s)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 50 {
+			s.SetElicitMissing(i%2 == 0)
+		}
+	}()
+	for range 10 {
+		if _, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "get_weather",
+			Arguments: map[string]any{"city": "Paris"},
+		}); err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+	}
+	<-done
+}
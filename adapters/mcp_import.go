@@ -0,0 +1,319 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ImportMCPTools lists every tool offered by a connected MCP server,
+// following tools/list pagination cursors, and converts each to canonical
+// form. The server name from the initialize result becomes each tool's
+// Namespace, and the negotiated protocol version is recorded in
+// SourceMeta["protocolVersion"]. Tools are returned in server order.
+func ImportMCPTools(ctx context.Context, session *mcp.ClientSession) ([]*tooladapter.CanonicalTool, error) {
+	imported, err := listMCPTools(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]*tooladapter.CanonicalTool, len(imported))
+	for i, t := range imported {
+		tools[i] = t.tool
+	}
+	return tools, nil
+}
+
+// importedTool pairs a canonical tool with the JSON encoding of the
+// mcp.Tool it was read from, which is used to detect changes.
+type importedTool struct {
+	tool *tooladapter.CanonicalTool
+	raw  []byte
+}
+
+func listMCPTools(ctx context.Context, session *mcp.ClientSession) ([]importedTool, error) {
+	if session == nil {
+		return nil, errors.New("nil mcp.ClientSession")
+	}
+	namespace, version := mcpServerInfo(session)
+	adapter := NewMCPAdapter()
+	if bound, err := adapter.WithMode(version); err == nil {
+		adapter = bound.(*MCPAdapter)
+	}
+
+	var tools []importedTool
+	params := &mcp.ListToolsParams{}
+	for {
+		res, err := session.ListTools(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("list tools: %w", err)
+		}
+		for _, t := range res.Tools {
+			if t == nil {
+				continue
+			}
+			canonical, err := adapter.ToCanonical(t)
+			if err != nil {
+				return nil, &tooladapter.ConversionError{
+					Adapter:   adapter.Name(),
+					Direction: "to_canonical",
+					Cause:     fmt.Errorf("tool %q: %w", t.Name, err),
+				}
+			}
+			canonical.Namespace = namespace
+			if version != "" {
+				canonical.SourceMeta["protocolVersion"] = version
+			}
+			raw, err := json.Marshal(t)
+			if err != nil {
+				return nil, fmt.Errorf("tool %q: %w", t.Name, err)
+			}
+			tools = append(tools, importedTool{tool: canonical, raw: raw})
+		}
+		if res.NextCursor == "" {
+			return tools, nil
+		}
+		params = &mcp.ListToolsParams{Cursor: res.NextCursor}
+	}
+}
+
+// mcpServerInfo returns the server name and protocol version negotiated
+// during initialization.
+func mcpServerInfo(session *mcp.ClientSession) (name, version string) {
+	res := session.InitializeResult()
+	if res == nil {
+		return "", ""
+	}
+	if res.ServerInfo != nil {
+		name = res.ServerInfo.Name
+	}
+	return name, res.ProtocolVersion
+}
+
+// MCPToolEventType identifies how a server's tool list changed.
+type MCPToolEventType string
+
+const (
+	// MCPToolAdded reports a tool that was not listed before.
+	MCPToolAdded MCPToolEventType = "added"
+
+	// MCPToolRemoved reports a tool that is no longer listed.
+	MCPToolRemoved MCPToolEventType = "removed"
+
+	// MCPToolChanged reports a listed tool whose definition changed.
+	MCPToolChanged MCPToolEventType = "changed"
+)
+
+// MCPToolEvent describes one change to a server's tool list.
+type MCPToolEvent struct {
+	// Type is the kind of change
+	Type MCPToolEventType
+
+	// Tool is the current tool, or the last known tool if it was removed
+	Tool *tooladapter.CanonicalTool
+
+	// Diffs lists the canonical differences for a changed tool. It may be
+	// empty if only MCP-specific fields, such as the title, changed.
+	Diffs []tooladapter.FieldDiff
+}
+
+// MCPToolWatcher keeps a canonical copy of one MCP server's tools and
+// reports incremental changes. Sync fetches the list on demand; Handler
+// returns a ToolListChangedHandler that resyncs whenever the server sends
+// notifications/tools/list_changed:
+//
+//	w := adapters.NewMCPToolWatcher(onEvent, onError)
+//	client := mcp.NewClient(impl, &mcp.ClientOptions{ToolListChangedHandler: w.Handler()})
+//	session, _ := client.Connect(ctx, transport, nil)
+//	_ = w.Sync(ctx, session)
+//
+// A watcher tracks a single session. Events are delivered one at a time, in
+// the order the syncs that found them completed and in tool ID order within
+// each sync. No lock is held while listing tools or delivering events, so
+// onEvent may call Tools or Sync. MCPToolWatcher is safe for concurrent use.
+type MCPToolWatcher struct {
+	onEvent func(MCPToolEvent)
+	onError func(error)
+
+	mu    sync.Mutex
+	tools map[string]importedTool
+
+	// started and applied number syncs, so that a sync that finishes after
+	// a later-started one does not replace its newer list
+	started uint64
+	applied uint64
+
+	// pending holds events not yet delivered; delivering is set while a
+	// goroutine is delivering them
+	pending    []MCPToolEvent
+	delivering bool
+
+	// changed holds the session of a list_changed notification not yet
+	// synced; listening is set while the Handler worker runs
+	changed    *mcp.ClientSession
+	changedCtx context.Context
+	listening  bool
+}
+
+// NewMCPToolWatcher creates a watcher that passes changes to onEvent.
+// onError, which may be nil, receives errors from syncs started by Handler.
+func NewMCPToolWatcher(onEvent func(MCPToolEvent), onError func(error)) *MCPToolWatcher {
+	return &MCPToolWatcher{
+		onEvent: onEvent,
+		onError: onError,
+		tools:   make(map[string]importedTool),
+	}
+}
+
+// Sync fetches the session's tool list and reports every addition, removal
+// and change since the previous sync. The first sync reports all tools as
+// added. On error the known tools are left unchanged.
+//
+// Sync returns once its events are delivered, unless another goroutine is
+// delivering events, in which case that goroutine delivers them too.
+func (w *MCPToolWatcher) Sync(ctx context.Context, session *mcp.ClientSession) error {
+	w.mu.Lock()
+	w.started++
+	seq := w.started
+	w.mu.Unlock()
+
+	listed, err := listMCPTools(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	next := make(map[string]importedTool, len(listed))
+	for _, t := range listed {
+		next[t.tool.ID()] = t
+	}
+
+	w.mu.Lock()
+	if seq < w.applied {
+		// a later sync already applied a newer list
+		w.mu.Unlock()
+		return nil
+	}
+	w.applied = seq
+	events := diffMCPTools(w.tools, next)
+	w.tools = next
+	if w.onEvent == nil {
+		w.mu.Unlock()
+		return nil
+	}
+	w.pending = append(w.pending, events...)
+	if w.delivering {
+		w.mu.Unlock()
+		return nil
+	}
+	w.delivering = true
+	w.mu.Unlock()
+
+	w.deliver()
+	return nil
+}
+
+// deliver passes pending events to onEvent until none are left. Only the
+// goroutine that set delivering calls it.
+func (w *MCPToolWatcher) deliver() {
+	for {
+		w.mu.Lock()
+		events := w.pending
+		w.pending = nil
+		if len(events) == 0 {
+			w.delivering = false
+			w.mu.Unlock()
+			return
+		}
+		w.mu.Unlock()
+
+		for _, e := range events {
+			w.onEvent(e)
+		}
+	}
+}
+
+// diffMCPTools returns the events that turn prev into next, in tool ID
+// order.
+func diffMCPTools(prev, next map[string]importedTool) []MCPToolEvent {
+	var events []MCPToolEvent
+	for id, t := range next {
+		old, ok := prev[id]
+		switch {
+		case !ok:
+			events = append(events, MCPToolEvent{Type: MCPToolAdded, Tool: t.tool})
+		case !bytes.Equal(old.raw, t.raw):
+			events = append(events, MCPToolEvent{
+				Type:  MCPToolChanged,
+				Tool:  t.tool,
+				Diffs: tooladapter.DiffCanonical(old.tool, t.tool),
+			})
+		}
+	}
+	for id, old := range prev {
+		if _, ok := next[id]; !ok {
+			events = append(events, MCPToolEvent{Type: MCPToolRemoved, Tool: old.tool})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Tool.ID() < events[j].Tool.ID()
+	})
+	return events
+}
+
+// Handler returns a function for mcp.ClientOptions.ToolListChangedHandler.
+// The go-sdk delivers notifications synchronously, so the handler syncs in a
+// separate goroutine rather than calling tools/list while the notification
+// is being handled. One sync runs at a time: notifications that arrive
+// during a sync are merged into a single sync after it.
+func (w *MCPToolWatcher) Handler() func(context.Context, *mcp.ToolListChangedRequest) {
+	return func(ctx context.Context, req *mcp.ToolListChangedRequest) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.changed = req.Session
+		w.changedCtx = context.WithoutCancel(ctx)
+		if !w.listening {
+			w.listening = true
+			go w.listen()
+		}
+	}
+}
+
+// listen syncs until no list_changed notification is pending.
+func (w *MCPToolWatcher) listen() {
+	for {
+		w.mu.Lock()
+		session, ctx := w.changed, w.changedCtx
+		w.changed, w.changedCtx = nil, nil
+		if session == nil {
+			w.listening = false
+			w.mu.Unlock()
+			return
+		}
+		w.mu.Unlock()
+
+		if err := w.Sync(ctx, session); err != nil && w.onError != nil {
+			w.onError(err)
+		}
+	}
+}
+
+// Tools returns the tools known from the last sync, sorted by ID.
+func (w *MCPToolWatcher) Tools() []*tooladapter.CanonicalTool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tools := make([]*tooladapter.CanonicalTool, 0, len(w.tools))
+	for _, t := range w.tools {
+		tools = append(tools, t.tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].ID() < tools[j].ID()
+	})
+	return tools
+}
@@ -0,0 +1,267 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func noopToolHandler(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{}, nil
+}
+
+func addRawTool(s *mcp.Server, name, description string) {
+	s.AddTool(&mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: map[string]any{"type": "object"},
+	}, noopToolHandler)
+}
+
+// connectRawMCPServer connects a client with the given options to an
+// mcp.Server over in-memory transports.
+func connectRawMCPServer(t *testing.T, s *mcp.Server, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, opts)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestImportMCPTools_Paginated(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "github", Version: "v1.0.0"}, &mcp.ServerOptions{PageSize: 2})
+	for i := range 5 {
+		addRawTool(server, fmt.Sprintf("tool_%d", i), "")
+	}
+	session := connectRawMCPServer(t, server, nil)
+
+	tools, err := ImportMCPTools(context.Background(), session)
+	if err != nil {
+		t.Fatalf("ImportMCPTools() error = %v", err)
+	}
+	if len(tools) != 5 {
+		t.Fatalf("ImportMCPTools() returned %d tools, want 5", len(tools))
+	}
+	for i, tool := range tools {
+		if want := fmt.Sprintf("github:tool_%d", i); tool.ID() != want {
+			t.Errorf("tools[%d].ID() = %q, want %q", i, tool.ID(), want)
+		}
+		if tool.SourceFormat != "mcp" {
+			t.Errorf("tools[%d].SourceFormat = %q, want mcp", i, tool.SourceFormat)
+		}
+		if v, _ := tool.SourceMeta["protocolVersion"].(string); v != session.InitializeResult().ProtocolVersion || v == "" {
+			t.Errorf("tools[%d].SourceMeta[protocolVersion] = %q, want the negotiated version", i, v)
+		}
+	}
+}
+
+func TestImportMCPTools_ConvertToOpenAI(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "files", Version: "v1.0.0"}, nil)
+	addRawTool(server, "read.file", "Read a file.")
+	session := connectRawMCPServer(t, server, nil)
+
+	tools, err := ImportMCPTools(context.Background(), session)
+	if err != nil {
+		t.Fatalf("ImportMCPTools() error = %v", err)
+	}
+
+	// Namespaced IDs are not provider-safe; map them before emitting
+	names := tooladapter.NewNameMapper()
+	for _, tool := range tools {
+		emitted := *tool
+		emitted.Namespace, emitted.Name = "", names.ProviderName(tool)
+
+		out, err := NewOpenAIAdapter().FromCanonical(&emitted)
+		if err != nil {
+			t.Fatalf("FromCanonical() error = %v", err)
+		}
+		if fn := out.(OpenAIFunction); fn.Name != "files_read_file" {
+			t.Errorf("Name = %q, want files_read_file", fn.Name)
+		}
+		if id, ok := names.CanonicalID("files_read_file"); !ok || id != "files:read.file" {
+			t.Errorf("CanonicalID() = %q, %v, want files:read.file", id, ok)
+		}
+	}
+}
+
+func TestImportMCPTools_NilSession(t *testing.T) {
+	if _, err := ImportMCPTools(context.Background(), nil); err == nil {
+		t.Error("ImportMCPTools(nil) error = nil, want error")
+	}
+}
+
+func TestMCPToolWatcher_ListChanged(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "srv", Version: "v1.0.0"}, nil)
+	addRawTool(server, "keep", "Kept.")
+	addRawTool(server, "drop", "Dropped.")
+
+	events := make(chan MCPToolEvent, 16)
+	errs := make(chan error, 1)
+	watcher := NewMCPToolWatcher(
+		func(e MCPToolEvent) { events <- e },
+		func(err error) { errs <- err },
+	)
+	session := connectRawMCPServer(t, server, &mcp.ClientOptions{ToolListChangedHandler: watcher.Handler()})
+
+	if err := watcher.Sync(context.Background(), session); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	expectEvents(t, events, errs, "added srv:drop", "added srv:keep")
+
+	addRawTool(server, "new", "New.")
+	expectEvents(t, events, errs, "added srv:new")
+
+	server.RemoveTools("drop")
+	expectEvents(t, events, errs, "removed srv:drop")
+
+	addRawTool(server, "keep", "Kept, now changed.")
+	e := expectEvents(t, events, errs, "changed srv:keep")[0]
+	if len(e.Diffs) != 1 || e.Diffs[0].Path != "/description" {
+		t.Errorf("Diffs = %v, want a /description diff", e.Diffs)
+	}
+
+	var ids []string
+	for _, tool := range watcher.Tools() {
+		ids = append(ids, tool.ID())
+	}
+	if fmt.Sprint(ids) != "[srv:keep srv:new]" {
+		t.Errorf("Tools() = %v, want [srv:keep srv:new]", ids)
+	}
+}
+
+func TestMCPToolWatcher_SyncWithoutChanges(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "srv", Version: "v1.0.0"}, nil)
+	addRawTool(server, "a", "")
+	session := connectRawMCPServer(t, server, nil)
+
+	count := 0
+	watcher := NewMCPToolWatcher(func(MCPToolEvent) { count++ }, nil)
+	for range 2 {
+		if err := watcher.Sync(context.Background(), session); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("events = %d, want 1", count)
+	}
+}
+
+func TestMCPToolWatcher_CallbackCallsTools(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "srv", Version: "v1.0.0"}, nil)
+	addRawTool(server, "a", "")
+
+	events := make(chan MCPToolEvent, 16)
+	errs := make(chan error, 1)
+	var watcher *MCPToolWatcher
+	known := make(chan int, 16)
+	watcher = NewMCPToolWatcher(
+		func(e MCPToolEvent) {
+			known <- len(watcher.Tools())
+			events <- e
+		},
+		func(err error) { errs <- err },
+	)
+	session := connectRawMCPServer(t, server, &mcp.ClientOptions{ToolListChangedHandler: watcher.Handler()})
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Sync(context.Background(), session) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync() deadlocked calling Tools() from onEvent")
+	}
+	expectEvents(t, events, errs, "added srv:a")
+	if n := <-known; n != 1 {
+		t.Errorf("Tools() in onEvent = %d tools, want 1", n)
+	}
+
+	addRawTool(server, "b", "")
+	expectEvents(t, events, errs, "added srv:b")
+	if n := <-known; n != 2 {
+		t.Errorf("Tools() in onEvent = %d tools, want 2", n)
+	}
+}
+
+func TestMCPToolWatcher_MergesNotifications(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "srv", Version: "v1.0.0"}, nil)
+	events := make(chan MCPToolEvent, 64)
+	errs := make(chan error, 1)
+	watcher := NewMCPToolWatcher(
+		func(e MCPToolEvent) { events <- e },
+		func(err error) { errs <- err },
+	)
+	session := connectRawMCPServer(t, server, &mcp.ClientOptions{ToolListChangedHandler: watcher.Handler()})
+	if err := watcher.Sync(context.Background(), session); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for _, name := range names {
+		addRawTool(server, name, "")
+	}
+
+	// each tool is added exactly once, however the notifications merge
+	seen := make(map[string]int)
+	timeout := time.After(5 * time.Second)
+	for len(seen) < len(names) {
+		select {
+		case e := <-events:
+			if e.Type != MCPToolAdded {
+				t.Fatalf("event = %s %s, want only additions", e.Type, e.Tool.ID())
+			}
+			seen[e.Tool.ID()]++
+		case err := <-errs:
+			t.Fatalf("sync error = %v", err)
+		case <-timeout:
+			t.Fatalf("events = %v, want all %d tools added", seen, len(names))
+		}
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("%s added %d times, want 1", id, n)
+		}
+	}
+	if got := len(watcher.Tools()); got != len(names) {
+		t.Errorf("Tools() = %d tools, want %d", got, len(names))
+	}
+}
+
+// expectEvents waits for events with the given "type id" descriptions, in
+// order, and fails on a sync error or timeout.
+func expectEvents(t *testing.T, events <-chan MCPToolEvent, errs <-chan error, want ...string) []MCPToolEvent {
+	t.Helper()
+	var got []MCPToolEvent
+	for _, w := range want {
+		select {
+		case e := <-events:
+			if desc := fmt.Sprintf("%s %s", e.Type, e.Tool.ID()); desc != w {
+				t.Fatalf("event = %q, want %q", desc, w)
+			}
+			got = append(got, e)
+		case err := <-errs:
+			t.Fatalf("sync error = %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %q", w)
+		}
+	}
+	return got
+}
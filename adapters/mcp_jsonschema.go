@@ -0,0 +1,365 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jonwraymond/tooladapter"
+)
+
+// WithTypedSchemas returns a copy of the adapter whose FromCanonical emits
+// InputSchema and OutputSchema as *jsonschema.Schema instead of
+// map[string]any, the form mcp.AddTool produces and mcp.Server expects.
+func (a *MCPAdapter) WithTypedSchemas() *MCPAdapter {
+	copied := *a
+	copied.typedSchemas = true
+	return &copied
+}
+
+// schemaFromMCP converts an mcp.Tool schema field to JSONSchema. The SDK
+// declares the fields as any; accepted values are map[string]any,
+// *jsonschema.Schema, jsonschema.Schema and json.RawMessage. A nil value or
+// nil pointer yields nil.
+func schemaFromMCP(raw any) (*tooladapter.JSONSchema, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case *jsonschema.Schema:
+		if v == nil {
+			return nil, nil
+		}
+		return typedToJSONSchema(v)
+	case jsonschema.Schema:
+		return typedToJSONSchema(&v)
+	case json.RawMessage:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		var m map[string]any
+		if err := json.Unmarshal(v, &m); err != nil {
+			return nil, fmt.Errorf("decode schema: %w", err)
+		}
+		return mapToJSONSchema(m)
+	default:
+		return mapToJSONSchema(raw)
+	}
+}
+
+// schemaToMCP converts a JSONSchema to the form FromCanonical emits.
+func (a *MCPAdapter) schemaToMCP(s *tooladapter.JSONSchema) (any, error) {
+	if a.typedSchemas {
+		return jsonSchemaToTyped(s)
+	}
+	return s.ToMap(), nil
+}
+
+// typedToJSONSchema converts a jsonschema-go schema to JSONSchema.
+//
+// Keywords JSONSchema has no field for are kept in Extra, as they are when
+// reading a map. A type list such as ["string", "null"] becomes an anyOf of
+// single-type schemas, and an additionalProperties schema other than the
+// true (empty) or false schema is kept in Extra.
+func typedToJSONSchema(s *jsonschema.Schema) (*tooladapter.JSONSchema, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	schema := &tooladapter.JSONSchema{
+		Type:        s.Type,
+		Description: s.Description,
+		Pattern:     s.Pattern,
+		Format:      s.Format,
+		Ref:         s.Ref,
+		Minimum:     copyFloat(s.Minimum),
+		Maximum:     copyFloat(s.Maximum),
+		MinLength:   copyInt(s.MinLength),
+		MaxLength:   copyInt(s.MaxLength),
+		MinItems:    copyInt(s.MinItems),
+		MaxItems:    copyInt(s.MaxItems),
+	}
+
+	if len(s.Enum) > 0 {
+		schema.Enum = append([]any(nil), s.Enum...)
+	}
+	if s.Const != nil {
+		schema.Const = *s.Const
+	}
+	if len(s.Default) > 0 {
+		if err := json.Unmarshal(s.Default, &schema.Default); err != nil {
+			return nil, fmt.Errorf("decode default: %w", err)
+		}
+	}
+	if len(s.Required) > 0 {
+		schema.Required = append([]string(nil), s.Required...)
+	}
+	if s.AdditionalProperties != nil {
+		switch {
+		case isTrueSchema(s.AdditionalProperties):
+			schema.AdditionalProperties = boolPtr(true)
+		case isFalseSchema(s.AdditionalProperties):
+			schema.AdditionalProperties = boolPtr(false)
+		}
+	}
+
+	var err error
+	if schema.Properties, err = typedSchemaMap(s.Properties); err != nil {
+		return nil, err
+	}
+	if schema.Defs, err = typedSchemaMap(s.Defs); err != nil {
+		return nil, err
+	}
+	if schema.Items, err = typedToJSONSchema(s.Items); err != nil {
+		return nil, err
+	}
+	if schema.Not, err = typedToJSONSchema(s.Not); err != nil {
+		return nil, err
+	}
+	if schema.AnyOf, err = typedSchemaList(s.AnyOf); err != nil {
+		return nil, err
+	}
+	if schema.OneOf, err = typedSchemaList(s.OneOf); err != nil {
+		return nil, err
+	}
+	if schema.AllOf, err = typedSchemaList(s.AllOf); err != nil {
+		return nil, err
+	}
+
+	if len(s.Types) > 0 {
+		applyTypeList(schema, s.Types)
+	}
+	if schema.Extra, err = typedExtra(s); err != nil {
+		return nil, err
+	}
+	if schema.AdditionalProperties != nil {
+		delete(schema.Extra, "additionalProperties")
+	}
+	return schema, nil
+}
+
+// typedExtra returns the keywords of a jsonschema-go schema that JSONSchema
+// has no field for, read from its JSON encoding.
+func typedExtra(s *jsonschema.Schema) (map[string]any, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	return schemaExtra(m), nil
+}
+
+// jsonSchemaToTyped converts a JSONSchema to a jsonschema-go schema.
+func jsonSchemaToTyped(s *tooladapter.JSONSchema) (*jsonschema.Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	schema := &jsonschema.Schema{
+		Type:        s.Type,
+		Description: s.Description,
+		Pattern:     s.Pattern,
+		Format:      s.Format,
+		Ref:         s.Ref,
+		Minimum:     copyFloat(s.Minimum),
+		Maximum:     copyFloat(s.Maximum),
+		MinLength:   copyInt(s.MinLength),
+		MaxLength:   copyInt(s.MaxLength),
+		MinItems:    copyInt(s.MinItems),
+		MaxItems:    copyInt(s.MaxItems),
+	}
+
+	if len(s.Enum) > 0 {
+		schema.Enum = append([]any(nil), s.Enum...)
+	}
+	if s.Const != nil {
+		c := s.Const
+		schema.Const = &c
+	}
+	if s.Default != nil {
+		data, err := json.Marshal(s.Default)
+		if err != nil {
+			return nil, fmt.Errorf("encode default: %w", err)
+		}
+		schema.Default = data
+	}
+	if len(s.Required) > 0 {
+		schema.Required = append([]string(nil), s.Required...)
+	}
+	if s.AdditionalProperties != nil {
+		if *s.AdditionalProperties {
+			schema.AdditionalProperties = &jsonschema.Schema{}
+		} else {
+			schema.AdditionalProperties = &jsonschema.Schema{Not: &jsonschema.Schema{}}
+		}
+	}
+
+	var err error
+	if schema.Properties, err = jsonSchemaMapToTyped(s.Properties); err != nil {
+		return nil, err
+	}
+	if schema.Defs, err = jsonSchemaMapToTyped(s.Defs); err != nil {
+		return nil, err
+	}
+	if schema.Items, err = jsonSchemaToTyped(s.Items); err != nil {
+		return nil, err
+	}
+	if schema.Not, err = jsonSchemaToTyped(s.Not); err != nil {
+		return nil, err
+	}
+	if schema.AnyOf, err = jsonSchemaListToTyped(s.AnyOf); err != nil {
+		return nil, err
+	}
+	if schema.OneOf, err = jsonSchemaListToTyped(s.OneOf); err != nil {
+		return nil, err
+	}
+	if schema.AllOf, err = jsonSchemaListToTyped(s.AllOf); err != nil {
+		return nil, err
+	}
+	if len(s.Extra) > 0 {
+		return withTypedExtra(schema, s.Extra)
+	}
+	return schema, nil
+}
+
+// withTypedExtra adds extra keywords to a jsonschema-go schema through its
+// JSON encoding, so that keywords it models, such as title, land in their
+// fields and others in its Extra map. Keywords already set are kept.
+func withTypedExtra(schema *jsonschema.Schema, extra map[string]any) (*jsonschema.Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	for k, v := range extra {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	if data, err = json.Marshal(m); err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+	merged := &jsonschema.Schema{}
+	if err := json.Unmarshal(data, merged); err != nil {
+		return nil, fmt.Errorf("decode schema keywords: %w", err)
+	}
+	return merged, nil
+}
+
+// applyTypeList records a JSON Schema type list on a schema that has a
+// single Type field. One type is stored as Type; several become an anyOf of
+// single-type schemas (nested under allOf if anyOf is already in use).
+// Other keywords stay on the schema, where they only constrain values of
+// the type they apply to, so validation is unchanged.
+func applyTypeList(schema *tooladapter.JSONSchema, types []string) {
+	if len(types) == 1 {
+		schema.Type = types[0]
+		return
+	}
+	alternatives := make([]*tooladapter.JSONSchema, len(types))
+	for i, t := range types {
+		alternatives[i] = &tooladapter.JSONSchema{Type: t}
+	}
+	if len(schema.AnyOf) == 0 {
+		schema.AnyOf = alternatives
+		return
+	}
+	schema.AllOf = append(schema.AllOf, &tooladapter.JSONSchema{AnyOf: alternatives})
+}
+
+func typedSchemaMap(m map[string]*jsonschema.Schema) (map[string]*tooladapter.JSONSchema, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]*tooladapter.JSONSchema, len(m))
+	for name, sub := range m {
+		schema, err := typedToJSONSchema(sub)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = schema
+	}
+	return out, nil
+}
+
+func typedSchemaList(list []*jsonschema.Schema) ([]*tooladapter.JSONSchema, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	out := make([]*tooladapter.JSONSchema, len(list))
+	for i, sub := range list {
+		schema, err := typedToJSONSchema(sub)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = schema
+	}
+	return out, nil
+}
+
+func jsonSchemaMapToTyped(m map[string]*tooladapter.JSONSchema) (map[string]*jsonschema.Schema, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]*jsonschema.Schema, len(m))
+	for name, sub := range m {
+		schema, err := jsonSchemaToTyped(sub)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = schema
+	}
+	return out, nil
+}
+
+func jsonSchemaListToTyped(list []*tooladapter.JSONSchema) ([]*jsonschema.Schema, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	out := make([]*jsonschema.Schema, len(list))
+	for i, sub := range list {
+		schema, err := jsonSchemaToTyped(sub)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = schema
+	}
+	return out, nil
+}
+
+// isTrueSchema reports whether s is the empty schema, which accepts any value.
+func isTrueSchema(s *jsonschema.Schema) bool {
+	return reflect.DeepEqual(*s, jsonschema.Schema{})
+}
+
+// isFalseSchema reports whether s is {"not": {}}, which jsonschema-go uses
+// for the false schema.
+func isFalseSchema(s *jsonschema.Schema) bool {
+	return s.Not != nil && isTrueSchema(s.Not) && reflect.DeepEqual(*s, jsonschema.Schema{Not: s.Not})
+}
+
+func copyFloat(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	v := *f
+	return &v
+}
+
+func copyInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	v := *i
+	return &v
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,261 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func typedWeatherSchema() *jsonschema.Schema {
+	minLen := 1
+	units := any("metric")
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"city":  {Type: "string", Description: "City name", MinLength: &minLen},
+			"units": {Type: "string", Enum: []any{"metric", "imperial"}, Default: json.RawMessage(`"metric"`)},
+			"kind":  {Const: &units},
+			"days":  {Types: []string{"integer", "null"}},
+		},
+		Required:             []string{"city"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+}
+
+func TestMCPAdapter_ToCanonical_TypedSchema(t *testing.T) {
+	tool := mcp.Tool{
+		Name:         "get_weather",
+		InputSchema:  typedWeatherSchema(),
+		OutputSchema: jsonschema.Schema{Type: "object", AdditionalProperties: &jsonschema.Schema{}},
+	}
+
+	got, err := NewMCPAdapter().ToCanonical(tool)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	in := got.InputSchema
+	if in == nil || in.Type != "object" {
+		t.Fatalf("InputSchema = %+v, want object schema", in)
+	}
+	if !reflect.DeepEqual(in.Required, []string{"city"}) {
+		t.Errorf("Required = %v, want [city]", in.Required)
+	}
+	if in.AdditionalProperties == nil || *in.AdditionalProperties {
+		t.Errorf("AdditionalProperties = %v, want false", in.AdditionalProperties)
+	}
+	if city := in.Properties["city"]; city.MinLength == nil || *city.MinLength != 1 || city.Description != "City name" {
+		t.Errorf("city = %+v, want minLength 1 and description", city)
+	}
+	if units := in.Properties["units"]; units.Default != "metric" || len(units.Enum) != 2 {
+		t.Errorf("units = %+v, want default metric and 2 enum values", units)
+	}
+	if kind := in.Properties["kind"]; kind.Const != "metric" {
+		t.Errorf("kind.Const = %v, want metric", kind.Const)
+	}
+	days := in.Properties["days"]
+	if days.Type != "" || len(days.AnyOf) != 2 || days.AnyOf[0].Type != "integer" || days.AnyOf[1].Type != "null" {
+		t.Errorf("days = %+v, want anyOf integer|null", days)
+	}
+
+	out := got.OutputSchema
+	if out == nil || out.AdditionalProperties == nil || !*out.AdditionalProperties {
+		t.Errorf("OutputSchema = %+v, want additionalProperties true", out)
+	}
+}
+
+func TestMCPAdapter_ToCanonical_InferredSchema(t *testing.T) {
+	type args struct {
+		Query string `json:"query" jsonschema:"the search query"`
+		Limit *int   `json:"limit,omitempty"`
+	}
+	schema, err := jsonschema.For[args](nil)
+	if err != nil {
+		t.Fatalf("For() error = %v", err)
+	}
+
+	got, err := NewMCPAdapter().ToCanonical(&mcp.Tool{Name: "search", InputSchema: schema})
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if got.InputSchema.Properties["query"].Description != "the search query" {
+		t.Errorf("query = %+v, want description from tag", got.InputSchema.Properties["query"])
+	}
+	if !reflect.DeepEqual(got.InputSchema.Required, []string{"query"}) {
+		t.Errorf("Required = %v, want [query]", got.InputSchema.Required)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestMCPAdapter_ToCanonical_RawMessageSchema(t *testing.T) {
+	tool := mcp.Tool{
+		Name:        "get_weather",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"city":{"type":["string","null"]}},"required":["city"]}`),
+	}
+
+	got, err := NewMCPAdapter().ToCanonical(tool)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if got.InputSchema.Type != "object" || !reflect.DeepEqual(got.InputSchema.Required, []string{"city"}) {
+		t.Errorf("InputSchema = %+v, want object requiring city", got.InputSchema)
+	}
+	if city := got.InputSchema.Properties["city"]; len(city.AnyOf) != 2 {
+		t.Errorf("city = %+v, want anyOf string|null", city)
+	}
+	if got.OutputSchema != nil {
+		t.Errorf("OutputSchema = %+v, want nil", got.OutputSchema)
+	}
+}
+
+func TestMCPAdapter_ToCanonical_SchemaForms(t *testing.T) {
+	tests := map[string]struct {
+		schema  any
+		wantNil bool
+		wantErr bool
+	}{
+		"nil typed pointer": {schema: (*jsonschema.Schema)(nil), wantNil: true},
+		"empty raw message": {schema: json.RawMessage(nil), wantNil: true},
+		"malformed raw":     {schema: json.RawMessage(`{"type":`), wantErr: true},
+		"raw non-object":    {schema: json.RawMessage(`"object"`), wantErr: true},
+		"unsupported type":  {schema: 42, wantErr: true},
+		"bad typed default": {schema: &jsonschema.Schema{Default: json.RawMessage(`{`)}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewMCPAdapter().ToCanonical(mcp.Tool{Name: "t", InputSchema: tt.schema})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToCanonical() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.InputSchema == nil) != tt.wantNil {
+				t.Errorf("InputSchema = %+v, want nil %v", got.InputSchema, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestMCPAdapter_WithTypedSchemas(t *testing.T) {
+	base := NewMCPAdapter()
+	adapter := base.WithTypedSchemas()
+	if base.typedSchemas {
+		t.Fatal("WithTypedSchemas() modified the receiver")
+	}
+
+	canonical, err := adapter.ToCanonical(mcp.Tool{Name: "get_weather", InputSchema: typedWeatherSchema()})
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	canonical.OutputSchema = &tooladapter.JSONSchema{Type: "string"}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	tool := out.(mcp.Tool)
+	in, ok := tool.InputSchema.(*jsonschema.Schema)
+	if !ok {
+		t.Fatalf("InputSchema type = %T, want *jsonschema.Schema", tool.InputSchema)
+	}
+	if _, ok := tool.OutputSchema.(*jsonschema.Schema); !ok {
+		t.Errorf("OutputSchema type = %T, want *jsonschema.Schema", tool.OutputSchema)
+	}
+	if !isFalseSchema(in.AdditionalProperties) {
+		t.Errorf("AdditionalProperties = %+v, want false schema", in.AdditionalProperties)
+	}
+	if string(in.Properties["units"].Default) != `"metric"` {
+		t.Errorf("units.Default = %s, want \"metric\"", in.Properties["units"].Default)
+	}
+
+	// The typed schema is resolvable by the SDK's validator
+	if _, err := in.Resolve(nil); err != nil {
+		t.Errorf("Resolve() error = %v", err)
+	}
+
+	back, err := adapter.ToCanonical(tool)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(canonical, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+}
+
+func TestMCPAdapter_WithTypedSchemas_UnknownKeywords(t *testing.T) {
+	adapter := NewMCPAdapter().WithTypedSchemas()
+	canonical := &tooladapter.CanonicalTool{
+		Name: "lookup",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"q": {Type: "string", Extra: map[string]any{"title": "Query", "x-order": 1.0}},
+			},
+			Extra: map[string]any{"additionalProperties": map[string]any{"type": "string"}},
+		},
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	in := out.(mcp.Tool).InputSchema.(*jsonschema.Schema)
+	if q := in.Properties["q"]; q.Title != "Query" || q.Extra["x-order"] != 1.0 {
+		t.Errorf("q = %+v, want title and x-order", q)
+	}
+	if in.AdditionalProperties == nil || in.AdditionalProperties.Type != "string" {
+		t.Errorf("AdditionalProperties = %+v, want string schema", in.AdditionalProperties)
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(canonical, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+}
+
+func TestJSONSchemaToTyped_MatchesToMap(t *testing.T) {
+	minimum := 0.0
+	maxItems := 3
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"tags":  {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}, MaxItems: &maxItems},
+			"count": {Type: "integer", Minimum: &minimum, Default: 1},
+			"ref":   {Ref: "#/$defs/Node"},
+			"either": {OneOf: []*tooladapter.JSONSchema{
+				{Type: "string"},
+				{Not: &tooladapter.JSONSchema{Type: "null"}},
+			}},
+		},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Node": {Type: "object", AllOf: []*tooladapter.JSONSchema{{Required: []string{"id"}}}},
+		},
+		AdditionalProperties: boolPtr(true),
+	}
+
+	typed, err := jsonSchemaToTyped(schema)
+	if err != nil {
+		t.Fatalf("jsonSchemaToTyped() error = %v", err)
+	}
+
+	gotJSON, err := json.Marshal(typed)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	wantJSON, _ := json.Marshal(schema.ToMap())
+
+	var got, want any
+	_ = json.Unmarshal(gotJSON, &got)
+	_ = json.Unmarshal(wantJSON, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("typed =\n%s\nwant\n%s", gotJSON, wantJSON)
+	}
+}
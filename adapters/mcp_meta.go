@@ -0,0 +1,184 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MCPMetaKey is the _meta key under which the MCP adapter stores canonical
+// fields that mcp.Tool has no field for. Its value is an object:
+//
+//	{
+//	  "category": "search",
+//	  "tags": ["web", "read"],
+//	  "version": "1.2.0",
+//	  "timeout": "30s",
+//	  "deprecated": true
+//	}
+//
+// Every member is optional. timeout is a Go duration string, and deprecated
+// holds ToolAnnotations.Deprecated, which MCP annotations cannot express.
+const MCPMetaKey = "io.github.jonwraymond/tooladapter"
+
+// mcpToolMeta is the value stored under MCPMetaKey.
+type mcpToolMeta struct {
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	Timeout  string   `json:"timeout,omitempty"`
+
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// metaToCanonical copies an MCP _meta object into a canonical tool. Fields
+// under MCPMetaKey populate the canonical fields; all other keys are kept in
+// SourceMeta["_meta"].
+func metaToCanonical(meta mcp.Meta, canonical *tooladapter.CanonicalTool) error {
+	rest := make(map[string]any, len(meta))
+	for k, v := range meta {
+		if k != MCPMetaKey {
+			rest[k] = v
+		}
+	}
+	if len(rest) > 0 {
+		canonical.SourceMeta["_meta"] = rest
+	}
+
+	raw, ok := meta[MCPMetaKey]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("encode _meta[%q]: %w", MCPMetaKey, err)
+	}
+	var m mcpToolMeta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("decode _meta[%q]: %w", MCPMetaKey, err)
+	}
+	if m.Timeout != "" {
+		timeout, err := time.ParseDuration(m.Timeout)
+		if err != nil {
+			return fmt.Errorf("decode _meta[%q]: timeout: %w", MCPMetaKey, err)
+		}
+		canonical.Timeout = timeout
+	}
+	canonical.Category = m.Category
+	canonical.Tags = m.Tags
+	canonical.Version = m.Version
+	if m.Deprecated {
+		if canonical.Annotations == nil {
+			canonical.Annotations = &tooladapter.ToolAnnotations{}
+		}
+		canonical.Annotations.Deprecated = true
+	}
+	return nil
+}
+
+// metaFromCanonical builds an MCP _meta object from SourceMeta["_meta"] and
+// the canonical fields. Returns nil if there is nothing to emit.
+func metaFromCanonical(tool *tooladapter.CanonicalTool) (mcp.Meta, error) {
+	meta := mcp.Meta{}
+	switch v := tool.SourceMeta["_meta"].(type) {
+	case nil:
+	case map[string]any:
+		for k, val := range v {
+			meta[k] = val
+		}
+	case mcp.Meta:
+		for k, val := range v {
+			meta[k] = val
+		}
+	default:
+		return nil, errors.New("SourceMeta[_meta] must be an object")
+	}
+
+	m := mcpToolMeta{
+		Category: tool.Category,
+		Version:  tool.Version,
+	}
+	if len(tool.Tags) > 0 {
+		m.Tags = append([]string(nil), tool.Tags...)
+	}
+	if tool.Timeout != 0 {
+		m.Timeout = tool.Timeout.String()
+	}
+	if tool.Annotations != nil {
+		m.Deprecated = tool.Annotations.Deprecated
+	}
+	if m.Category != "" || m.Version != "" || m.Tags != nil || m.Timeout != "" || m.Deprecated {
+		meta[MCPMetaKey] = m.toMap()
+	} else {
+		delete(meta, MCPMetaKey)
+	}
+
+	if len(meta) == 0 {
+		return nil, nil
+	}
+	return meta, nil
+}
+
+// toMap returns the namespace value in the generic JSON form that
+// json.Unmarshal produces, so emitted and decoded tools compare equal.
+func (m mcpToolMeta) toMap() map[string]any {
+	out := make(map[string]any, 4)
+	if m.Category != "" {
+		out["category"] = m.Category
+	}
+	if m.Tags != nil {
+		tags := make([]any, len(m.Tags))
+		for i, tag := range m.Tags {
+			tags[i] = tag
+		}
+		out["tags"] = tags
+	}
+	if m.Version != "" {
+		out["version"] = m.Version
+	}
+	if m.Timeout != "" {
+		out["timeout"] = m.Timeout
+	}
+	if m.Deprecated {
+		out["deprecated"] = true
+	}
+	return out
+}
+
+// iconsFromMeta reads icons recorded in SourceMeta, either as []mcp.Icon or
+// in decoded JSON form. Returns nil if none are recorded.
+func iconsFromMeta(meta map[string]any) ([]mcp.Icon, error) {
+	switch v := meta["icons"].(type) {
+	case nil:
+		return nil, nil
+	case []mcp.Icon:
+		return copyIcons(v), nil
+	case []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var icons []mcp.Icon
+		if err := json.Unmarshal(data, &icons); err != nil {
+			return nil, fmt.Errorf("decode icons: %w", err)
+		}
+		return icons, nil
+	default:
+		return nil, errors.New("SourceMeta[icons] must be an array")
+	}
+}
+
+func copyIcons(icons []mcp.Icon) []mcp.Icon {
+	out := make([]mcp.Icon, len(icons))
+	for i, icon := range icons {
+		out[i] = icon
+		if icon.Sizes != nil {
+			out[i].Sizes = append([]string(nil), icon.Sizes...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,254 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func fullMCPTool() mcp.Tool {
+	yes := true
+	return mcp.Tool{
+		Meta: mcp.Meta{
+			"com.example/route": "eu-west",
+			MCPMetaKey: map[string]any{
+				"category": "search",
+				"tags":     []any{"web", "read"},
+				"version":  "1.2.0",
+				"timeout":  "1m30s",
+			},
+		},
+		Name:        "search",
+		Title:       "Web Search",
+		Description: "Search the web.",
+		Icons: []mcp.Icon{
+			{Source: "https://example.com/search.png", MIMEType: "image/png", Sizes: []string{"48x48"}},
+			{Source: "https://example.com/search-dark.svg", Sizes: []string{"any"}, Theme: "dark"},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: &yes},
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"q": map[string]any{"type": "string", "minLength": 1},
+			},
+			"required": []any{"q"},
+		},
+		OutputSchema: map[string]any{"type": "object"},
+	}
+}
+
+func TestMCPAdapter_ToCanonical_Meta(t *testing.T) {
+	got, err := NewMCPAdapter().ToCanonical(fullMCPTool())
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	if got.Category != "search" {
+		t.Errorf("Category = %q, want search", got.Category)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"web", "read"}) {
+		t.Errorf("Tags = %v, want [web read]", got.Tags)
+	}
+	if got.Version != "1.2.0" {
+		t.Errorf("Version = %q, want 1.2.0", got.Version)
+	}
+	if got.Timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want 1m30s", got.Timeout)
+	}
+
+	meta, ok := got.SourceMeta["_meta"].(map[string]any)
+	if !ok {
+		t.Fatalf("SourceMeta[_meta] = %T, want map[string]any", got.SourceMeta["_meta"])
+	}
+	if _, ok := meta[MCPMetaKey]; ok {
+		t.Error("SourceMeta[_meta] keeps the tooladapter namespace")
+	}
+	if meta["com.example/route"] != "eu-west" {
+		t.Errorf("SourceMeta[_meta] = %v, want com.example/route kept", meta)
+	}
+	if icons, ok := got.SourceMeta["icons"].([]mcp.Icon); !ok || len(icons) != 2 {
+		t.Errorf("SourceMeta[icons] = %v, want 2 icons", got.SourceMeta["icons"])
+	}
+}
+
+func TestMCPAdapter_FromCanonical_Meta(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "search",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Category:    "search",
+		Tags:        []string{"web"},
+		Timeout:     1500 * time.Millisecond,
+	}
+
+	out, err := NewMCPAdapter().FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := mcp.Meta{MCPMetaKey: map[string]any{
+		"category": "search",
+		"tags":     []any{"web"},
+		"timeout":  "1.5s",
+	}}
+	if got := out.(mcp.Tool).Meta; !reflect.DeepEqual(got, want) {
+		t.Errorf("Meta = %v, want %v", got, want)
+	}
+
+	tool.Category, tool.Tags, tool.Timeout = "", nil, 0
+	out, err = NewMCPAdapter().FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if got := out.(mcp.Tool).Meta; got != nil {
+		t.Errorf("Meta = %v, want nil", got)
+	}
+}
+
+func TestMCPAdapter_Meta_Deprecated(t *testing.T) {
+	adapter := NewMCPAdapter()
+	tool := &tooladapter.CanonicalTool{
+		Name:        "search",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Annotations: &tooladapter.ToolAnnotations{Deprecated: true},
+	}
+
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	mcpTool := out.(mcp.Tool)
+	if mcpTool.Annotations != nil {
+		t.Errorf("Annotations = %+v, want nil; deprecated is carried in _meta", mcpTool.Annotations)
+	}
+	if want := (mcp.Meta{MCPMetaKey: map[string]any{"deprecated": true}}); !reflect.DeepEqual(mcpTool.Meta, want) {
+		t.Errorf("Meta = %v, want %v", mcpTool.Meta, want)
+	}
+
+	back, err := adapter.ToCanonical(mcpTool)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if !reflect.DeepEqual(back.Annotations, tool.Annotations) {
+		t.Errorf("Annotations = %+v, want %+v", back.Annotations, tool.Annotations)
+	}
+
+	// Other hints still use MCP annotations
+	tool.Annotations = &tooladapter.ToolAnnotations{ReadOnlyHint: true, Deprecated: true}
+	out, err = adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if back, err = adapter.ToCanonical(out); err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if !reflect.DeepEqual(back.Annotations, tool.Annotations) {
+		t.Errorf("Annotations = %+v, want %+v", back.Annotations, tool.Annotations)
+	}
+}
+
+func TestMCPAdapter_MetaRoundTrip_Lossless(t *testing.T) {
+	adapter := NewMCPAdapter()
+	original := fullMCPTool()
+
+	canonical, err := adapter.ToCanonical(original)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	// SourceMeta survives a JSON round trip of the canonical tool
+	data, err := json.Marshal(canonical.SourceMeta)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	canonical.SourceMeta = nil
+	if err := json.Unmarshal(data, &canonical.SourceMeta); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+
+	want, _ := json.Marshal(original)
+	got, _ := json.Marshal(out)
+	if string(got) != string(want) {
+		t.Errorf("round trip =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMCPAdapter_MetaRoundTripCheck(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+
+	result, err := registry.Convert(fullMCPTool(), "mcp", "mcp", tooladapter.WithRoundTripCheck())
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !result.RoundTrip.Lossless() {
+		t.Errorf("RoundTrip.Diffs = %v, want none", result.RoundTrip.Diffs)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", result.Warnings)
+	}
+}
+
+func TestMCPAdapter_FromCanonical_DoesNotAliasMeta(t *testing.T) {
+	adapter := NewMCPAdapter()
+	original := fullMCPTool()
+
+	canonical, err := adapter.ToCanonical(original)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+
+	emitted := out.(mcp.Tool)
+	emitted.Meta["com.example/route"] = "us-east"
+	emitted.Icons[0].Sizes[0] = "96x96"
+
+	if original.Meta["com.example/route"] != "eu-west" {
+		t.Error("FromCanonical() output shares _meta with the input")
+	}
+	if original.Icons[0].Sizes[0] != "48x48" {
+		t.Error("FromCanonical() output shares icons with the input")
+	}
+}
+
+func TestMCPAdapter_MetaErrors(t *testing.T) {
+	adapter := NewMCPAdapter()
+
+	toCanonical := map[string]mcp.Meta{
+		"namespace not an object": {MCPMetaKey: "search"},
+		"bad timeout":             {MCPMetaKey: map[string]any{"timeout": "soon"}},
+		"tags not strings":        {MCPMetaKey: map[string]any{"tags": []any{1}}},
+	}
+	for name, meta := range toCanonical {
+		t.Run(name, func(t *testing.T) {
+			tool := mcp.Tool{Name: "t", InputSchema: map[string]any{"type": "object"}, Meta: meta}
+			if _, err := adapter.ToCanonical(tool); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+
+	fromCanonical := map[string]map[string]any{
+		"_meta not an object": {"_meta": "x"},
+		"icons not an array":  {"icons": "x"},
+		"icon not an object":  {"icons": []any{"x"}},
+	}
+	for name, sourceMeta := range fromCanonical {
+		t.Run(name, func(t *testing.T) {
+			tool := &tooladapter.CanonicalTool{Name: "t", SourceMeta: sourceMeta}
+			if _, err := adapter.FromCanonical(tool); err == nil {
+				t.Error("FromCanonical() error = nil, want error")
+			}
+		})
+	}
+}
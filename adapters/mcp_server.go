@@ -0,0 +1,261 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MCPToolHandler handles a call to a canonical tool served by MCPServer.
+// args holds the decoded arguments, already validated against the tool's
+// InputSchema.
+//
+// The result becomes the tool result:
+//   - *mcp.CallToolResult is returned as is
+//   - a string becomes a single text content block
+//   - nil becomes an empty result
+//   - any other value is encoded as JSON text; if the tool has an
+//     OutputSchema it is also validated and, for clients on protocol
+//     revision 2025-06-18 or later, sent as structured content
+//
+// A returned error is reported to the model as a tool result with IsError
+// set, unless it is a *jsonrpc.Error, which is sent as a protocol error.
+type MCPToolHandler func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (any, error)
+
+// MCPServer serves canonical tools through an mcp.Server, so that tools
+// authored in any registered format can be exposed over MCP.
+type MCPServer struct {
+	server        *mcp.Server
+	adapter       *MCPAdapter
+	elicitMissing atomic.Bool
+}
+
+// NewMCPServer creates a bridge around a new mcp.Server. The arguments are
+// passed to mcp.NewServer.
+func NewMCPServer(impl *mcp.Implementation, opts *mcp.ServerOptions) *MCPServer {
+	return &MCPServer{
+		server:  mcp.NewServer(impl, opts),
+		adapter: NewMCPAdapter(),
+	}
+}
+
+// Server returns the underlying mcp.Server, for connecting transports or
+// registering prompts and resources.
+func (s *MCPServer) Server() *mcp.Server {
+	return s.server
+}
+
+// SetElicitMissing controls whether calls that omit required arguments ask
+// the user for them with an elicitation/create request before the arguments
+// are validated. The form is compiled from the missing properties with
+// CompileElicitationSchema; if the form cannot represent a missing
+// argument, the call returns a tool error instead. Clients that do not
+// advertise the elicitation capability get the usual invalid-arguments
+// error. The setting applies to every tool, from the next call on, and may
+// be changed while the server is running.
+func (s *MCPServer) SetElicitMissing(enabled bool) {
+	s.elicitMissing.Store(enabled)
+}
+
+// AddTool serves a canonical tool under its Name, replacing any tool with
+// the same name. The tool must be a valid function tool whose input schema,
+// and output schema if present, have type "object".
+func (s *MCPServer) AddTool(tool *tooladapter.CanonicalTool, handler MCPToolHandler) error {
+	if tool == nil {
+		return errors.New("nil CanonicalTool")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %q: nil handler", tool.Name)
+	}
+	if err := tool.Validate(); err != nil {
+		return fmt.Errorf("tool %q: %w", tool.Name, err)
+	}
+	if !tool.IsFunction() {
+		return unsupportedKindError(s.adapter.Name(), tool)
+	}
+
+	copied := *tool
+	tool = &copied
+	tool.InputSchema = tool.InputSchema.DeepCopy()
+	tool.OutputSchema = tool.OutputSchema.DeepCopy()
+	if tool.InputSchema.Type != "object" {
+		return fmt.Errorf("tool %q: input schema must have type \"object\"", tool.Name)
+	}
+	if tool.OutputSchema != nil && tool.OutputSchema.Type != "object" {
+		return fmt.Errorf("tool %q: output schema must have type \"object\"", tool.Name)
+	}
+
+	out, err := s.adapter.FromCanonical(tool)
+	if err != nil {
+		return fmt.Errorf("tool %q: %w", tool.Name, err)
+	}
+	mcpTool := out.(mcp.Tool)
+
+	s.server.AddTool(&mcpTool, serveCanonical(tool, handler, &s.elicitMissing))
+	return nil
+}
+
+// RemoveTools stops serving the named tools.
+func (s *MCPServer) RemoveTools(names ...string) {
+	s.server.RemoveTools(names...)
+}
+
+// serveCanonical wraps a handler with argument decoding, input validation
+// and result encoding. While elicit is set, missing required arguments are
+// requested from the user first.
+func serveCanonical(tool *tooladapter.CanonicalTool, handler MCPToolHandler, elicit *atomic.Bool) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := map[string]any{}
+		if raw := req.Params.Arguments; len(raw) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, invalidParams("arguments must be a JSON object: " + err.Error())
+			}
+		}
+		if elicit.Load() {
+			elicited, res, err := elicitMissingArguments(ctx, req, tool, args)
+			if res != nil || err != nil {
+				return res, err
+			}
+			args = elicited
+		}
+		if issues := tool.InputSchema.ValidateValue(args); len(issues) > 0 {
+			return nil, invalidParams("invalid arguments: " + joinIssues(issues))
+		}
+
+		result, err := handler(ctx, req, args)
+		if err != nil {
+			var wireErr *jsonrpc.Error
+			if errors.As(err, &wireErr) {
+				return nil, wireErr
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+				IsError: true,
+			}, nil
+		}
+		return toolResult(tool, result, structuredContentAllowed(req))
+	}
+}
+
+// elicitMissingArguments asks the user for the required top-level
+// properties missing from args and returns args with the answers added. If
+// the user declines or cancels, it returns an error result for the call
+// instead, as it does if the form cannot represent a missing argument.
+// Nothing is requested if no arguments are missing or the client does not
+// support elicitation.
+func elicitMissingArguments(ctx context.Context, req *mcp.CallToolRequest, tool *tooladapter.CanonicalTool, args map[string]any) (map[string]any, *mcp.CallToolResult, error) {
+	if req == nil || req.Session == nil {
+		return args, nil, nil
+	}
+	if params := req.Session.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.Elicitation == nil {
+		return args, nil, nil
+	}
+
+	missing := &tooladapter.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*tooladapter.JSONSchema{},
+		Defs:       tool.InputSchema.Defs,
+	}
+	for _, name := range tool.InputSchema.Required {
+		prop, ok := tool.InputSchema.Properties[name]
+		if _, given := args[name]; ok && !given {
+			missing.Properties[name] = prop
+			missing.Required = append(missing.Required, name)
+		}
+	}
+	if len(missing.Required) == 0 {
+		return args, nil, nil
+	}
+	c := compileElicitation(missing)
+	if len(c.lost) > 0 {
+		return nil, &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "the missing arguments cannot be requested from the user: " + joinIssues(c.lost)}},
+			IsError: true,
+		}, nil
+	}
+	form := c.form()
+	// The go-sdk validates declined and cancelled results against the
+	// requested schema too, so a required list would turn them into errors.
+	// Missing answers are caught by argument validation instead.
+	delete(form, "required")
+
+	res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+		Message:         fmt.Sprintf("%s needs more information.", tool.Name),
+		RequestedSchema: form,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("tool %q: elicit arguments: %w", tool.Name, err)
+	}
+	if res.Action != "accept" {
+		return nil, &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("the user did not provide the missing arguments (%s)", res.Action)}},
+			IsError: true,
+		}, nil
+	}
+
+	merged := make(map[string]any, len(args)+len(missing.Required))
+	for k, v := range args {
+		merged[k] = v
+	}
+	for k, v := range ExpandElicitationContent(missing, res.Content) {
+		merged[k] = v
+	}
+	return merged, nil, nil
+}
+
+// toolResult encodes a handler result as an MCP tool result.
+func toolResult(tool *tooladapter.CanonicalTool, result any, structured bool) (*mcp.CallToolResult, error) {
+	switch v := result.(type) {
+	case *mcp.CallToolResult:
+		return v, nil
+	case nil:
+		return &mcp.CallToolResult{Content: []mcp.Content{}}, nil
+	case string:
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: v}}}, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("tool %q: encode result: %w", tool.Name, err)
+	}
+	res := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}
+	if tool.OutputSchema != nil {
+		if issues := tool.OutputSchema.ValidateValue(result); len(issues) > 0 {
+			return nil, fmt.Errorf("tool %q: result does not match output schema: %s", tool.Name, joinIssues(issues))
+		}
+		if structured {
+			res.StructuredContent = json.RawMessage(data)
+		}
+	}
+	return res, nil
+}
+
+// structuredContentAllowed reports whether the calling client negotiated a
+// protocol revision with structured tool results. Revisions are dates, so
+// they compare as strings.
+func structuredContentAllowed(req *mcp.CallToolRequest) bool {
+	if req == nil || req.Session == nil {
+		return true
+	}
+	params := req.Session.InitializeParams()
+	return params == nil || params.ProtocolVersion == "" || params.ProtocolVersion >= MCPProtocol20250618
+}
+
+func invalidParams(message string) error {
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: message}
+}
+
+func joinIssues(issues []tooladapter.SchemaIssue) string {
+	parts := make([]string, len(issues))
+	for i, issue := range issues {
+		parts[i] = issue.String()
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,317 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connectMCPServer connects a client to the bridge over in-memory
+// transports and returns the client session.
+func connectMCPServer(t *testing.T, s *MCPServer) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := s.Server().Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func weatherTool() *tooladapter.CanonicalTool {
+	minLen := 1
+	return &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		Category:    "weather",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"city": {Type: "string", MinLength: &minLen},
+			},
+			Required: []string{"city"},
+		},
+		OutputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"temp": {Type: "number"},
+			},
+			Required: []string{"temp"},
+		},
+	}
+}
+
+// noArgsTool returns a function tool that takes an empty object.
+func noArgsTool(name string) *tooladapter.CanonicalTool {
+	return &tooladapter.CanonicalTool{Name: name, InputSchema: &tooladapter.JSONSchema{Type: "object"}}
+}
+
+func newTestMCPServer() *MCPServer {
+	return NewMCPServer(&mcp.Implementation{Name: "test-server", Version: "v0.0.1"}, nil)
+}
+
+func TestMCPServer_ListTools(t *testing.T) {
+	s := newTestMCPServer()
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return nil, nil }
+	if err := s.AddTool(weatherTool(), handler); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	if err := s.AddTool(noArgsTool("ping"), handler); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	session := connectMCPServer(t, s)
+	res, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(res.Tools) != 2 {
+		t.Fatalf("ListTools() returned %d tools, want 2", len(res.Tools))
+	}
+
+	// Listed tools read back into the canonical form they were served from
+	for _, listed := range res.Tools {
+		got, err := NewMCPAdapter().ToCanonical(listed)
+		if err != nil {
+			t.Fatalf("ToCanonical(%s) error = %v", listed.Name, err)
+		}
+		if listed.Name == "get_weather" {
+			if diffs := tooladapter.DiffCanonical(weatherTool(), got); len(diffs) != 0 {
+				t.Errorf("served tool diffs = %v, want none", diffs)
+			}
+		} else if diffs := tooladapter.DiffCanonical(noArgsTool("ping"), got); len(diffs) != 0 {
+			t.Errorf("served tool diffs = %v, want none", diffs)
+		}
+	}
+}
+
+func TestMCPServer_CallTool(t *testing.T) {
+	s := newTestMCPServer()
+	var gotArgs map[string]any
+	err := s.AddTool(weatherTool(), func(_ context.Context, _ *mcp.CallToolRequest, args map[string]any) (any, error) {
+		gotArgs = args
+		return map[string]any{"temp": 21.5}, nil
+	})
+	if err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	session := connectMCPServer(t, s)
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_weather",
+		Arguments: map[string]any{"city": "Paris"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("CallTool() IsError = true, content = %v", res.Content)
+	}
+	if gotArgs["city"] != "Paris" {
+		t.Errorf("handler args = %v, want city Paris", gotArgs)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != `{"temp":21.5}` {
+		t.Errorf("Content = %q, want JSON result", text)
+	}
+	data, _ := json.Marshal(res.StructuredContent)
+	if string(data) != `{"temp":21.5}` {
+		t.Errorf("StructuredContent = %s, want {\"temp\":21.5}", data)
+	}
+}
+
+func TestMCPServer_CallTool_InvalidArguments(t *testing.T) {
+	s := newTestMCPServer()
+	called := false
+	err := s.AddTool(weatherTool(), func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) {
+		called = true
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	session := connectMCPServer(t, s)
+
+	for name, args := range map[string]any{
+		"missing required": map[string]any{},
+		"wrong type":       map[string]any{"city": 42},
+		"too short":        map[string]any{"city": ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_weather", Arguments: args})
+			var wireErr *jsonrpc.Error
+			if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidParams {
+				t.Fatalf("CallTool() error = %v, want invalid params", err)
+			}
+			if !strings.Contains(wireErr.Message, "/city") && name != "missing required" {
+				t.Errorf("error message = %q, want path /city", wireErr.Message)
+			}
+		})
+	}
+	if called {
+		t.Error("handler called with invalid arguments")
+	}
+}
+
+func TestMCPServer_CallTool_Results(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    any
+		err       error
+		wantText  string
+		wantError bool
+	}{
+		{name: "string", result: "sunny", wantText: "sunny"},
+		{name: "tool error", err: errors.New("city not found"), wantText: "city not found", wantError: true},
+		{name: "call result", result: &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "raw"}}}, wantText: "raw"},
+		{name: "json", result: []int{1, 2}, wantText: "[1,2]"},
+	}
+
+	s := newTestMCPServer()
+	for _, tt := range tests {
+		tt := tt
+		err := s.AddTool(noArgsTool(strings.ReplaceAll(tt.name, " ", "_")), func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) {
+			return tt.result, tt.err
+		})
+		if err != nil {
+			t.Fatalf("AddTool() error = %v", err)
+		}
+	}
+	session := connectMCPServer(t, s)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: strings.ReplaceAll(tt.name, " ", "_")})
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if res.IsError != tt.wantError {
+				t.Errorf("IsError = %v, want %v", res.IsError, tt.wantError)
+			}
+			if len(res.Content) != 1 || res.Content[0].(*mcp.TextContent).Text != tt.wantText {
+				t.Errorf("Content = %v, want text %q", res.Content, tt.wantText)
+			}
+			if res.StructuredContent != nil {
+				t.Errorf("StructuredContent = %v, want nil without an output schema", res.StructuredContent)
+			}
+		})
+	}
+}
+
+func TestMCPServer_CallTool_ProtocolErrors(t *testing.T) {
+	s := newTestMCPServer()
+	_ = s.AddTool(weatherTool(), func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) {
+		return map[string]any{"temp": "warm"}, nil
+	})
+	_ = s.AddTool(noArgsTool("forbidden"), func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidRequest, Message: "forbidden"}
+	})
+	session := connectMCPServer(t, s)
+
+	_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_weather", Arguments: map[string]any{"city": "Paris"}})
+	if err == nil || !strings.Contains(err.Error(), "output schema") {
+		t.Errorf("CallTool(bad output) error = %v, want output schema error", err)
+	}
+
+	_, err = session.CallTool(context.Background(), &mcp.CallToolParams{Name: "forbidden"})
+	var wireErr *jsonrpc.Error
+	if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidRequest {
+		t.Errorf("CallTool(forbidden) error = %v, want invalid request", err)
+	}
+}
+
+func TestMCPServer_RemoveTools(t *testing.T) {
+	s := newTestMCPServer()
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return "ok", nil }
+	_ = s.AddTool(noArgsTool("a"), handler)
+	_ = s.AddTool(noArgsTool("b"), handler)
+	s.RemoveTools("a")
+
+	session := connectMCPServer(t, s)
+	res, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(res.Tools) != 1 || res.Tools[0].Name != "b" {
+		t.Errorf("ListTools() = %v, want only b", res.Tools)
+	}
+}
+
+func TestMCPServer_AddTool_Errors(t *testing.T) {
+	handler := func(context.Context, *mcp.CallToolRequest, map[string]any) (any, error) { return nil, nil }
+
+	tests := []struct {
+		name    string
+		tool    *tooladapter.CanonicalTool
+		handler MCPToolHandler
+	}{
+		{"nil tool", nil, handler},
+		{"nil handler", weatherTool(), nil},
+		{"invalid tool", &tooladapter.CanonicalTool{}, handler},
+		{"non-object input", &tooladapter.CanonicalTool{Name: "t", InputSchema: &tooladapter.JSONSchema{Type: "string"}}, handler},
+		{"missing input schema", &tooladapter.CanonicalTool{Name: "t"}, handler},
+		{"non-object output", &tooladapter.CanonicalTool{Name: "t", InputSchema: &tooladapter.JSONSchema{Type: "object"}, OutputSchema: &tooladapter.JSONSchema{Type: "string"}}, handler},
+		{"custom tool", &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "t"}, handler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := newTestMCPServer().AddTool(tt.tool, tt.handler); err == nil {
+				t.Error("AddTool() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestMCPServer_ServesOpenAITools(t *testing.T) {
+	canonical, err := NewOpenAIAdapter().ToCanonical(map[string]any{
+		"type": "function",
+		"function": map[string]any{
+			"name":        "add",
+			"description": "Add two numbers.",
+			"parameters": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"a": map[string]any{"type": "number"},
+					"b": map[string]any{"type": "number"},
+				},
+				"required": []any{"a", "b"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	s := newTestMCPServer()
+	err = s.AddTool(canonical, func(_ context.Context, _ *mcp.CallToolRequest, args map[string]any) (any, error) {
+		return map[string]any{"sum": args["a"].(float64) + args["b"].(float64)}, nil
+	})
+	if err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+
+	session := connectMCPServer(t, s)
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "add", Arguments: map[string]any{"a": 2, "b": 3}})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != `{"sum":5}` {
+		t.Errorf("Content = %q, want {\"sum\":5}", text)
+	}
+}
@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/jonwraymond/tooladapter"
@@ -425,3 +426,372 @@ func TestMCPAdapter_ToCanonical_WithCombinators(t *testing.T) {
 		t.Errorf("AnyOf length = %d, want 2", len(got.InputSchema.AnyOf))
 	}
 }
+
+func TestMCPAdapter_ToCanonical_TypedSlices(t *testing.T) {
+	adapter := NewMCPAdapter()
+
+	// ToMap emits Required as []string; it must survive being read back
+	mcpTool := mcp.Tool{
+		Name: "typed-slices",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"unit": map[string]any{
+					"type": "string",
+					"enum": []string{"c", "f"},
+				},
+			},
+			"required": []string{"unit"},
+		},
+	}
+
+	got, err := adapter.ToCanonical(mcpTool)
+
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if len(got.InputSchema.Required) != 1 || got.InputSchema.Required[0] != "unit" {
+		t.Errorf("Required = %v, want [unit]", got.InputSchema.Required)
+	}
+	if len(got.InputSchema.Properties["unit"].Enum) != 2 {
+		t.Errorf("Enum = %v, want 2 values", got.InputSchema.Properties["unit"].Enum)
+	}
+}
+
+func TestMCPAdapter_RoundTripCheck_Lossless(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+
+	mcpTool := mcp.Tool{
+		Name: "lossless",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"q": map[string]any{"type": "string", "minLength": 1},
+			},
+			"required": []any{"q"},
+		},
+	}
+
+	result, err := registry.Convert(mcpTool, "mcp", "openai", tooladapter.WithRoundTripCheck())
+
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !result.RoundTrip.Lossless() {
+		t.Errorf("RoundTrip.Diffs = %v, want none", result.RoundTrip.Diffs)
+	}
+}
+
+func TestMCPAdapter_RoundTripCheck_UnknownKeywords(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewGeminiAdapter())
+
+	mcpTool := mcp.Tool{
+		Name: "lookup",
+		InputSchema: map[string]any{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type":    "object",
+			"properties": map[string]any{
+				"q":      map[string]any{"type": "string", "title": "Query", "examples": []any{"paris"}},
+				"n":      map[string]any{"type": "integer", "exclusiveMinimum": 0},
+				"labels": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			},
+		},
+	}
+
+	result, err := registry.Convert(mcpTool, "mcp", "mcp", tooladapter.WithRoundTripCheck())
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !result.RoundTrip.Lossless() {
+		t.Errorf("RoundTrip.Diffs = %v, want none", result.RoundTrip.Diffs)
+	}
+	props := result.Tool.(mcp.Tool).InputSchema.(map[string]any)["properties"].(map[string]any)
+	if props["q"].(map[string]any)["title"] != "Query" {
+		t.Errorf("q = %v, want title kept", props["q"])
+	}
+	labels := props["labels"].(map[string]any)
+	if !reflect.DeepEqual(labels["additionalProperties"], map[string]any{"type": "string"}) {
+		t.Errorf("labels = %v, want additionalProperties schema kept", labels)
+	}
+
+	// A target that cannot carry them reports the loss
+	result, err = registry.Convert(mcpTool, "mcp", "gemini", tooladapter.WithRoundTripCheck())
+	if err != nil {
+		t.Fatalf("Convert() gemini error = %v", err)
+	}
+	lost := make(map[string]bool)
+	for _, d := range result.RoundTrip.Diffs {
+		lost[d.Path] = true
+	}
+	for _, issue := range result.Issues {
+		lost[issue.Path+" "+issue.Keyword] = true
+	}
+	for _, want := range []string{
+		"/inputSchema/$schema",
+		"/inputSchema/properties/q/title",
+		"/inputSchema/properties/n/exclusiveMinimum",
+		"/inputSchema $schema",
+		"/inputSchema/properties/labels additionalProperties",
+	} {
+		if !lost[want] {
+			t.Errorf("missing loss %q in diffs %v and issues %v", want, result.RoundTrip.Diffs, result.Issues)
+		}
+	}
+}
+
+func TestMCPAdapter_ExamplesInDescription(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Examples:    []map[string]any{{"city": "Paris"}},
+	}
+
+	out, err := NewMCPAdapter().FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if got := out.(mcp.Tool).Description; got != "Get the weather." {
+		t.Errorf("Description without rendering = %q, want %q", got, "Get the weather.")
+	}
+
+	adapter := NewMCPAdapter().WithExamplesInDescription()
+	out, err = adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}"
+	if got := out.(mcp.Tool).Description; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(tool, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+
+	invalid := *tool
+	invalid.InputSchema = &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}}}
+	invalid.Examples = []map[string]any{{"city": 42}}
+	if _, err := adapter.FromCanonical(&invalid); err == nil {
+		t.Error("FromCanonical() with invalid example error = nil, want error")
+	}
+}
+
+func TestMCPAdapter_Annotations_RoundTrip(t *testing.T) {
+	adapter := NewMCPAdapter()
+	no := false
+	original := mcp.Tool{
+		Name:        "search",
+		InputSchema: map[string]any{"type": "object"},
+		Annotations: &mcp.ToolAnnotations{
+			Title:          "Search",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+			OpenWorldHint:  &no,
+		},
+	}
+
+	canonical, err := adapter.ToCanonical(original)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	a := canonical.Annotations
+	if a == nil || a.Title != "Search" || !a.ReadOnlyHint || !a.IdempotentHint || a.OpenWorldHint == nil || *a.OpenWorldHint || a.DestructiveHint != nil {
+		t.Fatalf("Annotations = %+v, want hints copied from MCP", a)
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	got := out.(mcp.Tool).Annotations
+	if got == nil || got.Title != "Search" || !got.ReadOnlyHint || !got.IdempotentHint || got.OpenWorldHint == nil || *got.OpenWorldHint {
+		t.Errorf("Annotations = %+v, want %+v", got, original.Annotations)
+	}
+	if got.OpenWorldHint == original.Annotations.OpenWorldHint {
+		t.Error("FromCanonical() shares the OpenWorldHint pointer with the input")
+	}
+}
+
+func TestMCPAdapter_Annotations_LossWarnings(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+
+	tool := mcp.Tool{
+		Name:        "delete_file",
+		InputSchema: map[string]any{"type": "object"},
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: new(bool)},
+	}
+
+	for target, wantLoss := range map[string]bool{"mcp": false, "openai": true, "openai:strict": true} {
+		result, err := registry.Convert(tool, "mcp", target)
+		if err != nil {
+			t.Fatalf("Convert(%s) error = %v", target, err)
+		}
+		gotLoss := false
+		for _, w := range result.Warnings {
+			if w.Feature == tooladapter.FeatureAnnotations {
+				gotLoss = true
+			}
+		}
+		if gotLoss != wantLoss {
+			t.Errorf("Convert(%s) annotations loss = %v, want %v", target, gotLoss, wantLoss)
+		}
+	}
+}
+
+func TestMCPAdapter_WithMode(t *testing.T) {
+	adapter := NewMCPAdapter()
+
+	for _, mode := range []string{"", MCPProtocol20241105, MCPProtocol20250326, MCPProtocol20250618, MCPProtocol20251125} {
+		bound, err := adapter.WithMode(mode)
+		if err != nil {
+			t.Fatalf("WithMode(%q) error = %v", mode, err)
+		}
+		if got := bound.(*MCPAdapter).ModeOf(nil); got != mode {
+			t.Errorf("ModeOf() = %q, want %q", got, mode)
+		}
+	}
+	if _, err := adapter.WithMode("2023-01-01"); err == nil {
+		t.Error("WithMode(2023-01-01) error = nil, want error")
+	}
+	if adapter.mode != "" {
+		t.Error("WithMode() modified the receiver")
+	}
+}
+
+func TestMCPAdapter_FromCanonical_ProtocolVersions(t *testing.T) {
+	canonical, err := NewMCPAdapter().ToCanonical(fullMCPTool())
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	tests := []struct {
+		mode            string
+		wantAnnotations bool
+		wantTitle       bool
+		wantOutput      bool
+		wantMeta        bool
+		wantIcons       bool
+	}{
+		{MCPProtocol20241105, false, false, false, false, false},
+		{MCPProtocol20250326, true, false, false, false, false},
+		{MCPProtocol20250618, true, true, true, true, false},
+		{MCPProtocol20251125, true, true, true, true, true},
+		{"", true, true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			adapter, _ := NewMCPAdapter().WithMode(tt.mode)
+			out, err := adapter.FromCanonical(canonical)
+			if err != nil {
+				t.Fatalf("FromCanonical() error = %v", err)
+			}
+			got := out.(mcp.Tool)
+			if got.Name != "search" || got.InputSchema == nil {
+				t.Errorf("Name, InputSchema = %q, %v, want both emitted", got.Name, got.InputSchema)
+			}
+			if (got.Annotations != nil) != tt.wantAnnotations {
+				t.Errorf("Annotations = %v, want emitted %v", got.Annotations, tt.wantAnnotations)
+			}
+			if (got.Title != "") != tt.wantTitle {
+				t.Errorf("Title = %q, want emitted %v", got.Title, tt.wantTitle)
+			}
+			if (got.OutputSchema != nil) != tt.wantOutput {
+				t.Errorf("OutputSchema = %v, want emitted %v", got.OutputSchema, tt.wantOutput)
+			}
+			if (got.Meta != nil) != tt.wantMeta {
+				t.Errorf("Meta = %v, want emitted %v", got.Meta, tt.wantMeta)
+			}
+			if (got.Icons != nil) != tt.wantIcons {
+				t.Errorf("Icons = %v, want emitted %v", got.Icons, tt.wantIcons)
+			}
+		})
+	}
+}
+
+func TestMCPAdapter_ToCanonical_RecordsProtocolVersion(t *testing.T) {
+	adapter, _ := NewMCPAdapter().WithMode(MCPProtocol20250618)
+	got, err := adapter.ToCanonical(mcp.Tool{Name: "t", InputSchema: map[string]any{"type": "object"}})
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if got.SourceMeta["protocolVersion"] != MCPProtocol20250618 {
+		t.Errorf("SourceMeta[protocolVersion] = %v, want %s", got.SourceMeta["protocolVersion"], MCPProtocol20250618)
+	}
+
+	got, _ = NewMCPAdapter().ToCanonical(mcp.Tool{Name: "t", InputSchema: map[string]any{"type": "object"}})
+	if _, ok := got.SourceMeta["protocolVersion"]; ok {
+		t.Error("SourceMeta[protocolVersion] set by an adapter without a protocol version")
+	}
+}
+
+func TestMCPAdapter_ProtocolVersion_LossWarnings(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+
+	tests := map[string][]tooladapter.SchemaFeature{
+		"mcp:" + MCPProtocol20241105: {
+			tooladapter.FeatureAnnotations,
+			tooladapter.FeatureOutputSchema,
+			tooladapter.FeatureTitle,
+			tooladapter.FeatureIcons,
+			tooladapter.FeatureMetadata,
+		},
+		"mcp:" + MCPProtocol20250618: {tooladapter.FeatureIcons},
+		"mcp:" + MCPProtocol20251125: nil,
+		"mcp":                        nil,
+	}
+
+	for target, want := range tests {
+		t.Run(target, func(t *testing.T) {
+			result, err := registry.Convert(fullMCPTool(), "mcp", target)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			var got []tooladapter.SchemaFeature
+			for _, w := range result.Warnings {
+				got = append(got, w.Feature)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("warned features = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMCPAdapter_ToolFeatures_ToOpenAI(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+
+	result, err := registry.Convert(fullMCPTool(), "mcp", "openai")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	lost := make(map[tooladapter.SchemaFeature]bool)
+	for _, w := range result.Warnings {
+		lost[w.Feature] = true
+	}
+	for _, f := range []tooladapter.SchemaFeature{
+		tooladapter.FeatureAnnotations,
+		tooladapter.FeatureOutputSchema,
+		tooladapter.FeatureTitle,
+		tooladapter.FeatureIcons,
+		tooladapter.FeatureMetadata,
+	} {
+		if !lost[f] {
+			t.Errorf("no %s loss warning converting to openai", f)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package adapters
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/jonwraymond/tooladapter"
 )
@@ -22,51 +23,145 @@ type OpenAIFunction struct {
 	Strict bool `json:"strict,omitempty"`
 }
 
+// OpenAIModeStrict is the OpenAI adapter mode for strict function calling
+// (structured outputs). The default mode ("") is non-strict.
+const OpenAIModeStrict = "strict"
+
 // OpenAIAdapter converts between OpenAI function format and canonical format.
-type OpenAIAdapter struct{}
+//
+// The adapter has two modes with different capability sets: non-strict
+// (the default) and strict. An adapter in the default mode emits strict
+// functions only for tools whose SourceMeta["strict"] is set; an adapter
+// bound to strict mode via WithMode emits every tool as strict.
+type OpenAIAdapter struct {
+	mode              string
+	profile           *tooladapter.CapabilityProfile
+	envelope          OpenAIEnvelope
+	renderExamples    bool
+	renderAnnotations bool
+}
 
-// NewOpenAIAdapter creates a new OpenAI adapter.
+// NewOpenAIAdapter creates a new OpenAI adapter in the default mode.
 func NewOpenAIAdapter() *OpenAIAdapter {
 	return &OpenAIAdapter{}
 }
 
+// Mode returns the mode the adapter is bound to ("" for the default).
+func (a *OpenAIAdapter) Mode() string {
+	return a.mode
+}
+
+// ModeOf returns the mode a tool is emitted in: the bound mode if set,
+// otherwise strict when SourceMeta["strict"] is true, unless the tool is
+// emitted in the Realtime envelope, which has no strict mode.
+func (a *OpenAIAdapter) ModeOf(tool *tooladapter.CanonicalTool) string {
+	if a.mode != "" {
+		return a.mode
+	}
+	if tool != nil && tool.SourceMeta != nil {
+		if envelope, err := a.envelopeOf(tool); err == nil && envelope == OpenAIEnvelopeRealtime {
+			return ""
+		}
+		if strict, ok := tool.SourceMeta["strict"].(bool); ok && strict {
+			return OpenAIModeStrict
+		}
+	}
+	return ""
+}
+
+// WithMode returns an OpenAI adapter bound to the given mode.
+// Returns an error if no capability profile exists for the mode, or for
+// strict mode when the adapter emits the Realtime envelope.
+func (a *OpenAIAdapter) WithMode(mode string) (tooladapter.Adapter, error) {
+	if _, ok := builtinProfile(a.Name(), mode); !ok {
+		return nil, fmt.Errorf("openai adapter: unknown mode %q", mode)
+	}
+	if mode == OpenAIModeStrict && a.envelope == OpenAIEnvelopeRealtime {
+		return nil, errRealtimeStrict
+	}
+	copied := *a
+	copied.mode = mode
+	copied.profile = nil
+	return &copied, nil
+}
+
+// WithProfile returns an OpenAI adapter whose strict-mode compiler follows
+// the given profile. Only a strict-mode profile changes the output, since
+// non-strict parameters are copied as they are.
+func (a *OpenAIAdapter) WithProfile(profile *tooladapter.CapabilityProfile) tooladapter.Adapter {
+	copied := *a
+	copied.profile = profile.Clone()
+	return &copied
+}
+
+// WithExamplesInDescription returns a copy of the adapter that renders tool
+// examples into function descriptions, since OpenAI has no examples field.
+// ToCanonical on the copy splits rendered examples back out.
+func (a *OpenAIAdapter) WithExamplesInDescription() *OpenAIAdapter {
+	copied := *a
+	copied.renderExamples = true
+	return &copied
+}
+
+// WithAnnotationsInDescription returns a copy of the adapter that prefixes
+// function descriptions with the tool's title and behavioral hints, since
+// OpenAI has no annotations field. ToCanonical on the copy parses the
+// prefix back.
+func (a *OpenAIAdapter) WithAnnotationsInDescription() *OpenAIAdapter {
+	copied := *a
+	copied.renderAnnotations = true
+	return &copied
+}
+
 // Name returns the adapter identifier.
 func (a *OpenAIAdapter) Name() string {
 	return "openai"
 }
 
-// ToCanonical converts an OpenAI function to canonical format.
-// Accepts OpenAIFunction or *OpenAIFunction.
+// ToCanonical converts an OpenAI tool to canonical format.
+// Accepts OpenAIFunction, OpenAIChatTool, OpenAIFlatTool, OpenAICustomTool,
+// OpenAIChatCustomTool and OpenAIHostedTool (or pointers to them), and raw
+// JSON in any envelope as json.RawMessage, []byte or map[string]any.
 func (a *OpenAIAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
-	var fn OpenAIFunction
-
-	switch v := raw.(type) {
-	case OpenAIFunction:
-		fn = v
-	case *OpenAIFunction:
-		if v == nil {
-			return nil, errors.New("nil OpenAIFunction pointer")
-		}
-		fn = *v
-	default:
-		return nil, errors.New("expected OpenAIFunction or *OpenAIFunction")
+	entry, err := decodeOpenAITool(raw)
+	if err != nil {
+		return nil, err
 	}
+	fn := entry.fn
 
 	canonical := &tooladapter.CanonicalTool{
+		Kind:         entry.kind,
+		Custom:       entry.custom,
+		Hosted:       entry.hosted,
 		Name:         fn.Name,
 		Description:  fn.Description,
 		SourceFormat: "openai",
 		SourceMeta:   make(map[string]any),
 	}
 
+	if a.renderExamples && entry.kind == tooladapter.ToolKindFunction {
+		canonical.Description, canonical.Examples = tooladapter.SplitRenderedExamples(canonical.Description)
+	}
+	if a.renderAnnotations && entry.kind == tooladapter.ToolKindFunction {
+		canonical.Description, canonical.Annotations = tooladapter.SplitRenderedAnnotations(canonical.Description)
+	}
+
 	// Store OpenAI-specific fields in SourceMeta for round-trip
 	if fn.Strict {
 		canonical.SourceMeta["strict"] = true
 	}
+	if entry.envelope != OpenAIEnvelopeFunction {
+		canonical.SourceMeta["envelope"] = string(entry.envelope)
+	}
 
-	// Convert parameters schema
+	// Convert parameters schema; strict schemas encode optional fields as
+	// nullable required fields, which are mapped back to optional
 	if fn.Parameters != nil {
-		schema, err := mapToJSONSchema(fn.Parameters)
+		params := fn.Parameters
+		if fn.Strict {
+			params = RelaxStrictSchema(params)
+		}
+		schema, err := mapToJSONSchema(params)
 		if err != nil {
 			return nil, err
 		}
@@ -77,55 +172,125 @@ func (a *OpenAIAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error)
 }
 
 // FromCanonical converts a canonical tool to OpenAI format.
+// The result is an OpenAIFunction unless an envelope is selected with
+// WithEnvelope or recorded in SourceMeta["envelope"], in which case it is
+// the matching OpenAIChatTool or OpenAIFlatTool.
+//
+// Custom tools become OpenAIChatCustomTool or OpenAICustomTool, and hosted
+// tools become OpenAIHostedTool; the bare function envelope cannot carry a
+// tool type, so those kinds use the Responses shape instead.
 func (a *OpenAIAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
 	if tool == nil {
 		return nil, errors.New("nil CanonicalTool")
 	}
 
+	envelope, err := a.envelopeOf(tool)
+	if err != nil {
+		return nil, err
+	}
+	if a.mode == OpenAIModeStrict && envelope == OpenAIEnvelopeRealtime {
+		return nil, errRealtimeStrict
+	}
+
+	switch tool.Kind {
+	case "", tooladapter.ToolKindFunction:
+	case tooladapter.ToolKindCustom:
+		return encodeCustomTool(tool, envelope)
+	case tooladapter.ToolKindHosted:
+		return encodeHostedTool(tool, envelope)
+	default:
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
+
 	fn := OpenAIFunction{
 		Name:        tool.Name,
 		Description: tool.Description,
 	}
-
-	// Restore OpenAI-specific fields from SourceMeta
-	if tool.SourceMeta != nil {
-		if strict, ok := tool.SourceMeta["strict"].(bool); ok && strict {
-			fn.Strict = true
+	if a.renderAnnotations {
+		fn.Description = tooladapter.RenderAnnotations(fn.Description, tool.Annotations)
+	}
+	if a.renderExamples {
+		if err := tool.ValidateExamples(); err != nil {
+			return nil, err
 		}
+		fn.Description = tooladapter.RenderExamples(fn.Description, tool.Examples)
 	}
 
-	// Convert input schema to parameters map
-	if tool.InputSchema != nil {
-		params := tool.InputSchema.ToMap()
+	fn.Strict = a.ModeOf(tool) == OpenAIModeStrict
 
-		// In strict mode, enforce additionalProperties=false at root
+	// Convert input schema to parameters map, compiling it into strict
+	// form when strict mode is enabled
+	if tool.InputSchema != nil {
 		if fn.Strict {
-			params["additionalProperties"] = false
+			fn.Parameters, _ = compileStrictSchema(tool.InputSchema, a.strictProfile())
+		} else {
+			fn.Parameters = tool.InputSchema.ToMap()
 		}
+	}
+
+	return encodeOpenAITool(fn, envelope), nil
+}
 
-		fn.Parameters = params
+// SchemaIssues returns what the strict-mode compiler drops or rewrites in
+// a function tool's input schema, such as oneOf approximated by anyOf.
+// Tools emitted without strict mode have no issues.
+func (a *OpenAIAdapter) SchemaIssues(tool *tooladapter.CanonicalTool) []tooladapter.SchemaIssue {
+	if tool == nil || tool.InputSchema == nil {
+		return nil
+	}
+	if tool.Kind != "" && tool.Kind != tooladapter.ToolKindFunction {
+		return nil
+	}
+	if a.ModeOf(tool) != OpenAIModeStrict {
+		return nil
+	}
+	_, issues := compileStrictSchema(tool.InputSchema, a.strictProfile())
+	return schemaIssuesAt("/inputSchema", issues)
+}
+
+// envelopeOf returns the envelope a tool is emitted in: the configured one,
+// or the one recorded in SourceMeta["envelope"], or the bare function.
+func (a *OpenAIAdapter) envelopeOf(tool *tooladapter.CanonicalTool) (OpenAIEnvelope, error) {
+	if a.envelope != "" {
+		return a.envelope, nil
+	}
+	if name, ok := tool.SourceMeta["envelope"].(string); ok {
+		return ParseOpenAIEnvelope(name)
 	}
+	return OpenAIEnvelopeFunction, nil
+}
+
+// errRealtimeStrict rejects strict mode for the Realtime envelope, whose
+// tools have no strict flag.
+var errRealtimeStrict = errors.New("openai adapter: the realtime envelope has no strict mode")
 
-	return fn, nil
+// strictProfile returns the profile the strict-mode compiler follows: the
+// bound strict profile, or the built-in one.
+func (a *OpenAIAdapter) strictProfile() *tooladapter.CapabilityProfile {
+	if a.profile != nil && a.profile.Mode == OpenAIModeStrict {
+		return a.profile
+	}
+	p, _ := builtinProfile(a.Name(), OpenAIModeStrict)
+	return p
 }
 
 // SupportsFeature returns whether this adapter supports a schema feature.
-// OpenAI has limited schema support compared to full JSON Schema.
+// Answers come from the built-in capability profile of the bound mode;
+// annotations are also supported when rendered into descriptions.
 func (a *OpenAIAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
-	switch feature {
-	case tooladapter.FeatureRef:
-		return false // OpenAI doesn't support $ref
-	case tooladapter.FeatureDefs:
-		return false // OpenAI doesn't support $defs
-	case tooladapter.FeatureAnyOf:
-		return false // Limited/no support
-	case tooladapter.FeatureOneOf:
-		return false // Limited/no support
-	case tooladapter.FeatureAllOf:
-		return false // Limited/no support
-	case tooladapter.FeatureNot:
-		return false // Not supported
-	default:
-		return true // Other features are generally supported
+	if feature == tooladapter.FeatureAnnotations && a.renderAnnotations {
+		return true
+	}
+	return supportsBuiltin(a.Name(), a.mode, feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Supported modes are "" (default) and "strict". Annotations are marked
+// supported when rendered into descriptions.
+func (a *OpenAIAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	p, ok := builtinProfile(a.Name(), mode)
+	if ok && a.renderAnnotations {
+		p.Features[tooladapter.FeatureAnnotations] = true
 	}
+	return p, ok
 }
@@ -0,0 +1,284 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// OpenAIEnvelope names the wire shape an OpenAI tool definition is wrapped in.
+type OpenAIEnvelope string
+
+const (
+	// OpenAIEnvelopeFunction is the bare function object used by the legacy
+	// functions array: {"name", "description", "parameters"}
+	OpenAIEnvelopeFunction OpenAIEnvelope = "function"
+
+	// OpenAIEnvelopeChatCompletions is the Chat Completions tools entry:
+	// {"type": "function", "function": {...}}
+	OpenAIEnvelopeChatCompletions OpenAIEnvelope = "chat_completions"
+
+	// OpenAIEnvelopeResponses is the flat Responses API tools entry:
+	// {"type": "function", "name", "description", "parameters", "strict"}
+	OpenAIEnvelopeResponses OpenAIEnvelope = "responses"
+
+	// OpenAIEnvelopeRealtime is the flat Realtime API tools entry:
+	// {"type": "function", "name", "description", "parameters"}
+	OpenAIEnvelopeRealtime OpenAIEnvelope = "realtime"
+)
+
+// ParseOpenAIEnvelope returns the envelope with the given name.
+func ParseOpenAIEnvelope(name string) (OpenAIEnvelope, error) {
+	switch e := OpenAIEnvelope(name); e {
+	case OpenAIEnvelopeFunction, OpenAIEnvelopeChatCompletions, OpenAIEnvelopeResponses, OpenAIEnvelopeRealtime:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown openai envelope: %q", name)
+}
+
+// OpenAIChatTool is a Chat Completions tools entry wrapping a function.
+type OpenAIChatTool struct {
+	// Type is the tool type ("function")
+	Type string `json:"type"`
+
+	// Function is the wrapped function definition
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFlatTool is a Responses or Realtime API tools entry, where the
+// function fields sit next to the type instead of in a nested object.
+type OpenAIFlatTool struct {
+	// Type is the tool type ("function")
+	Type string `json:"type"`
+
+	// Name is the function identifier
+	Name string `json:"name"`
+
+	// Description explains what the function does
+	Description string `json:"description,omitempty"`
+
+	// Parameters is the JSON Schema for function arguments
+	Parameters map[string]any `json:"parameters"`
+
+	// Strict enables strict mode (Responses API only)
+	Strict bool `json:"strict,omitempty"`
+}
+
+// WithEnvelope returns a copy of the adapter that emits tools in the given
+// envelope from FromCanonical. ToCanonical accepts every envelope regardless.
+// Returns an error for the Realtime envelope on an adapter bound to strict
+// mode, since Realtime tools have no strict flag.
+func (a *OpenAIAdapter) WithEnvelope(name string) (*OpenAIAdapter, error) {
+	envelope, err := ParseOpenAIEnvelope(name)
+	if err != nil {
+		return nil, err
+	}
+	if envelope == OpenAIEnvelopeRealtime && a.mode == OpenAIModeStrict {
+		return nil, errRealtimeStrict
+	}
+	copied := *a
+	copied.envelope = envelope
+	return &copied, nil
+}
+
+// Envelope returns the envelope FromCanonical emits ("" means the envelope
+// recorded in SourceMeta, or the bare function when none is recorded).
+func (a *OpenAIAdapter) Envelope() OpenAIEnvelope {
+	return a.envelope
+}
+
+// ToCanonicalAll converts every tool in a raw OpenAI request body.
+// The body may be a request object with a "tools" or legacy "functions"
+// array, or a bare array of tool entries in any envelope.
+func (a *OpenAIAdapter) ToCanonicalAll(body []byte) ([]*tooladapter.CanonicalTool, error) {
+	var entries []json.RawMessage
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("decode openai tools: %w", err)
+		}
+	} else {
+		var req struct {
+			Tools     []json.RawMessage `json:"tools"`
+			Functions []json.RawMessage `json:"functions"`
+		}
+		if err := json.Unmarshal(trimmed, &req); err != nil {
+			return nil, fmt.Errorf("decode openai request: %w", err)
+		}
+		entries = append(req.Tools, req.Functions...)
+	}
+
+	tools := make([]*tooladapter.CanonicalTool, 0, len(entries))
+	for i, entry := range entries {
+		tool, err := a.ToCanonical(json.RawMessage(entry))
+		if err != nil {
+			return nil, fmt.Errorf("tool %d: %w", i, err)
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// decodeOpenAITool unwraps any supported OpenAI tool value and records the
+// envelope it arrived in.
+func decodeOpenAITool(raw any) (*openAITool, error) {
+	switch v := raw.(type) {
+	case OpenAIFunction:
+		return functionEntry(v, OpenAIEnvelopeFunction), nil
+	case *OpenAIFunction:
+		if v == nil {
+			return nil, errors.New("nil OpenAIFunction pointer")
+		}
+		return functionEntry(*v, OpenAIEnvelopeFunction), nil
+	case OpenAIChatTool:
+		return unwrapChatTool(v)
+	case *OpenAIChatTool:
+		if v == nil {
+			return nil, errors.New("nil OpenAIChatTool pointer")
+		}
+		return unwrapChatTool(*v)
+	case OpenAIFlatTool:
+		return unwrapFlatTool(v)
+	case *OpenAIFlatTool:
+		if v == nil {
+			return nil, errors.New("nil OpenAIFlatTool pointer")
+		}
+		return unwrapFlatTool(*v)
+	case OpenAICustomTool:
+		return responsesCustomEntry(v)
+	case *OpenAICustomTool:
+		if v == nil {
+			return nil, errors.New("nil OpenAICustomTool pointer")
+		}
+		return responsesCustomEntry(*v)
+	case OpenAIChatCustomTool:
+		return chatCustomEntry(v)
+	case *OpenAIChatCustomTool:
+		if v == nil {
+			return nil, errors.New("nil OpenAIChatCustomTool pointer")
+		}
+		return chatCustomEntry(*v)
+	case OpenAIHostedTool:
+		return hostedEntry(v)
+	case *OpenAIHostedTool:
+		if v == nil {
+			return nil, errors.New("nil OpenAIHostedTool pointer")
+		}
+		return hostedEntry(*v)
+	case json.RawMessage:
+		return decodeOpenAIJSON(v)
+	case []byte:
+		return decodeOpenAIJSON(v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return decodeOpenAIJSON(data)
+	default:
+		return nil, errors.New("expected an OpenAI function or tool entry")
+	}
+}
+
+func unwrapChatTool(t OpenAIChatTool) (*openAITool, error) {
+	if t.Type != "function" {
+		return nil, fmt.Errorf("unsupported openai tool type: %q", t.Type)
+	}
+	return functionEntry(t.Function, OpenAIEnvelopeChatCompletions), nil
+}
+
+// unwrapFlatTool converts a flat tool entry. The Responses and Realtime
+// shapes cannot be told apart on input, so Responses is recorded; select
+// OpenAIEnvelopeRealtime with WithEnvelope to emit the Realtime shape.
+func unwrapFlatTool(t OpenAIFlatTool) (*openAITool, error) {
+	if t.Type != "function" {
+		return nil, fmt.Errorf("unsupported openai tool type: %q", t.Type)
+	}
+	return functionEntry(OpenAIFunction{
+		Name:        t.Name,
+		Description: t.Description,
+		Parameters:  t.Parameters,
+		Strict:      t.Strict,
+	}, OpenAIEnvelopeResponses), nil
+}
+
+// decodeOpenAIJSON detects the kind and envelope of a JSON tool entry by
+// its shape.
+func decodeOpenAIJSON(data []byte) (*openAITool, error) {
+	var probe struct {
+		Type     string          `json:"type"`
+		Function json.RawMessage `json:"function"`
+		Custom   json.RawMessage `json:"custom"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decode openai tool: %w", err)
+	}
+
+	switch {
+	case probe.Type == "":
+		var fn OpenAIFunction
+		if err := json.Unmarshal(data, &fn); err != nil {
+			return nil, fmt.Errorf("decode openai function: %w", err)
+		}
+		return functionEntry(fn, OpenAIEnvelopeFunction), nil
+	case probe.Type == "function" && probe.Function != nil:
+		var t OpenAIChatTool
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("decode openai chat tool: %w", err)
+		}
+		return unwrapChatTool(t)
+	case probe.Type == "function":
+		var t OpenAIFlatTool
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("decode openai tool: %w", err)
+		}
+		return unwrapFlatTool(t)
+	case probe.Type == "custom" && probe.Custom != nil:
+		var t OpenAIChatCustomTool
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("decode openai chat custom tool: %w", err)
+		}
+		return chatCustomEntry(t)
+	case probe.Type == "custom":
+		var t OpenAICustomTool
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("decode openai custom tool: %w", err)
+		}
+		return responsesCustomEntry(t)
+	default:
+		var t OpenAIHostedTool
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("decode openai hosted tool: %w", err)
+		}
+		return hostedEntry(t)
+	}
+}
+
+// encodeOpenAITool wraps a function in the given envelope.
+func encodeOpenAITool(fn OpenAIFunction, envelope OpenAIEnvelope) any {
+	switch envelope {
+	case OpenAIEnvelopeChatCompletions:
+		return OpenAIChatTool{Type: "function", Function: fn}
+	case OpenAIEnvelopeResponses:
+		return OpenAIFlatTool{
+			Type:        "function",
+			Name:        fn.Name,
+			Description: fn.Description,
+			Parameters:  fn.Parameters,
+			Strict:      fn.Strict,
+		}
+	case OpenAIEnvelopeRealtime:
+		return OpenAIFlatTool{
+			Type:        "function",
+			Name:        fn.Name,
+			Description: fn.Description,
+			Parameters:  fn.Parameters,
+		}
+	default:
+		return fn
+	}
+}
@@ -0,0 +1,208 @@
+package adapters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestParseOpenAIEnvelope(t *testing.T) {
+	for _, name := range []string{"function", "chat_completions", "responses", "realtime"} {
+		if _, err := ParseOpenAIEnvelope(name); err != nil {
+			t.Errorf("ParseOpenAIEnvelope(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseOpenAIEnvelope("assistants"); err == nil {
+		t.Error("ParseOpenAIEnvelope(unknown) error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_ToCanonical_Envelopes(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+	params := map[string]any{"type": "object"}
+
+	tests := []struct {
+		name         string
+		raw          any
+		wantEnvelope any
+		wantStrict   bool
+	}{
+		{"chat struct", OpenAIChatTool{Type: "function", Function: OpenAIFunction{Name: "f", Parameters: params, Strict: true}}, "chat_completions", true},
+		{"flat struct", &OpenAIFlatTool{Type: "function", Name: "f", Parameters: params}, "responses", false},
+		{"chat json", json.RawMessage(`{"type":"function","function":{"name":"f","parameters":{"type":"object"}}}`), "chat_completions", false},
+		{"responses json", []byte(`{"type":"function","name":"f","parameters":{"type":"object"},"strict":true}`), "responses", true},
+		{"legacy json", []byte(`{"name":"f","parameters":{"type":"object"}}`), nil, false},
+		{"chat map", map[string]any{"type": "function", "function": map[string]any{"name": "f", "parameters": params}}, "chat_completions", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.ToCanonical(tt.raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Name != "f" {
+				t.Errorf("Name = %q, want %q", got.Name, "f")
+			}
+			if got.InputSchema == nil || got.InputSchema.Type != "object" {
+				t.Errorf("InputSchema = %+v, want object", got.InputSchema)
+			}
+			if got.SourceMeta["envelope"] != tt.wantEnvelope {
+				t.Errorf("SourceMeta[envelope] = %v, want %v", got.SourceMeta["envelope"], tt.wantEnvelope)
+			}
+			if (got.SourceMeta["strict"] == true) != tt.wantStrict {
+				t.Errorf("SourceMeta[strict] = %v, want %v", got.SourceMeta["strict"], tt.wantStrict)
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_ToCanonical_EnvelopeErrors(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	tests := map[string]any{
+		"non-function chat tool": OpenAIChatTool{Type: "code_interpreter"},
+		"nil chat pointer":       (*OpenAIChatTool)(nil),
+		"nil flat pointer":       (*OpenAIFlatTool)(nil),
+		"malformed json":         []byte(`{"type":`),
+		"unknown custom format":  []byte(`{"type":"custom","name":"x","format":{"type":"binary"}}`),
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := adapter.ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_WithEnvelope(t *testing.T) {
+	canonical := &tooladapter.CanonicalTool{
+		Name:        "f",
+		Description: "d",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		SourceMeta:  map[string]any{"strict": true},
+	}
+
+	tests := []struct {
+		envelope string
+		want     string
+	}{
+		{"function", `{"name":"f","description":"d","parameters":{"additionalProperties":false,"properties":{},"required":[],"type":"object"},"strict":true}`},
+		{"chat_completions", `{"type":"function","function":{"name":"f","description":"d","parameters":{"additionalProperties":false,"properties":{},"required":[],"type":"object"},"strict":true}}`},
+		{"responses", `{"type":"function","name":"f","description":"d","parameters":{"additionalProperties":false,"properties":{},"required":[],"type":"object"},"strict":true}`},
+		{"realtime", `{"type":"function","name":"f","description":"d","parameters":{"type":"object"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envelope, func(t *testing.T) {
+			adapter, err := NewOpenAIAdapter().WithEnvelope(tt.envelope)
+			if err != nil {
+				t.Fatalf("WithEnvelope() error = %v", err)
+			}
+			out, err := adapter.FromCanonical(canonical)
+			if err != nil {
+				t.Fatalf("FromCanonical() error = %v", err)
+			}
+			data, _ := json.Marshal(out)
+			if string(data) != tt.want {
+				t.Errorf("FromCanonical() JSON =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewOpenAIAdapter().WithEnvelope("bogus"); err == nil {
+		t.Error("WithEnvelope(unknown) error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_EnvelopeRoundTrip(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+	chat := OpenAIChatTool{Type: "function", Function: OpenAIFunction{Name: "f", Parameters: map[string]any{"type": "object"}}}
+
+	canonical, err := adapter.ToCanonical(chat)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+
+	if _, ok := out.(OpenAIChatTool); !ok {
+		t.Errorf("FromCanonical() type = %T, want OpenAIChatTool", out)
+	}
+}
+
+func TestOpenAIAdapter_ToCanonicalAll(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	bodies := map[string]string{
+		"chat request": `{"model":"gpt-4o","tools":[
+			{"type":"function","function":{"name":"a","parameters":{"type":"object"}}},
+			{"type":"function","function":{"name":"b","parameters":{"type":"object"}}}]}`,
+		"legacy request": `{"model":"gpt-3.5-turbo","functions":[
+			{"name":"a","parameters":{"type":"object"}},
+			{"name":"b","parameters":{"type":"object"}}]}`,
+		"bare array": `[{"type":"function","name":"a","parameters":{"type":"object"}},
+			{"type":"function","name":"b","parameters":{"type":"object"}}]`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			tools, err := adapter.ToCanonicalAll([]byte(body))
+			if err != nil {
+				t.Fatalf("ToCanonicalAll() error = %v", err)
+			}
+			if len(tools) != 2 || tools[0].Name != "a" || tools[1].Name != "b" {
+				t.Errorf("ToCanonicalAll() = %v, want tools a and b", tools)
+			}
+		})
+	}
+
+	if _, err := adapter.ToCanonicalAll([]byte(`{"tools":[{"type":"custom","name":"x","format":{"type":"binary"}}]}`)); err == nil {
+		t.Error("ToCanonicalAll() with unsupported tool error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_RealtimeHasNoStrictMode(t *testing.T) {
+	realtime, err := NewOpenAIAdapter().WithEnvelope("realtime")
+	if err != nil {
+		t.Fatalf("WithEnvelope() error = %v", err)
+	}
+	if _, err := realtime.WithMode(OpenAIModeStrict); err == nil {
+		t.Error("WithMode(strict) on realtime error = nil, want error")
+	}
+	strict, _ := NewOpenAIAdapter().WithMode(OpenAIModeStrict)
+	if _, err := strict.(*OpenAIAdapter).WithEnvelope("realtime"); err == nil {
+		t.Error("WithEnvelope(realtime) on strict error = nil, want error")
+	}
+
+	// A strict tool emitted in the Realtime envelope is converted in the
+	// default mode, so no strict-mode losses are reported
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(realtime)
+	flat := OpenAIFlatTool{
+		Type:       "function",
+		Name:       "f",
+		Parameters: map[string]any{"type": "object", "properties": map[string]any{"v": map[string]any{"type": "string", "minLength": 1}}},
+		Strict:     true,
+	}
+	result, err := registry.Convert(flat, "openai", "openai")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(result.Warnings) != 0 || len(result.Issues) != 0 {
+		t.Errorf("Convert() warnings = %v, issues = %v, want none", result.Warnings, result.Issues)
+	}
+
+	tool := &tooladapter.CanonicalTool{Name: "f", SourceMeta: map[string]any{"strict": true, "envelope": "realtime"}}
+	if mode := NewOpenAIAdapter().ModeOf(tool); mode != "" {
+		t.Errorf("ModeOf(realtime tool) = %q, want default mode", mode)
+	}
+	if _, err := strict.FromCanonical(tool); err == nil {
+		t.Error("strict FromCanonical(realtime tool) error = nil, want error")
+	}
+}
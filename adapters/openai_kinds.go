@@ -0,0 +1,239 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// OpenAICustomTool is a Responses API custom tool: the model sends free-form
+// text instead of JSON arguments, optionally constrained by a grammar.
+type OpenAICustomTool struct {
+	// Type is the tool type ("custom")
+	Type string `json:"type"`
+
+	// Name is the tool identifier
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// Format constrains the input; nil means unconstrained text
+	Format *OpenAICustomFormat `json:"format,omitempty"`
+}
+
+// OpenAICustomFormat is the input format of a Responses API custom tool.
+type OpenAICustomFormat struct {
+	// Type is "text" or "grammar"
+	Type string `json:"type"`
+
+	// Syntax is the grammar syntax ("lark" or "regex")
+	Syntax string `json:"syntax,omitempty"`
+
+	// Definition is the grammar source
+	Definition string `json:"definition,omitempty"`
+}
+
+// OpenAIChatCustomTool is a Chat Completions custom tool entry:
+// {"type": "custom", "custom": {...}}.
+type OpenAIChatCustomTool struct {
+	// Type is the tool type ("custom")
+	Type string `json:"type"`
+
+	// Custom is the wrapped custom tool definition
+	Custom OpenAIChatCustom `json:"custom"`
+}
+
+// OpenAIChatCustom is the custom tool definition inside OpenAIChatCustomTool.
+type OpenAIChatCustom struct {
+	// Name is the tool identifier
+	Name string `json:"name"`
+
+	// Description explains what the tool does
+	Description string `json:"description,omitempty"`
+
+	// Format constrains the input; nil means unconstrained text
+	Format *OpenAIChatCustomFormat `json:"format,omitempty"`
+}
+
+// OpenAIChatCustomFormat is the input format of a Chat Completions custom
+// tool, where the grammar is nested rather than flattened.
+type OpenAIChatCustomFormat struct {
+	// Type is "text" or "grammar"
+	Type string `json:"type"`
+
+	// Grammar is the grammar for the "grammar" type
+	Grammar *OpenAIGrammar `json:"grammar,omitempty"`
+}
+
+// OpenAIGrammar is a grammar definition for custom tool input.
+type OpenAIGrammar struct {
+	// Syntax is the grammar syntax ("lark" or "regex")
+	Syntax string `json:"syntax"`
+
+	// Definition is the grammar source
+	Definition string `json:"definition"`
+}
+
+// OpenAIHostedTool is a built-in tool executed by OpenAI, such as
+// web_search, file_search or code_interpreter. Its options sit next to the
+// type in JSON.
+type OpenAIHostedTool struct {
+	// Type is the built-in tool type (e.g., "web_search")
+	Type string
+
+	// Options holds the tool's remaining fields (e.g., "vector_store_ids")
+	Options map[string]any
+}
+
+// MarshalJSON flattens Options next to the type field.
+func (t OpenAIHostedTool) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(t.Options)+1)
+	for k, v := range t.Options {
+		m[k] = v
+	}
+	m["type"] = t.Type
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON splits the type field from the tool's options.
+func (t *OpenAIHostedTool) UnmarshalJSON(data []byte) error {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	typ, _ := m["type"].(string)
+	if typ == "" {
+		return errors.New("hosted tool type is required")
+	}
+	delete(m, "type")
+	t.Type = typ
+	t.Options = nil
+	if len(m) > 0 {
+		t.Options = m
+	}
+	return nil
+}
+
+// openAITool is a decoded OpenAI tools entry of any kind.
+type openAITool struct {
+	envelope OpenAIEnvelope
+	kind     tooladapter.ToolKind
+
+	// fn carries the name and description of every kind, and the
+	// parameters and strict flag of function tools
+	fn     OpenAIFunction
+	custom *tooladapter.CustomToolConfig
+	hosted *tooladapter.HostedToolConfig
+}
+
+func functionEntry(fn OpenAIFunction, envelope OpenAIEnvelope) *openAITool {
+	return &openAITool{envelope: envelope, kind: tooladapter.ToolKindFunction, fn: fn}
+}
+
+func customEntry(name, description string, format *OpenAICustomFormat, envelope OpenAIEnvelope) (*openAITool, error) {
+	custom := &tooladapter.CustomToolConfig{}
+	if format != nil {
+		switch format.Type {
+		case "text":
+		case "grammar":
+			custom.Syntax = format.Syntax
+			custom.Definition = format.Definition
+		default:
+			return nil, fmt.Errorf("unsupported custom tool format: %q", format.Type)
+		}
+	}
+	return &openAITool{
+		envelope: envelope,
+		kind:     tooladapter.ToolKindCustom,
+		fn:       OpenAIFunction{Name: name, Description: description},
+		custom:   custom,
+	}, nil
+}
+
+func chatCustomEntry(t OpenAIChatCustomTool) (*openAITool, error) {
+	if t.Type != "custom" {
+		return nil, fmt.Errorf("unsupported openai tool type: %q", t.Type)
+	}
+	var format *OpenAICustomFormat
+	if f := t.Custom.Format; f != nil {
+		format = &OpenAICustomFormat{Type: f.Type}
+		if f.Grammar != nil {
+			format.Syntax = f.Grammar.Syntax
+			format.Definition = f.Grammar.Definition
+		}
+	}
+	return customEntry(t.Custom.Name, t.Custom.Description, format, OpenAIEnvelopeChatCompletions)
+}
+
+func responsesCustomEntry(t OpenAICustomTool) (*openAITool, error) {
+	if t.Type != "custom" {
+		return nil, fmt.Errorf("unsupported openai tool type: %q", t.Type)
+	}
+	return customEntry(t.Name, t.Description, t.Format, OpenAIEnvelopeResponses)
+}
+
+// hostedEntry converts a built-in tool. Hosted tools have no name, so the
+// type doubles as the canonical name.
+func hostedEntry(t OpenAIHostedTool) (*openAITool, error) {
+	switch t.Type {
+	case "", "function", "custom":
+		return nil, fmt.Errorf("invalid hosted tool type: %q", t.Type)
+	}
+	return &openAITool{
+		envelope: OpenAIEnvelopeResponses,
+		kind:     tooladapter.ToolKindHosted,
+		fn:       OpenAIFunction{Name: t.Type},
+		hosted:   &tooladapter.HostedToolConfig{Provider: "openai", Type: t.Type, Options: copyOptions(t.Options)},
+	}, nil
+}
+
+// encodeCustomTool emits a custom tool in the given envelope.
+func encodeCustomTool(tool *tooladapter.CanonicalTool, envelope OpenAIEnvelope) (any, error) {
+	custom := tool.Custom
+	if custom == nil {
+		custom = &tooladapter.CustomToolConfig{}
+	}
+
+	switch envelope {
+	case OpenAIEnvelopeChatCompletions:
+		out := OpenAIChatCustomTool{
+			Type:   "custom",
+			Custom: OpenAIChatCustom{Name: tool.Name, Description: tool.Description},
+		}
+		if custom.Syntax != "" {
+			out.Custom.Format = &OpenAIChatCustomFormat{
+				Type:    "grammar",
+				Grammar: &OpenAIGrammar{Syntax: custom.Syntax, Definition: custom.Definition},
+			}
+		}
+		return out, nil
+	case OpenAIEnvelopeResponses, OpenAIEnvelopeFunction:
+		out := OpenAICustomTool{Type: "custom", Name: tool.Name, Description: tool.Description}
+		if custom.Syntax != "" {
+			out.Format = &OpenAICustomFormat{Type: "grammar", Syntax: custom.Syntax, Definition: custom.Definition}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("custom tools are not supported in the %s envelope", envelope)
+	}
+}
+
+// encodeHostedTool emits a built-in tool; only the Responses API accepts them.
+func encodeHostedTool(tool *tooladapter.CanonicalTool, envelope OpenAIEnvelope) (any, error) {
+	if tool.Hosted == nil || tool.Hosted.Type == "" {
+		return nil, errors.New("hosted tool type is required")
+	}
+	if tool.Hosted.Provider != "" && tool.Hosted.Provider != "openai" {
+		return nil, unsupportedKindError("openai", tool)
+	}
+	switch envelope {
+	case OpenAIEnvelopeResponses, OpenAIEnvelopeFunction:
+	default:
+		return nil, fmt.Errorf("hosted tools are not supported in the %s envelope", envelope)
+	}
+
+	return OpenAIHostedTool{Type: tool.Hosted.Type, Options: copyOptions(tool.Hosted.Options)}, nil
+}
@@ -0,0 +1,246 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestOpenAIAdapter_ToCanonical_CustomTools(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	tests := []struct {
+		name         string
+		raw          any
+		wantEnvelope string
+		wantCustom   tooladapter.CustomToolConfig
+	}{
+		{
+			name:         "responses text",
+			raw:          []byte(`{"type":"custom","name":"exec","description":"Run code","format":{"type":"text"}}`),
+			wantEnvelope: "responses",
+		},
+		{
+			name:         "responses grammar",
+			raw:          []byte(`{"type":"custom","name":"exec","description":"Run code","format":{"type":"grammar","syntax":"lark","definition":"start: /a+/"}}`),
+			wantEnvelope: "responses",
+			wantCustom:   tooladapter.CustomToolConfig{Syntax: "lark", Definition: "start: /a+/"},
+		},
+		{
+			name:         "chat unconstrained",
+			raw:          []byte(`{"type":"custom","custom":{"name":"exec","description":"Run code"}}`),
+			wantEnvelope: "chat_completions",
+		},
+		{
+			name: "chat grammar struct",
+			raw: OpenAIChatCustomTool{Type: "custom", Custom: OpenAIChatCustom{
+				Name:        "exec",
+				Description: "Run code",
+				Format: &OpenAIChatCustomFormat{
+					Type:    "grammar",
+					Grammar: &OpenAIGrammar{Syntax: "regex", Definition: `^\d+$`},
+				},
+			}},
+			wantEnvelope: "chat_completions",
+			wantCustom:   tooladapter.CustomToolConfig{Syntax: "regex", Definition: `^\d+$`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.ToCanonical(tt.raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Kind != tooladapter.ToolKindCustom {
+				t.Errorf("Kind = %q, want %q", got.Kind, tooladapter.ToolKindCustom)
+			}
+			if got.Name != "exec" || got.Description != "Run code" {
+				t.Errorf("Name, Description = %q, %q", got.Name, got.Description)
+			}
+			if got.InputSchema != nil {
+				t.Errorf("InputSchema = %+v, want nil", got.InputSchema)
+			}
+			if got.Custom == nil || *got.Custom != tt.wantCustom {
+				t.Errorf("Custom = %+v, want %+v", got.Custom, tt.wantCustom)
+			}
+			if got.SourceMeta["envelope"] != tt.wantEnvelope {
+				t.Errorf("SourceMeta[envelope] = %v, want %v", got.SourceMeta["envelope"], tt.wantEnvelope)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_ToCanonical_HostedTools(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	got, err := adapter.ToCanonical([]byte(`{"type":"file_search","vector_store_ids":["vs_1"],"max_num_results":5}`))
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+
+	if got.Kind != tooladapter.ToolKindHosted {
+		t.Errorf("Kind = %q, want %q", got.Kind, tooladapter.ToolKindHosted)
+	}
+	if got.Name != "file_search" {
+		t.Errorf("Name = %q, want %q", got.Name, "file_search")
+	}
+	want := &tooladapter.HostedToolConfig{
+		Provider: "openai",
+		Type:     "file_search",
+		Options:  map[string]any{"vector_store_ids": []any{"vs_1"}, "max_num_results": 5.0},
+	}
+	if !reflect.DeepEqual(got.Hosted, want) {
+		t.Errorf("Hosted = %+v, want %+v", got.Hosted, want)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	hosted := OpenAIHostedTool{Type: "web_search", Options: map[string]any{"search_context_size": "low"}}
+	got, err = adapter.ToCanonical(hosted)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	hosted.Options["search_context_size"] = "high"
+	if got.Hosted.Options["search_context_size"] != "low" {
+		t.Error("ToCanonical() shares the hosted tool's Options map")
+	}
+}
+
+func TestOpenAIAdapter_FromCanonical_CustomTools(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Kind:        tooladapter.ToolKindCustom,
+		Name:        "exec",
+		Description: "Run code",
+		Custom:      &tooladapter.CustomToolConfig{Syntax: "lark", Definition: "start: /a+/"},
+	}
+
+	tests := []struct {
+		envelope string
+		want     string
+	}{
+		{"", `{"type":"custom","name":"exec","description":"Run code","format":{"type":"grammar","syntax":"lark","definition":"start: /a+/"}}`},
+		{"responses", `{"type":"custom","name":"exec","description":"Run code","format":{"type":"grammar","syntax":"lark","definition":"start: /a+/"}}`},
+		{"chat_completions", `{"type":"custom","custom":{"name":"exec","description":"Run code","format":{"type":"grammar","grammar":{"syntax":"lark","definition":"start: /a+/"}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envelope, func(t *testing.T) {
+			adapter := NewOpenAIAdapter()
+			if tt.envelope != "" {
+				var err error
+				if adapter, err = adapter.WithEnvelope(tt.envelope); err != nil {
+					t.Fatalf("WithEnvelope() error = %v", err)
+				}
+			}
+
+			out, err := adapter.FromCanonical(tool)
+			if err != nil {
+				t.Fatalf("FromCanonical() error = %v", err)
+			}
+			data, err := json.Marshal(out)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("FromCanonical() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_FromCanonical_HostedTools(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+	tool := &tooladapter.CanonicalTool{
+		Kind:   tooladapter.ToolKindHosted,
+		Name:   "web_search",
+		Hosted: &tooladapter.HostedToolConfig{Provider: "openai", Type: "web_search", Options: map[string]any{"search_context_size": "low"}},
+	}
+
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"search_context_size":"low","type":"web_search"}`; string(data) != want {
+		t.Errorf("FromCanonical() = %s, want %s", data, want)
+	}
+}
+
+func TestOpenAIAdapter_FromCanonical_KindErrors(t *testing.T) {
+	hosted := &tooladapter.CanonicalTool{
+		Kind:   tooladapter.ToolKindHosted,
+		Name:   "web_search",
+		Hosted: &tooladapter.HostedToolConfig{Provider: "openai", Type: "web_search"},
+	}
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+
+	tests := []struct {
+		name     string
+		envelope string
+		tool     *tooladapter.CanonicalTool
+	}{
+		{"hosted in chat", "chat_completions", hosted},
+		{"hosted in realtime", "realtime", hosted},
+		{"custom in realtime", "realtime", custom},
+		{"foreign hosted", "", &tooladapter.CanonicalTool{
+			Kind:   tooladapter.ToolKindHosted,
+			Name:   "web_search",
+			Hosted: &tooladapter.HostedToolConfig{Provider: "anthropic", Type: "web_search_20250305"},
+		}},
+		{"hosted without type", "", &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindHosted, Name: "x"}},
+		{"unknown kind", "", &tooladapter.CanonicalTool{Kind: "mystery", Name: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewOpenAIAdapter()
+			if tt.envelope != "" {
+				var err error
+				if adapter, err = adapter.WithEnvelope(tt.envelope); err != nil {
+					t.Fatalf("WithEnvelope() error = %v", err)
+				}
+			}
+			if _, err := adapter.FromCanonical(tt.tool); err == nil {
+				t.Error("FromCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_KindsRoundTrip(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	inputs := []string{
+		`{"type":"custom","custom":{"name":"exec","format":{"type":"grammar","grammar":{"syntax":"lark","definition":"start: /a+/"}}}}`,
+		`{"type":"custom","name":"exec","description":"Run code"}`,
+		`{"type":"code_interpreter","container":{"type":"auto"}}`,
+	}
+
+	for _, input := range inputs {
+		original, err := adapter.ToCanonical([]byte(input))
+		if err != nil {
+			t.Fatalf("ToCanonical(%s) error = %v", input, err)
+		}
+		out, err := adapter.FromCanonical(original)
+		if err != nil {
+			t.Fatalf("FromCanonical() error = %v", err)
+		}
+		back, err := adapter.ToCanonical(out)
+		if err != nil {
+			t.Fatalf("ToCanonical(round trip) error = %v", err)
+		}
+		if diffs := tooladapter.DiffCanonical(original, back); len(diffs) != 0 {
+			t.Errorf("round trip of %s: diffs = %v", input, diffs)
+		}
+	}
+}
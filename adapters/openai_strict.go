@@ -0,0 +1,427 @@
+package adapters
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// CompileStrictSchema rewrites a canonical schema into a parameters object
+// accepted by OpenAI strict mode (structured outputs).
+//
+// Every object schema gets additionalProperties: false and lists all of its
+// properties in required. Properties that were optional become nullable,
+// as type: [T, "null"] where possible and as anyOf with a null branch
+// otherwise. oneOf is approximated by anyOf, allOf branches are merged into
+// their parent, and keywords or format values the strict capability profile
+// does not accept are removed. Each such change is reported as an issue.
+//
+// The input schema is not modified. Returns nil if schema is nil.
+func CompileStrictSchema(schema *tooladapter.JSONSchema) (map[string]any, []tooladapter.SchemaIssue) {
+	profile, _ := builtinProfile("openai", OpenAIModeStrict)
+	return compileStrictSchema(schema, profile)
+}
+
+// compileStrictSchema is CompileStrictSchema against a given profile.
+// oneOf, allOf and not are kept when the profile supports them.
+func compileStrictSchema(schema *tooladapter.JSONSchema, profile *tooladapter.CapabilityProfile) (map[string]any, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	c := &strictCompiler{profile: profile}
+
+	if schema.Type != "object" {
+		c.issue("", "type", "root schema must be an object in strict mode")
+	}
+	out := c.compile(schema.DeepCopy(), "")
+
+	for _, v := range profile.CheckLimits(schema) {
+		c.issues = append(c.issues, tooladapter.SchemaIssue{
+			Path:    v.Path,
+			Keyword: v.Limit,
+			Message: fmt.Sprintf("exceeds strict mode limit (%d > %d)", v.Actual, v.Max),
+		})
+	}
+	return out, c.issues
+}
+
+// RelaxStrictSchema reverses the nullable-required encoding produced for
+// OpenAI strict mode. A required property whose schema is nullable, either
+// as type: [T, "null"] or as anyOf with a null branch, becomes an optional
+// property of type T.
+//
+// The input map is not modified.
+func RelaxStrictSchema(params map[string]any) map[string]any {
+	if params == nil {
+		return nil
+	}
+	return relaxStrict(params)
+}
+
+// strictCompiler carries the capability profile and collected issues
+// through a single compilation.
+type strictCompiler struct {
+	profile *tooladapter.CapabilityProfile
+	issues  []tooladapter.SchemaIssue
+}
+
+func (c *strictCompiler) issue(path, keyword, message string) {
+	c.issues = append(c.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// compile converts a schema node the compiler owns (it may be modified).
+func (c *strictCompiler) compile(s *tooladapter.JSONSchema, path string) map[string]any {
+	if !c.profile.SupportsFeature(tooladapter.FeatureAllOf) {
+		c.mergeAllOf(s, path)
+	}
+	c.dropUnsupported(s, path)
+
+	if len(s.OneOf) > 0 && !c.profile.SupportsFeature(tooladapter.FeatureOneOf) {
+		c.issue(path, "oneOf", "approximated as anyOf; exclusivity is not enforced")
+		s.AnyOf = append(s.AnyOf, s.OneOf...)
+		s.OneOf = nil
+	}
+	if s.Not != nil && !c.profile.SupportsFeature(tooladapter.FeatureNot) {
+		c.issue(path, "not", "removed; negation is not supported")
+		s.Not = nil
+	}
+
+	// Emit scalar keywords, then compile children separately
+	children := *s
+	children.Properties, children.Items, children.Defs, children.Not = nil, nil, nil, nil
+	children.AnyOf, children.OneOf, children.AllOf = nil, nil, nil
+	m := children.ToMap()
+
+	isObject := s.Type == "object" || len(s.Properties) > 0
+	if isObject {
+		if s.AdditionalProperties != nil && *s.AdditionalProperties {
+			c.issue(path, "additionalProperties", "forced to false; strict mode forbids extra properties")
+		} else if s.AdditionalProperties == nil && len(s.Properties) == 0 {
+			c.issue(path, "additionalProperties", "closed; free-form objects cannot be expressed in strict mode")
+		}
+		m["additionalProperties"] = false
+
+		required := make(map[string]bool, len(s.Required))
+		for _, r := range s.Required {
+			required[r] = true
+		}
+
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		props := make(map[string]any, len(s.Properties))
+		allRequired := make([]string, 0, len(names))
+		for _, r := range s.Required {
+			if _, ok := s.Properties[r]; ok {
+				allRequired = append(allRequired, r)
+			}
+		}
+		for _, name := range names {
+			prop := c.compile(s.Properties[name], path+"/properties/"+escapePointer(name))
+			if !required[name] {
+				prop = makeNullable(prop)
+				allRequired = append(allRequired, name)
+			}
+			props[name] = prop
+		}
+		m["properties"] = props
+		m["required"] = allRequired
+	}
+
+	if s.Items != nil {
+		m["items"] = c.compile(s.Items, path+"/items")
+	}
+	if len(s.Defs) > 0 {
+		defs := make(map[string]any, len(s.Defs))
+		for name, def := range s.Defs {
+			defs[name] = c.compile(def, path+"/$defs/"+escapePointer(name))
+		}
+		m["$defs"] = defs
+	}
+	if s.Not != nil {
+		m["not"] = c.compile(s.Not, path+"/not")
+	}
+	c.compileList(m, "anyOf", s.AnyOf, path)
+	c.compileList(m, "oneOf", s.OneOf, path)
+	c.compileList(m, "allOf", s.AllOf, path)
+	return m
+}
+
+// compileList compiles the branches of a combinator into m[keyword].
+func (c *strictCompiler) compileList(m map[string]any, keyword string, list []*tooladapter.JSONSchema, path string) {
+	if len(list) == 0 {
+		return
+	}
+	out := make([]any, len(list))
+	for i, sub := range list {
+		out[i] = c.compile(sub, fmt.Sprintf("%s/%s/%d", path, keyword, i))
+	}
+	m[keyword] = out
+}
+
+// mergeAllOf folds object-shaped allOf branches into the parent schema.
+// Branches that cannot be merged are dropped with an issue.
+func (c *strictCompiler) mergeAllOf(s *tooladapter.JSONSchema, path string) {
+	if len(s.AllOf) == 0 {
+		return
+	}
+	for i, branch := range s.AllOf {
+		branchPath := fmt.Sprintf("%s/allOf/%d", path, i)
+		if !isMergeableObject(branch) {
+			c.issue(branchPath, "allOf", "removed; only object branches without references or combinators can be merged")
+			continue
+		}
+		if s.Type == "" {
+			s.Type = "object"
+		}
+		if s.Description == "" {
+			s.Description = branch.Description
+		}
+		if len(branch.Properties) > 0 && s.Properties == nil {
+			s.Properties = make(map[string]*tooladapter.JSONSchema, len(branch.Properties))
+		}
+		for name, prop := range branch.Properties {
+			if _, exists := s.Properties[name]; exists {
+				c.issue(branchPath+"/properties/"+escapePointer(name), "allOf", "conflicting property definition; kept the first")
+				continue
+			}
+			s.Properties[name] = prop
+		}
+		for _, r := range branch.Required {
+			if !slices.Contains(s.Required, r) {
+				s.Required = append(s.Required, r)
+			}
+		}
+	}
+	s.AllOf = nil
+}
+
+// isMergeableObject reports whether an allOf branch only describes object
+// properties and can be folded into its parent.
+func isMergeableObject(s *tooladapter.JSONSchema) bool {
+	if s == nil || (s.Type != "" && s.Type != "object") {
+		return false
+	}
+	return s.Ref == "" && len(s.AnyOf) == 0 && len(s.OneOf) == 0 && len(s.AllOf) == 0 &&
+		s.Not == nil && s.Items == nil && len(s.Defs) == 0
+}
+
+// dropUnsupported removes keywords and format values that the strict
+// profile does not accept.
+func (c *strictCompiler) dropUnsupported(s *tooladapter.JSONSchema, path string) {
+	drop := func(feature tooladapter.SchemaFeature, present bool, clear func()) {
+		if present && !c.profile.SupportsFeature(feature) {
+			c.issue(path, feature.String(), "removed; not supported in strict mode")
+			clear()
+		}
+	}
+	drop(tooladapter.FeaturePattern, s.Pattern != "", func() { s.Pattern = "" })
+	drop(tooladapter.FeatureMinimum, s.Minimum != nil, func() { s.Minimum = nil })
+	drop(tooladapter.FeatureMaximum, s.Maximum != nil, func() { s.Maximum = nil })
+	drop(tooladapter.FeatureMinLength, s.MinLength != nil, func() { s.MinLength = nil })
+	drop(tooladapter.FeatureMaxLength, s.MaxLength != nil, func() { s.MaxLength = nil })
+	drop(tooladapter.FeatureConst, s.Const != nil, func() { s.Const = nil })
+	drop(tooladapter.FeatureDefault, s.Default != nil, func() { s.Default = nil })
+	drop(tooladapter.FeatureFormat, s.Format != "", func() { s.Format = "" })
+
+	if s.Format != "" && !c.profile.SupportsFormat(s.Format) {
+		c.issue(path, "format", fmt.Sprintf("removed; format %q is not supported in strict mode", s.Format))
+		s.Format = ""
+	}
+	for _, k := range extraKeywords(s) {
+		c.issue(path, k, "removed; not supported in strict mode")
+	}
+	s.Extra = nil
+}
+
+// makeNullable returns a schema that also accepts null.
+func makeNullable(m map[string]any) map[string]any {
+	t, hasType := m["type"].(string)
+	_, hasConst := m["const"]
+	_, hasRef := m["$ref"]
+	_, hasAnyOf := m["anyOf"]
+
+	if hasType && t != "null" && !hasConst && !hasRef && !hasAnyOf {
+		m["type"] = []any{t, "null"}
+		if enum, ok := m["enum"].([]any); ok && !slices.Contains(enum, nil) {
+			m["enum"] = append(slices.Clone(enum), nil)
+		}
+		return m
+	}
+	if hasType && t == "null" {
+		return m
+	}
+	return map[string]any{
+		"anyOf": []any{m, map[string]any{"type": "null"}},
+	}
+}
+
+// relaxStrict copies a strict schema map, turning nullable required
+// properties back into optional ones.
+func relaxStrict(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	if props, ok := m["properties"].(map[string]any); ok {
+		required := stringList(m["required"])
+		keep := make([]any, 0, len(required))
+		relaxed := make(map[string]any, len(props))
+		for name, raw := range props {
+			prop, ok := raw.(map[string]any)
+			if !ok {
+				relaxed[name] = raw
+				continue
+			}
+			relaxed[name] = relaxStrict(prop)
+		}
+		for _, r := range required {
+			prop, ok := relaxed[r].(map[string]any)
+			if !ok {
+				keep = append(keep, r)
+				continue
+			}
+			if inner, nullable := stripNull(prop); nullable {
+				relaxed[r] = inner
+				continue
+			}
+			keep = append(keep, r)
+		}
+		out["properties"] = relaxed
+		if _, had := m["required"]; had {
+			out["required"] = keep
+		}
+	}
+
+	if items, ok := m["items"].(map[string]any); ok {
+		out["items"] = relaxStrict(items)
+	}
+	if defs, ok := m["$defs"].(map[string]any); ok {
+		relaxed := make(map[string]any, len(defs))
+		for name, raw := range defs {
+			if def, ok := raw.(map[string]any); ok {
+				relaxed[name] = relaxStrict(def)
+			} else {
+				relaxed[name] = raw
+			}
+		}
+		out["$defs"] = relaxed
+	}
+	if anyOf, ok := m["anyOf"].([]any); ok {
+		relaxed := make([]any, len(anyOf))
+		for i, raw := range anyOf {
+			if sub, ok := raw.(map[string]any); ok {
+				relaxed[i] = relaxStrict(sub)
+			} else {
+				relaxed[i] = raw
+			}
+		}
+		out["anyOf"] = relaxed
+	}
+	return out
+}
+
+// stripNull removes the null alternative from a nullable schema.
+// Returns the schema unchanged and false if it is not nullable.
+func stripNull(m map[string]any) (map[string]any, bool) {
+	// type: [T, "null"]
+	if types, ok := m["type"].([]any); ok && len(types) == 2 {
+		var other any
+		hasNull := false
+		for _, t := range types {
+			if t == "null" {
+				hasNull = true
+			} else {
+				other = t
+			}
+		}
+		if hasNull && other != nil {
+			out := make(map[string]any, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+			out["type"] = other
+			if enum, ok := m["enum"].([]any); ok {
+				out["enum"] = slices.DeleteFunc(slices.Clone(enum), func(v any) bool { return v == nil })
+			}
+			return out, true
+		}
+	}
+
+	// anyOf: [S, {"type": "null"}]
+	if anyOf, ok := m["anyOf"].([]any); ok && len(m) == 1 && len(anyOf) == 2 {
+		for i, raw := range anyOf {
+			branch, ok := raw.(map[string]any)
+			if ok && len(branch) == 1 && branch["type"] == "null" {
+				if inner, ok := anyOf[1-i].(map[string]any); ok {
+					return inner, true
+				}
+			}
+		}
+	}
+	return m, false
+}
+
+// stringList extracts a list of strings from a decoded JSON array or []string.
+func stringList(v any) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// extraKeywords returns the keywords of a schema's Extra map in order.
+func extraKeywords(s *tooladapter.JSONSchema) []string {
+	keywords := make([]string, 0, len(s.Extra))
+	for k := range s.Extra {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+// schemaIssuesAt prefixes the paths of schema issues with the JSON Pointer
+// of the schema they were found in, such as "/inputSchema".
+func schemaIssuesAt(prefix string, issues []tooladapter.SchemaIssue) []tooladapter.SchemaIssue {
+	if len(issues) == 0 {
+		return nil
+	}
+	out := make([]tooladapter.SchemaIssue, len(issues))
+	for i, issue := range issues {
+		issue.Path = prefix + issue.Path
+		out[i] = issue
+	}
+	return out
+}
+
+// escapePointer escapes a JSON Pointer reference token per RFC 6901.
+func escapePointer(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '~':
+			out = append(out, '~', '0')
+		case '/':
+			out = append(out, '~', '1')
+		default:
+			out = append(out, s[i])
+		}
+	}
+	return string(out)
+}
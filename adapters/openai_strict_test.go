@@ -0,0 +1,304 @@
+package adapters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestCompileStrictSchema_NestedObjects(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"name": {Type: "string"},
+			"address": {
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"city": {Type: "string"},
+					"zip":  {Type: "string"},
+				},
+				Required: []string{"city"},
+			},
+		},
+		Required: []string{"name"},
+	}
+
+	got, issues := CompileStrictSchema(schema)
+
+	if len(issues) != 0 {
+		t.Errorf("issues = %v, want none", issues)
+	}
+	if got["additionalProperties"] != false {
+		t.Errorf("root additionalProperties = %v, want false", got["additionalProperties"])
+	}
+	if want := []string{"name", "address"}; !reflect.DeepEqual(got["required"], want) {
+		t.Errorf("root required = %v, want %v", got["required"], want)
+	}
+
+	address := got["properties"].(map[string]any)["address"].(map[string]any)
+	// address was optional, so it becomes nullable
+	if want := []any{"object", "null"}; !reflect.DeepEqual(address["type"], want) {
+		t.Errorf("address type = %v, want %v", address["type"], want)
+	}
+	if address["additionalProperties"] != false {
+		t.Errorf("nested additionalProperties = %v, want false", address["additionalProperties"])
+	}
+	if want := []string{"city", "zip"}; !reflect.DeepEqual(address["required"], want) {
+		t.Errorf("nested required = %v, want %v", address["required"], want)
+	}
+	zip := address["properties"].(map[string]any)["zip"].(map[string]any)
+	if want := []any{"string", "null"}; !reflect.DeepEqual(zip["type"], want) {
+		t.Errorf("zip type = %v, want %v", zip["type"], want)
+	}
+
+	// The input must not be modified
+	if len(schema.Required) != 1 || schema.AdditionalProperties != nil {
+		t.Error("CompileStrictSchema() modified its input")
+	}
+}
+
+func TestCompileStrictSchema_NullableForms(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"unit":  {Type: "string", Enum: []any{"c", "f"}},
+			"owner": {Ref: "#/$defs/Person"},
+			"kind":  {Const: "fixed"},
+		},
+		Defs: map[string]*tooladapter.JSONSchema{
+			"Person": {Type: "object", Properties: map[string]*tooladapter.JSONSchema{"name": {Type: "string"}}},
+		},
+	}
+
+	got, _ := CompileStrictSchema(schema)
+	props := got["properties"].(map[string]any)
+
+	unit := props["unit"].(map[string]any)
+	if want := []any{"c", "f", nil}; !reflect.DeepEqual(unit["enum"], want) {
+		t.Errorf("unit enum = %v, want %v", unit["enum"], want)
+	}
+
+	for _, name := range []string{"owner", "kind"} {
+		anyOf, ok := props[name].(map[string]any)["anyOf"].([]any)
+		if !ok || len(anyOf) != 2 {
+			t.Errorf("%s = %v, want anyOf with null branch", name, props[name])
+		}
+	}
+
+	person := got["$defs"].(map[string]any)["Person"].(map[string]any)
+	if person["additionalProperties"] != false {
+		t.Errorf("$defs object additionalProperties = %v, want false", person["additionalProperties"])
+	}
+}
+
+func TestCompileStrictSchema_Issues(t *testing.T) {
+	minLen := 1
+	allow := true
+	schema := &tooladapter.JSONSchema{
+		Type:                 "object",
+		AdditionalProperties: &allow,
+		Properties: map[string]*tooladapter.JSONSchema{
+			"id":    {OneOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			"name":  {Type: "string", MinLength: &minLen, Default: "x"},
+			"site":  {Type: "string", Format: "uri"},
+			"when":  {Type: "string", Format: "date-time"},
+			"other": {Not: &tooladapter.JSONSchema{Type: "null"}},
+			"label": {Type: "string", Extra: map[string]any{"title": "Label"}},
+		},
+		Required: []string{"id", "name", "site", "when", "other"},
+	}
+
+	got, issues := CompileStrictSchema(schema)
+
+	found := make(map[string]bool)
+	for _, i := range issues {
+		found[i.Path+" "+i.Keyword] = true
+	}
+	for _, want := range []string{
+		" additionalProperties",
+		"/properties/id oneOf",
+		"/properties/name minLength",
+		"/properties/name default",
+		"/properties/site format",
+		"/properties/other not",
+		"/properties/label title",
+	} {
+		if !found[want] {
+			t.Errorf("missing issue %q in %v", want, issues)
+		}
+	}
+	if found["/properties/when format"] {
+		t.Error("supported format date-time reported as an issue")
+	}
+
+	if _, ok := got["properties"].(map[string]any)["label"].(map[string]any)["title"]; ok {
+		t.Error("label keeps title, want it removed")
+	}
+	id := got["properties"].(map[string]any)["id"].(map[string]any)
+	if _, ok := id["anyOf"]; !ok {
+		t.Errorf("id = %v, want oneOf rewritten as anyOf", id)
+	}
+}
+
+func TestCompileStrictSchema_FreeFormObject(t *testing.T) {
+	closed := false
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"metadata": {Type: "object"},
+			"empty":    {Type: "object", AdditionalProperties: &closed},
+		},
+		Required: []string{"metadata", "empty"},
+	}
+
+	got, issues := CompileStrictSchema(schema)
+
+	if len(issues) != 1 || issues[0].Path != "/properties/metadata" || issues[0].Keyword != "additionalProperties" {
+		t.Errorf("issues = %v, want one additionalProperties issue for metadata", issues)
+	}
+	metadata := got["properties"].(map[string]any)["metadata"].(map[string]any)
+	if metadata["additionalProperties"] != false {
+		t.Errorf("metadata = %v, want a closed object", metadata)
+	}
+}
+
+func TestOpenAIAdapter_Convert_StrictIssues(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewOpenAIAdapter())
+
+	tool := OpenAIFunction{Name: "lookup", Parameters: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "integer"},
+			}},
+		},
+	}}
+
+	result, err := registry.Convert(tool, "openai", "openai:strict")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	found := false
+	for _, i := range result.Issues {
+		if i.Path == "/inputSchema/properties/id" && i.Keyword == "oneOf" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Issues = %v, want oneOf at /inputSchema/properties/id", result.Issues)
+	}
+
+	result, err = registry.Convert(tool, "openai", "openai")
+	if err != nil {
+		t.Fatalf("Convert() non-strict error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("non-strict Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestCompileStrictSchema_MergesAllOf(t *testing.T) {
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		AllOf: []*tooladapter.JSONSchema{
+			{Properties: map[string]*tooladapter.JSONSchema{"a": {Type: "string"}}, Required: []string{"a"}},
+			{Properties: map[string]*tooladapter.JSONSchema{"b": {Type: "integer"}}},
+			{Ref: "#/$defs/Other"},
+		},
+	}
+
+	got, issues := CompileStrictSchema(schema)
+
+	if _, ok := got["allOf"]; ok {
+		t.Error("allOf still present after compilation")
+	}
+	props := got["properties"].(map[string]any)
+	if len(props) != 2 {
+		t.Errorf("merged properties = %v, want a and b", props)
+	}
+	if len(issues) != 1 || issues[0].Path != "/allOf/2" {
+		t.Errorf("issues = %v, want one for the $ref branch", issues)
+	}
+}
+
+func TestCompileStrictSchema_NonObjectRoot(t *testing.T) {
+	_, issues := CompileStrictSchema(&tooladapter.JSONSchema{Type: "string"})
+
+	if len(issues) == 0 || issues[0].Keyword != "type" {
+		t.Errorf("issues = %v, want root type issue", issues)
+	}
+}
+
+func TestRelaxStrictSchema(t *testing.T) {
+	params := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"unit": map[string]any{"type": []any{"string", "null"}, "enum": []any{"c", "f", nil}},
+			"owner": map[string]any{"anyOf": []any{
+				map[string]any{"$ref": "#/$defs/Person"},
+				map[string]any{"type": "null"},
+			}},
+		},
+		"required": []any{"name", "unit", "owner"},
+	}
+
+	got := RelaxStrictSchema(params)
+
+	if want := []any{"name"}; !reflect.DeepEqual(got["required"], want) {
+		t.Errorf("required = %v, want %v", got["required"], want)
+	}
+	props := got["properties"].(map[string]any)
+	unit := props["unit"].(map[string]any)
+	if unit["type"] != "string" || !reflect.DeepEqual(unit["enum"], []any{"c", "f"}) {
+		t.Errorf("unit = %v, want non-null string enum", unit)
+	}
+	if owner := props["owner"].(map[string]any); owner["$ref"] != "#/$defs/Person" {
+		t.Errorf("owner = %v, want unwrapped $ref", owner)
+	}
+
+	// The input must not be modified
+	if len(params["required"].([]any)) != 3 {
+		t.Error("RelaxStrictSchema() modified its input")
+	}
+}
+
+func TestOpenAIAdapter_StrictRoundTrip(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+	canonical := &tooladapter.CanonicalTool{
+		Name: "search",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"query": {Type: "string"},
+				"limit": {Type: "integer"},
+			},
+			Required: []string{"query"},
+		},
+		SourceMeta: map[string]any{"strict": true},
+	}
+
+	out, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	fn := out.(OpenAIFunction)
+	if want := []string{"query", "limit"}; !reflect.DeepEqual(fn.Parameters["required"], want) {
+		t.Errorf("strict required = %v, want %v", fn.Parameters["required"], want)
+	}
+
+	back, err := adapter.ToCanonical(fn)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if want := []string{"query"}; !reflect.DeepEqual(back.InputSchema.Required, want) {
+		t.Errorf("round-trip Required = %v, want %v", back.InputSchema.Required, want)
+	}
+	if back.InputSchema.Properties["limit"].Type != "integer" {
+		t.Errorf("round-trip limit type = %q, want integer", back.InputSchema.Properties["limit"].Type)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestOpenAIAdapter_Name(t *testing.T) {
@@ -355,3 +356,241 @@ func TestOpenAIAdapter_FromCanonical_NestedProperties(t *testing.T) {
 		t.Errorf("enabled.type = %v, want %q", enabled["type"], "boolean")
 	}
 }
+
+func TestOpenAIAdapter_SupportsFeature_StrictMode(t *testing.T) {
+	adapter, err := NewOpenAIAdapter().WithMode(OpenAIModeStrict)
+	if err != nil {
+		t.Fatalf("WithMode() error = %v", err)
+	}
+
+	tests := []struct {
+		feature tooladapter.SchemaFeature
+		want    bool
+	}{
+		{tooladapter.FeatureRef, true},
+		{tooladapter.FeatureDefs, true},
+		{tooladapter.FeatureAnyOf, true},
+		{tooladapter.FeatureOneOf, false},
+		{tooladapter.FeatureAllOf, false},
+		{tooladapter.FeatureNot, false},
+		{tooladapter.FeatureDefault, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.feature.String(), func(t *testing.T) {
+			if got := adapter.SupportsFeature(tt.feature); got != tt.want {
+				t.Errorf("SupportsFeature(%v) = %v, want %v", tt.feature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_WithMode_Unknown(t *testing.T) {
+	if _, err := NewOpenAIAdapter().WithMode("lenient"); err == nil {
+		t.Error("WithMode(unknown) error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_ModeOf(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+	strictTool := &tooladapter.CanonicalTool{Name: "s", SourceMeta: map[string]any{"strict": true}}
+	plainTool := &tooladapter.CanonicalTool{Name: "p"}
+
+	if got := adapter.ModeOf(strictTool); got != OpenAIModeStrict {
+		t.Errorf("ModeOf(strict tool) = %q, want %q", got, OpenAIModeStrict)
+	}
+	if got := adapter.ModeOf(plainTool); got != "" {
+		t.Errorf("ModeOf(plain tool) = %q, want default", got)
+	}
+
+	bound, _ := adapter.WithMode(OpenAIModeStrict)
+	if got := bound.(*OpenAIAdapter).ModeOf(plainTool); got != OpenAIModeStrict {
+		t.Errorf("strict-bound ModeOf(plain tool) = %q, want %q", got, OpenAIModeStrict)
+	}
+}
+
+func TestOpenAIAdapter_FromCanonical_StrictBound(t *testing.T) {
+	adapter, _ := NewOpenAIAdapter().WithMode(OpenAIModeStrict)
+	canonical := &tooladapter.CanonicalTool{
+		Name:        "plain",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+	}
+
+	result, err := adapter.FromCanonical(canonical)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+
+	fn := result.(OpenAIFunction)
+	if !fn.Strict {
+		t.Error("Strict = false, want true for strict-bound adapter")
+	}
+}
+
+func TestOpenAIAdapter_Convert_ModeAware(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+
+	anyOfParams := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "string"},
+					map[string]any{"type": "integer"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		tool     any
+		from     string
+		to       string
+		wantLoss bool
+		strict   bool
+	}{
+		{"non-strict source", OpenAIFunction{Name: "f", Parameters: anyOfParams}, "openai", "openai", true, false},
+		{"strict source", OpenAIFunction{Name: "f", Parameters: anyOfParams, Strict: true}, "openai", "openai", false, true},
+		{"strict target option", mcp.Tool{Name: "f", InputSchema: anyOfParams}, "mcp", "openai:strict", false, true},
+		{"default target", mcp.Tool{Name: "f", InputSchema: anyOfParams}, "mcp", "openai", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := registry.Convert(tt.tool, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+
+			gotLoss := false
+			for _, w := range result.Warnings {
+				if w.Feature == tooladapter.FeatureAnyOf {
+					gotLoss = true
+				}
+			}
+			if gotLoss != tt.wantLoss {
+				t.Errorf("anyOf loss warning = %v, want %v (warnings: %v)", gotLoss, tt.wantLoss, result.Warnings)
+			}
+			if fn := result.Tool.(OpenAIFunction); fn.Strict != tt.strict {
+				t.Errorf("Strict = %v, want %v", fn.Strict, tt.strict)
+			}
+		})
+	}
+}
+
+func TestOpenAIAdapter_Convert_UnknownMode(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewOpenAIAdapter())
+
+	_, err := registry.Convert(OpenAIFunction{Name: "f"}, "openai", "openai:lenient")
+
+	if err == nil {
+		t.Error("Convert() unknown mode error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_ExamplesInDescription(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name:        "get_weather",
+		Description: "Get the weather.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Examples:    []map[string]any{{"city": "Paris"}, {"city": "Tokyo", "units": "metric"}},
+	}
+
+	adapter, err := NewOpenAIAdapter().WithExamplesInDescription().WithEnvelope("chat_completions")
+	if err != nil {
+		t.Fatalf("WithEnvelope() error = %v", err)
+	}
+	strict, err := adapter.WithMode(OpenAIModeStrict)
+	if err != nil {
+		t.Fatalf("WithMode() error = %v", err)
+	}
+
+	out, err := strict.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}\n{\"city\":\"Tokyo\",\"units\":\"metric\"}"
+	if got := out.(OpenAIChatTool).Function.Description; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+
+	back, err := strict.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if back.Description != tool.Description || len(back.Examples) != 2 {
+		t.Errorf("ToCanonical() = %q with %d examples, want original description and 2 examples", back.Description, len(back.Examples))
+	}
+
+	invalid := *tool
+	invalid.InputSchema = &tooladapter.JSONSchema{Type: "object", Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}}}
+	invalid.Examples = []map[string]any{{"city": 42}}
+	if _, err := strict.FromCanonical(&invalid); err == nil {
+		t.Error("FromCanonical() with invalid example error = nil, want error")
+	}
+}
+
+func TestOpenAIAdapter_AnnotationsInDescription(t *testing.T) {
+	adapter := NewOpenAIAdapter().WithAnnotationsInDescription().WithExamplesInDescription()
+	no := false
+	tool := &tooladapter.CanonicalTool{
+		Name:        "lookup",
+		Description: "Look up a record.",
+		InputSchema: &tooladapter.JSONSchema{Type: "object"},
+		Examples:    []map[string]any{{"id": "1"}},
+		Annotations: &tooladapter.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: &no},
+	}
+
+	out, err := adapter.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	want := "[read-only, closed-world] Look up a record.\n\nExamples:\n{\"id\":\"1\"}"
+	if got := out.(OpenAIFunction).Description; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+
+	back, err := adapter.ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if diffs := tooladapter.DiffCanonical(tool, back); len(diffs) != 0 {
+		t.Errorf("round trip diffs = %v, want none", diffs)
+	}
+
+	if !adapter.SupportsFeature(tooladapter.FeatureAnnotations) {
+		t.Error("SupportsFeature(annotations) = false, want true when rendering")
+	}
+	if NewOpenAIAdapter().SupportsFeature(tooladapter.FeatureAnnotations) {
+		t.Error("default SupportsFeature(annotations) = true, want false")
+	}
+}
+
+func TestOpenAIAdapter_AnnotationsInDescription_NoLossWarning(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter().WithAnnotationsInDescription())
+
+	tool := mcp.Tool{
+		Name:        "lookup",
+		InputSchema: map[string]any{"type": "object"},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	result, err := registry.Convert(tool, "mcp", "openai")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	for _, w := range result.Warnings {
+		if w.Feature == tooladapter.FeatureAnnotations {
+			t.Errorf("unexpected annotations loss warning: %v", w)
+		}
+	}
+	if got := result.Tool.(OpenAIFunction).Description; got != "[read-only]" {
+		t.Errorf("Description = %q, want %q", got, "[read-only]")
+	}
+}
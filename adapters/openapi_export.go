@@ -0,0 +1,339 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// OpenAPIExportInfo describes the document ExportOpenAPI produces.
+type OpenAPIExportInfo struct {
+	// Title is the API title (required)
+	Title string
+
+	// Version is the API version (required)
+	Version string
+
+	// Description explains the API
+	Description string
+
+	// ServerURL is the base URL of the API, if known
+	ServerURL string
+
+	// SecuritySchemeName names the security scheme that RequiredScopes
+	// refer to; it defaults to "bearerAuth"
+	SecuritySchemeName string
+
+	// SecurityScheme is the security scheme object, declared under
+	// components.securitySchemes when a tool has RequiredScopes. It
+	// defaults to HTTP bearer authentication, where OpenAPI 3.1 treats
+	// scopes as role names. For an OAuth2 scheme, every flow's scopes map
+	// is completed with the scopes the tools use.
+	SecurityScheme map[string]any
+}
+
+// ExportOpenAPI builds an OpenAPI 3.1 document with one POST operation per
+// tool. The result is a JSON-encodable document map.
+//
+// Each operation is built from its tool:
+//   - The path is "/<namespace>/<name>" ("/<name>" without a namespace)
+//   - operationId is the provider-safe name a tooladapter.NameMapper
+//     assigns to the tool
+//   - InputSchema is the application/json request body, required if the
+//     schema has required properties
+//   - OutputSchema is the application/json content of the 200 response
+//   - RequiredScopes become a security requirement on the scheme named in
+//     info
+//   - Tags, Description, Annotations.Title (as the summary) and
+//     Annotations.Deprecated are copied
+//
+// Schema $defs are moved to components.schemas, since a "#/$defs/..."
+// reference inside an OpenAPI document would resolve against the document
+// root. Definitions of the same name and content are shared; otherwise
+// the name is prefixed with the operationId.
+//
+// Returns an error if a tool is not a function tool or two tools have the
+// same ID.
+func ExportOpenAPI(info OpenAPIExportInfo, tools []*tooladapter.CanonicalTool) (map[string]any, error) {
+	if info.Title == "" || info.Version == "" {
+		return nil, errors.New("OpenAPI export requires a title and a version")
+	}
+	schemeName := info.SecuritySchemeName
+	if schemeName == "" {
+		schemeName = "bearerAuth"
+	}
+
+	exp := &openAPIExporter{
+		names:   tooladapter.NewNameMapper(),
+		schemas: make(map[string]any),
+	}
+	paths := make(map[string]any, len(tools))
+	var scopes []string
+	for i, tool := range tools {
+		if tool == nil {
+			return nil, fmt.Errorf("tool %d: nil CanonicalTool", i)
+		}
+		if !tool.IsFunction() {
+			return nil, fmt.Errorf("tool %d: %w", i, unsupportedKindError("openapi", tool))
+		}
+		path := openAPIToolPath(tool)
+		if _, ok := paths[path]; ok {
+			return nil, fmt.Errorf("tool %d: duplicate tool %q", i, tool.ID())
+		}
+
+		op := exp.operation(tool)
+		if len(tool.RequiredScopes) > 0 {
+			op["security"] = []any{map[string]any{schemeName: stringsToAny(tool.RequiredScopes)}}
+			scopes = append(scopes, tool.RequiredScopes...)
+		}
+		paths[path] = map[string]any{"post": op}
+	}
+
+	docInfo := map[string]any{"title": info.Title, "version": info.Version}
+	if info.Description != "" {
+		docInfo["description"] = info.Description
+	}
+	doc := map[string]any{
+		"openapi": "3.1.0",
+		"info":    docInfo,
+		"paths":   paths,
+	}
+	if info.ServerURL != "" {
+		doc["servers"] = []any{map[string]any{"url": info.ServerURL}}
+	}
+
+	components := make(map[string]any)
+	if len(exp.schemas) > 0 {
+		components["schemas"] = exp.schemas
+	}
+	if len(scopes) > 0 {
+		components["securitySchemes"] = map[string]any{
+			schemeName: openAPISecurityScheme(info.SecurityScheme, scopes),
+		}
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+	return doc, nil
+}
+
+// openAPIExporter collects the component schemas of an export.
+type openAPIExporter struct {
+	names   *tooladapter.NameMapper
+	schemas map[string]any
+}
+
+// operation builds the POST operation for a tool, without security.
+func (exp *openAPIExporter) operation(tool *tooladapter.CanonicalTool) map[string]any {
+	opID := exp.names.ProviderName(tool)
+	op := map[string]any{"operationId": opID}
+	if tool.Annotations != nil && tool.Annotations.Title != "" {
+		op["summary"] = tool.Annotations.Title
+	}
+	if tool.Description != "" {
+		op["description"] = tool.Description
+	}
+	if len(tool.Tags) > 0 {
+		op["tags"] = stringsToAny(tool.Tags)
+	}
+	if tool.Annotations != nil && tool.Annotations.Deprecated {
+		op["deprecated"] = true
+	}
+
+	if tool.InputSchema != nil {
+		body := map[string]any{
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": exp.schema(tool.InputSchema, opID)},
+			},
+		}
+		if len(tool.InputSchema.Required) > 0 {
+			body["required"] = true
+		}
+		op["requestBody"] = body
+	}
+
+	response := map[string]any{"description": "Successful response"}
+	if tool.OutputSchema != nil {
+		response["content"] = map[string]any{
+			"application/json": map[string]any{"schema": exp.schema(tool.OutputSchema, opID)},
+		}
+	}
+	op["responses"] = map[string]any{"200": response}
+	return op
+}
+
+// schema converts a schema, moving its $defs to the component schemas
+// and rewriting references to them.
+func (exp *openAPIExporter) schema(s *tooladapter.JSONSchema, opID string) map[string]any {
+	m := s.ToMap()
+	defs, _ := m["$defs"].(map[string]any)
+	delete(m, "$defs")
+	if len(defs) == 0 {
+		return m
+	}
+
+	// Choose component names first, since definitions refer to each other
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	base := make(map[string]string, len(defs))
+	for _, name := range names {
+		base[name] = openAPIComponentName(name)
+	}
+	// A name is free if no definition of this schema took it and no earlier
+	// tool registered a different schema under it; component names are
+	// lossy, so distinct definitions may map to the same base name
+	taken := make(map[string]bool, len(defs))
+	free := func(component, name string) bool {
+		if taken[component] {
+			return false
+		}
+		existing, ok := exp.schemas[component]
+		return !ok || reflect.DeepEqual(existing, rewriteOpenAPIRefs(defs[name], base))
+	}
+	renames := make(map[string]string, len(defs))
+	for _, name := range names {
+		component := base[name]
+		if !free(component, name) {
+			component = opID + "." + base[name]
+		}
+		for n := 2; !free(component, name); n++ {
+			component = fmt.Sprintf("%s.%s_%d", opID, base[name], n)
+		}
+		taken[component] = true
+		renames[name] = component
+	}
+
+	for _, name := range names {
+		exp.schemas[renames[name]] = rewriteOpenAPIRefs(defs[name], renames)
+	}
+	return rewriteOpenAPIRefs(m, renames).(map[string]any)
+}
+
+// rewriteOpenAPIRefs copies a schema value, pointing "#/$defs/..."
+// references at the renamed component schemas.
+func rewriteOpenAPIRefs(v any, renames map[string]string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			switch key {
+			case "enum", "const", "default", "examples", "dependentRequired":
+				// Values, not schemas
+				out[key] = value
+			case "properties", "patternProperties", "$defs", "dependentSchemas":
+				// Schemas keyed by name
+				out[key] = rewriteOpenAPIRefsIn(value, renames)
+			case "$ref":
+				ref, _ := value.(string)
+				if name, ok := strings.CutPrefix(ref, "#/$defs/"); ok {
+					name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+					if component, ok := renames[name]; ok {
+						ref = openAPISchemaPrefix + escapePointer(component)
+					}
+				}
+				out[key] = ref
+			default:
+				out[key] = rewriteOpenAPIRefs(value, renames)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = rewriteOpenAPIRefs(item, renames)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// rewriteOpenAPIRefsIn rewrites the values of a map of schemas keyed by
+// name, leaving the names alone.
+func rewriteOpenAPIRefsIn(v any, renames map[string]string) any {
+	schemas, ok := v.(map[string]any)
+	if !ok {
+		return rewriteOpenAPIRefs(v, renames)
+	}
+	out := make(map[string]any, len(schemas))
+	for name, schema := range schemas {
+		out[name] = rewriteOpenAPIRefs(schema, renames)
+	}
+	return out
+}
+
+// openAPIComponentName maps a $defs name to a component name, which must
+// match ^[a-zA-Z0-9.\-_]+$.
+func openAPIComponentName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_' {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// openAPIToolPath returns the path of a tool's operation.
+func openAPIToolPath(tool *tooladapter.CanonicalTool) string {
+	if tool.Namespace == "" {
+		return "/" + url.PathEscape(tool.Name)
+	}
+	return "/" + url.PathEscape(tool.Namespace) + "/" + url.PathEscape(tool.Name)
+}
+
+// openAPISecurityScheme returns the security scheme to declare, completing
+// the scopes of OAuth2 flows.
+func openAPISecurityScheme(scheme map[string]any, scopes []string) map[string]any {
+	if scheme == nil {
+		return map[string]any{"type": "http", "scheme": "bearer"}
+	}
+	out := copyOpenAPIMap(scheme)
+	flows, ok := scheme["flows"].(map[string]any)
+	if !ok {
+		return out
+	}
+	outFlows := make(map[string]any, len(flows))
+	for name, raw := range flows {
+		flow, ok := raw.(map[string]any)
+		if !ok {
+			outFlows[name] = raw
+			continue
+		}
+		flow = copyOpenAPIMap(flow)
+		declared, _ := flow["scopes"].(map[string]any)
+		declared = copyOpenAPIMap(declared)
+		for _, scope := range scopes {
+			if _, ok := declared[scope]; !ok {
+				declared[scope] = scope
+			}
+		}
+		flow["scopes"] = declared
+		outFlows[name] = flow
+	}
+	out["flows"] = outFlows
+	return out
+}
+
+// stringsToAny converts a string slice to a JSON array value.
+func stringsToAny(list []string) []any {
+	out := make([]any, len(list))
+	for i, s := range list {
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func exportCatalog() []*tooladapter.CanonicalTool {
+	address := &tooladapter.JSONSchema{
+		Type:       "object",
+		Properties: map[string]*tooladapter.JSONSchema{"city": {Type: "string"}},
+	}
+	return []*tooladapter.CanonicalTool{
+		{
+			Namespace:   "crm",
+			Name:        "create_contact",
+			Description: "Create a contact.",
+			Tags:        []string{"contacts"},
+			Annotations: &tooladapter.ToolAnnotations{Title: "Create contact"},
+			InputSchema: &tooladapter.JSONSchema{
+				Type: "object",
+				Properties: map[string]*tooladapter.JSONSchema{
+					"name":    {Type: "string"},
+					"address": {Ref: "#/$defs/Address"},
+				},
+				Required: []string{"name"},
+				Defs:     map[string]*tooladapter.JSONSchema{"Address": address},
+			},
+			OutputSchema: &tooladapter.JSONSchema{
+				Type:       "object",
+				Properties: map[string]*tooladapter.JSONSchema{"id": {Type: "string"}},
+			},
+			RequiredScopes: []string{"contacts:write"},
+		},
+		{
+			Name: "geocode",
+			InputSchema: &tooladapter.JSONSchema{
+				Type:       "object",
+				Properties: map[string]*tooladapter.JSONSchema{"address": {Ref: "#/$defs/Address"}},
+				Defs: map[string]*tooladapter.JSONSchema{
+					// Same name, different content
+					"Address": {Type: "string"},
+				},
+			},
+			Annotations: &tooladapter.ToolAnnotations{Deprecated: true},
+		},
+	}
+}
+
+func TestExportOpenAPI(t *testing.T) {
+	doc, err := ExportOpenAPI(OpenAPIExportInfo{
+		Title:     "Tools",
+		Version:   "1.0.0",
+		ServerURL: "https://tools.example.com",
+	}, exportCatalog())
+	if err != nil {
+		t.Fatalf("ExportOpenAPI() error = %v", err)
+	}
+
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v, want 3.1.0", doc["openapi"])
+	}
+	paths := doc["paths"].(map[string]any)
+	create := paths["/crm/create_contact"].(map[string]any)["post"].(map[string]any)
+	if create["operationId"] != "crm_create_contact" || create["summary"] != "Create contact" {
+		t.Errorf("create operation = %v, want operationId and summary", create)
+	}
+	body := create["requestBody"].(map[string]any)
+	if body["required"] != true {
+		t.Errorf("requestBody.required = %v, want true", body["required"])
+	}
+	schema := body["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	if ref := schema["properties"].(map[string]any)["address"].(map[string]any)["$ref"]; ref != "#/components/schemas/Address" {
+		t.Errorf("address $ref = %v, want the component schema", ref)
+	}
+	if _, ok := schema["$defs"]; ok {
+		t.Error("request schema keeps $defs, want them moved to components")
+	}
+	wantSecurity := []any{map[string]any{"bearerAuth": []any{"contacts:write"}}}
+	if !reflect.DeepEqual(create["security"], wantSecurity) {
+		t.Errorf("security = %v, want %v", create["security"], wantSecurity)
+	}
+
+	geocode := paths["/geocode"].(map[string]any)["post"].(map[string]any)
+	if geocode["deprecated"] != true || geocode["requestBody"].(map[string]any)["required"] != nil {
+		t.Errorf("geocode = %v, want deprecated with an optional body", geocode)
+	}
+	if _, ok := geocode["security"]; ok {
+		t.Error("geocode has security, want none")
+	}
+	response := geocode["responses"].(map[string]any)["200"].(map[string]any)
+	if response["description"] == nil || response["content"] != nil {
+		t.Errorf("geocode response = %v, want a description without content", response)
+	}
+
+	components := doc["components"].(map[string]any)
+	schemas := components["schemas"].(map[string]any)
+	if len(schemas) != 2 || schemas["Address"] == nil || schemas["geocode.Address"] == nil {
+		t.Errorf("component schemas = %v, want Address and geocode.Address", schemas)
+	}
+	wantScheme := map[string]any{"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"}}
+	if !reflect.DeepEqual(components["securitySchemes"], wantScheme) {
+		t.Errorf("securitySchemes = %v, want %v", components["securitySchemes"], wantScheme)
+	}
+}
+
+func TestExportOpenAPI_KeywordPropertyNames(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name: "configure",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"default": {Ref: "#/$defs/Thing"},
+				"$ref":    {Type: "string"},
+			},
+			Defs: map[string]*tooladapter.JSONSchema{"Thing": {Type: "integer"}},
+		},
+	}
+
+	doc, err := ExportOpenAPI(OpenAPIExportInfo{Title: "Tools", Version: "1.0.0"}, []*tooladapter.CanonicalTool{tool})
+	if err != nil {
+		t.Fatalf("ExportOpenAPI() error = %v", err)
+	}
+	op := doc["paths"].(map[string]any)["/configure"].(map[string]any)["post"].(map[string]any)
+	schema := op["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	props := schema["properties"].(map[string]any)
+	if ref := props["default"].(map[string]any)["$ref"]; ref != "#/components/schemas/Thing" {
+		t.Errorf("default property $ref = %v, want the component schema", ref)
+	}
+	if want := map[string]any{"type": "string"}; !reflect.DeepEqual(props["$ref"], want) {
+		t.Errorf("$ref property = %v, want %v", props["$ref"], want)
+	}
+}
+
+func TestExportOpenAPI_ComponentNameCollisions(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name: "pair",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"text":   {Ref: "#/$defs/a b"},
+				"number": {Ref: "#/$defs/a_b"},
+				"other":  {Ref: "#/$defs/a.b"},
+			},
+			Defs: map[string]*tooladapter.JSONSchema{
+				"a b": {Type: "string"},
+				"a_b": {Type: "integer"},
+				"a.b": {Type: "boolean"},
+			},
+		},
+	}
+	// An earlier tool holds pair.a_b, the first fallback name
+	earlier := &tooladapter.CanonicalTool{
+		Name: "earlier",
+		InputSchema: &tooladapter.JSONSchema{
+			Type:       "object",
+			Properties: map[string]*tooladapter.JSONSchema{"x": {Ref: "#/$defs/pair.a_b"}},
+			Defs:       map[string]*tooladapter.JSONSchema{"pair.a_b": {Type: "null"}},
+		},
+	}
+
+	doc, err := ExportOpenAPI(OpenAPIExportInfo{Title: "Tools", Version: "1.0.0"}, []*tooladapter.CanonicalTool{earlier, tool})
+	if err != nil {
+		t.Fatalf("ExportOpenAPI() error = %v", err)
+	}
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	if len(schemas) != 4 {
+		t.Errorf("component schemas = %v, want 4", schemas)
+	}
+
+	op := doc["paths"].(map[string]any)["/pair"].(map[string]any)["post"].(map[string]any)
+	schema := op["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	props := schema["properties"].(map[string]any)
+	for prop, wantType := range map[string]string{"text": "string", "number": "integer", "other": "boolean"} {
+		ref, _ := props[prop].(map[string]any)["$ref"].(string)
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if got, _ := schemas[name].(map[string]any); got["type"] != wantType {
+			t.Errorf("%s $ref = %q resolves to %v, want type %s", prop, ref, got, wantType)
+		}
+	}
+}
+
+func TestExportOpenAPI_OAuth2(t *testing.T) {
+	doc, err := ExportOpenAPI(OpenAPIExportInfo{
+		Title:              "Tools",
+		Version:            "1",
+		SecuritySchemeName: "oauth",
+		SecurityScheme: map[string]any{
+			"type": "oauth2",
+			"flows": map[string]any{
+				"clientCredentials": map[string]any{
+					"tokenUrl": "https://auth.example.com/token",
+					"scopes":   map[string]any{"contacts:write": "Write contacts"},
+				},
+			},
+		},
+	}, []*tooladapter.CanonicalTool{{
+		Name:           "t",
+		InputSchema:    &tooladapter.JSONSchema{Type: "object"},
+		RequiredScopes: []string{"contacts:write", "contacts:read"},
+	}})
+	if err != nil {
+		t.Fatalf("ExportOpenAPI() error = %v", err)
+	}
+
+	scheme := doc["components"].(map[string]any)["securitySchemes"].(map[string]any)["oauth"].(map[string]any)
+	flow := scheme["flows"].(map[string]any)["clientCredentials"].(map[string]any)
+	want := map[string]any{"contacts:write": "Write contacts", "contacts:read": "contacts:read"}
+	if !reflect.DeepEqual(flow["scopes"], want) {
+		t.Errorf("scopes = %v, want %v", flow["scopes"], want)
+	}
+}
+
+func TestExportOpenAPI_Errors(t *testing.T) {
+	info := OpenAPIExportInfo{Title: "Tools", Version: "1"}
+	object := &tooladapter.JSONSchema{Type: "object"}
+	tests := map[string]struct {
+		info  OpenAPIExportInfo
+		tools []*tooladapter.CanonicalTool
+	}{
+		"no title":  {OpenAPIExportInfo{Version: "1"}, nil},
+		"nil tool":  {info, []*tooladapter.CanonicalTool{nil}},
+		"custom":    {info, []*tooladapter.CanonicalTool{{Kind: tooladapter.ToolKindCustom, Name: "exec"}}},
+		"duplicate": {info, []*tooladapter.CanonicalTool{{Name: "a", InputSchema: object}, {Name: "a", InputSchema: object}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ExportOpenAPI(tt.info, tt.tools); err == nil {
+				t.Error("ExportOpenAPI() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestExportOpenAPI_RoundTrip(t *testing.T) {
+	catalog := exportCatalog()
+	doc, err := ExportOpenAPI(OpenAPIExportInfo{Title: "Tools", Version: "1"}, catalog)
+	if err != nil {
+		t.Fatalf("ExportOpenAPI() error = %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	tools, err := ImportOpenAPI(data)
+	if err != nil {
+		t.Fatalf("ImportOpenAPI() error = %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("ImportOpenAPI() returned %d tools, want 2", len(tools))
+	}
+
+	// The title is exported as the summary, which the importer joins to
+	// the description
+	got, want := tools[0], catalog[0]
+	if got.Name != "crm_create_contact" || got.Description != "Create contact\n\nCreate a contact." {
+		t.Errorf("tool = %s %q, want crm_create_contact with the summary", got.Name, got.Description)
+	}
+	if !reflect.DeepEqual(got.InputSchema, want.InputSchema) {
+		t.Errorf("InputSchema =\n%+v\nwant\n%+v", got.InputSchema, want.InputSchema)
+	}
+	if !reflect.DeepEqual(got.OutputSchema, want.OutputSchema) {
+		t.Errorf("OutputSchema = %+v, want %+v", got.OutputSchema, want.OutputSchema)
+	}
+	if !reflect.DeepEqual(got.Tags, want.Tags) || !reflect.DeepEqual(got.RequiredScopes, want.RequiredScopes) {
+		t.Errorf("Tags, RequiredScopes = %v, %v, want %v, %v", got.Tags, got.RequiredScopes, want.Tags, want.RequiredScopes)
+	}
+
+	// The renamed definition keeps its own content
+	geocode := tools[1]
+	if def := geocode.InputSchema.Defs["geocode.Address"]; def == nil || def.Type != "string" {
+		t.Errorf("geocode $defs = %+v, want geocode.Address string", geocode.InputSchema.Defs)
+	}
+}
@@ -0,0 +1,662 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods lists the HTTP methods of an OpenAPI path item in the
+// order operations are imported.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPISchemaPrefix is the reference prefix of component schemas, which
+// are imported as $defs.
+const openAPISchemaPrefix = "#/components/schemas/"
+
+// ImportOpenAPI reads an OpenAPI 3.0 or 3.1 document, in JSON or YAML, and
+// returns one canonical tool per operation, ordered by path and then by
+// method.
+//
+// Each tool is built from its operation:
+//   - Name is the operationId, or "<method>_<path>" sanitized by
+//     tooladapter.SanitizeToolName when there is none
+//   - Description joins the summary and the description
+//   - Tags are the operation tags
+//   - InputSchema is an object merging the path, query, header and cookie
+//     parameters with the properties of a JSON object request body. A body
+//     that is not an object, or whose properties clash with a parameter,
+//     is kept whole under a "body" property
+//   - OutputSchema is the JSON schema of the lowest 2xx response
+//   - RequiredScopes are the scopes of the first security requirement in
+//     effect for the operation
+//   - Annotations.Deprecated is set for deprecated operations
+//
+// Component schemas referenced from a schema are copied to its $defs, and
+// OpenAPI 3.0 nullable schemas become type lists. Deprecated parameters
+// carry the JSON Schema "deprecated" keyword. The HTTP binding needed to
+// turn arguments back into a request is recorded in SourceMeta["http"];
+// see the design notes for its layout.
+func ImportOpenAPI(data []byte) ([]*tooladapter.CanonicalTool, error) {
+	doc, err := decodeOpenAPIDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", version)
+	}
+	imp := &openAPIImporter{doc: doc, nullable: strings.HasPrefix(version, "3.0")}
+	if servers, ok := doc["servers"].([]any); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]any); ok {
+			imp.server, _ = server["url"].(string)
+		}
+	}
+	imp.security = doc["security"]
+
+	paths, _ := doc["paths"].(map[string]any)
+	names := make([]string, 0, len(paths))
+	for path := range paths {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	var tools []*tooladapter.CanonicalTool
+	seen := make(map[string]string)
+	for _, path := range names {
+		item, err := imp.resolve(paths[path])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			endpoint := strings.ToUpper(method) + " " + path
+			tool, err := imp.operation(path, method, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", endpoint, err)
+			}
+			if prev, ok := seen[tool.Name]; ok {
+				return nil, fmt.Errorf("%s: operation name %q is already used by %s", endpoint, tool.Name, prev)
+			}
+			seen[tool.Name] = endpoint
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// decodeOpenAPIDocument decodes a JSON or YAML document into generic JSON
+// values. YAML is re-encoded as JSON so that both yield the same types.
+func decodeOpenAPIDocument(data []byte) (map[string]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty OpenAPI document")
+	}
+	if trimmed[0] != '{' {
+		var doc any
+		if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+		}
+		trimmed = converted
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// openAPIImporter holds the document-wide state of an import.
+type openAPIImporter struct {
+	doc map[string]any
+
+	// nullable is set for OpenAPI 3.0, whose schemas use nullable: true
+	// instead of a null type
+	nullable bool
+
+	// server is the URL of the first server, if any
+	server string
+
+	// security is the document-level security requirement list
+	security any
+}
+
+// openAPIParameter is a parameter of an operation.
+type openAPIParameter struct {
+	// name is the argument name in the input schema
+	name string
+
+	// param is the parameter name in the HTTP request
+	param string
+
+	in       string
+	schema   map[string]any
+	required bool
+}
+
+// operation converts one operation. item is the path item holding it,
+// whose parameters apply to every operation of the path.
+func (imp *openAPIImporter) operation(path, method string, item, op map[string]any) (*tooladapter.CanonicalTool, error) {
+	tool := &tooladapter.CanonicalTool{
+		SourceFormat: "openapi",
+		SourceMeta:   make(map[string]any),
+	}
+
+	tool.Name, _ = op["operationId"].(string)
+	if tool.Name == "" {
+		tool.Name = tooladapter.SanitizeToolName(method + "_" + strings.Trim(path, "/"))
+	}
+	summary, _ := op["summary"].(string)
+	description, _ := op["description"].(string)
+	switch {
+	case summary != "" && description != "":
+		tool.Description = summary + "\n\n" + description
+	case summary != "":
+		tool.Description = summary
+	default:
+		tool.Description = description
+	}
+	tool.Tags = stringList(op["tags"])
+	if deprecated, ok := op["deprecated"].(bool); ok && deprecated {
+		tool.Annotations = &tooladapter.ToolAnnotations{Deprecated: true}
+	}
+
+	params, err := imp.parameters(item["parameters"], op["parameters"])
+	if err != nil {
+		return nil, err
+	}
+
+	input := map[string]any{"type": "object"}
+	properties := make(map[string]any)
+	var required []string
+	bindings := make(map[string]any, len(params))
+	for _, p := range params {
+		properties[p.name] = p.schema
+		if p.required {
+			required = append(required, p.name)
+		}
+		bindings[p.name] = map[string]any{"in": p.in, "name": p.param}
+	}
+
+	http := map[string]any{
+		"method": strings.ToUpper(method),
+		"path":   path,
+	}
+	if imp.server != "" {
+		http["server"] = imp.server
+	}
+	if len(bindings) > 0 {
+		http["parameters"] = bindings
+	}
+
+	if raw, ok := op["requestBody"]; ok {
+		body, err := imp.resolve(raw)
+		if err != nil {
+			return nil, fmt.Errorf("requestBody: %w", err)
+		}
+		contentType, media := openAPIMediaType(body["content"])
+		if media != nil {
+			http["contentType"] = contentType
+			schema, _ := media["schema"].(map[string]any)
+			bodyRequired, _ := body["required"].(bool)
+			merged, err := imp.mergeBody(schema, properties, &required, bodyRequired)
+			if err != nil {
+				return nil, fmt.Errorf("requestBody: %w", err)
+			}
+			if !merged {
+				http["bodyProperty"] = "body"
+			}
+		}
+	}
+	tool.SourceMeta["http"] = http
+
+	if len(properties) > 0 {
+		input["properties"] = properties
+	}
+	if len(required) > 0 {
+		input["required"] = required
+	}
+	tool.InputSchema, err = imp.schema(input)
+	if err != nil {
+		return nil, fmt.Errorf("input schema: %w", err)
+	}
+
+	if output, err := imp.responseSchema(op["responses"]); err != nil {
+		return nil, err
+	} else if output != nil {
+		tool.OutputSchema, err = imp.schema(output)
+		if err != nil {
+			return nil, fmt.Errorf("output schema: %w", err)
+		}
+	}
+
+	security := imp.security
+	if s, ok := op["security"]; ok {
+		security = s
+	}
+	tool.RequiredScopes = openAPIScopes(security)
+
+	return tool, nil
+}
+
+// parameters merges path-level and operation-level parameters. An
+// operation parameter overrides a path parameter with the same name and
+// location. A parameter whose name is used in another location is named
+// "<in>.<name>" in the input schema.
+func (imp *openAPIImporter) parameters(pathParams, opParams any) ([]openAPIParameter, error) {
+	type key struct{ name, in string }
+	var order []key
+	byKey := make(map[key]map[string]any)
+	for _, list := range []any{pathParams, opParams} {
+		items, _ := list.([]any)
+		for _, raw := range items {
+			p, err := imp.resolve(raw)
+			if err != nil {
+				return nil, fmt.Errorf("parameter: %w", err)
+			}
+			name, _ := p["name"].(string)
+			in, _ := p["in"].(string)
+			if name == "" || in == "" {
+				return nil, errors.New("parameter without name or location")
+			}
+			k := key{name, in}
+			if _, ok := byKey[k]; !ok {
+				order = append(order, k)
+			}
+			byKey[k] = p
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, k := range order {
+		counts[k.name]++
+	}
+
+	params := make([]openAPIParameter, 0, len(order))
+	for _, k := range order {
+		p := byKey[k]
+		schema, _ := p["schema"].(map[string]any)
+		if schema == nil {
+			// Parameters may describe their value by media type instead
+			if _, media := openAPIMediaType(p["content"]); media != nil {
+				schema, _ = media["schema"].(map[string]any)
+			}
+		}
+		schema = copyOpenAPIMap(schema)
+		if desc, ok := p["description"].(string); ok && schema["description"] == nil {
+			schema["description"] = desc
+		}
+		if deprecated, ok := p["deprecated"].(bool); ok && deprecated {
+			schema["deprecated"] = true
+		}
+
+		name := k.name
+		if counts[k.name] > 1 {
+			name = k.in + "." + k.name
+		}
+		required, _ := p["required"].(bool)
+		params = append(params, openAPIParameter{
+			name:     name,
+			param:    k.name,
+			in:       k.in,
+			schema:   schema,
+			required: required || k.in == "path",
+		})
+	}
+	return params, nil
+}
+
+// mergeBody adds a request body schema to the input properties. The
+// properties of an object body are merged when none clashes with a
+// parameter; any other body is added as a "body" property. Reports whether
+// the body was merged.
+func (imp *openAPIImporter) mergeBody(schema map[string]any, properties map[string]any, required *[]string, bodyRequired bool) (bool, error) {
+	if schema == nil {
+		schema = map[string]any{}
+	}
+	resolved := schema
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := imp.resolveSchemaRef(ref)
+		if err != nil {
+			return false, err
+		}
+		resolved = target
+	}
+
+	bodyProps, hasProps := resolved["properties"].(map[string]any)
+	typ, _ := resolved["type"].(string)
+	if hasProps && (typ == "" || typ == "object") {
+		clash := false
+		for name := range bodyProps {
+			if _, ok := properties[name]; ok {
+				clash = true
+				break
+			}
+		}
+		if !clash {
+			for name, prop := range bodyProps {
+				properties[name] = prop
+			}
+			if bodyRequired {
+				*required = append(*required, stringList(resolved["required"])...)
+			}
+			return true, nil
+		}
+	}
+
+	if _, ok := properties["body"]; ok {
+		return false, errors.New(`parameter "body" clashes with the request body`)
+	}
+	properties["body"] = schema
+	if bodyRequired {
+		*required = append(*required, "body")
+	}
+	return false, nil
+}
+
+// responseSchema returns the schema of the lowest 2xx response with
+// content, or nil if there is none. Explicit status codes are preferred
+// over the 2XX range.
+func (imp *openAPIImporter) responseSchema(raw any) (map[string]any, error) {
+	responses, _ := raw.(map[string]any)
+	var codes []string
+	for code := range responses {
+		if len(code) == 3 && code[0] == '2' {
+			codes = append(codes, code)
+		}
+	}
+	// "2XX" sorts after the numeric codes
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		resp, err := imp.resolve(responses[code])
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", code, err)
+		}
+		if _, media := openAPIMediaType(resp["content"]); media != nil {
+			schema, _ := media["schema"].(map[string]any)
+			if schema != nil {
+				return schema, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// schema converts an OpenAPI schema, copying the component schemas it
+// references into $defs.
+func (imp *openAPIImporter) schema(raw map[string]any) (*tooladapter.JSONSchema, error) {
+	defs := make(map[string]any)
+	converted, err := imp.convertSchema(raw, defs)
+	if err != nil {
+		return nil, err
+	}
+	root := converted.(map[string]any)
+	if len(defs) > 0 {
+		root["$defs"] = defs
+	}
+	return mapToJSONSchema(root)
+}
+
+// convertSchema copies a schema value, rewriting component references to
+// $defs and OpenAPI 3.0 nullable schemas to schemas that accept null.
+// Referenced components are converted into defs as they are found.
+func (imp *openAPIImporter) convertSchema(v any, defs map[string]any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			switch key {
+			case "enum", "const", "default", "example", "examples", "dependentRequired":
+				// Values, not schemas
+				out[key] = value
+				continue
+			case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+				// Schemas keyed by name
+				converted, err := imp.convertSchemas(value, defs)
+				if err != nil {
+					return nil, err
+				}
+				out[key] = converted
+				continue
+			case "nullable":
+				if imp.nullable {
+					continue
+				}
+			case "$ref":
+				ref, _ := value.(string)
+				name, ok := strings.CutPrefix(ref, openAPISchemaPrefix)
+				if !ok {
+					return nil, fmt.Errorf("unsupported schema reference %q", ref)
+				}
+				out[key] = "#/$defs/" + name
+				if err := imp.addDef(name, defs); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			converted, err := imp.convertSchema(value, defs)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = converted
+		}
+		if nullable, ok := v["nullable"].(bool); ok && nullable && imp.nullable {
+			return openAPINullable(out), nil
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			converted, err := imp.convertSchema(item, defs)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// convertSchemas converts the values of a map of schemas keyed by name,
+// leaving the names alone.
+func (imp *openAPIImporter) convertSchemas(v any, defs map[string]any) (any, error) {
+	schemas, ok := v.(map[string]any)
+	if !ok {
+		return imp.convertSchema(v, defs)
+	}
+	out := make(map[string]any, len(schemas))
+	for name, schema := range schemas {
+		converted, err := imp.convertSchema(schema, defs)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = converted
+	}
+	return out, nil
+}
+
+// openAPINullable makes a converted OpenAPI 3.0 nullable schema accept
+// null: null joins its type and its enum, and a schema without a type,
+// such as a reference or a combinator, becomes one branch of an anyOf
+// with null.
+func openAPINullable(schema map[string]any) map[string]any {
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, nil) {
+		schema["enum"] = append(slices.Clone(enum), nil)
+	}
+	switch typ := schema["type"].(type) {
+	case string:
+		schema["type"] = []any{typ, "null"}
+	case []any:
+		if !slices.Contains(typ, any("null")) {
+			schema["type"] = append(slices.Clone(typ), "null")
+		}
+	default:
+		for _, key := range []string{"$ref", "allOf", "anyOf", "oneOf", "not"} {
+			if _, ok := schema[key]; ok {
+				return map[string]any{
+					"anyOf": []any{schema, map[string]any{"type": "null"}},
+				}
+			}
+		}
+	}
+	return schema
+}
+
+// addDef converts a component schema into defs, once.
+func (imp *openAPIImporter) addDef(escaped string, defs map[string]any) error {
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(escaped)
+	if _, ok := defs[name]; ok {
+		return nil
+	}
+	target, err := imp.resolveSchemaRef(openAPISchemaPrefix + escaped)
+	if err != nil {
+		return err
+	}
+	// A component that refers to itself, directly or through other
+	// components, finds its placeholder here and stops descending
+	defs[name] = map[string]any{}
+	converted, err := imp.convertSchema(target, defs)
+	if err != nil {
+		return err
+	}
+	defs[name] = converted
+	return nil
+}
+
+// resolveSchemaRef returns the component schema a reference points to.
+func (imp *openAPIImporter) resolveSchemaRef(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, openAPISchemaPrefix) {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	target, err := imp.pointer(ref)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("reference %q is not a schema", ref)
+	}
+	return m, nil
+}
+
+// resolve returns an object, following a $ref to another part of the
+// document (e.g., #/components/parameters/limit) if it is one.
+func (imp *openAPIImporter) resolve(v any) (map[string]any, error) {
+	for range 32 {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, errors.New("expected an object")
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+		target, err := imp.pointer(ref)
+		if err != nil {
+			return nil, err
+		}
+		v = target
+	}
+	return nil, errors.New("reference chain too long")
+}
+
+// pointer evaluates a local JSON Pointer reference against the document.
+func (imp *openAPIImporter) pointer(ref string) (any, error) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q; only local references are resolved", ref)
+	}
+	var cur any = imp.doc
+	for _, token := range strings.Split(path, "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot resolve %q", ref)
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, fmt.Errorf("cannot resolve %q", ref)
+		}
+	}
+	return cur, nil
+}
+
+// openAPIMediaType picks the media type of a content map to read a schema
+// from: application/json, then any other JSON type, then the first in
+// name order. Returns a nil media type if content is empty.
+func openAPIMediaType(raw any) (string, map[string]any) {
+	content, _ := raw.(map[string]any)
+	if len(content) == 0 {
+		return "", nil
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	pick := types[0]
+	for _, t := range types {
+		if t == "application/json" {
+			pick = t
+			break
+		}
+		if strings.HasSuffix(t, "+json") || strings.HasSuffix(t, "/json") {
+			pick = t
+		}
+	}
+	media, _ := content[pick].(map[string]any)
+	if media == nil {
+		media = map[string]any{}
+	}
+	return pick, media
+}
+
+// openAPIScopes returns the scopes of the first security requirement, in
+// scheme name order. Requirements are alternatives, so the first one
+// stands for the operation; an empty list means no authorization.
+func openAPIScopes(raw any) []string {
+	requirements, _ := raw.([]any)
+	if len(requirements) == 0 {
+		return nil
+	}
+	first, _ := requirements[0].(map[string]any)
+	schemes := make([]string, 0, len(first))
+	for scheme := range first {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	var scopes []string
+	for _, scheme := range schemes {
+		scopes = append(scopes, stringList(first[scheme])...)
+	}
+	return scopes
+}
+
+// copyOpenAPIMap returns a shallow copy of a map, or an empty map for nil.
+func copyOpenAPIMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
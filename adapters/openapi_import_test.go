@@ -0,0 +1,321 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const petstoreYAML = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com/v1
+security:
+  - petstore_auth: [read:pets]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      tags: [pets]
+      parameters:
+        - $ref: '#/components/parameters/limit'
+        - name: X-Request-ID
+          in: header
+          schema: {type: string}
+      responses:
+        '200':
+          description: A page of pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: createPet
+      description: Add a pet to the store.
+      tags: [pets]
+      security:
+        - petstore_auth: [write:pets, read:pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        default:
+          description: Error
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        description: The pet to act on
+        schema: {type: integer}
+    delete:
+      deprecated: true
+      security: []
+      responses:
+        '204': {description: Deleted}
+    put:
+      operationId: replacePet
+      parameters:
+        - name: petId
+          in: query
+          deprecated: true
+          schema: {type: string}
+      requestBody:
+        content:
+          text/plain:
+            schema: {type: string}
+      responses:
+        '200':
+          description: OK
+components:
+  parameters:
+    limit:
+      name: limit
+      in: query
+      description: Page size
+      schema: {type: integer, maximum: 100}
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string, nullable: true}
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          required: [id]
+          properties:
+            id: {type: integer}
+            parent: {$ref: '#/components/schemas/Pet'}
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	tools, err := ImportOpenAPI([]byte(petstoreYAML))
+	if err != nil {
+		t.Fatalf("ImportOpenAPI() error = %v", err)
+	}
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+		if err := tool.Validate(); err != nil {
+			t.Errorf("%s: Validate() error = %v", tool.Name, err)
+		}
+	}
+	// Paths in order, then methods in get, put, post, delete order
+	if want := []string{"listPets", "createPet", "replacePet", "delete_pets__petId_"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	list := tools[0]
+	if list.Description != "List all pets" || !reflect.DeepEqual(list.Tags, []string{"pets"}) || list.SourceFormat != "openapi" {
+		t.Errorf("listPets = %+v, want description, tags and source format", list)
+	}
+	if limit := list.InputSchema.Properties["limit"]; limit == nil || limit.Type != "integer" || limit.Description != "Page size" || *limit.Maximum != 100 {
+		t.Errorf("limit = %+v, want the resolved parameter schema", limit)
+	}
+	if !reflect.DeepEqual(list.RequiredScopes, []string{"read:pets"}) {
+		t.Errorf("listPets RequiredScopes = %v, want document security", list.RequiredScopes)
+	}
+	out := list.OutputSchema
+	if out == nil || out.Type != "array" || out.Items.Ref != "#/$defs/Pet" || out.Defs["Pet"] == nil || out.Defs["NewPet"] == nil {
+		t.Errorf("listPets OutputSchema = %+v, want an array of Pet with $defs", out)
+	}
+	http := list.SourceMeta["http"].(map[string]any)
+	wantHTTP := map[string]any{
+		"method": "GET",
+		"path":   "/pets",
+		"server": "https://petstore.example.com/v1",
+		"parameters": map[string]any{
+			"limit":        map[string]any{"in": "query", "name": "limit"},
+			"X-Request-ID": map[string]any{"in": "header", "name": "X-Request-ID"},
+		},
+	}
+	if !reflect.DeepEqual(http, wantHTTP) {
+		t.Errorf("SourceMeta[http] = %v, want %v", http, wantHTTP)
+	}
+
+	create := tools[1]
+	in := create.InputSchema
+	if in.Properties["name"] == nil || !reflect.DeepEqual(in.Required, []string{"name"}) {
+		t.Errorf("createPet InputSchema = %+v, want the body properties merged", in)
+	}
+	if tag := in.Properties["tag"]; len(tag.AnyOf) != 2 || tag.AnyOf[1].Type != "null" {
+		t.Errorf("tag = %+v, want nullable string as anyOf", tag)
+	}
+	if !reflect.DeepEqual(create.RequiredScopes, []string{"write:pets", "read:pets"}) {
+		t.Errorf("createPet RequiredScopes = %v, want operation security", create.RequiredScopes)
+	}
+	if create.SourceMeta["http"].(map[string]any)["contentType"] != "application/json" {
+		t.Errorf("createPet http = %v, want contentType", create.SourceMeta["http"])
+	}
+	if pet := create.OutputSchema.Defs["Pet"]; pet == nil || len(pet.AllOf) != 2 {
+		t.Errorf("createPet OutputSchema $defs = %+v, want the recursive Pet", create.OutputSchema.Defs)
+	}
+
+	replace := tools[2]
+	in = replace.InputSchema
+	if in.Properties["path.petId"].Type != "integer" || in.Properties["query.petId"].Type != "string" || in.Properties["body"].Type != "string" {
+		t.Errorf("replacePet InputSchema = %+v, want location-qualified petIds and a body property", in)
+	}
+	http = replace.SourceMeta["http"].(map[string]any)
+	if http["bodyProperty"] != "body" || http["contentType"] != "text/plain" {
+		t.Errorf("replacePet http = %v, want the body under a property", http)
+	}
+	if binding := http["parameters"].(map[string]any)["query.petId"]; !reflect.DeepEqual(binding, map[string]any{"in": "query", "name": "petId"}) {
+		t.Errorf("query.petId binding = %v", binding)
+	}
+	if in.Properties["query.petId"].Extra["deprecated"] != true || in.Properties["path.petId"].Extra != nil {
+		t.Errorf("petId Extra = %v, %v, want only the query parameter deprecated", in.Properties["query.petId"].Extra, in.Properties["path.petId"].Extra)
+	}
+	if replace.OutputSchema != nil {
+		t.Errorf("replacePet OutputSchema = %+v, want nil without content", replace.OutputSchema)
+	}
+
+	del := tools[3]
+	if del.Annotations == nil || !del.Annotations.Deprecated || del.RequiredScopes != nil {
+		t.Errorf("delete = %+v, want deprecated without scopes", del)
+	}
+	if petID := del.InputSchema.Properties["petId"]; petID.Description != "The pet to act on" || !reflect.DeepEqual(del.InputSchema.Required, []string{"petId"}) {
+		t.Errorf("delete InputSchema = %+v, want a required petId", del.InputSchema)
+	}
+}
+
+func TestImportOpenAPI_31(t *testing.T) {
+	doc := `{
+		"openapi": "3.1.0",
+		"info": {"title": "Search", "version": "1"},
+		"paths": {
+			"/search": {
+				"post": {
+					"operationId": "search",
+					"parameters": [{"name": "q", "in": "query", "required": true, "schema": {"type": "string"}}],
+					"requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {"q": {"type": "string"}}}}}},
+					"responses": {
+						"2XX": {"description": "Results", "content": {"application/json": {"schema": {"type": ["object", "null"]}}}},
+						"202": {"description": "Accepted", "content": {"application/json": {"schema": {"type": "object", "properties": {"job": {"type": "string"}}}}}}
+					}
+				}
+			}
+		}
+	}`
+
+	tools, err := ImportOpenAPI([]byte(doc))
+	if err != nil {
+		t.Fatalf("ImportOpenAPI() error = %v", err)
+	}
+	tool := tools[0]
+	// The body clashes with the q parameter, so it is kept whole
+	if body := tool.InputSchema.Properties["body"]; body == nil || body.Properties["q"] == nil {
+		t.Errorf("InputSchema = %+v, want the body under a property", tool.InputSchema)
+	}
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"q"}) {
+		t.Errorf("Required = %v, want [q]", tool.InputSchema.Required)
+	}
+	// An explicit status code is preferred over the range
+	if tool.OutputSchema == nil || tool.OutputSchema.Properties["job"] == nil {
+		t.Errorf("OutputSchema = %+v, want the 202 schema", tool.OutputSchema)
+	}
+}
+
+func TestImportOpenAPI_Nullable(t *testing.T) {
+	doc := `{
+		"openapi": "3.0.3",
+		"info": {"title": "Settings", "version": "1"},
+		"paths": {
+			"/settings": {
+				"put": {
+					"operationId": "putSettings",
+					"requestBody": {"content": {"application/json": {"schema": {
+						"type": "object",
+						"properties": {
+							"nullable": {"type": "boolean"},
+							"default": {"$ref": "#/components/schemas/Level"},
+							"$ref": {"type": "string"},
+							"owner": {"allOf": [{"$ref": "#/components/schemas/Owner"}], "nullable": true},
+							"level": {"$ref": "#/components/schemas/Level", "nullable": true},
+							"mode": {"enum": ["on", "off"], "nullable": true},
+							"size": {"type": "string", "enum": ["s", "m"], "nullable": true}
+						}
+					}}}},
+					"responses": {"204": {"description": "Saved"}}
+				}
+			}
+		},
+		"components": {"schemas": {
+			"Level": {"type": "integer"},
+			"Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}}
+	}`
+
+	tools, err := ImportOpenAPI([]byte(doc))
+	if err != nil {
+		t.Fatalf("ImportOpenAPI() error = %v", err)
+	}
+	in := tools[0].InputSchema
+	// Property names are not keywords
+	if p := in.Properties["nullable"]; p == nil || p.Type != "boolean" {
+		t.Errorf("nullable property = %+v, want a boolean", p)
+	}
+	if p := in.Properties["default"]; p == nil || p.Ref != "#/$defs/Level" {
+		t.Errorf("default property = %+v, want a reference into $defs", p)
+	}
+	if p := in.Properties["$ref"]; p == nil || p.Type != "string" {
+		t.Errorf("$ref property = %+v, want a string", p)
+	}
+	if in.Defs["Level"] == nil || in.Defs["Owner"] == nil {
+		t.Errorf("$defs = %+v, want Level and Owner", in.Defs)
+	}
+
+	// Schemas without a type accept null as an anyOf branch
+	for _, name := range []string{"owner", "level"} {
+		p := in.Properties[name]
+		if len(p.AnyOf) != 2 || p.AnyOf[1].Type != "null" {
+			t.Errorf("%s = %+v, want an anyOf with null", name, p)
+		}
+	}
+	if got := in.Properties["mode"].Enum; !reflect.DeepEqual(got, []any{"on", "off", nil}) {
+		t.Errorf("mode enum = %v, want null added", got)
+	}
+	size := in.Properties["size"]
+	if !reflect.DeepEqual(size.Enum, []any{"s", "m", nil}) || len(size.AnyOf) != 2 || size.AnyOf[1].Type != "null" {
+		t.Errorf("size = %+v, want a nullable string enum", size)
+	}
+}
+
+func TestImportOpenAPI_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":              ``,
+		"malformed":          `{"openapi":`,
+		"swagger 2":          `{"swagger": "2.0", "paths": {}}`,
+		"unresolvable param": `{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"$ref": "#/components/parameters/x"}]}}}}`,
+		"external ref":       `{"openapi": "3.1.0", "paths": {"/a": {"get": {"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "other.yaml#/Pet"}}}}}}}}}`,
+		"duplicate name":     `{"openapi": "3.1.0", "paths": {"/a": {"get": {"operationId": "x"}}, "/b": {"get": {"operationId": "x"}}}}`,
+		"unnamed param":      `{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"in": "query"}]}}}}`,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ImportOpenAPI([]byte(doc)); err == nil {
+				t.Error("ImportOpenAPI() error = nil, want error")
+			}
+		})
+	}
+
+	_, err := ImportOpenAPI([]byte(tests["duplicate name"]))
+	if err == nil || !strings.Contains(err.Error(), "GET /a") {
+		t.Errorf("ImportOpenAPI() error = %v, want it to name the other operation", err)
+	}
+}
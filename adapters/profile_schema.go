@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// restrictSchema returns a copy of a schema without the keywords and format
+// values a capability profile does not support, with an issue for each
+// removal. oneOf is approximated as anyOf when the profile supports only
+// anyOf. Returns nil if schema is nil.
+func restrictSchema(schema *tooladapter.JSONSchema, profile *tooladapter.CapabilityProfile) (*tooladapter.JSONSchema, []tooladapter.SchemaIssue) {
+	if schema == nil {
+		return nil, nil
+	}
+	r := &schemaRestrictor{profile: profile}
+	out := schema.DeepCopy()
+	r.restrict(out, "")
+	return out, r.issues
+}
+
+type schemaRestrictor struct {
+	profile *tooladapter.CapabilityProfile
+	issues  []tooladapter.SchemaIssue
+}
+
+func (r *schemaRestrictor) issue(path, keyword, message string) {
+	r.issues = append(r.issues, tooladapter.SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// restrict removes unsupported keywords from a schema node the restrictor
+// owns, then from its subschemas.
+func (r *schemaRestrictor) restrict(s *tooladapter.JSONSchema, path string) {
+	drop := func(feature tooladapter.SchemaFeature, present bool, clear func()) {
+		if present && !r.profile.SupportsFeature(feature) {
+			r.issue(path, feature.String(), "removed; not supported")
+			clear()
+		}
+	}
+	if len(s.OneOf) > 0 && !r.profile.SupportsFeature(tooladapter.FeatureOneOf) && r.profile.SupportsFeature(tooladapter.FeatureAnyOf) {
+		r.issue(path, "oneOf", "approximated as anyOf; exclusivity is not enforced")
+		s.AnyOf = append(s.AnyOf, s.OneOf...)
+		s.OneOf = nil
+	}
+	drop(tooladapter.FeatureRef, s.Ref != "", func() { s.Ref = "" })
+	drop(tooladapter.FeatureDefs, len(s.Defs) > 0, func() { s.Defs = nil })
+	drop(tooladapter.FeatureAnyOf, len(s.AnyOf) > 0, func() { s.AnyOf = nil })
+	drop(tooladapter.FeatureOneOf, len(s.OneOf) > 0, func() { s.OneOf = nil })
+	drop(tooladapter.FeatureAllOf, len(s.AllOf) > 0, func() { s.AllOf = nil })
+	drop(tooladapter.FeatureNot, s.Not != nil, func() { s.Not = nil })
+	drop(tooladapter.FeaturePattern, s.Pattern != "", func() { s.Pattern = "" })
+	drop(tooladapter.FeatureFormat, s.Format != "", func() { s.Format = "" })
+	drop(tooladapter.FeatureAdditionalProperties, s.AdditionalProperties != nil, func() { s.AdditionalProperties = nil })
+	drop(tooladapter.FeatureMinimum, s.Minimum != nil, func() { s.Minimum = nil })
+	drop(tooladapter.FeatureMaximum, s.Maximum != nil, func() { s.Maximum = nil })
+	drop(tooladapter.FeatureMinLength, s.MinLength != nil, func() { s.MinLength = nil })
+	drop(tooladapter.FeatureMaxLength, s.MaxLength != nil, func() { s.MaxLength = nil })
+	drop(tooladapter.FeatureEnum, len(s.Enum) > 0, func() { s.Enum = nil })
+	drop(tooladapter.FeatureConst, s.Const != nil, func() { s.Const = nil })
+	drop(tooladapter.FeatureDefault, s.Default != nil, func() { s.Default = nil })
+	if s.Format != "" && !r.profile.SupportsFormat(s.Format) {
+		r.issue(path, "format", fmt.Sprintf("removed; format %q is not supported", s.Format))
+		s.Format = ""
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		r.restrict(s.Properties[name], path+"/properties/"+escapePointer(name))
+	}
+	if s.Items != nil {
+		r.restrict(s.Items, path+"/items")
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		r.restrict(s.Defs[name], path+"/$defs/"+escapePointer(name))
+	}
+	for i, sub := range s.AnyOf {
+		r.restrict(sub, fmt.Sprintf("%s/anyOf/%d", path, i))
+	}
+	for i, sub := range s.OneOf {
+		r.restrict(sub, fmt.Sprintf("%s/oneOf/%d", path, i))
+	}
+	for i, sub := range s.AllOf {
+		r.restrict(sub, fmt.Sprintf("%s/allOf/%d", path, i))
+	}
+	if s.Not != nil {
+		r.restrict(s.Not, path+"/not")
+	}
+}
@@ -0,0 +1,58 @@
+package adapters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestRestrictSchema(t *testing.T) {
+	maxLen := 10
+	schema := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"id":   {OneOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			"name": {Type: "string", MaxLength: &maxLen, Pattern: "^[a-z]+$"},
+			"site": {Type: "string", Format: "uri"},
+		},
+	}
+	profile := &tooladapter.CapabilityProfile{
+		Adapter: "test",
+		Features: map[tooladapter.SchemaFeature]bool{
+			tooladapter.FeatureAnyOf:   true,
+			tooladapter.FeaturePattern: true,
+			tooladapter.FeatureFormat:  true,
+		},
+		Formats: []string{"email"},
+	}
+
+	got, issues := restrictSchema(schema, profile)
+
+	want := &tooladapter.JSONSchema{
+		Type: "object",
+		Properties: map[string]*tooladapter.JSONSchema{
+			"id":   {AnyOf: []*tooladapter.JSONSchema{{Type: "string"}, {Type: "integer"}}},
+			"name": {Type: "string", Pattern: "^[a-z]+$"},
+			"site": {Type: "string"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("restrictSchema() = %+v, want %+v", got, want)
+	}
+	wantIssues := []tooladapter.SchemaIssue{
+		{Path: "/properties/id", Keyword: "oneOf", Message: "approximated as anyOf; exclusivity is not enforced"},
+		{Path: "/properties/name", Keyword: "maxLength", Message: "removed; not supported"},
+		{Path: "/properties/site", Keyword: "format", Message: `removed; format "uri" is not supported`},
+	}
+	if !reflect.DeepEqual(issues, wantIssues) {
+		t.Errorf("issues = %v, want %v", issues, wantIssues)
+	}
+	if schema.Properties["name"].MaxLength == nil {
+		t.Error("restrictSchema() modified its input")
+	}
+
+	if got, issues := restrictSchema(nil, profile); got != nil || issues != nil {
+		t.Errorf("restrictSchema(nil) = %v, %v, want nil", got, issues)
+	}
+}
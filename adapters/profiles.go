@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+	"sync"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// profileFiles holds the built-in capability profiles, one file per adapter.
+//
+//go:embed profiles/*.json
+var profileFiles embed.FS
+
+// builtinProfiles parses the embedded profiles once, keyed by profile key.
+// The embedded data is validated by tests, so a parse failure is a build
+// defect and panics.
+var builtinProfiles = sync.OnceValue(func() map[string]*tooladapter.CapabilityProfile {
+	profiles := make(map[string]*tooladapter.CapabilityProfile)
+	files, err := fs.Glob(profileFiles, "profiles/*.json")
+	if err != nil {
+		panic("adapters: " + err.Error())
+	}
+	for _, name := range files {
+		data, err := profileFiles.ReadFile(name)
+		if err != nil {
+			panic("adapters: " + err.Error())
+		}
+		parsed, err := tooladapter.ParseCapabilityProfiles(data)
+		if err != nil {
+			panic("adapters: " + name + ": " + err.Error())
+		}
+		for i := range parsed {
+			p := parsed[i]
+			profiles[p.Key()] = &p
+		}
+	}
+	return profiles
+})
+
+// DefaultProfiles returns copies of all built-in capability profiles,
+// sorted by adapter name and mode.
+func DefaultProfiles() []tooladapter.CapabilityProfile {
+	builtin := builtinProfiles()
+	keys := make([]string, 0, len(builtin))
+	for k := range builtin {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	profiles := make([]tooladapter.CapabilityProfile, 0, len(keys))
+	for _, k := range keys {
+		profiles = append(profiles, *builtin[k].Clone())
+	}
+	return profiles
+}
+
+// builtinProfile returns a copy of the built-in profile for an adapter mode.
+func builtinProfile(adapter, mode string) (*tooladapter.CapabilityProfile, bool) {
+	p, ok := builtinProfiles()[tooladapter.ProfileKey(adapter, mode)]
+	if !ok {
+		return nil, false
+	}
+	return p.Clone(), true
+}
+
+// supportsBuiltin reports whether the built-in profile for an adapter mode
+// supports a feature.
+func supportsBuiltin(adapter, mode string, feature tooladapter.SchemaFeature) bool {
+	p, ok := builtinProfiles()[tooladapter.ProfileKey(adapter, mode)]
+	return ok && p.SupportsFeature(feature)
+}
@@ -0,0 +1,164 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDefaultProfiles(t *testing.T) {
+	profiles := DefaultProfiles()
+
+	want := []string{"anthropic", "bedrock", "cohere", "cohere:v1", "gemini", "gemini:jsonschema", "hermes", "llama3", "mcp", "mcp:2024-11-05", "mcp:2025-03-26", "mcp:2025-06-18", "mcp:2025-11-25", "openai", "openai:strict", "xml"}
+	if len(profiles) != len(want) {
+		t.Fatalf("DefaultProfiles() returned %d profiles, want %d", len(profiles), len(want))
+	}
+	for i, key := range want {
+		if profiles[i].Key() != key {
+			t.Errorf("DefaultProfiles()[%d].Key() = %q, want %q", i, profiles[i].Key(), key)
+		}
+		// Every profile must declare every feature explicitly
+		for _, f := range tooladapter.AllFeatures() {
+			if _, ok := profiles[i].Features[f]; !ok {
+				t.Errorf("profile %s does not declare %s", key, f)
+			}
+		}
+	}
+}
+
+func TestDefaultProfiles_ReturnsCopies(t *testing.T) {
+	profiles := DefaultProfiles()
+	profiles[0].Features[tooladapter.FeatureRef] = true
+
+	p, _ := NewAnthropicAdapter().Profile("")
+	if p.SupportsFeature(tooladapter.FeatureRef) {
+		t.Error("mutating DefaultProfiles() result changed the built-in profile")
+	}
+}
+
+func TestOpenAIAdapter_Profile_Strict(t *testing.T) {
+	adapter := NewOpenAIAdapter()
+
+	strict, ok := adapter.Profile("strict")
+	if !ok {
+		t.Fatal("Profile(strict) not found")
+	}
+
+	// Strict mode accepts anyOf and recursive references
+	for _, f := range []tooladapter.SchemaFeature{tooladapter.FeatureAnyOf, tooladapter.FeatureRef, tooladapter.FeatureDefs} {
+		if !strict.SupportsFeature(f) {
+			t.Errorf("strict SupportsFeature(%s) = false, want true", f)
+		}
+	}
+	if strict.SupportsFormat("uri") {
+		t.Error("strict SupportsFormat(uri) = true, want false")
+	}
+	if !strict.SupportsFormat("date-time") {
+		t.Error("strict SupportsFormat(date-time) = false, want true")
+	}
+	if strict.Limits.MaxDepth == 0 {
+		t.Error("strict Limits.MaxDepth is unset")
+	}
+
+	if _, ok := adapter.Profile("unknown"); ok {
+		t.Error("Profile(unknown) found, want not found")
+	}
+}
+
+func TestRegistry_Convert_StrictMode(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+
+	mcpTool := mcp.Tool{
+		Name: "ref-tool",
+		InputSchema: map[string]any{
+			"$ref": "#/$defs/Person",
+			"$defs": map[string]any{
+				"Person": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := registry.Convert(mcpTool, "mcp", "openai")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(result.Warnings) == 0 {
+		t.Error("Convert() default mode warnings = none, want $ref/$defs warnings")
+	}
+
+	result, err = registry.Convert(mcpTool, "mcp", "openai", tooladapter.WithTargetMode("strict"))
+	if err != nil {
+		t.Fatalf("Convert() strict error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Convert() strict warnings = %v, want none", result.Warnings)
+	}
+}
+
+func TestRegistry_Convert_ProfileOverrideChangesOutput(t *testing.T) {
+	registry := tooladapter.NewRegistry()
+	_ = registry.Register(NewMCPAdapter())
+	_ = registry.Register(NewOpenAIAdapter())
+	_ = registry.Register(NewCohereAdapter())
+
+	for _, key := range []string{"openai:strict", "cohere"} {
+		p, err := registry.Profile(splitKey(key))
+		if err != nil {
+			t.Fatalf("Profile(%s) error = %v", key, err)
+		}
+		p.Features[tooladapter.FeatureOneOf] = true
+		p.Features[tooladapter.FeatureNot] = true
+		if err := registry.SetProfile(*p); err != nil {
+			t.Fatalf("SetProfile(%s) error = %v", key, err)
+		}
+	}
+
+	mcpTool := mcp.Tool{
+		Name: "pick",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"value": map[string]any{
+					"oneOf": []any{
+						map[string]any{"type": "string"},
+						map[string]any{"type": "integer"},
+					},
+					"not": map[string]any{"const": "none"},
+				},
+			},
+			"required": []any{"value"},
+		},
+	}
+
+	for _, target := range []string{"openai:strict", "cohere"} {
+		result, err := registry.Convert(mcpTool, "mcp", target, tooladapter.WithRoundTripCheck())
+		if err != nil {
+			t.Fatalf("Convert(%s) error = %v", target, err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("Convert(%s) warnings = %v, want none", target, result.Warnings)
+		}
+		if len(result.Issues) != 0 {
+			t.Errorf("Convert(%s) issues = %v, want none", target, result.Issues)
+		}
+		value := result.RoundTrip.RoundTripped.InputSchema.Properties["value"]
+		if len(value.OneOf) != 2 || value.Not == nil {
+			t.Errorf("Convert(%s) value schema = %+v, want oneOf and not kept", target, value)
+		}
+	}
+}
+
+// splitKey splits a profile key into adapter and mode.
+func splitKey(key string) (adapter, mode string) {
+	adapter, mode, _ = strings.Cut(key, ":")
+	return adapter, mode
+}
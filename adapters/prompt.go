@@ -0,0 +1,301 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// PromptFormat identifies a convention for describing tools in a prompt
+// and for the tool calls a model writes in reply.
+type PromptFormat string
+
+const (
+	// PromptFormatHermes is the Hermes / Qwen convention: tools are JSON
+	// lines inside <tools></tools>, and each call is a JSON object with
+	// "name" and "arguments" inside <tool_call></tool_call>.
+	PromptFormatHermes PromptFormat = "hermes"
+
+	// PromptFormatLlama3 is the Llama 3.1+ JSON tool calling convention:
+	// tools are indented JSON objects, and a call is a bare JSON object
+	// with "name" and "parameters", optionally after <|python_tag|>.
+	PromptFormatLlama3 PromptFormat = "llama3"
+
+	// PromptFormatXML describes tools as JSON inside <function></function>
+	// elements, and calls as <invoke name="..."> elements with one
+	// <parameter name="..."> element per argument, inside
+	// <function_calls></function_calls>.
+	PromptFormatXML PromptFormat = "xml"
+)
+
+// PromptAdapter converts between canonical tools and tool descriptions
+// rendered into a prompt, for models served without native tool calling
+// (e.g., by llama.cpp or vLLM). Use NewHermesAdapter, NewLlama3Adapter or
+// NewXMLPromptAdapter to create one.
+//
+// FromCanonical renders a single tool as the format describes it inside
+// the tools block, and ToCanonical parses such a description. RenderTools
+// renders the whole block with its instructions for a system prompt, and
+// ParseToolCalls reads tool calls back out of the model's reply.
+type PromptAdapter struct {
+	format PromptFormat
+}
+
+// NewHermesAdapter creates a prompt adapter for PromptFormatHermes.
+func NewHermesAdapter() *PromptAdapter {
+	return &PromptAdapter{format: PromptFormatHermes}
+}
+
+// NewLlama3Adapter creates a prompt adapter for PromptFormatLlama3.
+func NewLlama3Adapter() *PromptAdapter {
+	return &PromptAdapter{format: PromptFormatLlama3}
+}
+
+// NewXMLPromptAdapter creates a prompt adapter for PromptFormatXML.
+func NewXMLPromptAdapter() *PromptAdapter {
+	return &PromptAdapter{format: PromptFormatXML}
+}
+
+// Name returns the adapter identifier, which is the format name.
+func (a *PromptAdapter) Name() string {
+	return string(a.format)
+}
+
+// Format returns the prompt format of the adapter.
+func (a *PromptAdapter) Format() PromptFormat {
+	return a.format
+}
+
+// promptFunction is the JSON description of a tool shared by all formats.
+type promptFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// promptTool wraps a function description in the envelope the Hermes and
+// Llama 3 formats use.
+type promptTool struct {
+	Type     string         `json:"type"`
+	Function promptFunction `json:"function"`
+}
+
+// ToCanonical converts a rendered tool description to canonical format.
+// Accepts the text FromCanonical produces as a string, []byte or
+// json.RawMessage, and its decoded JSON as map[string]any. The function
+// envelope and the XML <function> element are optional.
+func (a *PromptAdapter) ToCanonical(raw any) (*tooladapter.CanonicalTool, error) {
+	var data []byte
+	switch v := raw.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	case map[string]any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		data = encoded
+	default:
+		return nil, errors.New("expected rendered tool text or raw JSON")
+	}
+
+	data = bytes.TrimSpace(data)
+	if inner, ok := cutElement(string(data), "<function>", "</function>"); ok {
+		data = []byte(strings.TrimSpace(unescapeXML(inner)))
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decode %s tool: %w", a.format, err)
+	}
+	var fn promptFunction
+	if _, ok := probe["function"]; ok {
+		var tool promptTool
+		if err := json.Unmarshal(data, &tool); err != nil {
+			return nil, fmt.Errorf("decode %s tool: %w", a.format, err)
+		}
+		fn = tool.Function
+	} else if err := json.Unmarshal(data, &fn); err != nil {
+		return nil, fmt.Errorf("decode %s tool: %w", a.format, err)
+	}
+
+	canonical := &tooladapter.CanonicalTool{
+		Name:         fn.Name,
+		Description:  fn.Description,
+		SourceFormat: a.Name(),
+		SourceMeta:   make(map[string]any),
+	}
+	if fn.Parameters != nil {
+		schema, err := mapToJSONSchema(fn.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		canonical.InputSchema = schema
+	}
+	return canonical, nil
+}
+
+// FromCanonical renders a canonical tool as a string, in the form it takes
+// inside the tools block: one line of JSON for Hermes, indented JSON for
+// Llama 3, and a <function> element for XML, whose JSON is escaped as XML
+// text.
+func (a *PromptAdapter) FromCanonical(tool *tooladapter.CanonicalTool) (any, error) {
+	if tool == nil {
+		return nil, errors.New("nil CanonicalTool")
+	}
+	if !tool.IsFunction() {
+		return nil, unsupportedKindError(a.Name(), tool)
+	}
+
+	fn := promptFunction{
+		Name:        tool.Name,
+		Description: tool.Description,
+	}
+	if tool.InputSchema != nil {
+		fn.Parameters = tool.InputSchema.ToMap()
+	}
+
+	switch a.format {
+	case PromptFormatHermes:
+		return promptJSON(promptTool{Type: "function", Function: fn}, "")
+	case PromptFormatLlama3:
+		return promptJSON(promptTool{Type: "function", Function: fn}, "    ")
+	default:
+		text, err := promptJSON(fn, "")
+		if err != nil {
+			return nil, err
+		}
+		return "<function>" + escapeXMLText(text) + "</function>", nil
+	}
+}
+
+// RenderTools renders tools and the instructions for calling them, to be
+// placed in the system prompt. The wording follows the reference chat
+// templates of each format.
+func (a *PromptAdapter) RenderTools(tools []*tooladapter.CanonicalTool) (string, error) {
+	rendered := make([]string, 0, len(tools))
+	for i, tool := range tools {
+		out, err := a.FromCanonical(tool)
+		if err != nil {
+			return "", fmt.Errorf("tool %d: %w", i, err)
+		}
+		rendered = append(rendered, out.(string))
+	}
+
+	var b strings.Builder
+	switch a.format {
+	case PromptFormatHermes:
+		b.WriteString("# Tools\n\n")
+		b.WriteString("You may call one or more functions to assist with the user query.\n\n")
+		b.WriteString("You are provided with function signatures within <tools></tools> XML tags:\n")
+		b.WriteString("<tools>\n")
+		for _, r := range rendered {
+			b.WriteString(r + "\n")
+		}
+		b.WriteString("</tools>\n\n")
+		b.WriteString("For each function call, return a json object with function name and arguments within <tool_call></tool_call> XML tags:\n")
+		b.WriteString("<tool_call>\n")
+		b.WriteString(`{"name": <function-name>, "arguments": <args-json-object>}` + "\n")
+		b.WriteString("</tool_call>")
+	case PromptFormatLlama3:
+		b.WriteString("Given the following functions, please respond with a JSON for a function call with its proper arguments that best answers the given prompt.\n\n")
+		b.WriteString(`Respond in the format {"name": function name, "parameters": dictionary of argument name and its value}. Do not use variables.`)
+		for _, r := range rendered {
+			b.WriteString("\n\n" + r)
+		}
+	default:
+		b.WriteString("In this environment you have access to a set of tools you can use to answer the user's question.\n\n")
+		b.WriteString("You may call them like this:\n")
+		b.WriteString("<function_calls>\n")
+		b.WriteString(`<invoke name="$FUNCTION_NAME">` + "\n")
+		b.WriteString(`<parameter name="$PARAMETER_NAME">$PARAMETER_VALUE</parameter>` + "\n")
+		b.WriteString("...\n")
+		b.WriteString("</invoke>\n")
+		b.WriteString("</function_calls>\n\n")
+		b.WriteString("String and scalar parameters should be specified as is, while lists and objects should use JSON format.\n\n")
+		b.WriteString("Here are the functions available:\n")
+		b.WriteString("<functions>\n")
+		for _, r := range rendered {
+			b.WriteString(r + "\n")
+		}
+		b.WriteString("</functions>")
+	}
+	return b.String(), nil
+}
+
+// SupportsFeature returns whether this adapter supports a schema feature.
+// Answers come from the built-in capability profile: the input schema is
+// rendered as JSON Schema, so every keyword is kept, but tool-level
+// features are not rendered.
+func (a *PromptAdapter) SupportsFeature(feature tooladapter.SchemaFeature) bool {
+	return supportsBuiltin(a.Name(), "", feature)
+}
+
+// Profile returns the built-in capability profile for a mode.
+// Only the default mode ("") is defined.
+func (a *PromptAdapter) Profile(mode string) (*tooladapter.CapabilityProfile, bool) {
+	return builtinProfile(a.Name(), mode)
+}
+
+// promptJSON encodes a value for a prompt: without HTML escaping, which
+// would turn "<" into "\u003c", and indented if indent is set.
+func promptJSON(v any, indent string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// cutElement returns the text between an opening and closing tag that
+// enclose the whole of s.
+func cutElement(s, open, close string) (string, bool) {
+	inner, ok := strings.CutPrefix(s, open)
+	if !ok {
+		return "", false
+	}
+	return strings.CutSuffix(inner, close)
+}
+
+// escapeXMLText escapes text for element content. Line breaks are kept, so
+// that multi-line values stay readable to the model.
+func escapeXMLText(s string) string {
+	return xmlTextEscaper.Replace(s)
+}
+
+// escapeXMLAttr escapes text for a double-quoted attribute value.
+func escapeXMLAttr(s string) string {
+	return xmlAttrEscaper.Replace(s)
+}
+
+// unescapeXML replaces the predefined XML entities and their numeric forms
+// as written by encoding/xml. Anything else, including a bare "&" or "<"
+// that a model wrote unescaped, is kept as is.
+func unescapeXML(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	return xmlUnescaper.Replace(s)
+}
+
+var (
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	xmlUnescaper   = strings.NewReplacer(
+		"&lt;", "<", "&gt;", ">", "&amp;", "&", "&quot;", `"`, "&apos;", "'",
+		"&#34;", `"`, "&#39;", "'", "&#xA;", "\n", "&#x9;", "\t", "&#xD;", "\r",
+	)
+)
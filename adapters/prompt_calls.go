@@ -0,0 +1,302 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// llama3Tokens are special tokens a Llama 3 reply may contain around a
+// tool call when the server does not strip them.
+var llama3Tokens = []string{"<|python_tag|>", "<|eom_id|>", "<|eot_id|>"}
+
+// promptCall is the JSON encoding of a Hermes or Llama 3 tool call. Hermes
+// uses "arguments" and Llama 3 "parameters"; both are accepted.
+type promptCall struct {
+	Name       string          `json:"name"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ParseToolCalls reads the tool calls a model wrote in its reply. It
+// returns the rest of the reply, trimmed, and the calls in order. Call IDs
+// are empty, since none of the formats assigns them.
+//
+// XML parameter values are text; tools, if given, supply the input schemas
+// used to decode them. A value is decoded as JSON unless its property is a
+// string, and kept as a string when it is not valid JSON or the tool or
+// property is unknown. The other formats carry JSON arguments and ignore
+// tools.
+//
+// Returns an error if a Hermes <tool_call> block does not hold a JSON call,
+// or if call arguments are not an object (or a string holding one).
+// Llama 3 text that does not decode as a call is kept as content.
+func (a *PromptAdapter) ParseToolCalls(text string, tools []*tooladapter.CanonicalTool) (string, []tooladapter.ToolCall, error) {
+	switch a.format {
+	case PromptFormatHermes:
+		return parseHermesCalls(text)
+	case PromptFormatLlama3:
+		return parseLlama3Calls(text)
+	default:
+		return parseXMLCalls(text, tools)
+	}
+}
+
+// RenderToolCalls renders calls as the model writes them, to replay an
+// assistant turn in the conversation history.
+func (a *PromptAdapter) RenderToolCalls(calls []tooladapter.ToolCall) (string, error) {
+	parts := make([]string, 0, len(calls))
+	for _, call := range calls {
+		args := call.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		switch a.format {
+		case PromptFormatHermes:
+			data, err := promptJSON(struct {
+				Name      string         `json:"name"`
+				Arguments map[string]any `json:"arguments"`
+			}{call.Name, args}, "")
+			if err != nil {
+				return "", fmt.Errorf("tool call %q: %w", call.Name, err)
+			}
+			parts = append(parts, "<tool_call>\n"+data+"\n</tool_call>")
+		case PromptFormatLlama3:
+			data, err := promptJSON(struct {
+				Name       string         `json:"name"`
+				Parameters map[string]any `json:"parameters"`
+			}{call.Name, args}, "")
+			if err != nil {
+				return "", fmt.Errorf("tool call %q: %w", call.Name, err)
+			}
+			parts = append(parts, data)
+		default:
+			invoke, err := renderXMLInvoke(call.Name, args)
+			if err != nil {
+				return "", fmt.Errorf("tool call %q: %w", call.Name, err)
+			}
+			parts = append(parts, invoke)
+		}
+	}
+
+	switch a.format {
+	case PromptFormatHermes:
+		return strings.Join(parts, "\n"), nil
+	case PromptFormatLlama3:
+		return strings.Join(parts, "; "), nil
+	default:
+		if len(parts) == 0 {
+			return "", nil
+		}
+		return "<function_calls>\n" + strings.Join(parts, "\n") + "\n</function_calls>", nil
+	}
+}
+
+// parseHermesCalls reads <tool_call> blocks. A block left open at the end
+// of the reply, as when generation stops at the closing tag, runs to the
+// end.
+func parseHermesCalls(text string) (string, []tooladapter.ToolCall, error) {
+	var calls []tooladapter.ToolCall
+	var content strings.Builder
+	rest := text
+	for {
+		before, block, found := strings.Cut(rest, "<tool_call>")
+		content.WriteString(before)
+		if !found {
+			break
+		}
+		body, after, _ := strings.Cut(block, "</tool_call>")
+		rest = after
+
+		var call promptCall
+		if err := json.Unmarshal([]byte(strings.TrimSpace(body)), &call); err != nil {
+			return "", nil, fmt.Errorf("tool call %d: %w", len(calls), err)
+		}
+		args, err := decodeCallArguments(call.Arguments)
+		if err != nil {
+			return "", nil, fmt.Errorf("tool call %d: %w", len(calls), err)
+		}
+		calls = append(calls, tooladapter.ToolCall{Name: call.Name, Arguments: args})
+	}
+	return strings.TrimSpace(content.String()), calls, nil
+}
+
+// parseLlama3Calls finds JSON objects with a "name" and "parameters" (or
+// "arguments") in the reply. Separators between consecutive calls are
+// dropped with them.
+func parseLlama3Calls(text string) (string, []tooladapter.ToolCall, error) {
+	for _, token := range llama3Tokens {
+		text = strings.ReplaceAll(text, token, "")
+	}
+
+	var calls []tooladapter.ToolCall
+	var gaps []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text[i:]))
+		var call promptCall
+		if err := dec.Decode(&call); err != nil || call.Name == "" {
+			continue
+		}
+		raw := call.Parameters
+		if raw == nil {
+			raw = call.Arguments
+		}
+		if raw == nil {
+			continue
+		}
+		args, err := decodeCallArguments(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("tool call %d: %w", len(calls), err)
+		}
+
+		gaps = append(gaps, text[start:i])
+		calls = append(calls, tooladapter.ToolCall{Name: call.Name, Arguments: args})
+		start = i + int(dec.InputOffset())
+		i = start - 1
+	}
+	gaps = append(gaps, text[start:])
+
+	var content []string
+	for i, gap := range gaps {
+		between := i > 0 && i < len(gaps)-1
+		if between && strings.Trim(gap, " \t\r\n;,") == "" {
+			continue
+		}
+		if trimmed := strings.TrimSpace(strings.Trim(strings.TrimSpace(gap), ";,")); trimmed != "" {
+			content = append(content, trimmed)
+		}
+	}
+	return strings.Join(content, "\n"), calls, nil
+}
+
+// parseXMLCalls reads <invoke> elements from <function_calls> blocks. A
+// block or element left open at the end of the reply runs to the end.
+// Names and values are unescaped.
+func parseXMLCalls(text string, tools []*tooladapter.CanonicalTool) (string, []tooladapter.ToolCall, error) {
+	byName := make(map[string]*tooladapter.CanonicalTool, len(tools))
+	for _, tool := range tools {
+		if tool != nil {
+			byName[tool.Name] = tool
+		}
+	}
+
+	var calls []tooladapter.ToolCall
+	var content strings.Builder
+	rest := text
+	for {
+		before, block, found := strings.Cut(rest, "<function_calls>")
+		content.WriteString(before)
+		if !found {
+			break
+		}
+		block, rest, _ = strings.Cut(block, "</function_calls>")
+
+		for {
+			name, body, ok := cutNamedElement(block, "invoke")
+			if !ok {
+				break
+			}
+			call := tooladapter.ToolCall{Name: unescapeXML(name), Arguments: make(map[string]any)}
+			var next string
+			body, next, _ = strings.Cut(body, "</invoke>")
+			block = next
+
+			for {
+				param, value, ok := cutNamedElement(body, "parameter")
+				if !ok {
+					break
+				}
+				value, body, _ = strings.Cut(value, "</parameter>")
+				param = unescapeXML(param)
+				call.Arguments[param] = decodeXMLParameter(byName[unescapeXML(name)], param, unescapeXML(value))
+			}
+			calls = append(calls, call)
+		}
+	}
+	return strings.TrimSpace(content.String()), calls, nil
+}
+
+// cutNamedElement finds the next <tag name="..."> opening tag in s and
+// returns the name and the text after the tag.
+func cutNamedElement(s, tag string) (name, after string, found bool) {
+	open := "<" + tag + ` name="`
+	i := strings.Index(s, open)
+	if i < 0 {
+		return "", "", false
+	}
+	rest := s[i+len(open):]
+	name, after, found = strings.Cut(rest, `">`)
+	if !found {
+		return "", "", false
+	}
+	return name, after, true
+}
+
+// decodeXMLParameter converts an XML parameter value using the property
+// schema, if known.
+func decodeXMLParameter(tool *tooladapter.CanonicalTool, param, value string) any {
+	if tool == nil || tool.InputSchema == nil {
+		return value
+	}
+	prop := tool.InputSchema.Properties[param]
+	if prop == nil || prop.Type == "string" {
+		return value
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// renderXMLInvoke renders one call as an <invoke> element with parameters
+// in name order. Strings are written as is and other values as JSON, both
+// escaped as XML text, so that a value cannot close its element.
+func renderXMLInvoke(name string, args map[string]any) (string, error) {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(`<invoke name="` + escapeXMLAttr(name) + `">` + "\n")
+	for _, k := range keys {
+		value, ok := args[k].(string)
+		if !ok {
+			encoded, err := promptJSON(args[k], "")
+			if err != nil {
+				return "", err
+			}
+			value = encoded
+		}
+		b.WriteString(`<parameter name="` + escapeXMLAttr(k) + `">` + escapeXMLText(value) + "</parameter>\n")
+	}
+	b.WriteString("</invoke>")
+	return b.String(), nil
+}
+
+// decodeCallArguments decodes call arguments given as a JSON object or as
+// a string holding one. Missing or null arguments decode to an empty map.
+func decodeCallArguments(raw json.RawMessage) (map[string]any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return map[string]any{}, nil
+	}
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		raw = json.RawMessage(encoded)
+	}
+	var args map[string]any
+	if err := json.Unmarshal(raw, &args); err != nil || args == nil {
+		return nil, errors.New("tool call arguments are not a JSON object")
+	}
+	return args, nil
+}
@@ -0,0 +1,239 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func TestPromptAdapter_ParseToolCalls(t *testing.T) {
+	weather := tooladapter.ToolCall{Name: "get_weather", Arguments: map[string]any{"city": "Paris", "days": float64(3)}}
+	tests := []struct {
+		name        string
+		adapter     *PromptAdapter
+		text        string
+		wantContent string
+		wantCalls   []tooladapter.ToolCall
+	}{
+		{
+			name:        "hermes",
+			adapter:     NewHermesAdapter(),
+			text:        "Let me check.\n<tool_call>\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\", \"days\": 3}}\n</tool_call>\n<tool_call>\n{\"name\": \"get_time\", \"arguments\": \"{}\"}\n</tool_call>",
+			wantContent: "Let me check.",
+			wantCalls:   []tooladapter.ToolCall{weather, {Name: "get_time", Arguments: map[string]any{}}},
+		},
+		{
+			name:      "hermes unclosed",
+			adapter:   NewHermesAdapter(),
+			text:      "<tool_call>\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\", \"days\": 3}}\n",
+			wantCalls: []tooladapter.ToolCall{weather},
+		},
+		{
+			name:        "hermes no calls",
+			adapter:     NewHermesAdapter(),
+			text:        "  It is sunny.\n",
+			wantContent: "It is sunny.",
+		},
+		{
+			name:      "llama3",
+			adapter:   NewLlama3Adapter(),
+			text:      "<|python_tag|>{\"name\": \"get_weather\", \"parameters\": {\"city\": \"Paris\", \"days\": 3}}; {\"name\": \"get_time\", \"parameters\": {}}<|eom_id|>",
+			wantCalls: []tooladapter.ToolCall{weather, {Name: "get_time", Arguments: map[string]any{}}},
+		},
+		{
+			name:        "llama3 with text",
+			adapter:     NewLlama3Adapter(),
+			text:        "Sure, {braces} first.\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\", \"days\": 3}}",
+			wantContent: "Sure, {braces} first.",
+			wantCalls:   []tooladapter.ToolCall{weather},
+		},
+		{
+			name:        "xml",
+			adapter:     NewXMLPromptAdapter(),
+			text:        "Checking.\n<function_calls>\n<invoke name=\"get_weather\">\n<parameter name=\"city\">Paris</parameter>\n<parameter name=\"days\">3</parameter>\n</invoke>\n</function_calls>",
+			wantContent: "Checking.",
+			wantCalls:   []tooladapter.ToolCall{weather},
+		},
+		{
+			name:      "xml unknown tool",
+			adapter:   NewXMLPromptAdapter(),
+			text:      "<function_calls><invoke name=\"lookup\"><parameter name=\"id\">42</parameter></invoke><invoke name=\"get_time\"></invoke></function_calls>",
+			wantCalls: []tooladapter.ToolCall{{Name: "lookup", Arguments: map[string]any{"id": "42"}}, {Name: "get_time", Arguments: map[string]any{}}},
+		},
+	}
+
+	tool := weatherTool()
+	tool.InputSchema.Properties["days"] = &tooladapter.JSONSchema{Type: "integer"}
+	tools := []*tooladapter.CanonicalTool{tool}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, calls, err := tt.adapter.ParseToolCalls(tt.text, tools)
+			if err != nil {
+				t.Fatalf("ParseToolCalls() error = %v", err)
+			}
+			if content != tt.wantContent {
+				t.Errorf("content = %q, want %q", content, tt.wantContent)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %+v, want %+v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPromptAdapter_ParseToolCalls_XMLValues(t *testing.T) {
+	tool := &tooladapter.CanonicalTool{
+		Name: "search",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"query":  {Type: "string"},
+				"tags":   {Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}},
+				"limit":  {Type: "integer"},
+				"strict": {Type: "boolean"},
+			},
+		},
+	}
+	text := `<function_calls><invoke name="search">` +
+		`<parameter name="query">42</parameter>` +
+		`<parameter name="tags">["a", "b"]</parameter>` +
+		`<parameter name="limit">ten</parameter>` +
+		`<parameter name="strict">true</parameter>` +
+		`<parameter name="extra">1</parameter>` +
+		`</invoke></function_calls>`
+
+	_, calls, err := NewXMLPromptAdapter().ParseToolCalls(text, []*tooladapter.CanonicalTool{tool})
+	if err != nil {
+		t.Fatalf("ParseToolCalls() error = %v", err)
+	}
+	want := map[string]any{
+		"query":  "42",
+		"tags":   []any{"a", "b"},
+		"limit":  "ten",
+		"strict": true,
+		"extra":  "1",
+	}
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].Arguments, want) {
+		t.Errorf("calls = %+v, want arguments %v", calls, want)
+	}
+
+	// Undecodable values are left for validation to report
+	issues, err := calls[0].Validate(tool)
+	if err != nil || len(issues) == 0 {
+		t.Errorf("Validate() = %v, %v, want an issue for limit", issues, err)
+	}
+}
+
+func TestPromptAdapter_ParseToolCalls_Errors(t *testing.T) {
+	tests := map[string]struct {
+		adapter *PromptAdapter
+		text    string
+	}{
+		"hermes not JSON":  {NewHermesAdapter(), "<tool_call>get_weather(city=Paris)</tool_call>"},
+		"hermes arguments": {NewHermesAdapter(), `<tool_call>{"name": "get_weather", "arguments": [1]}</tool_call>`},
+		"llama3 arguments": {NewLlama3Adapter(), `{"name": "get_weather", "parameters": "city=Paris"}`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := tt.adapter.ParseToolCalls(tt.text, nil); err == nil {
+				t.Error("ParseToolCalls() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPromptAdapter_RenderToolCalls(t *testing.T) {
+	calls := []tooladapter.ToolCall{
+		{Name: "get_weather", Arguments: map[string]any{"city": "Paris", "days": float64(3)}},
+		{Name: "get_time"},
+	}
+	tests := map[*PromptAdapter]string{
+		NewHermesAdapter(): "<tool_call>\n{\"name\":\"get_weather\",\"arguments\":{\"city\":\"Paris\",\"days\":3}}\n</tool_call>\n" +
+			"<tool_call>\n{\"name\":\"get_time\",\"arguments\":{}}\n</tool_call>",
+		NewLlama3Adapter(): `{"name":"get_weather","parameters":{"city":"Paris","days":3}}; {"name":"get_time","parameters":{}}`,
+		NewXMLPromptAdapter(): "<function_calls>\n<invoke name=\"get_weather\">\n<parameter name=\"city\">Paris</parameter>\n<parameter name=\"days\">3</parameter>\n</invoke>\n" +
+			"<invoke name=\"get_time\">\n</invoke>\n</function_calls>",
+	}
+	tool := weatherTool()
+	tool.InputSchema.Properties["days"] = &tooladapter.JSONSchema{Type: "integer"}
+	tools := []*tooladapter.CanonicalTool{tool}
+	for a, want := range tests {
+		t.Run(a.Name(), func(t *testing.T) {
+			got, err := a.RenderToolCalls(calls)
+			if err != nil {
+				t.Fatalf("RenderToolCalls() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("RenderToolCalls() =\n%s\nwant\n%s", got, want)
+			}
+
+			_, parsed, err := a.ParseToolCalls(got, tools)
+			if err != nil {
+				t.Fatalf("ParseToolCalls() error = %v", err)
+			}
+			wantParsed := []tooladapter.ToolCall{calls[0], {Name: "get_time", Arguments: map[string]any{}}}
+			if !reflect.DeepEqual(parsed, wantParsed) {
+				t.Errorf("ParseToolCalls(RenderToolCalls()) = %+v, want %+v", parsed, wantParsed)
+			}
+		})
+	}
+
+	if got, err := NewXMLPromptAdapter().RenderToolCalls(nil); err != nil || got != "" {
+		t.Errorf("RenderToolCalls(nil) = %q, %v, want empty", got, err)
+	}
+}
+
+func TestPromptAdapter_XMLEscaping(t *testing.T) {
+	a := NewXMLPromptAdapter()
+	tool := &tooladapter.CanonicalTool{
+		Name:        "run_query",
+		Description: "Runs a < b & c; never </function> early.",
+		InputSchema: &tooladapter.JSONSchema{
+			Type: "object",
+			Properties: map[string]*tooladapter.JSONSchema{
+				"sql":    {Type: "string", Description: `Query, e.g. "a < b"`},
+				"filter": {Type: "object"},
+			},
+		},
+	}
+
+	rendered, err := a.FromCanonical(tool)
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if strings.Count(rendered.(string), "</function>") != 1 {
+		t.Errorf("FromCanonical() = %s, want the description escaped", rendered)
+	}
+	back, err := a.ToCanonical(rendered)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	if back.Description != tool.Description || back.InputSchema.Properties["sql"].Description != `Query, e.g. "a < b"` {
+		t.Errorf("ToCanonical(FromCanonical()) = %+v, want the original text", back)
+	}
+
+	calls := []tooladapter.ToolCall{{
+		Name: "run_query",
+		Arguments: map[string]any{
+			"sql":      "SELECT '</parameter>' WHERE a < b && c\n</invoke></function_calls>",
+			"filter":   map[string]any{"tag": "</invoke>", "op": "&amp;"},
+			`odd"name`: "x",
+		},
+	}}
+	text, err := a.RenderToolCalls(calls)
+	if err != nil {
+		t.Fatalf("RenderToolCalls() error = %v", err)
+	}
+	if strings.Count(text, "</invoke>") != 1 || strings.Count(text, "</parameter>") != 3 {
+		t.Errorf("RenderToolCalls() =\n%s\nwant values escaped", text)
+	}
+	_, parsed, err := a.ParseToolCalls(text, []*tooladapter.CanonicalTool{tool})
+	if err != nil {
+		t.Fatalf("ParseToolCalls() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, calls) {
+		t.Errorf("ParseToolCalls(RenderToolCalls()) = %+v, want %+v", parsed, calls)
+	}
+}
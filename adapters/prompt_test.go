@@ -0,0 +1,151 @@
+package adapters
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+func promptAdapters() []*PromptAdapter {
+	return []*PromptAdapter{NewHermesAdapter(), NewLlama3Adapter(), NewXMLPromptAdapter()}
+}
+
+func TestPromptAdapter_Name(t *testing.T) {
+	for _, a := range promptAdapters() {
+		if a.Name() != string(a.Format()) {
+			t.Errorf("Name() = %q, want %q", a.Name(), a.Format())
+		}
+	}
+}
+
+func TestPromptAdapter_FromCanonical(t *testing.T) {
+	tests := []struct {
+		adapter *PromptAdapter
+		want    string
+	}{
+		{NewHermesAdapter(), `{"type":"function","function":{"name":"get_weather","description":"Get the weather.","parameters":{"properties":{"city":{"minLength":1,"type":"string"}},"required":["city"],"type":"object"}}}`},
+		{NewXMLPromptAdapter(), `<function>{"name":"get_weather","description":"Get the weather.","parameters":{"properties":{"city":{"minLength":1,"type":"string"}},"required":["city"],"type":"object"}}</function>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.adapter.Name(), func(t *testing.T) {
+			got, err := tt.adapter.FromCanonical(weatherTool())
+			if err != nil {
+				t.Fatalf("FromCanonical() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FromCanonical() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	got, err := NewLlama3Adapter().FromCanonical(weatherTool())
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	if !strings.HasPrefix(got.(string), "{\n    \"type\": \"function\",\n    \"function\": {\n        \"name\": \"get_weather\"") {
+		t.Errorf("llama3 FromCanonical() =\n%s\nwant indented JSON", got)
+	}
+}
+
+func TestPromptAdapter_RoundTrip(t *testing.T) {
+	for _, a := range promptAdapters() {
+		t.Run(a.Name(), func(t *testing.T) {
+			out, err := a.FromCanonical(weatherTool())
+			if err != nil {
+				t.Fatalf("FromCanonical() error = %v", err)
+			}
+			got, err := a.ToCanonical(out)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			want := weatherTool()
+			if got.Name != want.Name || got.Description != want.Description || got.SourceFormat != a.Name() {
+				t.Errorf("tool = %+v, want %s from %s", got, want.Name, a.Name())
+			}
+			if got.InputSchema.Properties["city"].Type != "string" || len(got.InputSchema.Required) != 1 {
+				t.Errorf("InputSchema = %+v, want the original schema", got.InputSchema)
+			}
+		})
+	}
+}
+
+func TestPromptAdapter_ToCanonical_Inputs(t *testing.T) {
+	inputs := map[string]any{
+		"bare JSON":     []byte(`{"name":"get_weather","parameters":{"type":"object"}}`),
+		"envelope":      json.RawMessage(`{"type":"function","function":{"name":"get_weather","parameters":{"type":"object"}}}`),
+		"xml element":   "  <function>{\"name\":\"get_weather\",\"parameters\":{\"type\":\"object\"}}</function>\n",
+		"decoded JSON":  map[string]any{"name": "get_weather", "parameters": map[string]any{"type": "object"}},
+		"no parameters": `{"name":"get_weather"}`,
+	}
+	for name, raw := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewXMLPromptAdapter().ToCanonical(raw)
+			if err != nil {
+				t.Fatalf("ToCanonical() error = %v", err)
+			}
+			if got.Name != "get_weather" {
+				t.Errorf("Name = %q, want get_weather", got.Name)
+			}
+		})
+	}
+
+	for name, raw := range map[string]any{"not JSON": "get_weather()", "wrong type": 42} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewHermesAdapter().ToCanonical(raw); err == nil {
+				t.Error("ToCanonical() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPromptAdapter_FromCanonical_Errors(t *testing.T) {
+	a := NewHermesAdapter()
+	if _, err := a.FromCanonical(nil); err == nil {
+		t.Error("FromCanonical(nil) error = nil, want error")
+	}
+	custom := &tooladapter.CanonicalTool{Kind: tooladapter.ToolKindCustom, Name: "exec"}
+	if _, err := a.FromCanonical(custom); err == nil {
+		t.Error("FromCanonical(custom) error = nil, want error")
+	}
+}
+
+func TestPromptAdapter_RenderTools(t *testing.T) {
+	tests := map[*PromptAdapter][]string{
+		NewHermesAdapter():    {"<tools>\n{\"type\":\"function\"", "</tools>", "<tool_call>"},
+		NewLlama3Adapter():    {`"parameters": dictionary of argument name and its value`, "\n\n{\n    \"type\": \"function\""},
+		NewXMLPromptAdapter(): {"<function_calls>", "<functions>\n<function>{\"name\":\"get_weather\"", "</function>\n</functions>"},
+	}
+	for a, wants := range tests {
+		t.Run(a.Name(), func(t *testing.T) {
+			got, err := a.RenderTools([]*tooladapter.CanonicalTool{weatherTool()})
+			if err != nil {
+				t.Fatalf("RenderTools() error = %v", err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderTools() =\n%s\nwant it to contain %q", got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := NewHermesAdapter().RenderTools([]*tooladapter.CanonicalTool{nil}); err == nil {
+		t.Error("RenderTools(nil tool) error = nil, want error")
+	}
+}
+
+func TestPromptAdapter_SupportsFeature(t *testing.T) {
+	for _, a := range promptAdapters() {
+		if !a.SupportsFeature(tooladapter.FeatureRef) || !a.SupportsFeature(tooladapter.FeatureOneOf) {
+			t.Errorf("%s SupportsFeature($ref, oneOf) = false, want true", a.Name())
+		}
+		if a.SupportsFeature(tooladapter.FeatureOutputSchema) {
+			t.Errorf("%s SupportsFeature(outputSchema) = true, want false", a.Name())
+		}
+		if _, ok := a.Profile(""); !ok {
+			t.Errorf("%s Profile(\"\") not found", a.Name())
+		}
+	}
+}
@@ -0,0 +1,574 @@
+package adapters
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The descriptor types below hold the parts of descriptor.proto the
+// protobuf importer uses, decoded from the wire format. Field numbers are
+// those of google/protobuf/descriptor.proto.
+
+// protoFile is a FileDescriptorProto.
+type protoFile struct {
+	name     string
+	pkg      string
+	messages []*protoMessage
+	enums    []*protoEnum
+	services []*protoService
+}
+
+// protoMessage is a DescriptorProto.
+type protoMessage struct {
+	// fullName is the fully qualified name, with a leading dot, as type
+	// references write it
+	fullName string
+	comment  string
+	fields   []*protoField
+	nested   []*protoMessage
+	enums    []*protoEnum
+	oneofs   []string
+
+	// mapEntry is set for the synthetic entry messages of map fields
+	mapEntry bool
+}
+
+// protoField is a FieldDescriptorProto.
+type protoField struct {
+	name     string
+	jsonName string
+	comment  string
+	label    uint64
+	typ      uint64
+	typeName string
+
+	// oneof is the index of the containing oneof, or -1
+	oneof int
+
+	// proto3Optional marks a field whose oneof is synthetic
+	proto3Optional bool
+	deprecated     bool
+}
+
+// protoEnum is an EnumDescriptorProto.
+type protoEnum struct {
+	fullName string
+	comment  string
+	values   []string
+}
+
+// protoService is a ServiceDescriptorProto.
+type protoService struct {
+	name       string
+	comment    string
+	methods    []*protoMethod
+	deprecated bool
+}
+
+// protoMethod is a MethodDescriptorProto.
+type protoMethod struct {
+	name            string
+	comment         string
+	inputType       string
+	outputType      string
+	clientStreaming bool
+	serverStreaming bool
+	deprecated      bool
+
+	// idempotency is the MethodOptions.IdempotencyLevel value
+	idempotency uint64
+}
+
+// Field labels and types of FieldDescriptorProto.
+const (
+	protoLabelRequired = 2
+	protoLabelRepeated = 3
+
+	protoTypeDouble   = 1
+	protoTypeFloat    = 2
+	protoTypeInt64    = 3
+	protoTypeUint64   = 4
+	protoTypeInt32    = 5
+	protoTypeFixed64  = 6
+	protoTypeFixed32  = 7
+	protoTypeBool     = 8
+	protoTypeString   = 9
+	protoTypeGroup    = 10
+	protoTypeMessage  = 11
+	protoTypeBytes    = 12
+	protoTypeUint32   = 13
+	protoTypeEnum     = 14
+	protoTypeSfixed32 = 15
+	protoTypeSfixed64 = 16
+	protoTypeSint32   = 17
+	protoTypeSint64   = 18
+)
+
+// Idempotency levels of MethodOptions.
+const (
+	protoNoSideEffects = 1
+	protoIdempotent    = 2
+)
+
+// Wire types.
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// protoRawField is one field of an encoded message. Varint and fixed
+// values are in value, length-delimited ones in data.
+type protoRawField struct {
+	num   int
+	wire  int
+	value uint64
+	data  []byte
+}
+
+// parseProtoFields splits an encoded message into its fields.
+func parseProtoFields(data []byte) ([]protoRawField, error) {
+	var fields []protoRawField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid field key")
+		}
+		data = data[n:]
+		f := protoRawField{num: int(key >> 3), wire: int(key & 7)}
+		if f.num <= 0 {
+			return nil, fmt.Errorf("invalid field number %d", f.num)
+		}
+		switch f.wire {
+		case protoWireVarint:
+			f.value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("field %d: invalid varint", f.num)
+			}
+			data = data[n:]
+		case protoWireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("field %d: truncated", f.num)
+			}
+			f.value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case protoWireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("field %d: truncated", f.num)
+			}
+			f.value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case protoWireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return nil, fmt.Errorf("field %d: truncated", f.num)
+			}
+			f.data, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			// Groups (3 and 4) do not occur in descriptors
+			return nil, fmt.Errorf("field %d: unsupported wire type %d", f.num, f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// protoVarints returns the values of a repeated integer field, packed or
+// not.
+func protoVarints(f protoRawField) ([]uint64, error) {
+	if f.wire == protoWireVarint {
+		return []uint64{f.value}, nil
+	}
+	var values []uint64
+	for data := f.data; len(data) > 0; {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("field %d: invalid varint", f.num)
+		}
+		values = append(values, v)
+		data = data[n:]
+	}
+	return values, nil
+}
+
+// parseFileDescriptorSet decodes a FileDescriptorSet.
+func parseFileDescriptorSet(data []byte) ([]*protoFile, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	var files []*protoFile
+	for _, f := range fields {
+		if f.num != 1 || f.wire != protoWireBytes {
+			continue
+		}
+		file, err := parseProtoFile(f.data)
+		if err != nil {
+			return nil, fmt.Errorf("file %d: %w", len(files), err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// protoComments maps source paths, written as dotted numbers, to leading
+// comments.
+type protoComments map[string]string
+
+// at returns the comment of the element at a path.
+func (c protoComments) at(path []int) string {
+	return c[protoPathKey(path)]
+}
+
+// protoPathKey writes a source path as dotted numbers.
+func protoPathKey(path []int) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func parseProtoFile(data []byte) (*protoFile, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments come last in the encoding but are needed first
+	comments := protoComments{}
+	file := &protoFile{}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.wire == protoWireBytes:
+			file.name = string(f.data)
+		case f.num == 2 && f.wire == protoWireBytes:
+			file.pkg = string(f.data)
+		case f.num == 9 && f.wire == protoWireBytes:
+			if err := parseProtoSourceInfo(f.data, comments); err != nil {
+				return nil, fmt.Errorf("source code info: %w", err)
+			}
+		}
+	}
+	wrap := func(err error) error {
+		if file.name == "" {
+			return err
+		}
+		return fmt.Errorf("%s: %w", file.name, err)
+	}
+
+	scope := ""
+	if file.pkg != "" {
+		scope = "." + file.pkg
+	}
+	var counts [7]int
+	for _, f := range fields {
+		if f.wire != protoWireBytes || f.num < 4 || f.num > 6 {
+			continue
+		}
+		path := []int{f.num, counts[f.num]}
+		counts[f.num]++
+		switch f.num {
+		case 4:
+			m, err := parseProtoMessage(f.data, scope, path, comments)
+			if err != nil {
+				return nil, wrap(err)
+			}
+			file.messages = append(file.messages, m)
+		case 5:
+			e, err := parseProtoEnum(f.data, scope, path, comments)
+			if err != nil {
+				return nil, wrap(err)
+			}
+			file.enums = append(file.enums, e)
+		case 6:
+			s, err := parseProtoService(f.data, path, comments)
+			if err != nil {
+				return nil, wrap(err)
+			}
+			file.services = append(file.services, s)
+		}
+	}
+	return file, nil
+}
+
+// parseProtoSourceInfo collects the leading comments of a SourceCodeInfo.
+func parseProtoSourceInfo(data []byte, comments protoComments) error {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.num != 1 || f.wire != protoWireBytes {
+			continue
+		}
+		loc, err := parseProtoFields(f.data)
+		if err != nil {
+			return err
+		}
+		var path []int
+		var comment string
+		for _, lf := range loc {
+			switch {
+			case lf.num == 1:
+				values, err := protoVarints(lf)
+				if err != nil {
+					return err
+				}
+				for _, v := range values {
+					path = append(path, int(v))
+				}
+			case lf.num == 3 && lf.wire == protoWireBytes:
+				comment = protoComment(string(lf.data))
+			}
+		}
+		if comment != "" {
+			comments[protoPathKey(path)] = comment
+		}
+	}
+	return nil
+}
+
+// protoComment cleans up a comment as protoc records it: each line keeps
+// the space after "//", and the text ends with a newline.
+func protoComment(text string) string {
+	lines := strings.Split(strings.TrimRight(text, " \t\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.TrimPrefix(line, " "), " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func parseProtoMessage(data []byte, scope string, path []int, comments protoComments) (*protoMessage, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &protoMessage{comment: comments.at(path)}
+	for _, f := range fields {
+		if f.num == 1 && f.wire == protoWireBytes {
+			m.fullName = scope + "." + string(f.data)
+		}
+	}
+	if m.fullName == "" || m.fullName == scope+"." {
+		return nil, errors.New("message without a name")
+	}
+
+	var counts [9]int
+	for _, f := range fields {
+		if f.wire != protoWireBytes {
+			continue
+		}
+		var sub []int
+		if f.num < len(counts) {
+			sub = append(append([]int{}, path...), f.num, counts[f.num])
+			counts[f.num]++
+		}
+		switch f.num {
+		case 2:
+			field, err := parseProtoField(f.data, sub, comments)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", m.fullName[1:], err)
+			}
+			m.fields = append(m.fields, field)
+		case 3:
+			nested, err := parseProtoMessage(f.data, m.fullName, sub, comments)
+			if err != nil {
+				return nil, err
+			}
+			m.nested = append(m.nested, nested)
+		case 4:
+			e, err := parseProtoEnum(f.data, m.fullName, sub, comments)
+			if err != nil {
+				return nil, err
+			}
+			m.enums = append(m.enums, e)
+		case 7:
+			opts, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			m.mapEntry = protoBoolOption(opts, 7)
+		case 8:
+			oneof, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			var name string
+			for _, of := range oneof {
+				if of.num == 1 && of.wire == protoWireBytes {
+					name = string(of.data)
+				}
+			}
+			m.oneofs = append(m.oneofs, name)
+		}
+	}
+	return m, nil
+}
+
+func parseProtoField(data []byte, path []int, comments protoComments) (*protoField, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	field := &protoField{comment: comments.at(path), oneof: -1}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.wire == protoWireBytes:
+			field.name = string(f.data)
+		case f.num == 4 && f.wire == protoWireVarint:
+			field.label = f.value
+		case f.num == 5 && f.wire == protoWireVarint:
+			field.typ = f.value
+		case f.num == 6 && f.wire == protoWireBytes:
+			field.typeName = string(f.data)
+		case f.num == 8 && f.wire == protoWireBytes:
+			opts, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			field.deprecated = protoBoolOption(opts, 3)
+		case f.num == 9 && f.wire == protoWireVarint:
+			field.oneof = int(f.value)
+		case f.num == 10 && f.wire == protoWireBytes:
+			field.jsonName = string(f.data)
+		case f.num == 17 && f.wire == protoWireVarint:
+			field.proto3Optional = f.value != 0
+		}
+	}
+	if field.name == "" {
+		return nil, errors.New("field without a name")
+	}
+	if field.jsonName == "" {
+		field.jsonName = protoJSONName(field.name)
+	}
+	return field, nil
+}
+
+func parseProtoEnum(data []byte, scope string, path []int, comments protoComments) (*protoEnum, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	e := &protoEnum{comment: comments.at(path)}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.wire == protoWireBytes:
+			e.fullName = scope + "." + string(f.data)
+		case f.num == 2 && f.wire == protoWireBytes:
+			value, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			for _, vf := range value {
+				if vf.num == 1 && vf.wire == protoWireBytes {
+					e.values = append(e.values, string(vf.data))
+				}
+			}
+		}
+	}
+	if e.fullName == "" || e.fullName == scope+"." {
+		return nil, errors.New("enum without a name")
+	}
+	return e, nil
+}
+
+func parseProtoService(data []byte, path []int, comments protoComments) (*protoService, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	s := &protoService{comment: comments.at(path)}
+	methods := 0
+	for _, f := range fields {
+		if f.wire != protoWireBytes {
+			continue
+		}
+		switch f.num {
+		case 1:
+			s.name = string(f.data)
+		case 2:
+			sub := append(append([]int{}, path...), 2, methods)
+			methods++
+			m, err := parseProtoMethod(f.data, sub, comments)
+			if err != nil {
+				return nil, err
+			}
+			s.methods = append(s.methods, m)
+		case 3:
+			opts, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			s.deprecated = protoBoolOption(opts, 33)
+		}
+	}
+	return s, nil
+}
+
+func parseProtoMethod(data []byte, path []int, comments protoComments) (*protoMethod, error) {
+	fields, err := parseProtoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &protoMethod{comment: comments.at(path)}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.wire == protoWireBytes:
+			m.name = string(f.data)
+		case f.num == 2 && f.wire == protoWireBytes:
+			m.inputType = string(f.data)
+		case f.num == 3 && f.wire == protoWireBytes:
+			m.outputType = string(f.data)
+		case f.num == 4 && f.wire == protoWireBytes:
+			opts, err := parseProtoFields(f.data)
+			if err != nil {
+				return nil, err
+			}
+			m.deprecated = protoBoolOption(opts, 33)
+			for _, of := range opts {
+				if of.num == 34 && of.wire == protoWireVarint {
+					m.idempotency = of.value
+				}
+			}
+		case f.num == 5 && f.wire == protoWireVarint:
+			m.clientStreaming = f.value != 0
+		case f.num == 6 && f.wire == protoWireVarint:
+			m.serverStreaming = f.value != 0
+		}
+	}
+	return m, nil
+}
+
+// protoBoolOption reads a bool field of an options message.
+func protoBoolOption(opts []protoRawField, num int) bool {
+	set := false
+	for _, f := range opts {
+		if f.num == num && f.wire == protoWireVarint {
+			set = f.value != 0
+		}
+	}
+	return set
+}
+
+// protoJSONName derives the JSON name of a field as protoc does: each
+// underscore is dropped and the letter after it capitalized.
+func protoJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			b.WriteRune(r)
+			upper = false
+		}
+	}
+	return b.String()
+}
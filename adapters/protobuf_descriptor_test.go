@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// protoBuilder encodes messages for tests.
+type protoBuilder []byte
+
+func (b protoBuilder) varint(num int, v uint64) protoBuilder {
+	out := binary.AppendUvarint(b, uint64(num)<<3|protoWireVarint)
+	return binary.AppendUvarint(out, v)
+}
+
+func (b protoBuilder) bytes(num int, data []byte) protoBuilder {
+	out := binary.AppendUvarint(b, uint64(num)<<3|protoWireBytes)
+	out = binary.AppendUvarint(out, uint64(len(data)))
+	return append(out, data...)
+}
+
+func (b protoBuilder) str(num int, s string) protoBuilder {
+	return b.bytes(num, []byte(s))
+}
+
+func (b protoBuilder) msg(num int, m protoBuilder) protoBuilder {
+	return b.bytes(num, m)
+}
+
+func (b protoBuilder) packed(num int, values ...uint64) protoBuilder {
+	var data []byte
+	for _, v := range values {
+		data = binary.AppendUvarint(data, v)
+	}
+	return b.bytes(num, data)
+}
+
+// protoLocation encodes a SourceCodeInfo.Location with a leading comment.
+func protoLocation(comment string, path ...uint64) protoBuilder {
+	return protoBuilder{}.packed(1, path...).str(3, comment)
+}
+
+func TestParseFileDescriptorSet(t *testing.T) {
+	field := protoBuilder{}.str(1, "display_name").varint(3, 1).varint(4, 1).varint(5, protoTypeString).
+		msg(8, protoBuilder{}.varint(3, 1))
+	entry := protoBuilder{}.str(1, "LabelsEntry").msg(7, protoBuilder{}.varint(7, 1))
+	message := protoBuilder{}.str(1, "Shelf").msg(2, field).msg(3, entry).
+		msg(4, protoBuilder{}.str(1, "Kind").msg(2, protoBuilder{}.str(1, "KIND_UNSPECIFIED").varint(2, 0))).
+		msg(8, protoBuilder{}.str(1, "choice"))
+	method := protoBuilder{}.str(1, "GetShelf").str(2, ".shop.Shelf").str(3, ".shop.Shelf").
+		msg(4, protoBuilder{}.varint(33, 1).varint(34, protoIdempotent)).varint(6, 1)
+	service := protoBuilder{}.str(1, "Shelves").msg(2, method)
+	info := protoBuilder{}.
+		msg(1, protoLocation(" A shelf.\n  Indented.\n", 4, 0)).
+		msg(1, protoLocation(" The name. \n", 4, 0, 2, 0)).
+		// Unpacked paths are accepted too
+		msg(1, protoBuilder{}.varint(1, 6).varint(1, 0).varint(1, 2).varint(1, 0).str(3, " Gets a shelf.\n"))
+	file := protoBuilder{}.str(1, "shop.proto").str(2, "shop").msg(4, message).msg(6, service).msg(9, info)
+	set := protoBuilder{}.msg(1, file)
+
+	files, err := parseFileDescriptorSet(set)
+	if err != nil {
+		t.Fatalf("parseFileDescriptorSet() error = %v", err)
+	}
+	if len(files) != 1 || files[0].name != "shop.proto" || files[0].pkg != "shop" {
+		t.Fatalf("files = %+v", files)
+	}
+
+	shelf := files[0].messages[0]
+	if shelf.fullName != ".shop.Shelf" || shelf.comment != "A shelf.\n Indented." {
+		t.Errorf("message = %q, comment %q", shelf.fullName, shelf.comment)
+	}
+	if got := shelf.nested[0]; got.fullName != ".shop.Shelf.LabelsEntry" || !got.mapEntry {
+		t.Errorf("nested = %+v, want the map entry", got)
+	}
+	if got := shelf.enums[0]; got.fullName != ".shop.Shelf.Kind" || !reflect.DeepEqual(got.values, []string{"KIND_UNSPECIFIED"}) {
+		t.Errorf("enum = %+v", got)
+	}
+	if !reflect.DeepEqual(shelf.oneofs, []string{"choice"}) {
+		t.Errorf("oneofs = %v", shelf.oneofs)
+	}
+	want := &protoField{
+		name: "display_name", jsonName: "displayName", comment: "The name.",
+		label: 1, typ: protoTypeString, oneof: -1, deprecated: true,
+	}
+	if got := shelf.fields[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("field = %+v, want %+v", got, want)
+	}
+
+	got := files[0].services[0].methods[0]
+	if got.comment != "Gets a shelf." || !got.deprecated || got.idempotency != protoIdempotent || !got.serverStreaming || got.clientStreaming {
+		t.Errorf("method = %+v", got)
+	}
+}
+
+func TestParseFileDescriptorSet_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"truncated bytes", []byte{0x0a, 0x05, 0x01}, "truncated"},
+		{"bad varint", []byte{0x08, 0xff}, "invalid varint"},
+		{"group", []byte{0x0b}, "unsupported wire type 3"},
+		{"field zero", []byte{0x00, 0x00}, "invalid field number 0"},
+		{
+			"nested error",
+			protoBuilder{}.msg(1, protoBuilder{}.str(1, "a.proto").bytes(4, []byte{0x12, 0x09})),
+			"a.proto: field 2: truncated",
+		},
+		{"nameless message", []byte{0x0a, 0x05, 0x22, 0x03, 0x12, 0x01, 0x00}, "message without a name"},
+		{
+			"nameless field",
+			protoBuilder{}.msg(1, protoBuilder{}.msg(4, protoBuilder{}.str(1, "M").msg(2, protoBuilder{}.varint(3, 1)))),
+			"M: field without a name",
+		},
+		{
+			"nameless enum",
+			protoBuilder{}.msg(1, protoBuilder{}.msg(5, protoBuilder{}.msg(2, protoBuilder{}.str(1, "A")))),
+			"enum without a name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFileDescriptorSet(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseFileDescriptorSet() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoJSONName(t *testing.T) {
+	tests := map[string]string{
+		"name":          "name",
+		"page_count":    "pageCount",
+		"x_2d":          "x2d",
+		"already_Upper": "alreadyUpper",
+	}
+	for in, want := range tests {
+		if got := protoJSONName(in); got != want {
+			t.Errorf("protoJSONName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
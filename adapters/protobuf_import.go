@@ -0,0 +1,382 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// Patterns of the proto3 JSON string encodings.
+const (
+	protoInt64Pattern    = `^-?[0-9]+$`
+	protoUint64Pattern   = `^[0-9]+$`
+	protoDurationPattern = `^-?[0-9]+(\.[0-9]{1,9})?s$`
+)
+
+// Bounds of the 32-bit integer types.
+var (
+	protoInt32Bounds  = [2]float64{-1 << 31, 1<<31 - 1}
+	protoUint32Bounds = [2]float64{0, 1<<32 - 1}
+)
+
+// protoWellKnown builds the schemas of the well-known types, which have
+// special JSON encodings. They are inlined rather than kept in $defs.
+var protoWellKnown = map[string]func() *tooladapter.JSONSchema{
+	".google.protobuf.Timestamp": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "string", Format: "date-time"}
+	},
+	".google.protobuf.Duration": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "string", Pattern: protoDurationPattern}
+	},
+	".google.protobuf.FieldMask": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "string"}
+	},
+	".google.protobuf.Struct": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "object"}
+	},
+	".google.protobuf.Value": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{}
+	},
+	".google.protobuf.ListValue": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "array"}
+	},
+	".google.protobuf.Any": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{
+			Type:       "object",
+			Properties: map[string]*tooladapter.JSONSchema{"@type": {Type: "string"}},
+			Required:   []string{"@type"},
+		}
+	},
+	".google.protobuf.Empty": func() *tooladapter.JSONSchema {
+		return &tooladapter.JSONSchema{Type: "object", AdditionalProperties: boolPtr(false)}
+	},
+	".google.protobuf.DoubleValue": func() *tooladapter.JSONSchema { return protoScalar(protoTypeDouble) },
+	".google.protobuf.FloatValue":  func() *tooladapter.JSONSchema { return protoScalar(protoTypeFloat) },
+	".google.protobuf.Int64Value":  func() *tooladapter.JSONSchema { return protoScalar(protoTypeInt64) },
+	".google.protobuf.UInt64Value": func() *tooladapter.JSONSchema { return protoScalar(protoTypeUint64) },
+	".google.protobuf.Int32Value":  func() *tooladapter.JSONSchema { return protoScalar(protoTypeInt32) },
+	".google.protobuf.UInt32Value": func() *tooladapter.JSONSchema { return protoScalar(protoTypeUint32) },
+	".google.protobuf.BoolValue":   func() *tooladapter.JSONSchema { return protoScalar(protoTypeBool) },
+	".google.protobuf.StringValue": func() *tooladapter.JSONSchema { return protoScalar(protoTypeString) },
+	".google.protobuf.BytesValue":  func() *tooladapter.JSONSchema { return protoScalar(protoTypeBytes) },
+}
+
+// ImportFileDescriptorSet reads a serialized google.protobuf.FileDescriptorSet,
+// as written by protoc --descriptor_set_out or buf build, and returns one
+// canonical tool per RPC method, in file, service and method order.
+//
+// Each tool is built from its method:
+//   - Name is "<Service>_<Method>" and Namespace is the proto package
+//   - Description is the leading comment of the method, or else of the
+//     service
+//   - InputSchema and OutputSchema are the request and response messages
+//   - Annotations mark NO_SIDE_EFFECTS methods read-only and IDEMPOTENT
+//     ones idempotent
+//   - Annotations.Deprecated is set for deprecated methods and methods of
+//     deprecated services
+//
+// Schemas follow the proto3 JSON mapping: fields are named by their JSON
+// name, 64-bit integers are decimal strings, enums are value names, bytes
+// are base64 strings, and well-known types take their JSON forms. Other
+// messages and enums are referenced from $defs by full name. Maps are
+// objects whose additionalProperties schema, kept in JSONSchema.Extra, is
+// the value schema. A oneof allows at most one of its fields, which is
+// written as a oneOf. Deprecated fields carry the JSON Schema "deprecated"
+// keyword. Leading comments become descriptions; the descriptor set must
+// be built with source info (protoc --include_source_info) to carry them.
+//
+// The gRPC binding is recorded in SourceMeta["grpc"]; see the design
+// notes for its layout.
+//
+// Returns an error if the data is not a descriptor set, a message, enum or
+// field has no name, or a method refers to a message that is not in it.
+func ImportFileDescriptorSet(data []byte) ([]*tooladapter.CanonicalTool, error) {
+	files, err := parseFileDescriptorSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode FileDescriptorSet: %w", err)
+	}
+	imp := &protoImporter{
+		messages: make(map[string]*protoMessage),
+		enums:    make(map[string]*protoEnum),
+	}
+	for _, file := range files {
+		imp.index(file.messages, file.enums)
+	}
+
+	var tools []*tooladapter.CanonicalTool
+	for _, file := range files {
+		for _, service := range file.services {
+			for _, method := range service.methods {
+				tool, err := imp.method(file, service, method)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", protoServiceName(file, service)+"."+method.name, err)
+				}
+				tools = append(tools, tool)
+			}
+		}
+	}
+	return tools, nil
+}
+
+// protoImporter holds the types of every file in the set, by full name.
+type protoImporter struct {
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+}
+
+func (imp *protoImporter) index(messages []*protoMessage, enums []*protoEnum) {
+	for _, e := range enums {
+		imp.enums[e.fullName] = e
+	}
+	for _, m := range messages {
+		imp.messages[m.fullName] = m
+		imp.index(m.nested, m.enums)
+	}
+}
+
+// protoServiceName returns the full name of a service.
+func protoServiceName(file *protoFile, service *protoService) string {
+	if file.pkg == "" {
+		return service.name
+	}
+	return file.pkg + "." + service.name
+}
+
+// method converts one RPC method.
+func (imp *protoImporter) method(file *protoFile, service *protoService, method *protoMethod) (*tooladapter.CanonicalTool, error) {
+	tool := &tooladapter.CanonicalTool{
+		Namespace:    file.pkg,
+		Name:         service.name + "_" + method.name,
+		Description:  method.comment,
+		SourceFormat: "protobuf",
+		SourceMeta:   make(map[string]any),
+	}
+	if tool.Description == "" {
+		tool.Description = service.comment
+	}
+	switch method.idempotency {
+	case protoNoSideEffects:
+		tool.Annotations = &tooladapter.ToolAnnotations{ReadOnlyHint: true}
+	case protoIdempotent:
+		tool.Annotations = &tooladapter.ToolAnnotations{IdempotentHint: true}
+	}
+	if method.deprecated || service.deprecated {
+		if tool.Annotations == nil {
+			tool.Annotations = &tooladapter.ToolAnnotations{}
+		}
+		tool.Annotations.Deprecated = true
+	}
+
+	var err error
+	if tool.InputSchema, err = imp.root(method.inputType); err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	if tool.OutputSchema, err = imp.root(method.outputType); err != nil {
+		return nil, fmt.Errorf("response: %w", err)
+	}
+
+	serviceName := protoServiceName(file, service)
+	grpc := map[string]any{
+		"service": serviceName,
+		"method":  method.name,
+		"path":    "/" + serviceName + "/" + method.name,
+	}
+	if method.clientStreaming {
+		grpc["clientStreaming"] = true
+	}
+	if method.serverStreaming {
+		grpc["serverStreaming"] = true
+	}
+	tool.SourceMeta["grpc"] = grpc
+	return tool, nil
+}
+
+// root converts a request or response message, with the messages and
+// enums it references in $defs.
+func (imp *protoImporter) root(typeName string) (*tooladapter.JSONSchema, error) {
+	if wkt, ok := protoWellKnown[typeName]; ok {
+		return wkt(), nil
+	}
+	m, ok := imp.messages[typeName]
+	if !ok {
+		return nil, fmt.Errorf("undefined message %q", strings.TrimPrefix(typeName, "."))
+	}
+	defs := make(map[string]*tooladapter.JSONSchema)
+	schema, err := imp.message(m, defs)
+	if err != nil {
+		return nil, err
+	}
+	if len(defs) > 0 {
+		schema.Defs = defs
+	}
+	return schema, nil
+}
+
+// message converts a message to an object schema.
+func (imp *protoImporter) message(m *protoMessage, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	schema := &tooladapter.JSONSchema{Type: "object", Description: m.comment}
+	oneofs := make([][]string, len(m.oneofs))
+	for _, f := range m.fields {
+		prop, err := imp.field(f, defs)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", m.fullName[1:], f.name, err)
+		}
+		if f.comment != "" {
+			prop.Description = f.comment
+		}
+		if f.deprecated {
+			markDeprecated(prop)
+		}
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]*tooladapter.JSONSchema, len(m.fields))
+		}
+		schema.Properties[f.jsonName] = prop
+		if f.label == protoLabelRequired {
+			schema.Required = append(schema.Required, f.jsonName)
+		}
+		if f.oneof >= 0 && f.oneof < len(oneofs) && !f.proto3Optional {
+			oneofs[f.oneof] = append(oneofs[f.oneof], f.jsonName)
+		}
+	}
+
+	// Each oneof holds one of its fields or none
+	var constraints []*tooladapter.JSONSchema
+	for _, names := range oneofs {
+		if len(names) == 0 {
+			continue
+		}
+		var branches []*tooladapter.JSONSchema
+		for _, name := range names {
+			branches = append(branches, &tooladapter.JSONSchema{Required: []string{name}})
+		}
+		none := &tooladapter.JSONSchema{Not: &tooladapter.JSONSchema{AnyOf: branches}}
+		constraints = append(constraints, &tooladapter.JSONSchema{OneOf: append(branches, none)})
+	}
+	switch len(constraints) {
+	case 0:
+	case 1:
+		schema.OneOf = constraints[0].OneOf
+	default:
+		schema.AllOf = constraints
+	}
+	return schema, nil
+}
+
+// field converts a field, including its repetition.
+func (imp *protoImporter) field(f *protoField, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	if f.label == protoLabelRepeated {
+		if entry, ok := imp.messages[f.typeName]; ok && entry.mapEntry {
+			return imp.mapField(entry, defs)
+		}
+		items, err := imp.value(f, defs)
+		if err != nil {
+			return nil, err
+		}
+		return &tooladapter.JSONSchema{Type: "array", Items: items}, nil
+	}
+	return imp.value(f, defs)
+}
+
+// mapField converts a map field, given its entry message, to an object
+// whose additionalProperties schema is the value schema. Canonical schemas
+// model only the boolean form, so the schema is kept in Extra, in the
+// decoded JSON form that ToMap and MCP produce.
+func (imp *protoImporter) mapField(entry *protoMessage, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	schema := &tooladapter.JSONSchema{Type: "object"}
+	for _, f := range entry.fields {
+		if f.name != "value" {
+			continue
+		}
+		value, err := imp.value(f, defs)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value.ToMap())
+		if err != nil {
+			return nil, fmt.Errorf("encode map value schema: %w", err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("decode map value schema: %w", err)
+		}
+		schema.Extra = map[string]any{"additionalProperties": m}
+	}
+	return schema, nil
+}
+
+// value converts a single value of a field's type.
+func (imp *protoImporter) value(f *protoField, defs map[string]*tooladapter.JSONSchema) (*tooladapter.JSONSchema, error) {
+	switch f.typ {
+	case protoTypeMessage, protoTypeGroup:
+		if wkt, ok := protoWellKnown[f.typeName]; ok {
+			return wkt(), nil
+		}
+		m, ok := imp.messages[f.typeName]
+		if !ok {
+			return nil, fmt.Errorf("undefined message %q", strings.TrimPrefix(f.typeName, "."))
+		}
+		name := m.fullName[1:]
+		if _, ok := defs[name]; !ok {
+			// Messages such as a tree node can contain themselves; a
+			// field of the message being converted refers to this placeholder
+			defs[name] = &tooladapter.JSONSchema{}
+			def, err := imp.message(m, defs)
+			if err != nil {
+				return nil, err
+			}
+			defs[name] = def
+		}
+		return &tooladapter.JSONSchema{Ref: "#/$defs/" + name}, nil
+	case protoTypeEnum:
+		if f.typeName == ".google.protobuf.NullValue" {
+			return &tooladapter.JSONSchema{Type: "null"}, nil
+		}
+		e, ok := imp.enums[f.typeName]
+		if !ok {
+			return nil, fmt.Errorf("undefined enum %q", strings.TrimPrefix(f.typeName, "."))
+		}
+		name := e.fullName[1:]
+		if _, ok := defs[name]; !ok {
+			values := make([]any, len(e.values))
+			for i, v := range e.values {
+				values[i] = v
+			}
+			defs[name] = &tooladapter.JSONSchema{Type: "string", Enum: values, Description: e.comment}
+		}
+		return &tooladapter.JSONSchema{Ref: "#/$defs/" + name}, nil
+	}
+	if schema := protoScalar(f.typ); schema != nil {
+		return schema, nil
+	}
+	return nil, fmt.Errorf("unknown field type %d", f.typ)
+}
+
+// protoScalar returns the schema of a scalar type, or nil if typ is not
+// one.
+func protoScalar(typ uint64) *tooladapter.JSONSchema {
+	bounded := func(bounds [2]float64) *tooladapter.JSONSchema {
+		minimum, maximum := bounds[0], bounds[1]
+		return &tooladapter.JSONSchema{Type: "integer", Minimum: &minimum, Maximum: &maximum}
+	}
+	switch typ {
+	case protoTypeDouble, protoTypeFloat:
+		return &tooladapter.JSONSchema{Type: "number"}
+	case protoTypeInt32, protoTypeSint32, protoTypeSfixed32:
+		return bounded(protoInt32Bounds)
+	case protoTypeUint32, protoTypeFixed32:
+		return bounded(protoUint32Bounds)
+	case protoTypeInt64, protoTypeSint64, protoTypeSfixed64:
+		return &tooladapter.JSONSchema{Type: "string", Pattern: protoInt64Pattern}
+	case protoTypeUint64, protoTypeFixed64:
+		return &tooladapter.JSONSchema{Type: "string", Pattern: protoUint64Pattern}
+	case protoTypeBool:
+		return &tooladapter.JSONSchema{Type: "boolean"}
+	case protoTypeString:
+		return &tooladapter.JSONSchema{Type: "string"}
+	case protoTypeBytes:
+		return &tooladapter.JSONSchema{Type: "string", Format: "byte"}
+	}
+	return nil
+}
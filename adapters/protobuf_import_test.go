@@ -0,0 +1,308 @@
+package adapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonwraymond/tooladapter"
+)
+
+// protoFieldDesc encodes a FieldDescriptorProto.
+func protoFieldDesc(name string, number, label, typ uint64, typeName string) protoBuilder {
+	b := protoBuilder{}.str(1, name).varint(3, number).varint(4, label).varint(5, typ)
+	if typeName != "" {
+		b = b.str(6, typeName)
+	}
+	return b.str(10, protoJSONName(name))
+}
+
+// libraryDescriptorSet encodes a descriptor set for a small library
+// service, as protoc would with --include_source_info.
+func libraryDescriptorSet() []byte {
+	const optional, required, repeated = 1, 2, 3
+	book := protoBuilder{}.str(1, "Book").
+		msg(2, protoFieldDesc("name", 1, optional, protoTypeString, "")).
+		msg(2, protoFieldDesc("page_count", 2, optional, protoTypeInt32, "")).
+		msg(2, protoFieldDesc("isbn", 3, optional, protoTypeUint64, "")).
+		msg(2, protoFieldDesc("tags", 4, repeated, protoTypeString, "")).
+		msg(2, protoFieldDesc("published", 5, optional, protoTypeMessage, ".google.protobuf.Timestamp")).
+		msg(2, protoFieldDesc("genre", 6, optional, protoTypeEnum, ".acme.library.v1.Genre")).
+		msg(2, protoFieldDesc("sequel", 7, optional, protoTypeMessage, ".acme.library.v1.Book")).
+		msg(2, protoFieldDesc("labels", 8, repeated, protoTypeMessage, ".acme.library.v1.Book.LabelsEntry")).
+		msg(2, protoFieldDesc("url", 9, optional, protoTypeString, "").varint(9, 0)).
+		msg(2, protoFieldDesc("scan", 10, optional, protoTypeBytes, "").varint(9, 0)).
+		msg(2, protoFieldDesc("rating", 11, optional, protoTypeDouble, "").varint(9, 1).varint(17, 1)).
+		msg(2, protoFieldDesc("extra", 12, optional, protoTypeMessage, ".google.protobuf.Struct")).
+		msg(3, protoBuilder{}.str(1, "LabelsEntry").
+			msg(2, protoFieldDesc("key", 1, optional, protoTypeString, "")).
+			msg(2, protoFieldDesc("value", 2, optional, protoTypeString, "")).
+			msg(7, protoBuilder{}.varint(7, 1))).
+		msg(8, protoBuilder{}.str(1, "source")).
+		msg(8, protoBuilder{}.str(1, "_rating"))
+	getBook := protoBuilder{}.str(1, "GetBookRequest").
+		msg(2, protoFieldDesc("name", 1, required, protoTypeString, ""))
+	listBooks := protoBuilder{}.str(1, "ListBooksResponse").
+		msg(2, protoFieldDesc("books", 1, repeated, protoTypeMessage, ".acme.library.v1.Book")).
+		msg(2, protoFieldDesc("next_page_token", 2, optional, protoTypeString, ""))
+	genre := protoBuilder{}.str(1, "Genre").
+		msg(2, protoBuilder{}.str(1, "GENRE_UNSPECIFIED").varint(2, 0)).
+		msg(2, protoBuilder{}.str(1, "FICTION").varint(2, 1))
+
+	library := protoBuilder{}.str(1, "Library").
+		msg(2, protoBuilder{}.str(1, "GetBook").str(2, ".acme.library.v1.GetBookRequest").str(3, ".acme.library.v1.Book").
+			msg(4, protoBuilder{}.varint(34, protoNoSideEffects))).
+		msg(2, protoBuilder{}.str(1, "ListBooks").str(2, ".google.protobuf.Empty").str(3, ".acme.library.v1.ListBooksResponse").
+			msg(4, protoBuilder{}.varint(33, 1)).varint(6, 1))
+	admin := protoBuilder{}.str(1, "Admin").
+		msg(2, protoBuilder{}.str(1, "Reset").str(2, ".google.protobuf.Empty").str(3, ".google.protobuf.Empty").
+			msg(4, protoBuilder{}.varint(34, protoIdempotent))).
+		msg(3, protoBuilder{}.varint(33, 1))
+
+	info := protoBuilder{}.
+		msg(1, protoLocation(" A book in the catalog.\n", 4, 0)).
+		msg(1, protoLocation(" Resource name, e.g. \"books/1\".\n", 4, 0, 2, 0)).
+		msg(1, protoLocation(" Book genres.\n", 5, 0)).
+		msg(1, protoLocation(" Gets a book.\n\n Returns NOT_FOUND if there is none.\n", 6, 0, 2, 0)).
+		msg(1, protoLocation(" Administrative tasks.\n", 6, 1))
+
+	file := protoBuilder{}.str(1, "acme/library/v1/library.proto").str(2, "acme.library.v1").
+		msg(4, book).msg(4, getBook).msg(4, listBooks).msg(5, genre).
+		msg(6, library).msg(6, admin).msg(9, info).str(12, "proto3")
+	return protoBuilder{}.msg(1, file)
+}
+
+func TestImportFileDescriptorSet(t *testing.T) {
+	tools, err := ImportFileDescriptorSet(libraryDescriptorSet())
+	if err != nil {
+		t.Fatalf("ImportFileDescriptorSet() error = %v", err)
+	}
+
+	var ids []string
+	for _, tool := range tools {
+		ids = append(ids, tool.ID())
+		if err := tool.Validate(); err != nil {
+			t.Errorf("%s: Validate() error = %v", tool.Name, err)
+		}
+		if tool.SourceFormat != "protobuf" {
+			t.Errorf("%s: SourceFormat = %q, want protobuf", tool.Name, tool.SourceFormat)
+		}
+	}
+	want := []string{"acme.library.v1:Library_GetBook", "acme.library.v1:Library_ListBooks", "acme.library.v1:Admin_Reset"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("IDs = %v, want %v", ids, want)
+	}
+
+	get := tools[0]
+	if get.Description != "Gets a book.\n\nReturns NOT_FOUND if there is none." {
+		t.Errorf("Description = %q", get.Description)
+	}
+	if get.Annotations == nil || !get.Annotations.ReadOnlyHint {
+		t.Errorf("Annotations = %+v, want read-only", get.Annotations)
+	}
+	if got := get.InputSchema; got.Type != "object" || !reflect.DeepEqual(got.Required, []string{"name"}) {
+		t.Errorf("InputSchema = %+v, want the request with name required", got)
+	}
+	wantGRPC := map[string]any{"service": "acme.library.v1.Library", "method": "GetBook", "path": "/acme.library.v1.Library/GetBook"}
+	if got := get.SourceMeta["grpc"]; !reflect.DeepEqual(got, wantGRPC) {
+		t.Errorf("grpc = %v, want %v", got, wantGRPC)
+	}
+
+	list := tools[1]
+	if list.Annotations == nil || !list.Annotations.Deprecated {
+		t.Error("ListBooks should be deprecated")
+	}
+	if got := list.SourceMeta["grpc"].(map[string]any)["serverStreaming"]; got != true {
+		t.Errorf("serverStreaming = %v, want true", got)
+	}
+	if got := list.InputSchema; got.Type != "object" || got.AdditionalProperties == nil || *got.AdditionalProperties {
+		t.Errorf("Empty request = %+v, want a closed object", got)
+	}
+	books := list.OutputSchema.Properties["books"]
+	if books.Type != "array" || books.Items.Ref != "#/$defs/acme.library.v1.Book" {
+		t.Errorf("books = %+v, want an array of Book references", books)
+	}
+	if list.OutputSchema.Properties["nextPageToken"] == nil {
+		t.Error("fields should be named by their JSON name")
+	}
+
+	reset := tools[2]
+	if reset.Description != "Administrative tasks." {
+		t.Errorf("Description = %q, want the service comment", reset.Description)
+	}
+	if want := (&tooladapter.ToolAnnotations{IdempotentHint: true, Deprecated: true}); !reflect.DeepEqual(reset.Annotations, want) {
+		t.Errorf("Annotations = %+v, want idempotent and deprecated, as a method of a deprecated service", reset.Annotations)
+	}
+}
+
+func TestImportFileDescriptorSet_JSONMapping(t *testing.T) {
+	tools, err := ImportFileDescriptorSet(libraryDescriptorSet())
+	if err != nil {
+		t.Fatalf("ImportFileDescriptorSet() error = %v", err)
+	}
+	book := tools[0].OutputSchema
+	if book.Description != "A book in the catalog." {
+		t.Errorf("Description = %q", book.Description)
+	}
+	minInt, maxInt := float64(-1<<31), float64(1<<31-1)
+
+	tests := []struct {
+		prop string
+		want *tooladapter.JSONSchema
+	}{
+		{"name", &tooladapter.JSONSchema{Type: "string", Description: `Resource name, e.g. "books/1".`}},
+		{"pageCount", &tooladapter.JSONSchema{Type: "integer", Minimum: &minInt, Maximum: &maxInt}},
+		{"isbn", &tooladapter.JSONSchema{Type: "string", Pattern: `^[0-9]+$`}},
+		{"tags", &tooladapter.JSONSchema{Type: "array", Items: &tooladapter.JSONSchema{Type: "string"}}},
+		{"published", &tooladapter.JSONSchema{Type: "string", Format: "date-time"}},
+		{"genre", &tooladapter.JSONSchema{Ref: "#/$defs/acme.library.v1.Genre"}},
+		{"sequel", &tooladapter.JSONSchema{Ref: "#/$defs/acme.library.v1.Book"}},
+		{"labels", &tooladapter.JSONSchema{Type: "object", Extra: map[string]any{"additionalProperties": map[string]any{"type": "string"}}}},
+		{"scan", &tooladapter.JSONSchema{Type: "string", Format: "byte"}},
+		{"rating", &tooladapter.JSONSchema{Type: "number"}},
+		{"extra", &tooladapter.JSONSchema{Type: "object"}},
+	}
+	for _, tt := range tests {
+		if got := book.Properties[tt.prop]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.prop, got, tt.want)
+		}
+	}
+
+	genre := book.Defs["acme.library.v1.Genre"]
+	if genre == nil || genre.Description != "Book genres." || !reflect.DeepEqual(genre.Enum, []any{"GENRE_UNSPECIFIED", "FICTION"}) {
+		t.Errorf("Genre = %+v", genre)
+	}
+	// The recursive reference is kept in $defs
+	if def := book.Defs["acme.library.v1.Book"]; def == nil || def.Properties["sequel"].Ref != "#/$defs/acme.library.v1.Book" {
+		t.Errorf("Book def = %+v", def)
+	}
+
+	// The source oneof allows url, scan or neither; the synthetic _rating
+	// oneof of a proto3 optional field adds nothing
+	if len(book.OneOf) != 3 || len(book.AllOf) != 0 {
+		t.Fatalf("oneOf = %+v, want url, scan and neither", book.OneOf)
+	}
+	for _, tt := range []struct {
+		value map[string]any
+		valid bool
+	}{
+		{map[string]any{"name": "books/1"}, true},
+		{map[string]any{"url": "https://example.com"}, true},
+		{map[string]any{"scan": "AAEC"}, true},
+		{map[string]any{"url": "https://example.com", "scan": "AAEC"}, false},
+		{map[string]any{"isbn": 9780262510875.0}, false},
+		{map[string]any{"pageCount": 1 << 31}, false},
+	} {
+		if issues := book.ValidateValue(tt.value); (len(issues) == 0) != tt.valid {
+			t.Errorf("ValidateValue(%v) = %v, want valid %v", tt.value, issues, tt.valid)
+		}
+	}
+}
+
+func TestImportFileDescriptorSet_Maps(t *testing.T) {
+	const optional, repeated = 1, 3
+	entry := func(name string, typ uint64, typeName string) protoBuilder {
+		return protoBuilder{}.str(1, name).
+			msg(2, protoFieldDesc("key", 1, optional, protoTypeString, "")).
+			msg(2, protoFieldDesc("value", 2, optional, typ, typeName)).
+			msg(7, protoBuilder{}.varint(7, 1))
+	}
+	item := protoBuilder{}.str(1, "Item").
+		msg(2, protoFieldDesc("sku", 1, optional, protoTypeString, ""))
+	stock := protoBuilder{}.str(1, "Stock").
+		msg(2, protoFieldDesc("counts", 1, repeated, protoTypeMessage, ".shop.Stock.CountsEntry")).
+		msg(2, protoFieldDesc("items", 2, repeated, protoTypeMessage, ".shop.Stock.ItemsEntry")).
+		msg(2, protoFieldDesc("legacy", 3, optional, protoTypeString, "").msg(8, protoBuilder{}.varint(3, 1))).
+		msg(3, entry("CountsEntry", protoTypeUint64, "")).
+		msg(3, entry("ItemsEntry", protoTypeMessage, ".shop.Item"))
+	method := protoBuilder{}.str(1, "Get").str(2, ".shop.Stock").str(3, ".shop.Stock")
+	file := protoBuilder{}.str(1, "shop.proto").str(2, "shop").
+		msg(4, item).msg(4, stock).msg(6, protoBuilder{}.str(1, "Shop").msg(2, method)).str(12, "proto3")
+
+	tools, err := ImportFileDescriptorSet(protoBuilder{}.msg(1, file))
+	if err != nil {
+		t.Fatalf("ImportFileDescriptorSet() error = %v", err)
+	}
+	input := tools[0].InputSchema
+
+	counts := input.Properties["counts"]
+	if want := map[string]any{"type": "string", "pattern": `^[0-9]+$`}; counts.Type != "object" || !reflect.DeepEqual(counts.Extra["additionalProperties"], want) {
+		t.Errorf("counts = %+v, want an object of uint64 strings", counts)
+	}
+	items := input.Properties["items"]
+	if want := map[string]any{"$ref": "#/$defs/shop.Item"}; !reflect.DeepEqual(items.Extra["additionalProperties"], want) {
+		t.Errorf("items = %+v, want an object of Item references", items)
+	}
+	if input.Defs["shop.Item"] == nil || input.Defs["shop.Stock.ItemsEntry"] != nil {
+		t.Errorf("$defs = %v, want Item without the map entry", input.Defs)
+	}
+	if got := input.Properties["legacy"].Extra["deprecated"]; got != true {
+		t.Errorf("legacy deprecated = %v, want true", got)
+	}
+
+	// The value schemas are emitted, and converted as keywords an MCP
+	// schema keeps
+	out, err := NewMCPAdapter().FromCanonical(tools[0])
+	if err != nil {
+		t.Fatalf("FromCanonical() error = %v", err)
+	}
+	back, err := NewMCPAdapter().ToCanonical(out)
+	if err != nil {
+		t.Fatalf("ToCanonical() error = %v", err)
+	}
+	for _, d := range tooladapter.DiffCanonical(tools[0], back) {
+		if strings.HasPrefix(d.Path, "/inputSchema") || strings.HasPrefix(d.Path, "/outputSchema") {
+			t.Errorf("MCP round trip diff %v, want schemas unchanged", d)
+		}
+	}
+}
+
+func TestImportFileDescriptorSet_MultipleOneofs(t *testing.T) {
+	msg := protoBuilder{}.str(1, "Pick").
+		msg(2, protoFieldDesc("a", 1, 1, protoTypeBool, "").varint(9, 0)).
+		msg(2, protoFieldDesc("b", 2, 1, protoTypeBool, "").varint(9, 1)).
+		msg(8, protoBuilder{}.str(1, "first")).
+		msg(8, protoBuilder{}.str(1, "second"))
+	method := protoBuilder{}.str(1, "Do").str(2, ".Pick").str(3, ".Pick")
+	file := protoBuilder{}.str(1, "pick.proto").msg(4, msg).msg(6, protoBuilder{}.str(1, "Svc").msg(2, method))
+
+	tools, err := ImportFileDescriptorSet(protoBuilder{}.msg(1, file))
+	if err != nil {
+		t.Fatalf("ImportFileDescriptorSet() error = %v", err)
+	}
+	tool := tools[0]
+	if tool.Namespace != "" || tool.Name != "Svc_Do" {
+		t.Errorf("ID = %q, want Svc_Do without a namespace", tool.ID())
+	}
+	if got := tool.SourceMeta["grpc"].(map[string]any)["path"]; got != "/Svc/Do" {
+		t.Errorf("path = %v, want /Svc/Do", got)
+	}
+	if got := tool.InputSchema; len(got.AllOf) != 2 || len(got.OneOf) != 0 {
+		t.Errorf("InputSchema = %+v, want one allOf constraint per oneof", got)
+	}
+}
+
+func TestImportFileDescriptorSet_Errors(t *testing.T) {
+	method := protoBuilder{}.str(1, "Get").str(2, ".pkg.Missing").str(3, ".google.protobuf.Empty")
+	file := protoBuilder{}.str(1, "a.proto").str(2, "pkg").msg(6, protoBuilder{}.str(1, "Svc").msg(2, method))
+	_, err := ImportFileDescriptorSet(protoBuilder{}.msg(1, file))
+	if err == nil || !strings.Contains(err.Error(), `pkg.Svc.Get: request: undefined message "pkg.Missing"`) {
+		t.Errorf("error = %v, want an undefined request message", err)
+	}
+
+	msg := protoBuilder{}.str(1, "M").msg(2, protoFieldDesc("e", 1, 1, protoTypeEnum, ".pkg.Nope"))
+	method = protoBuilder{}.str(1, "Get").str(2, ".pkg.M").str(3, ".pkg.M")
+	file = protoBuilder{}.str(1, "a.proto").str(2, "pkg").msg(4, msg).msg(6, protoBuilder{}.str(1, "Svc").msg(2, method))
+	_, err = ImportFileDescriptorSet(protoBuilder{}.msg(1, file))
+	if err == nil || !strings.Contains(err.Error(), `pkg.M.e: undefined enum "pkg.Nope"`) {
+		t.Errorf("error = %v, want an undefined enum", err)
+	}
+
+	if _, err := ImportFileDescriptorSet([]byte{0x0a, 0x05}); err == nil || !strings.Contains(err.Error(), "decode FileDescriptorSet") {
+		t.Errorf("error = %v, want a decode error", err)
+	}
+	// A message without a name
+	if _, err := ImportFileDescriptorSet([]byte{0x0a, 0x05, 0x22, 0x03, 0x12, 0x01, 0x00}); err == nil || !strings.Contains(err.Error(), "message without a name") {
+		t.Errorf("error = %v, want a nameless message error", err)
+	}
+}
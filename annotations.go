@@ -0,0 +1,129 @@
+package tooladapter
+
+import "strings"
+
+// Annotation labels used by RenderAnnotations, in rendering order.
+const (
+	labelReadOnly       = "read-only"
+	labelDestructive    = "destructive"
+	labelNonDestructive = "non-destructive"
+	labelIdempotent     = "idempotent"
+	labelOpenWorld      = "open-world"
+	labelClosedWorld    = "closed-world"
+	labelDeprecated     = "deprecated"
+)
+
+// titlePrefix starts the leading line RenderAnnotations writes for a title.
+const titlePrefix = "Title: "
+
+// RenderAnnotations prefixes a description with the tool's title and
+// behavioral hints for formats that have no annotations field:
+//
+//	Title: Cached lookup
+//	[read-only, closed-world] Look up a cached record.
+//
+// Only the title and hints that are set are rendered; line breaks in the
+// title become spaces. Returns the description unchanged if neither is set.
+func RenderAnnotations(description string, a *ToolAnnotations) string {
+	if a == nil {
+		return description
+	}
+	if a.Title != "" {
+		title := titlePrefix + strings.ReplaceAll(a.Title, "\n", " ")
+		hints := RenderAnnotations(description, &ToolAnnotations{
+			ReadOnlyHint:    a.ReadOnlyHint,
+			DestructiveHint: a.DestructiveHint,
+			IdempotentHint:  a.IdempotentHint,
+			OpenWorldHint:   a.OpenWorldHint,
+			Deprecated:      a.Deprecated,
+		})
+		if hints == "" {
+			return title
+		}
+		return title + "\n" + hints
+	}
+
+	var labels []string
+	if a.ReadOnlyHint {
+		labels = append(labels, labelReadOnly)
+	}
+	if a.DestructiveHint != nil {
+		if *a.DestructiveHint {
+			labels = append(labels, labelDestructive)
+		} else {
+			labels = append(labels, labelNonDestructive)
+		}
+	}
+	if a.IdempotentHint {
+		labels = append(labels, labelIdempotent)
+	}
+	if a.OpenWorldHint != nil {
+		if *a.OpenWorldHint {
+			labels = append(labels, labelOpenWorld)
+		} else {
+			labels = append(labels, labelClosedWorld)
+		}
+	}
+	if a.Deprecated {
+		labels = append(labels, labelDeprecated)
+	}
+
+	if len(labels) == 0 {
+		return description
+	}
+	prefix := "[" + strings.Join(labels, ", ") + "]"
+	if description == "" {
+		return prefix
+	}
+	return prefix + " " + description
+}
+
+// SplitRenderedAnnotations reverses RenderAnnotations. It returns the
+// description without the title line and prefix and the decoded
+// annotations, or the description unchanged and nil if it starts with
+// neither a title line nor a prefix made only of known labels.
+func SplitRenderedAnnotations(description string) (string, *ToolAnnotations) {
+	if rest, ok := strings.CutPrefix(description, titlePrefix); ok {
+		title, rest, _ := strings.Cut(rest, "\n")
+		rest, a := SplitRenderedAnnotations(rest)
+		if a == nil {
+			a = &ToolAnnotations{}
+		}
+		a.Title = title
+		return rest, a
+	}
+	if !strings.HasPrefix(description, "[") {
+		return description, nil
+	}
+	end := strings.IndexByte(description, ']')
+	if end < 0 {
+		return description, nil
+	}
+
+	a := &ToolAnnotations{}
+	for _, label := range strings.Split(description[1:end], ", ") {
+		switch label {
+		case labelReadOnly:
+			a.ReadOnlyHint = true
+		case labelDestructive:
+			a.DestructiveHint = boolPtr(true)
+		case labelNonDestructive:
+			a.DestructiveHint = boolPtr(false)
+		case labelIdempotent:
+			a.IdempotentHint = true
+		case labelOpenWorld:
+			a.OpenWorldHint = boolPtr(true)
+		case labelClosedWorld:
+			a.OpenWorldHint = boolPtr(false)
+		case labelDeprecated:
+			a.Deprecated = true
+		default:
+			return description, nil
+		}
+	}
+	return strings.TrimPrefix(description[end+1:], " "), a
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,70 @@
+package tooladapter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderAnnotations(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name        string
+		annotations *ToolAnnotations
+		want        string
+	}{
+		{"nil", nil, "Look up a record."},
+		{"title only", &ToolAnnotations{Title: "Lookup"}, "Title: Lookup\nLook up a record."},
+		{"title and hints", &ToolAnnotations{Title: "Cached\nlookup", ReadOnlyHint: true}, "Title: Cached lookup\n[read-only] Look up a record."},
+		{"read-only closed", &ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: &no}, "[read-only, closed-world] Look up a record."},
+		{"all", &ToolAnnotations{DestructiveHint: &yes, IdempotentHint: true, OpenWorldHint: &yes}, "[destructive, idempotent, open-world] Look up a record."},
+		{"non-destructive", &ToolAnnotations{DestructiveHint: &no}, "[non-destructive] Look up a record."},
+		{"deprecated", &ToolAnnotations{ReadOnlyHint: true, Deprecated: true}, "[read-only, deprecated] Look up a record."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderAnnotations("Look up a record.", tt.annotations); got != tt.want {
+				t.Errorf("RenderAnnotations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := RenderAnnotations("", &ToolAnnotations{ReadOnlyHint: true}); got != "[read-only]" {
+		t.Errorf("RenderAnnotations(empty description) = %q, want %q", got, "[read-only]")
+	}
+}
+
+func TestSplitRenderedAnnotations(t *testing.T) {
+	yes, no := true, false
+	original := &ToolAnnotations{Title: "Lookup", ReadOnlyHint: true, DestructiveHint: &no, IdempotentHint: true, OpenWorldHint: &yes, Deprecated: true}
+
+	desc, got := SplitRenderedAnnotations(RenderAnnotations("Look up a record.", original))
+
+	if desc != "Look up a record." {
+		t.Errorf("description = %q, want %q", desc, "Look up a record.")
+	}
+	if !reflect.DeepEqual(got, original) {
+		t.Errorf("annotations = %+v, want %+v", got, original)
+	}
+}
+
+func TestSplitRenderedAnnotations_NotRendered(t *testing.T) {
+	for _, input := range []string{"Plain.", "[beta] New tool.", "[read-only", "[read-only, beta] Mixed."} {
+		desc, got := SplitRenderedAnnotations(input)
+		if desc != input || got != nil {
+			t.Errorf("SplitRenderedAnnotations(%q) = %q, %+v; want input unchanged", input, desc, got)
+		}
+	}
+}
+
+func TestSplitRenderedAnnotations_TitleOnly(t *testing.T) {
+	desc, got := SplitRenderedAnnotations(RenderAnnotations("Look up a record.", &ToolAnnotations{Title: "Lookup"}))
+
+	if desc != "Look up a record." {
+		t.Errorf("description = %q, want %q", desc, "Look up a record.")
+	}
+	if want := (&ToolAnnotations{Title: "Lookup"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("annotations = %+v, want %+v", got, want)
+	}
+}
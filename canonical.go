@@ -2,13 +2,93 @@ package tooladapter
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
+// ToolKind classifies how a tool receives its input and who executes it.
+type ToolKind string
+
+const (
+	// ToolKindFunction is a tool whose arguments are described by InputSchema.
+	// It is the default when Kind is empty.
+	ToolKindFunction ToolKind = "function"
+
+	// ToolKindCustom is a tool that takes free-form text input, optionally
+	// constrained by a grammar (see CustomToolConfig)
+	ToolKindCustom ToolKind = "custom"
+
+	// ToolKindHosted is a built-in tool defined by the provider, such as
+	// web search, a code interpreter or Anthropic's bash tool
+	// (see HostedToolConfig)
+	ToolKindHosted ToolKind = "hosted"
+)
+
+// CustomToolConfig configures a ToolKindCustom tool.
+type CustomToolConfig struct {
+	// Syntax is the grammar syntax (e.g., "lark", "regex").
+	// Empty means the input is unconstrained text.
+	Syntax string
+
+	// Definition is the grammar source in the given syntax
+	Definition string
+}
+
+// HostedToolConfig configures a ToolKindHosted tool.
+type HostedToolConfig struct {
+	// Provider is the adapter that defines the tool (e.g., "openai").
+	// Hosted tools are provider-specific and cannot move between providers.
+	Provider string
+
+	// Type is the provider's tool type (e.g., "web_search", "code_interpreter")
+	Type string
+
+	// Options holds provider-specific settings (e.g., "vector_store_ids")
+	Options map[string]any
+}
+
+// ToolAnnotations are behavioral hints about a tool, mirroring MCP tool
+// annotations. They are advisory and drive approval UX; clients must not
+// rely on them for security decisions about untrusted servers.
+type ToolAnnotations struct {
+	// Title is a human-readable title for the tool
+	Title string
+
+	// ReadOnlyHint indicates the tool does not modify its environment
+	ReadOnlyHint bool
+
+	// DestructiveHint indicates the tool may perform destructive updates
+	// (meaningful only when ReadOnlyHint is false; nil means the MCP
+	// default of true)
+	DestructiveHint *bool
+
+	// IdempotentHint indicates repeated calls with the same arguments have
+	// no additional effect (meaningful only when ReadOnlyHint is false)
+	IdempotentHint bool
+
+	// OpenWorldHint indicates the tool interacts with external entities
+	// (nil means the MCP default of true)
+	OpenWorldHint *bool
+
+	// Deprecated indicates the source marks the tool as deprecated (e.g.,
+	// an OpenAPI operation or a GraphQL @deprecated field). MCP has no such
+	// annotation; the MCP adapter carries it under MCPMetaKey.
+	Deprecated bool
+}
+
 // CanonicalTool is the protocol-agnostic representation of a tool definition.
 // It serves as the intermediate format for converting between MCP, OpenAI,
 // and Anthropic tool formats.
 type CanonicalTool struct {
+	// Kind is the tool kind; empty means ToolKindFunction
+	Kind ToolKind
+
+	// Custom configures ToolKindCustom tools
+	Custom *CustomToolConfig
+
+	// Hosted configures ToolKindHosted tools
+	Hosted *HostedToolConfig
+
 	// Namespace groups related tools (e.g., "github", "slack")
 	Namespace string
 
@@ -27,12 +107,20 @@ type CanonicalTool struct {
 	// Tags are keywords for discovery
 	Tags []string
 
-	// InputSchema defines the tool's input parameters (required)
+	// InputSchema defines the tool's input parameters
+	// (required for ToolKindFunction)
 	InputSchema *JSONSchema
 
+	// Examples are example argument objects; each must validate against
+	// InputSchema
+	Examples []map[string]any
+
 	// OutputSchema defines the tool's output format
 	OutputSchema *JSONSchema
 
+	// Annotations are behavioral hints such as read-only or destructive
+	Annotations *ToolAnnotations
+
 	// Timeout is the maximum execution time
 	Timeout time.Duration
 
@@ -56,17 +144,53 @@ func (t *CanonicalTool) ID() string {
 }
 
 // Validate checks that the tool has all required fields.
-// Returns an error if Name or InputSchema is missing.
+// Returns an error if Name is missing, if a function tool has no
+// InputSchema, if a hosted tool has no HostedToolConfig type, or if an
+// example does not validate against InputSchema.
 func (t *CanonicalTool) Validate() error {
 	if t.Name == "" {
 		return errors.New("tool name is required")
 	}
+	switch t.Kind {
+	case "", ToolKindFunction:
+		if t.InputSchema == nil {
+			return errors.New("tool input schema is required")
+		}
+	case ToolKindCustom:
+	case ToolKindHosted:
+		if t.Hosted == nil || t.Hosted.Type == "" {
+			return errors.New("hosted tool type is required")
+		}
+	default:
+		return errors.New("unknown tool kind: " + string(t.Kind))
+	}
+	return t.ValidateExamples()
+}
+
+// ValidateExamples checks every example against the input schema.
+// Returns an error for the first invalid example, or if examples are set
+// without an input schema.
+func (t *CanonicalTool) ValidateExamples() error {
+	if len(t.Examples) == 0 {
+		return nil
+	}
 	if t.InputSchema == nil {
-		return errors.New("tool input schema is required")
+		return errors.New("tool examples require an input schema")
+	}
+	for i, example := range t.Examples {
+		if issues := t.InputSchema.ValidateValue(example); len(issues) > 0 {
+			return fmt.Errorf("example %d: %s", i, issues[0])
+		}
 	}
 	return nil
 }
 
+// IsFunction reports whether the tool is a schema-described function tool,
+// which every adapter can represent.
+func (t *CanonicalTool) IsFunction() bool {
+	return t.Kind == "" || t.Kind == ToolKindFunction
+}
+
 // JSONSchema represents a JSON Schema definition.
 // It is a superset supporting features from MCP, OpenAI, and Anthropic formats.
 type JSONSchema struct {
@@ -82,6 +206,12 @@ type JSONSchema struct {
 	// Items is the schema for array elements
 	Items *JSONSchema
 
+	// MinItems is the minimum array length
+	MinItems *int
+
+	// MaxItems is the maximum array length
+	MaxItems *int
+
 	// Description explains the schema
 	Description string
 
@@ -132,6 +262,12 @@ type JSONSchema struct {
 
 	// Not disallows the specified schema
 	Not *JSONSchema
+
+	// Extra holds keywords this model has no field for (e.g., title,
+	// examples, exclusiveMinimum), by keyword, so that they survive
+	// conversions between formats that accept any JSON Schema. Values are
+	// kept as decoded and are not interpreted.
+	Extra map[string]any
 }
 
 // DeepCopy creates a deep copy of the JSONSchema.
@@ -168,6 +304,14 @@ func (s *JSONSchema) DeepCopy() *JSONSchema {
 		v := *s.MaxLength
 		copied.MaxLength = &v
 	}
+	if s.MinItems != nil {
+		v := *s.MinItems
+		copied.MinItems = &v
+	}
+	if s.MaxItems != nil {
+		v := *s.MaxItems
+		copied.MaxItems = &v
+	}
 	if s.AdditionalProperties != nil {
 		v := *s.AdditionalProperties
 		copied.AdditionalProperties = &v
@@ -225,9 +369,37 @@ func (s *JSONSchema) DeepCopy() *JSONSchema {
 	// Deep copy Not
 	copied.Not = s.Not.DeepCopy()
 
+	if s.Extra != nil {
+		copied.Extra = make(map[string]any, len(s.Extra))
+		for k, v := range s.Extra {
+			copied.Extra[k] = copyAny(v)
+		}
+	}
+
 	return copied
 }
 
+// copyAny deep-copies a decoded JSON value, so nested objects and arrays
+// in a copy can be modified without affecting the original.
+func copyAny(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for k, item := range v {
+			copied[k] = copyAny(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = copyAny(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
 // ToMap converts the JSONSchema to a map[string]any representation.
 // Zero-valued fields are omitted from the output.
 func (s *JSONSchema) ToMap() map[string]any {
@@ -275,6 +447,12 @@ func (s *JSONSchema) ToMap() map[string]any {
 	if s.MaxLength != nil {
 		m["maxLength"] = *s.MaxLength
 	}
+	if s.MinItems != nil {
+		m["minItems"] = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		m["maxItems"] = *s.MaxItems
+	}
 	if s.AdditionalProperties != nil {
 		m["additionalProperties"] = *s.AdditionalProperties
 	}
@@ -336,5 +514,12 @@ func (s *JSONSchema) ToMap() map[string]any {
 		m["not"] = s.Not.ToMap()
 	}
 
+	// Extra keywords never replace modelled ones
+	for k, v := range s.Extra {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+
 	return m
 }
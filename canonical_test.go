@@ -71,6 +71,74 @@ func TestCanonicalTool_Validate_MissingInputSchema(t *testing.T) {
 	}
 }
 
+func TestCanonicalTool_Validate_Kinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    CanonicalTool
+		wantErr bool
+	}{
+		{"custom without schema", CanonicalTool{Name: "t", Kind: ToolKindCustom}, false},
+		{"custom with grammar", CanonicalTool{Name: "t", Kind: ToolKindCustom, Custom: &CustomToolConfig{Syntax: "lark", Definition: "start: /a+/"}}, false},
+		{"hosted", CanonicalTool{Name: "t", Kind: ToolKindHosted, Hosted: &HostedToolConfig{Type: "web_search"}}, false},
+		{"hosted without type", CanonicalTool{Name: "t", Kind: ToolKindHosted, Hosted: &HostedToolConfig{}}, true},
+		{"hosted without config", CanonicalTool{Name: "t", Kind: ToolKindHosted}, true},
+		{"explicit function without schema", CanonicalTool{Name: "t", Kind: ToolKindFunction}, true},
+		{"unknown kind", CanonicalTool{Name: "t", Kind: "mystery", InputSchema: &JSONSchema{Type: "object"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tool.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanonicalTool_Validate_Examples(t *testing.T) {
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: map[string]*JSONSchema{"city": {Type: "string"}},
+		Required:   []string{"city"},
+	}
+
+	valid := &CanonicalTool{Name: "t", InputSchema: schema, Examples: []map[string]any{{"city": "Paris"}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := &CanonicalTool{Name: "t", InputSchema: schema, Examples: []map[string]any{{"city": "Paris"}, {"city": 3}}}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want error for invalid example")
+	}
+	if want := "example 1: type at /city: expected string, got number"; err.Error() != want {
+		t.Errorf("Validate() error = %q, want %q", err, want)
+	}
+
+	noSchema := &CanonicalTool{Name: "t", Kind: ToolKindCustom, Examples: []map[string]any{{"city": "Paris"}}}
+	if err := noSchema.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for examples without input schema")
+	}
+}
+
+func TestCanonicalTool_IsFunction(t *testing.T) {
+	tests := map[ToolKind]bool{
+		"":               true,
+		ToolKindFunction: true,
+		ToolKindCustom:   false,
+		ToolKindHosted:   false,
+	}
+
+	for kind, want := range tests {
+		tool := &CanonicalTool{Kind: kind}
+		if got := tool.IsFunction(); got != want {
+			t.Errorf("IsFunction() with kind %q = %v, want %v", kind, got, want)
+		}
+	}
+}
+
 func TestJSONSchema_DeepCopy_Nil(t *testing.T) {
 	var s *JSONSchema
 	got := s.DeepCopy()
@@ -160,11 +228,16 @@ func TestJSONSchema_DeepCopy_NestedProperties(t *testing.T) {
 }
 
 func TestJSONSchema_DeepCopy_Items(t *testing.T) {
+	minItems := 1
+	maxItems := 5
+
 	original := &JSONSchema{
 		Type: "array",
 		Items: &JSONSchema{
 			Type: "string",
 		},
+		MinItems: &minItems,
+		MaxItems: &maxItems,
 	}
 
 	copied := original.DeepCopy()
@@ -175,6 +248,43 @@ func TestJSONSchema_DeepCopy_Items(t *testing.T) {
 	if copied.Items.Type != original.Items.Type {
 		t.Errorf("Items.Type = %q, want %q", copied.Items.Type, original.Items.Type)
 	}
+	if copied.MinItems == original.MinItems || *copied.MinItems != minItems || *copied.MaxItems != maxItems {
+		t.Errorf("MinItems, MaxItems = %v, %v, want unaliased %d and %d", copied.MinItems, copied.MaxItems, minItems, maxItems)
+	}
+}
+
+func TestJSONSchema_ToMap_ArrayBounds(t *testing.T) {
+	minItems := 1
+	maxItems := 5
+
+	got := (&JSONSchema{Type: "array", MinItems: &minItems, MaxItems: &maxItems}).ToMap()
+
+	if got["minItems"] != minItems || got["maxItems"] != maxItems {
+		t.Errorf("minItems, maxItems = %v, %v, want %d and %d", got["minItems"], got["maxItems"], minItems, maxItems)
+	}
+}
+
+func TestJSONSchema_Extra(t *testing.T) {
+	schema := &JSONSchema{
+		Type:  "string",
+		Extra: map[string]any{"title": "City", "type": "integer", "examples": []any{"Paris"}},
+	}
+
+	got := schema.ToMap()
+	want := map[string]any{"type": "string", "title": "City", "examples": []any{"Paris"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v (modelled keywords win)", got, want)
+	}
+
+	copied := schema.DeepCopy()
+	copied.Extra["title"] = "Town"
+	if schema.Extra["title"] != "City" {
+		t.Error("DeepCopy() shares the Extra map")
+	}
+	copied.Extra["examples"].([]any)[0] = "Tokyo"
+	if schema.Extra["examples"].([]any)[0] != "Paris" {
+		t.Error("DeepCopy() shares nested Extra values")
+	}
 }
 
 func TestJSONSchema_DeepCopy_Defs(t *testing.T) {
@@ -1,6 +1,16 @@
 // Package tooladapter provides protocol-agnostic tool format conversion.
-// It enables bidirectional transformation between MCP, OpenAI, and Anthropic
-// tool definitions through a canonical intermediate representation.
+// It enables bidirectional transformation between MCP, OpenAI, Anthropic,
+// Bedrock, Cohere and Gemini tool definitions, and tools rendered into
+// prompts, through a canonical intermediate representation.
 //
-// This is a pure data-transform library with no I/O, network, or runtime execution.
+// Conversion is a pure data transform with no I/O, network, or runtime
+// execution. The MCP bridge in the adapters package is the exception, and
+// all of its I/O goes through an MCP session the caller connects:
+//
+//   - MCPServer serves canonical tools over an MCP transport and runs the
+//     handlers registered for them, eliciting missing arguments from the
+//     client when enabled.
+//   - ImportMCPTools lists the tools of a live client session, and
+//     MCPToolWatcher re-lists them whenever the server reports that its
+//     tool list changed.
 package tooladapter
@@ -0,0 +1,58 @@
+package tooladapter
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// examplesHeading introduces the examples block appended by RenderExamples.
+const examplesHeading = "\n\nExamples:\n"
+
+// RenderExamples appends tool examples to a description for formats that
+// have no native examples field. Each example is written as one line of
+// compact JSON with sorted keys:
+//
+//	Get the weather.
+//
+//	Examples:
+//	{"city":"Paris"}
+//	{"city":"Tokyo","units":"metric"}
+//
+// Examples that cannot be encoded are skipped. Returns the description
+// unchanged if there are no examples.
+func RenderExamples(description string, examples []map[string]any) string {
+	lines := make([]string, 0, len(examples))
+	for _, example := range examples {
+		data, err := json.Marshal(example)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, string(data))
+	}
+	if len(lines) == 0 {
+		return description
+	}
+	return description + examplesHeading + strings.Join(lines, "\n")
+}
+
+// SplitRenderedExamples reverses RenderExamples. It returns the description
+// without the examples block and the decoded examples, or the description
+// unchanged and nil if it does not end with a block in which every line is
+// a JSON object.
+func SplitRenderedExamples(description string) (string, []map[string]any) {
+	i := strings.LastIndex(description, examplesHeading)
+	if i < 0 {
+		return description, nil
+	}
+
+	lines := strings.Split(description[i+len(examplesHeading):], "\n")
+	examples := make([]map[string]any, 0, len(lines))
+	for _, line := range lines {
+		var example map[string]any
+		if err := json.Unmarshal([]byte(line), &example); err != nil || example == nil {
+			return description, nil
+		}
+		examples = append(examples, example)
+	}
+	return description[:i], examples
+}
@@ -0,0 +1,52 @@
+package tooladapter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderExamples(t *testing.T) {
+	examples := []map[string]any{
+		{"city": "Paris"},
+		{"units": "metric", "city": "Tokyo"},
+	}
+
+	got := RenderExamples("Get the weather.", examples)
+
+	want := "Get the weather.\n\nExamples:\n{\"city\":\"Paris\"}\n{\"city\":\"Tokyo\",\"units\":\"metric\"}"
+	if got != want {
+		t.Errorf("RenderExamples() = %q, want %q", got, want)
+	}
+	if got := RenderExamples("Plain.", nil); got != "Plain." {
+		t.Errorf("RenderExamples(no examples) = %q, want %q", got, "Plain.")
+	}
+}
+
+func TestSplitRenderedExamples(t *testing.T) {
+	examples := []map[string]any{{"city": "Paris"}, {"count": 2.0}}
+	rendered := RenderExamples("Get the weather.", examples)
+
+	desc, got := SplitRenderedExamples(rendered)
+
+	if desc != "Get the weather." {
+		t.Errorf("description = %q, want %q", desc, "Get the weather.")
+	}
+	if !reflect.DeepEqual(got, examples) {
+		t.Errorf("examples = %v, want %v", got, examples)
+	}
+}
+
+func TestSplitRenderedExamples_NotRendered(t *testing.T) {
+	tests := []string{
+		"Plain description.",
+		"Intro.\n\nExamples:\nask for the weather in Paris",
+		"Intro.\n\nExamples:\n[1,2]",
+	}
+
+	for _, input := range tests {
+		desc, examples := SplitRenderedExamples(input)
+		if desc != input || examples != nil {
+			t.Errorf("SplitRenderedExamples(%q) = %q, %v; want input unchanged", input, desc, examples)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package tooladapter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MaxToolNameLength is the longest tool name OpenAI and Anthropic accept.
+const MaxToolNameLength = 64
+
+// nameHashLength is the number of hex digits in a shortening hash suffix.
+const nameHashLength = 8
+
+// IsProviderSafeName reports whether a name matches ^[a-zA-Z0-9_-]{1,64}$,
+// the tool name rule shared by OpenAI and Anthropic.
+func IsProviderSafeName(name string) bool {
+	if name == "" || len(name) > MaxToolNameLength {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SanitizeToolName converts a canonical ID (e.g., "github:repos/get") into a
+// provider-safe name. Disallowed characters become underscores, and names
+// longer than MaxToolNameLength are shortened with a hash of the full ID so
+// that distinct long IDs stay distinct.
+//
+// SanitizeToolName is stateless; use a NameMapper to also guarantee that
+// different IDs never share a name and to map names back to IDs.
+func SanitizeToolName(id string) string {
+	var b strings.Builder
+	b.Grow(len(id))
+	for i := 0; i < len(id); i++ {
+		if isNameByte(id[i]) {
+			b.WriteByte(id[i])
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+
+	switch {
+	case name == "":
+		return "tool"
+	case len(name) > MaxToolNameLength:
+		return hashedName(name, id, "")
+	default:
+		return name
+	}
+}
+
+// ToolNameSanitizer is an optional interface for adapters whose format
+// restricts tool names further than IsProviderSafeName, such as Bedrock.
+//
+// AdapterRegistry.Convert with WithNameMapping passes the names it assigns
+// through the target's sanitizer, so that the emitted name is one the
+// target accepts and still routes back to the canonical ID.
+type ToolNameSanitizer interface {
+	// SanitizeToolName converts a provider-safe name into one the format
+	// accepts, of at most MaxToolNameLength characters. Names the format
+	// already accepts must be returned unchanged.
+	SanitizeToolName(name string) string
+}
+
+// NameMapper assigns provider-safe tool names to canonical IDs and keeps the
+// reverse mapping, so that tool calls naming a provider-safe name can be
+// routed back to the canonical tool.
+//
+// Names are stable: an ID keeps the name it was first given. When two IDs
+// sanitize to the same name, the first keeps it and later ones get a hash
+// suffix. NameMapper is safe for concurrent use.
+type NameMapper struct {
+	mu sync.RWMutex
+
+	// forward maps a name rule and canonical ID to the assigned name; the
+	// rule is empty for the provider-safe rule
+	forward map[nameKey]string
+	reverse map[string]mappedTool
+}
+
+// nameKey identifies an assigned name by name rule and canonical ID.
+type nameKey struct {
+	rule string
+	id   string
+}
+
+// mappedTool records the canonical identity behind a provider name.
+type mappedTool struct {
+	namespace string
+	name      string
+}
+
+func (t mappedTool) id() string {
+	if t.namespace == "" {
+		return t.name
+	}
+	return t.namespace + ":" + t.name
+}
+
+// NewNameMapper creates an empty name mapper.
+func NewNameMapper() *NameMapper {
+	return &NameMapper{
+		forward: make(map[nameKey]string),
+		reverse: make(map[string]mappedTool),
+	}
+}
+
+// ProviderName returns the provider-safe name for a tool, assigning one on
+// first use.
+func (m *NameMapper) ProviderName(tool *CanonicalTool) string {
+	return m.assign(tool, "", func(name string) string { return name })
+}
+
+// ProviderNameFor returns the name of a tool under a stricter name rule,
+// assigning one on first use. The rule is identified by its format name
+// (e.g., "bedrock") and applied by sanitize to the provider-safe name; see
+// ToolNameSanitizer. Names assigned under every rule share one reverse
+// mapping, so CanonicalID resolves all of them.
+func (m *NameMapper) ProviderNameFor(tool *CanonicalTool, format string, sanitize func(string) string) string {
+	return m.assign(tool, format, sanitize)
+}
+
+// assign returns the name of a tool under a rule, assigning one on first
+// use. A name already given to the same tool under another rule is shared.
+func (m *NameMapper) assign(tool *CanonicalTool, rule string, sanitize func(string) string) string {
+	id := tool.ID()
+	key := nameKey{rule: rule, id: id}
+
+	m.mu.RLock()
+	name, ok := m.forward[key]
+	m.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name, ok := m.forward[key]; ok {
+		return name
+	}
+	name = sanitize(SanitizeToolName(id))
+	for salt := 0; ; salt++ {
+		if owner, taken := m.reverse[name]; !taken || owner.id() == id {
+			break
+		}
+		name = sanitize(hashedName(SanitizeToolName(id), id, fmt.Sprint(salt)))
+	}
+	m.forward[key] = name
+	m.reverse[name] = mappedTool{namespace: tool.Namespace, name: tool.Name}
+	return name
+}
+
+// CanonicalID returns the canonical ID a provider name was assigned to.
+// Returns false if the name was not produced by this mapper.
+func (m *NameMapper) CanonicalID(name string) (string, bool) {
+	t, ok := m.lookup(name)
+	if !ok {
+		return "", false
+	}
+	return t.id(), true
+}
+
+// Len returns the number of mapped tools. A tool named under several rules
+// counts once.
+func (m *NameMapper) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make(map[string]bool, len(m.forward))
+	for key := range m.forward {
+		ids[key.id] = true
+	}
+	return len(ids)
+}
+
+func (m *NameMapper) lookup(name string) (mappedTool, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.reverse[name]
+	return t, ok
+}
+
+// hashedName shortens a sanitized name to fit MaxToolNameLength with a
+// suffix derived from the full ID and an optional salt.
+func hashedName(sanitized, id, salt string) string {
+	sum := sha256.Sum256([]byte(id + "\x00" + salt))
+	suffix := "_" + hex.EncodeToString(sum[:])[:nameHashLength]
+	if len(sanitized)+len(suffix) > MaxToolNameLength {
+		sanitized = sanitized[:MaxToolNameLength-len(suffix)]
+	}
+	return sanitized + suffix
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
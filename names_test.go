@@ -0,0 +1,195 @@
+package tooladapter
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIsProviderSafeName(t *testing.T) {
+	tests := map[string]bool{
+		"get_weather":           true,
+		"get-weather-2":         true,
+		"":                      false,
+		"github:get_repo":       false,
+		"repos.get":             false,
+		"a/b":                   false,
+		strings.Repeat("a", 64): true,
+		strings.Repeat("a", 65): false,
+		"café":                  false,
+	}
+
+	for name, want := range tests {
+		if got := IsProviderSafeName(name); got != want {
+			t.Errorf("IsProviderSafeName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSanitizeToolName(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"get_weather", "get_weather"},
+		{"github:get_repo", "github_get_repo"},
+		{"fs:files/read.text", "fs_files_read_text"},
+		{"", "tool"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeToolName(tt.id); got != tt.want {
+			t.Errorf("SanitizeToolName(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeToolName_Long(t *testing.T) {
+	a := "server:" + strings.Repeat("x", 80) + ".a"
+	b := "server:" + strings.Repeat("x", 80) + ".b"
+
+	na, nb := SanitizeToolName(a), SanitizeToolName(b)
+
+	for _, name := range []string{na, nb} {
+		if !IsProviderSafeName(name) || len(name) != MaxToolNameLength {
+			t.Errorf("SanitizeToolName() = %q (len %d), want a safe %d-byte name", name, len(name), MaxToolNameLength)
+		}
+	}
+	if na == nb {
+		t.Errorf("SanitizeToolName() gave %q for two different IDs", na)
+	}
+	if SanitizeToolName(a) != na {
+		t.Error("SanitizeToolName() is not deterministic")
+	}
+}
+
+func TestNameMapper_Collisions(t *testing.T) {
+	m := NewNameMapper()
+
+	dotted := &CanonicalTool{Namespace: "fs", Name: "read.file"}
+	slashed := &CanonicalTool{Namespace: "fs", Name: "read/file"}
+
+	first := m.ProviderName(dotted)
+	second := m.ProviderName(slashed)
+
+	if first != "fs_read_file" {
+		t.Errorf("ProviderName(first) = %q, want %q", first, "fs_read_file")
+	}
+	if second == first || !IsProviderSafeName(second) {
+		t.Errorf("ProviderName(second) = %q, want a distinct safe name", second)
+	}
+	if again := m.ProviderName(dotted); again != first {
+		t.Errorf("ProviderName() not stable: %q then %q", first, again)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	for name, want := range map[string]string{first: "fs:read.file", second: "fs:read/file"} {
+		got, ok := m.CanonicalID(name)
+		if !ok || got != want {
+			t.Errorf("CanonicalID(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := m.CanonicalID("unknown"); ok {
+		t.Error("CanonicalID(unknown) ok = true, want false")
+	}
+}
+
+func TestNameMapper_ProviderNameFor(t *testing.T) {
+	m := NewNameMapper()
+	noHyphens := func(name string) string { return strings.ReplaceAll(name, "-", "_") }
+
+	hyphen := &CanonicalTool{Name: "get-weather"}
+	underscore := &CanonicalTool{Name: "get_weather"}
+	plain := &CanonicalTool{Name: "search"}
+
+	if got := m.ProviderName(hyphen); got != "get-weather" {
+		t.Errorf("ProviderName() = %q, want get-weather", got)
+	}
+	if got := m.ProviderName(underscore); got != "get_weather" {
+		t.Errorf("ProviderName() = %q, want get_weather", got)
+	}
+
+	// The sanitized name is taken by another tool, so it gets a suffix
+	strict := m.ProviderNameFor(hyphen, "strict", noHyphens)
+	if strict == "get_weather" || strings.Contains(strict, "-") {
+		t.Errorf("ProviderNameFor(get-weather) = %q, want a distinct name without hyphens", strict)
+	}
+	if again := m.ProviderNameFor(hyphen, "strict", noHyphens); again != strict {
+		t.Errorf("ProviderNameFor() not stable: %q then %q", strict, again)
+	}
+
+	// A tool keeps the name it already has under another rule
+	if got, want := m.ProviderNameFor(plain, "strict", noHyphens), m.ProviderName(plain); got != want {
+		t.Errorf("ProviderNameFor(search) = %q, want shared name %q", got, want)
+	}
+
+	for name, want := range map[string]string{"get-weather": "get-weather", strict: "get-weather", "get_weather": "get_weather"} {
+		if got, ok := m.CanonicalID(name); !ok || got != want {
+			t.Errorf("CanonicalID(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+}
+
+func TestNameMapper_Concurrent(t *testing.T) {
+	m := NewNameMapper()
+	tool := &CanonicalTool{Namespace: "ns", Name: "a.b"}
+
+	var wg sync.WaitGroup
+	names := make([]string, 16)
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			names[i] = m.ProviderName(tool)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, name := range names {
+		if name != names[0] {
+			t.Fatalf("concurrent ProviderName() returned %q and %q", names[0], name)
+		}
+	}
+}
+
+func TestRegistry_Convert_NameMapping(t *testing.T) {
+	r := NewRegistry()
+	adapter := &mockAdapter{
+		name: "passthrough",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			if tool, ok := raw.(*CanonicalTool); ok {
+				return &CanonicalTool{Name: tool.Name, InputSchema: &JSONSchema{Type: "object"}}, nil
+			}
+			return &CanonicalTool{Namespace: "github", Name: "repos.get", InputSchema: &JSONSchema{Type: "object"}}, nil
+		},
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) {
+			return tool, nil
+		},
+		supportsFunc: func(f SchemaFeature) bool { return true },
+	}
+	_ = r.Register(adapter)
+
+	result, err := r.Convert("input", "passthrough", "passthrough", WithNameMapping(), WithRoundTripCheck())
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	emitted := result.Tool.(*CanonicalTool)
+	if emitted.Name != "github_repos_get" || emitted.Namespace != "" {
+		t.Errorf("emitted %q in namespace %q, want github_repos_get without namespace", emitted.Name, emitted.Namespace)
+	}
+	if id, ok := r.ResolveToolName(emitted.Name); !ok || id != "github:repos.get" {
+		t.Errorf("ResolveToolName() = %q, %v; want github:repos.get", id, ok)
+	}
+	if !result.RoundTrip.Lossless() {
+		t.Errorf("RoundTrip.Diffs = %v, want none after resolving the mapped name", result.RoundTrip.Diffs)
+	}
+	if r.Names().Len() != 1 {
+		t.Errorf("Names().Len() = %d, want 1", r.Names().Len())
+	}
+}
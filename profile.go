@@ -0,0 +1,253 @@
+package tooladapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CapabilityProfile declares which schema features, format values and
+// size limits a target format accepts. Profiles replace hard-coded
+// capability tables so provider changes can be picked up by editing data
+// rather than code.
+//
+// A profile is identified by its Adapter name and Mode. The empty Mode is
+// the adapter's default; other modes (e.g., "strict") describe alternative
+// capability sets of the same adapter.
+type CapabilityProfile struct {
+	// Adapter is the name of the adapter this profile describes (e.g., "openai")
+	Adapter string `json:"adapter"`
+
+	// Mode selects a capability set within the adapter ("" is the default)
+	Mode string `json:"mode,omitempty"`
+
+	// Features maps each schema feature to whether it is supported.
+	// Features that are not listed are treated as unsupported.
+	Features map[SchemaFeature]bool `json:"features"`
+
+	// Formats lists the supported values of the format keyword.
+	// An empty list means any format value is accepted.
+	Formats []string `json:"formats,omitempty"`
+
+	// Limits caps schema size and complexity
+	Limits ProfileLimits `json:"limits,omitempty"`
+}
+
+// ProfileLimits caps the size and complexity of a schema.
+// A zero value for any limit means it is unbounded.
+type ProfileLimits struct {
+	// MaxProperties is the total number of object properties across the schema
+	MaxProperties int `json:"maxProperties,omitempty"`
+
+	// MaxDepth is the maximum nesting depth of object schemas
+	MaxDepth int `json:"maxDepth,omitempty"`
+
+	// MaxEnumValues is the maximum number of values in any single enum
+	MaxEnumValues int `json:"maxEnumValues,omitempty"`
+}
+
+// ProfileProvider is implemented by adapters whose capabilities are
+// described by CapabilityProfiles.
+type ProfileProvider interface {
+	// Profile returns the capability profile for the given mode.
+	// Returns false if the adapter has no profile for that mode.
+	Profile(mode string) (*CapabilityProfile, bool)
+}
+
+// LimitViolation reports a schema that exceeds a profile limit.
+type LimitViolation struct {
+	// Limit is the name of the exceeded limit (e.g., "maxDepth")
+	Limit string
+
+	// Max is the limit's configured value
+	Max int
+
+	// Actual is the value found in the schema
+	Actual int
+
+	// Path is a JSON Pointer to the offending schema, when applicable
+	Path string
+}
+
+// String returns a human-readable violation message.
+func (v LimitViolation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%s exceeded: %d > %d", v.Limit, v.Actual, v.Max)
+	}
+	return fmt.Sprintf("%s exceeded at %s: %d > %d", v.Limit, v.Path, v.Actual, v.Max)
+}
+
+// Key returns the registry key for the profile ("adapter" or "adapter:mode").
+func (p *CapabilityProfile) Key() string {
+	return ProfileKey(p.Adapter, p.Mode)
+}
+
+// SupportsFeature returns whether the profile lists the feature as supported.
+func (p *CapabilityProfile) SupportsFeature(feature SchemaFeature) bool {
+	return p.Features[feature]
+}
+
+// SupportsFormat returns whether the profile accepts the given format value.
+func (p *CapabilityProfile) SupportsFormat(format string) bool {
+	if len(p.Formats) == 0 {
+		return true
+	}
+	return slices.Contains(p.Formats, format)
+}
+
+// Clone returns a deep copy of the profile.
+func (p *CapabilityProfile) Clone() *CapabilityProfile {
+	if p == nil {
+		return nil
+	}
+	copied := &CapabilityProfile{
+		Adapter: p.Adapter,
+		Mode:    p.Mode,
+		Limits:  p.Limits,
+		Formats: slices.Clone(p.Formats),
+	}
+	if p.Features != nil {
+		copied.Features = make(map[SchemaFeature]bool, len(p.Features))
+		for k, v := range p.Features {
+			copied.Features[k] = v
+		}
+	}
+	return copied
+}
+
+// CheckLimits returns every limit in the profile that the schema exceeds.
+func (p *CapabilityProfile) CheckLimits(schema *JSONSchema) []LimitViolation {
+	if schema == nil {
+		return nil
+	}
+
+	var violations []LimitViolation
+	stats := &schemaStats{}
+	stats.walk(schema, "", 0, p.Limits.MaxEnumValues, &violations)
+
+	if p.Limits.MaxProperties > 0 && stats.properties > p.Limits.MaxProperties {
+		violations = append(violations, LimitViolation{
+			Limit:  "maxProperties",
+			Max:    p.Limits.MaxProperties,
+			Actual: stats.properties,
+		})
+	}
+	if p.Limits.MaxDepth > 0 && stats.depth > p.Limits.MaxDepth {
+		violations = append(violations, LimitViolation{
+			Limit:  "maxDepth",
+			Max:    p.Limits.MaxDepth,
+			Actual: stats.depth,
+		})
+	}
+	return violations
+}
+
+// schemaStats accumulates size measurements for CheckLimits.
+type schemaStats struct {
+	properties int
+	depth      int
+}
+
+func (st *schemaStats) walk(s *JSONSchema, path string, depth, maxEnum int, violations *[]LimitViolation) {
+	if s == nil {
+		return
+	}
+	if len(s.Properties) > 0 || s.Type == "object" {
+		depth++
+		if depth > st.depth {
+			st.depth = depth
+		}
+	}
+	st.properties += len(s.Properties)
+	if maxEnum > 0 && len(s.Enum) > maxEnum {
+		*violations = append(*violations, LimitViolation{
+			Limit:  "maxEnumValues",
+			Max:    maxEnum,
+			Actual: len(s.Enum),
+			Path:   path + "/enum",
+		})
+	}
+
+	for _, k := range sortedSchemaKeys(s.Properties) {
+		st.walk(s.Properties[k], path+"/properties/"+escapePointer(k), depth, maxEnum, violations)
+	}
+	for _, k := range sortedSchemaKeys(s.Defs) {
+		st.walk(s.Defs[k], path+"/$defs/"+escapePointer(k), 0, maxEnum, violations)
+	}
+	st.walk(s.Items, path+"/items", depth, maxEnum, violations)
+	for i, sub := range s.AnyOf {
+		st.walk(sub, fmt.Sprintf("%s/anyOf/%d", path, i), depth, maxEnum, violations)
+	}
+	for i, sub := range s.OneOf {
+		st.walk(sub, fmt.Sprintf("%s/oneOf/%d", path, i), depth, maxEnum, violations)
+	}
+	for i, sub := range s.AllOf {
+		st.walk(sub, fmt.Sprintf("%s/allOf/%d", path, i), depth, maxEnum, violations)
+	}
+	st.walk(s.Not, path+"/not", depth, maxEnum, violations)
+}
+
+// ParseCapabilityProfiles decodes capability profiles from JSON or YAML.
+// The document may hold a single profile or a list of profiles.
+func ParseCapabilityProfiles(data []byte) ([]CapabilityProfile, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty capability profile document")
+	}
+
+	// YAML is decoded generically and re-encoded as JSON so that a single
+	// set of field names and keyword decoders applies to both formats.
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		var doc any
+		if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("decode capability profiles: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("decode capability profiles: %w", err)
+		}
+		trimmed = converted
+	}
+
+	var profiles []CapabilityProfile
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &profiles); err != nil {
+			return nil, fmt.Errorf("decode capability profiles: %w", err)
+		}
+	} else {
+		var p CapabilityProfile
+		if err := json.Unmarshal(trimmed, &p); err != nil {
+			return nil, fmt.Errorf("decode capability profiles: %w", err)
+		}
+		profiles = []CapabilityProfile{p}
+	}
+
+	for i := range profiles {
+		if profiles[i].Adapter == "" {
+			return nil, fmt.Errorf("capability profile %d: adapter is required", i)
+		}
+	}
+	return profiles, nil
+}
+
+// ProfileKey returns the registry key of an adapter mode: the adapter name
+// for the default mode, "adapter:mode" otherwise.
+func ProfileKey(adapter, mode string) string {
+	if mode == "" {
+		return adapter
+	}
+	return adapter + ":" + mode
+}
+
+func sortedSchemaKeys(m map[string]*JSONSchema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
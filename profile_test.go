@@ -0,0 +1,369 @@
+package tooladapter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSchemaFeature(t *testing.T) {
+	for _, f := range AllFeatures() {
+		got, err := ParseSchemaFeature(f.String())
+		if err != nil {
+			t.Errorf("ParseSchemaFeature(%q) error = %v", f, err)
+		}
+		if got != f {
+			t.Errorf("ParseSchemaFeature(%q) = %v, want %v", f, got, f)
+		}
+	}
+
+	if _, err := ParseSchemaFeature("unknownKeyword"); err == nil {
+		t.Error("ParseSchemaFeature(unknown) error = nil, want error")
+	}
+}
+
+func TestSchemaFeature_TextRoundTrip(t *testing.T) {
+	in := map[SchemaFeature]bool{FeatureRef: true, FeatureAnyOf: false}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"$ref":true,"anyOf":false}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	var out map[SchemaFeature]bool
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !out[FeatureRef] || len(out) != 2 {
+		t.Errorf("Unmarshal() = %v, want %v", out, in)
+	}
+}
+
+func TestParseCapabilityProfiles_JSON(t *testing.T) {
+	data := []byte(`[
+		{"adapter": "openai", "features": {"$ref": false}},
+		{"adapter": "openai", "mode": "strict", "features": {"$ref": true},
+		 "formats": ["date-time"], "limits": {"maxDepth": 10}}
+	]`)
+
+	profiles, err := ParseCapabilityProfiles(data)
+
+	if err != nil {
+		t.Fatalf("ParseCapabilityProfiles() error = %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("len(profiles) = %d, want 2", len(profiles))
+	}
+	strict := profiles[1]
+	if strict.Key() != "openai:strict" {
+		t.Errorf("Key() = %q, want %q", strict.Key(), "openai:strict")
+	}
+	if !strict.SupportsFeature(FeatureRef) {
+		t.Error("SupportsFeature($ref) = false, want true")
+	}
+	if strict.Limits.MaxDepth != 10 {
+		t.Errorf("Limits.MaxDepth = %d, want 10", strict.Limits.MaxDepth)
+	}
+}
+
+func TestParseCapabilityProfiles_YAML(t *testing.T) {
+	data := []byte(`
+adapter: anthropic
+features:
+  $ref: false
+  anyOf: true
+formats: [email, uri]
+limits:
+  maxEnumValues: 50
+`)
+
+	profiles, err := ParseCapabilityProfiles(data)
+
+	if err != nil {
+		t.Fatalf("ParseCapabilityProfiles() error = %v", err)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("len(profiles) = %d, want 1", len(profiles))
+	}
+	p := profiles[0]
+	if p.Key() != "anthropic" {
+		t.Errorf("Key() = %q, want %q", p.Key(), "anthropic")
+	}
+	if p.SupportsFeature(FeatureRef) || !p.SupportsFeature(FeatureAnyOf) {
+		t.Errorf("Features = %v", p.Features)
+	}
+	if !p.SupportsFormat("uri") || p.SupportsFormat("uuid") {
+		t.Errorf("Formats = %v", p.Formats)
+	}
+	if p.Limits.MaxEnumValues != 50 {
+		t.Errorf("Limits.MaxEnumValues = %d, want 50", p.Limits.MaxEnumValues)
+	}
+}
+
+func TestParseCapabilityProfiles_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":           "  ",
+		"missing adapter": `{"features": {}}`,
+		"unknown feature": `{"adapter": "x", "features": {"bogus": true}}`,
+		"bad yaml":        "adapter: [",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCapabilityProfiles([]byte(data)); err == nil {
+				t.Error("ParseCapabilityProfiles() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCapabilityProfile_SupportsFormat_Unrestricted(t *testing.T) {
+	p := &CapabilityProfile{Adapter: "x"}
+
+	if !p.SupportsFormat("anything") {
+		t.Error("SupportsFormat() = false, want true when Formats is empty")
+	}
+}
+
+func TestCapabilityProfile_Clone(t *testing.T) {
+	p := &CapabilityProfile{
+		Adapter:  "x",
+		Features: map[SchemaFeature]bool{FeatureRef: true},
+		Formats:  []string{"email"},
+	}
+
+	c := p.Clone()
+	c.Features[FeatureRef] = false
+	c.Formats[0] = "uri"
+
+	if !p.Features[FeatureRef] || p.Formats[0] != "email" {
+		t.Error("Clone() shares state with the original")
+	}
+}
+
+func TestCapabilityProfile_CheckLimits(t *testing.T) {
+	p := &CapabilityProfile{
+		Adapter: "x",
+		Limits:  ProfileLimits{MaxProperties: 2, MaxDepth: 1, MaxEnumValues: 2},
+	}
+	schema := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"a": {Type: "string", Enum: []any{"x", "y", "z"}},
+			"b": {
+				Type:       "object",
+				Properties: map[string]*JSONSchema{"c": {Type: "string"}},
+			},
+		},
+	}
+
+	violations := p.CheckLimits(schema)
+
+	got := make(map[string]LimitViolation)
+	for _, v := range violations {
+		got[v.Limit] = v
+	}
+	if v := got["maxEnumValues"]; v.Path != "/properties/a/enum" || v.Actual != 3 {
+		t.Errorf("maxEnumValues violation = %+v", v)
+	}
+	if v := got["maxProperties"]; v.Actual != 3 {
+		t.Errorf("maxProperties violation = %+v", v)
+	}
+	if v := got["maxDepth"]; v.Actual != 2 {
+		t.Errorf("maxDepth violation = %+v", v)
+	}
+}
+
+func TestCapabilityProfile_CheckLimits_Unbounded(t *testing.T) {
+	p := &CapabilityProfile{Adapter: "x"}
+	schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{"a": {Enum: []any{1, 2, 3}}}}
+
+	if v := p.CheckLimits(schema); len(v) != 0 {
+		t.Errorf("CheckLimits() = %v, want none", v)
+	}
+}
+
+func TestLimitViolation_String(t *testing.T) {
+	v := LimitViolation{Limit: "maxEnumValues", Max: 2, Actual: 3, Path: "/enum"}
+
+	if got := v.String(); !containsString(got, "maxEnumValues") || !containsString(got, "/enum") {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestRegistry_Profile(t *testing.T) {
+	r := NewRegistry()
+	_ = r.Register(&profiledAdapter{mockAdapter: mockAdapter{name: "target"}})
+
+	p, err := r.Profile("target", "")
+	if err != nil {
+		t.Fatalf("Profile() error = %v", err)
+	}
+	if p.SupportsFeature(FeatureRef) {
+		t.Error("adapter profile SupportsFeature($ref) = true, want false")
+	}
+
+	if err := r.SetProfile(CapabilityProfile{Adapter: "target", Features: map[SchemaFeature]bool{FeatureRef: true}}); err != nil {
+		t.Fatalf("SetProfile() error = %v", err)
+	}
+	p, _ = r.Profile("target", "")
+	if !p.SupportsFeature(FeatureRef) {
+		t.Error("override SupportsFeature($ref) = false, want true")
+	}
+
+	if _, err := r.Profile("target", "missing"); err == nil {
+		t.Error("Profile(missing mode) error = nil, want error")
+	}
+	if err := r.SetProfile(CapabilityProfile{}); err == nil {
+		t.Error("SetProfile(no adapter) error = nil, want error")
+	}
+}
+
+func TestRegistry_Convert_ProfileOverride(t *testing.T) {
+	r := NewRegistry()
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name:        "test",
+				InputSchema: &JSONSchema{Type: "object", Ref: "#/$defs/X", Format: "uri"},
+			}, nil
+		},
+	}
+	target := &profiledAdapter{mockAdapter: mockAdapter{
+		name:              "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) { return tool.Name, nil },
+	}}
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	// The adapter's own profile rejects $ref
+	result, err := r.Convert("input", "source", "target")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if !hasWarning(result.Warnings, FeatureRef) {
+		t.Error("Convert() missing $ref warning from adapter profile")
+	}
+
+	// A strict mode that accepts $ref but not the uri format
+	_ = r.SetProfile(CapabilityProfile{
+		Adapter:  "target",
+		Mode:     "strict",
+		Features: map[SchemaFeature]bool{FeatureRef: true, FeatureFormat: true},
+		Formats:  []string{"email"},
+	})
+	result, err = r.Convert("input", "source", "target", WithTargetMode("strict"))
+	if err != nil {
+		t.Fatalf("Convert() strict error = %v", err)
+	}
+	if hasWarning(result.Warnings, FeatureRef) {
+		t.Error("Convert() strict has $ref warning, want none")
+	}
+	if !hasWarning(result.Warnings, FeatureFormat) {
+		t.Error("Convert() strict missing format warning for unsupported value")
+	}
+
+	if _, err := r.Convert("input", "source", "target", WithTargetMode("unknown")); err == nil {
+		t.Error("Convert() unknown mode error = nil, want error")
+	}
+}
+
+func TestRegistry_Convert_ProfileOverrideToolFeatures(t *testing.T) {
+	r := NewRegistry()
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name:        "delete_file",
+				InputSchema: &JSONSchema{Type: "object"},
+				Annotations: &ToolAnnotations{DestructiveHint: boolPtr(true)},
+			}, nil
+		},
+	}
+	// The adapter itself claims every feature
+	target := &mockAdapter{
+		name:              "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) { return tool.Name, nil },
+		supportsFunc:      func(f SchemaFeature) bool { return true },
+	}
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	result, err := r.Convert("input", "source", "target")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if hasWarning(result.Warnings, FeatureAnnotations) {
+		t.Error("Convert() has annotations warning before override, want none")
+	}
+
+	_ = r.SetProfile(CapabilityProfile{Adapter: "target", Features: map[SchemaFeature]bool{}})
+	result, err = r.Convert("input", "source", "target")
+	if err != nil {
+		t.Fatalf("Convert() override error = %v", err)
+	}
+	if !hasWarning(result.Warnings, FeatureAnnotations) {
+		t.Error("Convert() override missing annotations warning")
+	}
+}
+
+func TestRegistry_Convert_LimitViolations(t *testing.T) {
+	r := NewRegistry()
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name:        "test",
+				InputSchema: &JSONSchema{Type: "string", Enum: []any{"a", "b", "c"}},
+			}, nil
+		},
+	}
+	target := &mockAdapter{
+		name:              "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) { return tool.Name, nil },
+		supportsFunc:      func(f SchemaFeature) bool { return true },
+	}
+	_ = r.Register(source)
+	_ = r.Register(target)
+	_ = r.SetProfile(CapabilityProfile{
+		Adapter:  "target",
+		Features: map[SchemaFeature]bool{FeatureEnum: true},
+		Limits:   ProfileLimits{MaxEnumValues: 2},
+	})
+
+	result, err := r.Convert("input", "source", "target")
+
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Limit != "maxEnumValues" {
+		t.Errorf("Convert() violations = %v, want one maxEnumValues", result.Violations)
+	}
+}
+
+// profiledAdapter is a mockAdapter that supplies a default capability profile
+type profiledAdapter struct {
+	mockAdapter
+}
+
+func (a *profiledAdapter) Profile(mode string) (*CapabilityProfile, bool) {
+	if mode != "" {
+		return nil, false
+	}
+	return &CapabilityProfile{
+		Adapter:  a.name,
+		Features: map[SchemaFeature]bool{FeatureFormat: true},
+	}, true
+}
+
+func hasWarning(warnings []FeatureLossWarning, feature SchemaFeature) bool {
+	for _, w := range warnings {
+		if w.Feature == feature {
+			return true
+		}
+	}
+	return false
+}
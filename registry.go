@@ -2,6 +2,7 @@ package tooladapter
 
 import (
 	"errors"
+	"strings"
 	"sync"
 )
 
@@ -12,21 +13,90 @@ type ConversionResult struct {
 
 	// Warnings lists features that may have been lost during conversion
 	Warnings []FeatureLossWarning
+
+	// Violations lists profile limits exceeded by the tool's schemas
+	Violations []LimitViolation
+
+	// Issues lists the schema constructs the target adapter dropped,
+	// rewrote or approximated, for a SchemaIssueReporter target
+	Issues []SchemaIssue
+
+	// RoundTrip is the round-trip fidelity report, populated only when
+	// Convert is called with WithRoundTripCheck
+	RoundTrip *RoundTripReport
+}
+
+// ConvertOption configures optional behavior of AdapterRegistry.Convert.
+type ConvertOption func(*convertOptions)
+
+// convertOptions holds the resolved options for a single Convert call.
+type convertOptions struct {
+	roundTrip   bool
+	targetMode  string
+	nameMapping bool
+}
+
+// WithRoundTripCheck makes Convert feed the converted output back through the
+// target adapter's ToCanonical and attach a RoundTripReport to the result.
+//
+// Unlike FeatureLossWarning, which is predicted from SupportsFeature, the
+// report is derived from the actual output, so it also catches adapter bugs
+// that the capability table does not describe.
+func WithRoundTripCheck() ConvertOption {
+	return func(o *convertOptions) {
+		o.roundTrip = true
+	}
+}
+
+// WithTargetMode selects the capability profile mode of the target adapter
+// used for feature-loss detection (e.g., "strict"). Convert returns an error
+// if no profile exists for the requested mode.
+func WithTargetMode(mode string) ConvertOption {
+	return func(o *convertOptions) {
+		o.targetMode = mode
+	}
+}
+
+// WithNameMapping makes Convert emit the tool under a provider-safe name
+// assigned by the registry's NameMapper instead of its canonical name.
+// The namespace is folded into the name, so "github:repos.get" becomes
+// "github_repos_get"; use ResolveToolName to route tool calls back. A
+// target implementing ToolNameSanitizer further restricts the name.
+func WithNameMapping() ConvertOption {
+	return func(o *convertOptions) {
+		o.nameMapping = true
+	}
 }
 
 // AdapterRegistry is a thread-safe registry of protocol adapters.
 type AdapterRegistry struct {
 	mu       sync.RWMutex
 	adapters map[string]Adapter
+	profiles map[string]*CapabilityProfile
+	names    *NameMapper
 }
 
 // NewRegistry creates a new empty adapter registry.
 func NewRegistry() *AdapterRegistry {
 	return &AdapterRegistry{
 		adapters: make(map[string]Adapter),
+		profiles: make(map[string]*CapabilityProfile),
+		names:    NewNameMapper(),
 	}
 }
 
+// Names returns the registry's name mapper, which records every name
+// assigned by Convert with WithNameMapping.
+func (r *AdapterRegistry) Names() *NameMapper {
+	return r.names
+}
+
+// ResolveToolName returns the canonical ID behind a provider-safe name
+// assigned by Convert with WithNameMapping.
+func (r *AdapterRegistry) ResolveToolName(name string) (string, bool) {
+	return r.names.CanonicalID(name)
+}
+
 // Register adds an adapter to the registry.
 // Returns an error if an adapter with the same name is already registered.
 func (r *AdapterRegistry) Register(a Adapter) error {
@@ -79,10 +149,61 @@ func (r *AdapterRegistry) Unregister(name string) error {
 	return nil
 }
 
+// SetProfile overrides the capability profile for an adapter mode within this
+// registry. The override takes precedence over the adapter's own profile and
+// replaces any previous override for the same adapter and mode.
+func (r *AdapterRegistry) SetProfile(p CapabilityProfile) error {
+	if p.Adapter == "" {
+		return errors.New("capability profile adapter is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[p.Key()] = p.Clone()
+	return nil
+}
+
+// Profile returns the capability profile for an adapter mode.
+// Registry overrides are consulted first, then adapters implementing
+// ProfileProvider. Returns an error if neither has a profile.
+func (r *AdapterRegistry) Profile(adapter, mode string) (*CapabilityProfile, error) {
+	r.mu.RLock()
+	override, ok := r.profiles[ProfileKey(adapter, mode)]
+	a, registered := r.adapters[adapter]
+	r.mu.RUnlock()
+
+	if ok {
+		return override.Clone(), nil
+	}
+	if provider, isProvider := a.(ProfileProvider); registered && isProvider {
+		if p, found := provider.Profile(mode); found {
+			return p, nil
+		}
+	}
+	return nil, errors.New("capability profile not found: " + ProfileKey(adapter, mode))
+}
+
 // Convert transforms a tool from one format to another.
 // It uses the source adapter's ToCanonical and the target adapter's FromCanonical.
 // Returns warnings if schema features are lost during conversion.
-func (r *AdapterRegistry) Convert(tool any, fromFormat, toFormat string) (*ConversionResult, error) {
+// Options such as WithRoundTripCheck enable additional verification.
+//
+// The target format may name a mode as "adapter:mode" (e.g., "openai:strict").
+// Without an explicit mode, a ModalAdapter target chooses the mode from the
+// tool being converted.
+func (r *AdapterRegistry) Convert(tool any, fromFormat, toFormat string, opts ...ConvertOption) (*ConversionResult, error) {
+	var options convertOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	toFormat, mode := splitFormat(toFormat)
+	if options.targetMode != "" {
+		mode = options.targetMode
+	}
+	explicitMode := mode != ""
+
 	// Get source adapter
 	source, err := r.Get(fromFormat)
 	if err != nil {
@@ -105,11 +226,51 @@ func (r *AdapterRegistry) Convert(tool any, fromFormat, toFormat string) (*Conve
 		}
 	}
 
-	// Check for feature loss
-	warnings := detectFeatureLoss(canonical, source, target)
+	// Bind a modal target to the requested mode, or the mode of the tool
+	if modal, ok := target.(ModalAdapter); ok {
+		if mode == "" {
+			mode = modal.ModeOf(canonical)
+		}
+		target, err = modal.WithMode(mode)
+		if err != nil {
+			return nil, err
+		}
+		explicitMode = false
+	}
+
+	// Check for feature loss against the target's profile when one is
+	// available, falling back to the adapter's own SupportsFeature. The
+	// target emits against the same profile, so warnings match the output
+	var caps capabilities = target
+	var violations []LimitViolation
+	if profile, err := r.Profile(toFormat, mode); err == nil {
+		caps = profile
+		violations = append(profile.CheckLimits(canonical.InputSchema), profile.CheckLimits(canonical.OutputSchema)...)
+		if binder, ok := target.(ProfileBinder); ok {
+			target = binder.WithProfile(profile)
+		}
+	} else if explicitMode {
+		// Only a profile can give meaning to a mode of a non-modal adapter
+		return nil, err
+	}
+	warnings := detectFeatureLoss(canonical, source.Name(), target.Name(), caps)
+	warnings = append(warnings, detectToolFeatureLoss(canonical, source, target, caps)...)
+
+	// Emit the tool under its provider-safe name
+	emitted := canonical
+	if options.nameMapping {
+		mapped := *canonical
+		mapped.Namespace = ""
+		if sanitizer, ok := target.(ToolNameSanitizer); ok {
+			mapped.Name = r.names.ProviderNameFor(canonical, target.Name(), sanitizer.SanitizeToolName)
+		} else {
+			mapped.Name = r.names.ProviderName(canonical)
+		}
+		emitted = &mapped
+	}
 
 	// Convert from canonical
-	output, err := target.FromCanonical(canonical)
+	output, err := target.FromCanonical(emitted)
 	if err != nil {
 		return nil, &ConversionError{
 			Adapter:   toFormat,
@@ -118,29 +279,105 @@ func (r *AdapterRegistry) Convert(tool any, fromFormat, toFormat string) (*Conve
 		}
 	}
 
-	return &ConversionResult{
-		Tool:     output,
-		Warnings: warnings,
-	}, nil
+	result := &ConversionResult{
+		Tool:       output,
+		Warnings:   warnings,
+		Violations: violations,
+	}
+	if reporter, ok := target.(SchemaIssueReporter); ok {
+		result.Issues = reporter.SchemaIssues(emitted)
+	}
+
+	// Convert the output back and compare with the original
+	if options.roundTrip {
+		roundTripped, err := target.ToCanonical(output)
+		if err != nil {
+			return nil, &ConversionError{
+				Adapter:   toFormat,
+				Direction: "to_canonical",
+				Cause:     err,
+			}
+		}
+		if options.nameMapping {
+			if t, ok := r.names.lookup(roundTripped.Name); ok {
+				roundTripped.Namespace, roundTripped.Name = t.namespace, t.name
+			}
+		}
+		result.RoundTrip = &RoundTripReport{
+			Original:     canonical,
+			RoundTripped: roundTripped,
+			Diffs:        DiffCanonical(canonical, roundTripped),
+		}
+	}
+
+	return result, nil
+}
+
+// splitFormat separates an "adapter:mode" target format into its parts.
+func splitFormat(format string) (adapter, mode string) {
+	adapter, mode, _ = strings.Cut(format, ":")
+	return adapter, mode
+}
+
+// capabilities answers feature-support questions for loss detection.
+// Both Adapter and *CapabilityProfile satisfy it.
+type capabilities interface {
+	SupportsFeature(feature SchemaFeature) bool
+}
+
+// formatCapabilities is implemented by capability sets that restrict the
+// values of the format keyword.
+type formatCapabilities interface {
+	SupportsFormat(format string) bool
 }
 
 // detectFeatureLoss checks which features in the canonical tool are not
-// supported by the target adapter.
-func detectFeatureLoss(tool *CanonicalTool, source, target Adapter) []FeatureLossWarning {
+// supported by the target capabilities.
+func detectFeatureLoss(tool *CanonicalTool, source, target string, caps capabilities) []FeatureLossWarning {
 	var warnings []FeatureLossWarning
 
 	if tool.InputSchema != nil {
-		warnings = append(warnings, detectSchemaFeatureLoss(tool.InputSchema, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(tool.InputSchema, source, target, caps)...)
 	}
 	if tool.OutputSchema != nil {
-		warnings = append(warnings, detectSchemaFeatureLoss(tool.OutputSchema, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(tool.OutputSchema, source, target, caps)...)
+	}
+
+	return warnings
+}
+
+// detectToolFeatureLoss checks which tool-level features, such as
+// annotations, the target cannot carry. Features are read from canonical
+// fields and, for a ToolFeatureReporter source, from SourceMeta. Like
+// detectFeatureLoss, only the resolved capabilities are consulted, so a
+// registry profile override takes precedence over the adapter's own answer.
+func detectToolFeatureLoss(tool *CanonicalTool, source, target Adapter, caps capabilities) []FeatureLossWarning {
+	used := map[SchemaFeature]bool{
+		FeatureAnnotations:  tool.Annotations != nil,
+		FeatureOutputSchema: tool.OutputSchema != nil,
+		FeatureMetadata:     tool.Category != "" || len(tool.Tags) > 0 || tool.Version != "" || tool.Timeout != 0,
+	}
+	if reporter, ok := source.(ToolFeatureReporter); ok {
+		for _, f := range reporter.ToolFeatures(tool) {
+			used[f] = true
+		}
 	}
 
+	var warnings []FeatureLossWarning
+	for _, f := range AllFeatures() {
+		if used[f] && !caps.SupportsFeature(f) {
+			warnings = append(warnings, FeatureLossWarning{
+				Feature:     f,
+				FromAdapter: source.Name(),
+				ToAdapter:   target.Name(),
+			})
+		}
+	}
 	return warnings
 }
 
 // detectSchemaFeatureLoss checks which features in a schema are not supported.
-func detectSchemaFeatureLoss(schema *JSONSchema, source, target Adapter) []FeatureLossWarning {
+func detectSchemaFeatureLoss(schema *JSONSchema, source, target string, caps capabilities) []FeatureLossWarning {
 	var warnings []FeatureLossWarning
 
 	// Check each feature that's used in the schema
@@ -163,12 +400,22 @@ func detectSchemaFeatureLoss(schema *JSONSchema, source, target Adapter) []Featu
 		FeatureDefault:              schema.Default != nil,
 	}
 
+	// A supported format keyword is still lost if its value is not accepted
+	if fc, ok := caps.(formatCapabilities); ok && schema.Format != "" && !fc.SupportsFormat(schema.Format) {
+		featureUsage[FeatureFormat] = false
+		warnings = append(warnings, FeatureLossWarning{
+			Feature:     FeatureFormat,
+			FromAdapter: source,
+			ToAdapter:   target,
+		})
+	}
+
 	for feature, used := range featureUsage {
-		if used && !target.SupportsFeature(feature) {
+		if used && !caps.SupportsFeature(feature) {
 			warnings = append(warnings, FeatureLossWarning{
 				Feature:     feature,
-				FromAdapter: source.Name(),
-				ToAdapter:   target.Name(),
+				FromAdapter: source,
+				ToAdapter:   target,
 			})
 		}
 	}
@@ -176,28 +423,28 @@ func detectSchemaFeatureLoss(schema *JSONSchema, source, target Adapter) []Featu
 	// Recursively check nested schemas
 	if schema.Properties != nil {
 		for _, prop := range schema.Properties {
-			warnings = append(warnings, detectSchemaFeatureLoss(prop, source, target)...)
+			warnings = append(warnings, detectSchemaFeatureLoss(prop, source, target, caps)...)
 		}
 	}
 	if schema.Items != nil {
-		warnings = append(warnings, detectSchemaFeatureLoss(schema.Items, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(schema.Items, source, target, caps)...)
 	}
 	if schema.Defs != nil {
 		for _, def := range schema.Defs {
-			warnings = append(warnings, detectSchemaFeatureLoss(def, source, target)...)
+			warnings = append(warnings, detectSchemaFeatureLoss(def, source, target, caps)...)
 		}
 	}
 	for _, s := range schema.AnyOf {
-		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target, caps)...)
 	}
 	for _, s := range schema.OneOf {
-		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target, caps)...)
 	}
 	for _, s := range schema.AllOf {
-		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(s, source, target, caps)...)
 	}
 	if schema.Not != nil {
-		warnings = append(warnings, detectSchemaFeatureLoss(schema.Not, source, target)...)
+		warnings = append(warnings, detectSchemaFeatureLoss(schema.Not, source, target, caps)...)
 	}
 
 	return warnings
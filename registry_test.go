@@ -2,6 +2,7 @@ package tooladapter
 
 import (
 	"errors"
+	"reflect"
 	"sort"
 	"sync"
 	"testing"
@@ -371,3 +372,231 @@ func TestRegistry_Convert_SameFormat(t *testing.T) {
 		t.Errorf("Convert() same format result = %v, want %q", result.Tool, "test")
 	}
 }
+
+func TestRegistry_Convert_ModalTarget(t *testing.T) {
+	r := NewRegistry()
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name:        "test",
+				InputSchema: &JSONSchema{Type: "object", Ref: "#/$defs/X"},
+				SourceMeta:  map[string]any{"mode": raw},
+			}, nil
+		},
+	}
+	target := &modalAdapter{mockAdapter: mockAdapter{name: "target"}}
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	tests := []struct {
+		name     string
+		input    any
+		to       string
+		wantMode string
+		wantLoss bool
+	}{
+		{"derived default", nil, "target", "", true},
+		{"derived from tool", "loose", "target", "loose", false},
+		{"explicit suffix", nil, "target:loose", "loose", false},
+		{"empty suffix derives from tool", "loose", "target:", "loose", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Convert(tt.input, "source", tt.to)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if result.Tool != tt.wantMode {
+				t.Errorf("emitted mode = %v, want %q", result.Tool, tt.wantMode)
+			}
+			if got := hasWarning(result.Warnings, FeatureRef); got != tt.wantLoss {
+				t.Errorf("$ref warning = %v, want %v", got, tt.wantLoss)
+			}
+		})
+	}
+
+	if _, err := r.Convert(nil, "source", "target:bogus"); err == nil {
+		t.Error("Convert() with unknown mode error = nil, want error")
+	}
+}
+
+// modalAdapter is a mockAdapter with a "loose" mode that accepts $ref.
+// FromCanonical returns the bound mode so tests can observe it.
+type modalAdapter struct {
+	mockAdapter
+	mode string
+}
+
+func (a *modalAdapter) ModeOf(tool *CanonicalTool) string {
+	if a.mode != "" {
+		return a.mode
+	}
+	if m, ok := tool.SourceMeta["mode"].(string); ok {
+		return m
+	}
+	return ""
+}
+
+func (a *modalAdapter) WithMode(mode string) (Adapter, error) {
+	if mode != "" && mode != "loose" {
+		return nil, errors.New("unknown mode: " + mode)
+	}
+	return &modalAdapter{mockAdapter: a.mockAdapter, mode: mode}, nil
+}
+
+func (a *modalAdapter) FromCanonical(tool *CanonicalTool) (any, error) {
+	return a.mode, nil
+}
+
+func (a *modalAdapter) SupportsFeature(feature SchemaFeature) bool {
+	return a.mode == "loose"
+}
+
+func TestRegistry_Convert_AnnotationsLoss(t *testing.T) {
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name:        "delete_file",
+				InputSchema: &JSONSchema{Type: "object"},
+				Annotations: &ToolAnnotations{ReadOnlyHint: raw.(bool)},
+			}, nil
+		},
+		supportsFunc: func(f SchemaFeature) bool { return true },
+	}
+
+	tests := []struct {
+		name     string
+		supports bool
+		wantLoss bool
+	}{
+		{"target without annotations", false, true},
+		{"target with annotations", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			target := &mockAdapter{
+				name: "target",
+				fromCanonicalFunc: func(tool *CanonicalTool) (any, error) {
+					return tool.Name, nil
+				},
+				supportsFunc: func(f SchemaFeature) bool {
+					return f != FeatureAnnotations || tt.supports
+				},
+			}
+			_ = r.Register(source)
+			_ = r.Register(target)
+
+			result, err := r.Convert(true, "source", "target")
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+
+			gotLoss := false
+			for _, w := range result.Warnings {
+				if w.Feature == FeatureAnnotations {
+					gotLoss = true
+				}
+			}
+			if gotLoss != tt.wantLoss {
+				t.Errorf("annotations loss warning = %v, want %v", gotLoss, tt.wantLoss)
+			}
+		})
+	}
+}
+
+// reportingAdapter is a mock source that reports tool-level features kept
+// in SourceMeta.
+type reportingAdapter struct {
+	mockAdapter
+	features []SchemaFeature
+}
+
+func (a *reportingAdapter) ToolFeatures(*CanonicalTool) []SchemaFeature {
+	return a.features
+}
+
+func TestRegistry_Convert_ToolFeatureLoss(t *testing.T) {
+	source := &reportingAdapter{
+		mockAdapter: mockAdapter{
+			name: "source",
+			toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+				return &CanonicalTool{
+					Name:         "search",
+					InputSchema:  &JSONSchema{Type: "object"},
+					OutputSchema: &JSONSchema{Type: "object"},
+					Tags:         []string{"web"},
+				}, nil
+			},
+			supportsFunc: func(f SchemaFeature) bool { return true },
+		},
+		features: []SchemaFeature{FeatureIcons},
+	}
+	target := &mockAdapter{
+		name: "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) {
+			return tool.Name, nil
+		},
+		supportsFunc: func(f SchemaFeature) bool {
+			return f != FeatureOutputSchema && f != FeatureMetadata && f != FeatureIcons && f != FeatureTitle
+		},
+	}
+
+	r := NewRegistry()
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	result, err := r.Convert(nil, "source", "target")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	var got []SchemaFeature
+	for _, w := range result.Warnings {
+		got = append(got, w.Feature)
+	}
+	want := []SchemaFeature{FeatureOutputSchema, FeatureIcons, FeatureMetadata}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warned features = %v, want %v", got, want)
+	}
+}
+
+// issueAdapter is a mock target that reports schema issues.
+type issueAdapter struct {
+	mockAdapter
+}
+
+func (a *issueAdapter) SchemaIssues(tool *CanonicalTool) []SchemaIssue {
+	return []SchemaIssue{{Path: "/inputSchema", Keyword: "type", Message: "renamed " + tool.Name}}
+}
+
+func TestRegistry_Convert_SchemaIssues(t *testing.T) {
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{Name: "search", InputSchema: &JSONSchema{Type: "object"}}, nil
+		},
+	}
+	target := &issueAdapter{mockAdapter: mockAdapter{
+		name:              "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) { return tool.Name, nil },
+		supportsFunc:      func(f SchemaFeature) bool { return true },
+	}}
+
+	r := NewRegistry()
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	result, err := r.Convert(nil, "source", "target")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	want := []SchemaIssue{{Path: "/inputSchema", Keyword: "type", Message: "renamed search"}}
+	if !reflect.DeepEqual(result.Issues, want) {
+		t.Errorf("Issues = %v, want %v", result.Issues, want)
+	}
+}
@@ -0,0 +1,346 @@
+package tooladapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiffKind classifies a single difference between two canonical tools.
+type DiffKind string
+
+const (
+	// DiffAdded means the value is present only after the round trip
+	DiffAdded DiffKind = "added"
+	// DiffRemoved means the value was present before but is missing after the round trip
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged means the value is present on both sides but differs
+	DiffChanged DiffKind = "changed"
+)
+
+// FieldDiff describes one semantic difference between two canonical tools.
+type FieldDiff struct {
+	// Path is a JSON Pointer to the differing field (e.g., "/inputSchema/required")
+	Path string
+
+	// Kind is whether the value was added, removed, or changed
+	Kind DiffKind
+
+	// Before is the original value (nil when Kind is DiffAdded)
+	Before any
+
+	// After is the round-tripped value (nil when Kind is DiffRemoved)
+	After any
+}
+
+// String returns a human-readable description of the difference.
+func (d FieldDiff) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("%s added: %v", d.Path, d.After)
+	case DiffRemoved:
+		return fmt.Sprintf("%s removed: %v", d.Path, d.Before)
+	default:
+		return fmt.Sprintf("%s changed: %v -> %v", d.Path, d.Before, d.After)
+	}
+}
+
+// RoundTripReport records how faithfully a tool survived conversion to a
+// target format and back.
+type RoundTripReport struct {
+	// Original is the canonical tool produced by the source adapter
+	Original *CanonicalTool
+
+	// RoundTripped is the canonical tool produced by the target adapter's
+	// ToCanonical when fed its own FromCanonical output
+	RoundTripped *CanonicalTool
+
+	// Diffs lists every semantic difference between Original and RoundTripped,
+	// sorted by Path
+	Diffs []FieldDiff
+}
+
+// Lossless reports whether the round trip produced no differences.
+func (r *RoundTripReport) Lossless() bool {
+	return len(r.Diffs) == 0
+}
+
+// DiffCanonical returns the semantic differences between two canonical tools.
+//
+// The comparison covers the portable fields of CanonicalTool and recurses
+// into InputSchema and OutputSchema. SourceFormat and SourceMeta are ignored
+// because they describe where a tool came from rather than what it is.
+// Required lists are compared as sets, and Enum, Const and Default values are
+// compared by their JSON encoding so that 1 and 1.0 are considered equal.
+func DiffCanonical(before, after *CanonicalTool) []FieldDiff {
+	d := &differ{}
+
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		d.add("", DiffAdded, nil, after.ID())
+		return d.diffs
+	case after == nil:
+		d.add("", DiffRemoved, before.ID(), nil)
+		return d.diffs
+	}
+
+	d.value("/kind", normalizeKind(before.Kind), normalizeKind(after.Kind))
+	d.optional("/custom", customValue(before.Custom), customValue(after.Custom))
+	d.optional("/hosted", hostedValue(before.Hosted), hostedValue(after.Hosted))
+	d.value("/namespace", before.Namespace, after.Namespace)
+	d.value("/name", before.Name, after.Name)
+	d.value("/version", before.Version, after.Version)
+	d.value("/description", before.Description, after.Description)
+	d.value("/category", before.Category, after.Category)
+	d.stringSet("/tags", before.Tags, after.Tags)
+	d.value("/timeout", before.Timeout, after.Timeout)
+	d.stringSet("/requiredScopes", before.RequiredScopes, after.RequiredScopes)
+	d.schema("/inputSchema", before.InputSchema, after.InputSchema)
+	d.examples("/examples", before.Examples, after.Examples)
+	d.schema("/outputSchema", before.OutputSchema, after.OutputSchema)
+	d.optional("/annotations", annotationsValue(before.Annotations), annotationsValue(after.Annotations))
+
+	sort.SliceStable(d.diffs, func(i, j int) bool {
+		return d.diffs[i].Path < d.diffs[j].Path
+	})
+	return d.diffs
+}
+
+// differ accumulates FieldDiffs while walking two values in parallel.
+type differ struct {
+	diffs []FieldDiff
+}
+
+func (d *differ) add(path string, kind DiffKind, before, after any) {
+	d.diffs = append(d.diffs, FieldDiff{Path: path, Kind: kind, Before: before, After: after})
+}
+
+// value compares two scalar values, treating zero values as absent.
+func (d *differ) value(path string, before, after any) {
+	if isZeroValue(before) {
+		before = nil
+	}
+	if isZeroValue(after) {
+		after = nil
+	}
+	d.optional(path, before, after)
+}
+
+// optional compares two values where only nil means absent, so that
+// meaningful zero values such as const false are still compared.
+func (d *differ) optional(path string, before, after any) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.add(path, DiffAdded, nil, after)
+	case after == nil:
+		d.add(path, DiffRemoved, before, nil)
+	case !jsonEqual(before, after):
+		d.add(path, DiffChanged, before, after)
+	}
+}
+
+// stringSet compares two string slices ignoring order and duplicates.
+func (d *differ) stringSet(path string, before, after []string) {
+	bset := make(map[string]bool, len(before))
+	for _, s := range before {
+		bset[s] = true
+	}
+	aset := make(map[string]bool, len(after))
+	for _, s := range after {
+		aset[s] = true
+	}
+	for _, s := range sortedKeys(bset) {
+		if !aset[s] {
+			d.add(path+"/"+escapePointer(s), DiffRemoved, s, nil)
+		}
+	}
+	for _, s := range sortedKeys(aset) {
+		if !bset[s] {
+			d.add(path+"/"+escapePointer(s), DiffAdded, nil, s)
+		}
+	}
+}
+
+// examples compares two example lists by position.
+func (d *differ) examples(path string, before, after []map[string]any) {
+	for i := 0; i < len(before) || i < len(after); i++ {
+		var b, a any
+		if i < len(before) && before[i] != nil {
+			b = before[i]
+		}
+		if i < len(after) && after[i] != nil {
+			a = after[i]
+		}
+		d.optional(fmt.Sprintf("%s/%d", path, i), b, a)
+	}
+}
+
+// schema recursively compares two JSON schemas.
+func (d *differ) schema(path string, before, after *JSONSchema) {
+	switch {
+	case before == nil && after == nil:
+		return
+	case before == nil:
+		d.add(path, DiffAdded, nil, after.ToMap())
+		return
+	case after == nil:
+		d.add(path, DiffRemoved, before.ToMap(), nil)
+		return
+	}
+
+	d.value(path+"/type", before.Type, after.Type)
+	d.value(path+"/description", before.Description, after.Description)
+	d.value(path+"/pattern", before.Pattern, after.Pattern)
+	d.value(path+"/format", before.Format, after.Format)
+	d.value(path+"/$ref", before.Ref, after.Ref)
+	d.optional(path+"/const", before.Const, after.Const)
+	d.optional(path+"/default", before.Default, after.Default)
+	d.pointer(path+"/minimum", before.Minimum, after.Minimum)
+	d.pointer(path+"/maximum", before.Maximum, after.Maximum)
+	d.pointer(path+"/minLength", before.MinLength, after.MinLength)
+	d.pointer(path+"/maxLength", before.MaxLength, after.MaxLength)
+	d.pointer(path+"/minItems", before.MinItems, after.MinItems)
+	d.pointer(path+"/maxItems", before.MaxItems, after.MaxItems)
+	d.pointer(path+"/additionalProperties", before.AdditionalProperties, after.AdditionalProperties)
+	d.stringSet(path+"/required", before.Required, after.Required)
+	d.value(path+"/enum", before.Enum, after.Enum)
+
+	d.schemaMap(path+"/properties", before.Properties, after.Properties)
+	d.schemaMap(path+"/$defs", before.Defs, after.Defs)
+	d.schema(path+"/items", before.Items, after.Items)
+	d.schemaList(path+"/anyOf", before.AnyOf, after.AnyOf)
+	d.schemaList(path+"/oneOf", before.OneOf, after.OneOf)
+	d.schemaList(path+"/allOf", before.AllOf, after.AllOf)
+	d.schema(path+"/not", before.Not, after.Not)
+
+	keys := make(map[string]bool, len(before.Extra)+len(after.Extra))
+	for k := range before.Extra {
+		keys[k] = true
+	}
+	for k := range after.Extra {
+		keys[k] = true
+	}
+	for _, k := range sortedKeys(keys) {
+		d.optional(path+"/"+escapePointer(k), before.Extra[k], after.Extra[k])
+	}
+}
+
+// pointer compares two optional values held by pointer.
+func (d *differ) pointer(path string, before, after any) {
+	d.optional(path, deref(before), deref(after))
+}
+
+func (d *differ) schemaMap(path string, before, after map[string]*JSONSchema) {
+	keys := make(map[string]bool, len(before)+len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	for _, k := range sortedKeys(keys) {
+		d.schema(path+"/"+escapePointer(k), before[k], after[k])
+	}
+}
+
+func (d *differ) schemaList(path string, before, after []*JSONSchema) {
+	n := len(before)
+	if len(after) > n {
+		n = len(after)
+	}
+	for i := 0; i < n; i++ {
+		var b, a *JSONSchema
+		if i < len(before) {
+			b = before[i]
+		}
+		if i < len(after) {
+			a = after[i]
+		}
+		d.schema(fmt.Sprintf("%s/%d", path, i), b, a)
+	}
+}
+
+// isZeroValue reports whether v is nil or the zero value of its type.
+func isZeroValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+}
+
+// jsonEqual compares two values by their JSON encoding, falling back to
+// reflect.DeepEqual for values that cannot be encoded.
+func jsonEqual(a, b any) bool {
+	aj, aerr := json.Marshal(a)
+	bj, berr := json.Marshal(b)
+	if aerr != nil || berr != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aj) == string(bj)
+}
+
+// normalizeKind maps the empty kind to its ToolKindFunction default.
+func normalizeKind(k ToolKind) ToolKind {
+	if k == "" {
+		return ToolKindFunction
+	}
+	return k
+}
+
+// customValue returns a comparable value for a custom tool config, or nil.
+func customValue(c *CustomToolConfig) any {
+	if c == nil {
+		return nil
+	}
+	return *c
+}
+
+// hostedValue returns a comparable value for a hosted tool config, or nil.
+func hostedValue(h *HostedToolConfig) any {
+	if h == nil {
+		return nil
+	}
+	return *h
+}
+
+// annotationsValue returns a comparable value for tool annotations, or nil.
+func annotationsValue(a *ToolAnnotations) any {
+	if a == nil {
+		return nil
+	}
+	return *a
+}
+
+// deref returns the value a typed pointer points to, or nil for a nil pointer.
+func deref(p any) any {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil
+	}
+	return rv.Elem().Interface()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a JSON Pointer reference token per RFC 6901.
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
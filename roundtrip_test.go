@@ -0,0 +1,300 @@
+package tooladapter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffCanonical_Identical(t *testing.T) {
+	min := 1.0
+	tool := &CanonicalTool{
+		Name:        "test",
+		Description: "A test tool",
+		Tags:        []string{"a", "b"},
+		InputSchema: &JSONSchema{
+			Type: "object",
+			Properties: map[string]*JSONSchema{
+				"count": {Type: "integer", Minimum: &min},
+			},
+			Required: []string{"count"},
+		},
+	}
+
+	diffs := DiffCanonical(tool, &CanonicalTool{
+		Name:        "test",
+		Description: "A test tool",
+		Tags:        []string{"b", "a"},
+		InputSchema: tool.InputSchema.DeepCopy(),
+	})
+
+	if len(diffs) != 0 {
+		t.Errorf("DiffCanonical() = %v, want no diffs", diffs)
+	}
+}
+
+func TestDiffCanonical_IgnoresSourceFields(t *testing.T) {
+	before := &CanonicalTool{Name: "test", SourceFormat: "mcp", SourceMeta: map[string]any{"title": "T"}}
+	after := &CanonicalTool{Name: "test", SourceFormat: "openai"}
+
+	if diffs := DiffCanonical(before, after); len(diffs) != 0 {
+		t.Errorf("DiffCanonical() = %v, want no diffs", diffs)
+	}
+}
+
+func TestDiffCanonical_NumericNormalization(t *testing.T) {
+	before := &CanonicalTool{Name: "test", InputSchema: &JSONSchema{Enum: []any{1, 2}, Default: 1}}
+	after := &CanonicalTool{Name: "test", InputSchema: &JSONSchema{Enum: []any{1.0, 2.0}, Default: 1.0}}
+
+	if diffs := DiffCanonical(before, after); len(diffs) != 0 {
+		t.Errorf("DiffCanonical() = %v, want no diffs", diffs)
+	}
+}
+
+func TestDiffCanonical_ReportsChanges(t *testing.T) {
+	before := &CanonicalTool{
+		Namespace: "ns",
+		Name:      "test",
+		Timeout:   time.Second,
+		InputSchema: &JSONSchema{
+			Type: "object",
+			Properties: map[string]*JSONSchema{
+				"name": {Type: "string", Pattern: "^a"},
+				"a/b":  {Type: "string"},
+			},
+			Required: []string{"name"},
+			Const:    false,
+		},
+	}
+	after := &CanonicalTool{
+		Name: "test",
+		InputSchema: &JSONSchema{
+			Type: "object",
+			Properties: map[string]*JSONSchema{
+				"name": {Type: "integer"},
+			},
+		},
+		OutputSchema: &JSONSchema{Type: "object"},
+	}
+
+	diffs := DiffCanonical(before, after)
+
+	want := map[string]DiffKind{
+		"/inputSchema/const":                   DiffRemoved,
+		"/inputSchema/properties/a~1b":         DiffRemoved,
+		"/inputSchema/properties/name/pattern": DiffRemoved,
+		"/inputSchema/properties/name/type":    DiffChanged,
+		"/inputSchema/required/name":           DiffRemoved,
+		"/namespace":                           DiffRemoved,
+		"/outputSchema":                        DiffAdded,
+		"/timeout":                             DiffRemoved,
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffCanonical() returned %d diffs, want %d: %v", len(diffs), len(want), diffs)
+	}
+	for i, d := range diffs {
+		kind, ok := want[d.Path]
+		if !ok {
+			t.Errorf("unexpected diff %v", d)
+			continue
+		}
+		if d.Kind != kind {
+			t.Errorf("diff %s kind = %q, want %q", d.Path, d.Kind, kind)
+		}
+		if i > 0 && diffs[i-1].Path > d.Path {
+			t.Errorf("diffs not sorted by path: %q before %q", diffs[i-1].Path, d.Path)
+		}
+	}
+}
+
+func TestDiffCanonical_Kinds(t *testing.T) {
+	// An empty kind and ToolKindFunction are equivalent
+	if diffs := DiffCanonical(&CanonicalTool{Name: "t"}, &CanonicalTool{Name: "t", Kind: ToolKindFunction}); len(diffs) != 0 {
+		t.Errorf("DiffCanonical() = %v, want no diffs", diffs)
+	}
+
+	before := &CanonicalTool{
+		Name:   "t",
+		Kind:   ToolKindHosted,
+		Hosted: &HostedToolConfig{Provider: "openai", Type: "file_search", Options: map[string]any{"max_num_results": 5}},
+	}
+	after := &CanonicalTool{
+		Name:   "t",
+		Kind:   ToolKindCustom,
+		Custom: &CustomToolConfig{},
+	}
+
+	diffs := DiffCanonical(before, after)
+
+	want := map[string]DiffKind{
+		"/custom": DiffAdded,
+		"/hosted": DiffRemoved,
+		"/kind":   DiffChanged,
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffCanonical() returned %d diffs, want %d: %v", len(diffs), len(want), diffs)
+	}
+	for _, d := range diffs {
+		if kind, ok := want[d.Path]; !ok || d.Kind != kind {
+			t.Errorf("unexpected diff %v", d)
+		}
+	}
+}
+
+func TestDiffCanonical_Examples(t *testing.T) {
+	before := &CanonicalTool{Name: "t", Examples: []map[string]any{{"n": 1}, {"n": 2}}}
+	after := &CanonicalTool{Name: "t", Examples: []map[string]any{{"n": 1.0}, {"n": 3}, {"n": 4}}}
+
+	diffs := DiffCanonical(before, after)
+
+	want := []FieldDiff{
+		{Path: "/examples/1", Kind: DiffChanged},
+		{Path: "/examples/2", Kind: DiffAdded},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffCanonical() = %v, want %d diffs", diffs, len(want))
+	}
+	for i := range want {
+		if diffs[i].Path != want[i].Path || diffs[i].Kind != want[i].Kind {
+			t.Errorf("diff %d = %v, want %s %s", i, diffs[i], want[i].Path, want[i].Kind)
+		}
+	}
+}
+
+func TestDiffCanonical_Extra(t *testing.T) {
+	before := &CanonicalTool{Name: "t", InputSchema: &JSONSchema{
+		Type:  "object",
+		Extra: map[string]any{"title": "Query", "exclusiveMinimum": 1},
+	}}
+	after := &CanonicalTool{Name: "t", InputSchema: &JSONSchema{
+		Type:  "object",
+		Extra: map[string]any{"exclusiveMinimum": 1.0, "x-internal": true},
+	}}
+
+	diffs := DiffCanonical(before, after)
+
+	want := []FieldDiff{
+		{Path: "/inputSchema/title", Kind: DiffRemoved},
+		{Path: "/inputSchema/x-internal", Kind: DiffAdded},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffCanonical() = %v, want %d diffs", diffs, len(want))
+	}
+	for i := range want {
+		if diffs[i].Path != want[i].Path || diffs[i].Kind != want[i].Kind {
+			t.Errorf("diff %d = %v, want %s %s", i, diffs[i], want[i].Path, want[i].Kind)
+		}
+	}
+}
+
+func TestDiffCanonical_Annotations(t *testing.T) {
+	no := false
+	before := &CanonicalTool{Name: "t", Annotations: &ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: &no}}
+
+	if diffs := DiffCanonical(before, &CanonicalTool{Name: "t", Annotations: &ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: &no}}); len(diffs) != 0 {
+		t.Errorf("DiffCanonical() = %v, want no diffs", diffs)
+	}
+
+	diffs := DiffCanonical(before, &CanonicalTool{Name: "t"})
+	if len(diffs) != 1 || diffs[0].Path != "/annotations" || diffs[0].Kind != DiffRemoved {
+		t.Errorf("DiffCanonical() = %v, want /annotations removed", diffs)
+	}
+}
+
+func TestFieldDiff_String(t *testing.T) {
+	tests := []struct {
+		diff FieldDiff
+		want string
+	}{
+		{FieldDiff{Path: "/name", Kind: DiffAdded, After: "x"}, "/name added: x"},
+		{FieldDiff{Path: "/name", Kind: DiffRemoved, Before: "x"}, "/name removed: x"},
+		{FieldDiff{Path: "/name", Kind: DiffChanged, Before: "x", After: "y"}, "/name changed: x -> y"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.diff.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRegistry_Convert_RoundTripCheck(t *testing.T) {
+	r := NewRegistry()
+
+	source := &mockAdapter{
+		name: "source",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{
+				Name: "test",
+				InputSchema: &JSONSchema{
+					Type:       "object",
+					Properties: map[string]*JSONSchema{"q": {Type: "string"}},
+					Required:   []string{"q"},
+				},
+			}, nil
+		},
+		supportsFunc: func(f SchemaFeature) bool { return true },
+	}
+
+	// Target that claims full support but silently drops Required
+	target := &mockAdapter{
+		name: "target",
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) {
+			return tool.InputSchema.ToMap(), nil
+		},
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			m := raw.(map[string]any)
+			return &CanonicalTool{
+				Name: "test",
+				InputSchema: &JSONSchema{
+					Type:       m["type"].(string),
+					Properties: map[string]*JSONSchema{"q": {Type: "string"}},
+				},
+			}, nil
+		},
+		supportsFunc: func(f SchemaFeature) bool { return true },
+	}
+
+	_ = r.Register(source)
+	_ = r.Register(target)
+
+	result, err := r.Convert("input", "source", "target", WithRoundTripCheck())
+
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Convert() warnings = %v, want none", result.Warnings)
+	}
+	if result.RoundTrip == nil {
+		t.Fatal("Convert() RoundTrip = nil, want report")
+	}
+	if result.RoundTrip.Lossless() {
+		t.Fatal("RoundTrip.Lossless() = true, want false")
+	}
+	if got := result.RoundTrip.Diffs[0].Path; got != "/inputSchema/required/q" {
+		t.Errorf("RoundTrip.Diffs[0].Path = %q, want %q", got, "/inputSchema/required/q")
+	}
+}
+
+func TestRegistry_Convert_NoRoundTripByDefault(t *testing.T) {
+	r := NewRegistry()
+	adapter := &mockAdapter{
+		name: "same",
+		toCanonicalFunc: func(raw any) (*CanonicalTool, error) {
+			return &CanonicalTool{Name: "test", InputSchema: &JSONSchema{Type: "object"}}, nil
+		},
+		fromCanonicalFunc: func(tool *CanonicalTool) (any, error) {
+			return tool.Name, nil
+		},
+	}
+	_ = r.Register(adapter)
+
+	result, err := r.Convert("input", "same", "same")
+
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if result.RoundTrip != nil {
+		t.Errorf("Convert() RoundTrip = %+v, want nil", result.RoundTrip)
+	}
+}
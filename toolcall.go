@@ -0,0 +1,36 @@
+package tooladapter
+
+import "fmt"
+
+// ToolCall is a model's request to call a tool, in canonical form. Adapters
+// for formats in which the model writes tool calls as text (see the prompt
+// adapters) parse them into ToolCall values.
+type ToolCall struct {
+	// ID identifies the call within a response; empty for formats that do
+	// not assign call IDs
+	ID string
+
+	// Name is the tool name as the model wrote it
+	Name string
+
+	// Arguments are the decoded call arguments
+	Arguments map[string]any
+}
+
+// Validate checks the call against the tool it names. It returns an error
+// if the names differ, and otherwise the issues found by validating the
+// arguments against the tool's InputSchema. A nil argument map is checked
+// as an empty object.
+func (c ToolCall) Validate(tool *CanonicalTool) ([]SchemaIssue, error) {
+	if tool == nil {
+		return nil, fmt.Errorf("tool call %q: nil CanonicalTool", c.Name)
+	}
+	if c.Name != tool.Name {
+		return nil, fmt.Errorf("tool call %q does not name tool %q", c.Name, tool.Name)
+	}
+	args := c.Arguments
+	if args == nil {
+		args = map[string]any{}
+	}
+	return tool.InputSchema.ValidateValue(args), nil
+}
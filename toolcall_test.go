@@ -0,0 +1,31 @@
+package tooladapter
+
+import "testing"
+
+func TestToolCall_Validate(t *testing.T) {
+	tool := &CanonicalTool{
+		Name: "get_weather",
+		InputSchema: &JSONSchema{
+			Type:       "object",
+			Properties: map[string]*JSONSchema{"city": {Type: "string"}},
+			Required:   []string{"city"},
+		},
+	}
+
+	issues, err := ToolCall{Name: "get_weather", Arguments: map[string]any{"city": "Paris"}}.Validate(tool)
+	if err != nil || len(issues) != 0 {
+		t.Errorf("Validate() = %v, %v, want no issues", issues, err)
+	}
+
+	issues, err = ToolCall{Name: "get_weather"}.Validate(tool)
+	if err != nil || len(issues) != 1 || issues[0].Keyword != "required" {
+		t.Errorf("Validate() without arguments = %v, %v, want a required issue", issues, err)
+	}
+
+	if _, err := (ToolCall{Name: "get_time"}).Validate(tool); err == nil {
+		t.Error("Validate() with another name error = nil, want error")
+	}
+	if _, err := (ToolCall{Name: "get_weather"}).Validate(nil); err == nil {
+		t.Error("Validate(nil) error = nil, want error")
+	}
+}
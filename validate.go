@@ -0,0 +1,246 @@
+package tooladapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRefDepth bounds how many $ref hops may be followed without descending
+// into the value, which guards against reference cycles.
+const maxRefDepth = 32
+
+// ValidateValue checks a value against the schema and returns one issue per
+// violation, sorted by path. The value may be any JSON-encodable Go value;
+// it is normalized through encoding/json before validation.
+//
+// Issue paths are JSON Pointers into the value, not the schema. Local
+// references of the form "#/$defs/name" are resolved against the receiver;
+// format is treated as an annotation and not asserted, and neither is a
+// pattern Go's regexp package cannot compile, such as one with lookahead.
+func (s *JSONSchema) ValidateValue(value any) []SchemaIssue {
+	if s == nil {
+		return nil
+	}
+
+	normalized, err := normalizeJSON(value)
+	if err != nil {
+		return []SchemaIssue{{Keyword: "type", Message: "value is not JSON-encodable: " + err.Error()}}
+	}
+
+	v := &validator{root: s}
+	v.validate(s, normalized, "", 0)
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		return v.issues[i].Path < v.issues[j].Path
+	})
+	return v.issues
+}
+
+// validator carries the root schema and collected issues through a single
+// validation.
+type validator struct {
+	root   *JSONSchema
+	issues []SchemaIssue
+}
+
+func (v *validator) issue(path, keyword, message string) {
+	v.issues = append(v.issues, SchemaIssue{Path: path, Keyword: keyword, Message: message})
+}
+
+// matches reports whether a value satisfies a schema without recording issues.
+func (v *validator) matches(s *JSONSchema, value any, path string, refDepth int) bool {
+	sub := &validator{root: v.root}
+	sub.validate(s, value, path, refDepth)
+	return len(sub.issues) == 0
+}
+
+func (v *validator) validate(s *JSONSchema, value any, path string, refDepth int) {
+	if s == nil {
+		return
+	}
+
+	if s.Ref != "" {
+		target, ok := v.resolve(s.Ref)
+		switch {
+		case !ok:
+			v.issue(path, "$ref", fmt.Sprintf("cannot resolve reference %q", s.Ref))
+		case refDepth >= maxRefDepth:
+			v.issue(path, "$ref", "reference depth exceeded")
+		default:
+			v.validate(target, value, path, refDepth+1)
+		}
+	}
+
+	if s.Type != "" && !hasJSONType(value, s.Type) {
+		v.issue(path, "type", fmt.Sprintf("expected %s, got %s", s.Type, jsonTypeOf(value)))
+		return
+	}
+	if len(s.Enum) > 0 && !containsJSON(s.Enum, value) {
+		v.issue(path, "enum", "value is not one of the allowed values")
+	}
+	if s.Const != nil && !jsonEqual(s.Const, value) {
+		v.issue(path, "const", "value does not equal the constant")
+	}
+
+	switch val := value.(type) {
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			v.issue(path, "minimum", fmt.Sprintf("%v is less than %v", val, *s.Minimum))
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			v.issue(path, "maximum", fmt.Sprintf("%v is greater than %v", val, *s.Maximum))
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			v.issue(path, "minLength", fmt.Sprintf("length %d is less than %d", n, *s.MinLength))
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			v.issue(path, "maxLength", fmt.Sprintf("length %d is greater than %d", n, *s.MaxLength))
+		}
+		if s.Pattern != "" {
+			// ECMA-262 features RE2 lacks leave the pattern unassertable
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(val) {
+				v.issue(path, "pattern", fmt.Sprintf("does not match %q", s.Pattern))
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			v.issue(path, "minItems", fmt.Sprintf("%d items is less than %d", len(val), *s.MinItems))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			v.issue(path, "maxItems", fmt.Sprintf("%d items is greater than %d", len(val), *s.MaxItems))
+		}
+		for i, item := range val {
+			v.validate(s.Items, item, fmt.Sprintf("%s/%d", path, i), 0)
+		}
+	case map[string]any:
+		v.validateObject(s, val, path)
+	}
+
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, path, refDepth)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if v.matches(sub, value, path, refDepth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.issue(path, "anyOf", "value matches none of the schemas")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		count := 0
+		for _, sub := range s.OneOf {
+			if v.matches(sub, value, path, refDepth) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.issue(path, "oneOf", fmt.Sprintf("value matches %d schemas, want exactly one", count))
+		}
+	}
+	if s.Not != nil && v.matches(s.Not, value, path, refDepth) {
+		v.issue(path, "not", "value matches a disallowed schema")
+	}
+}
+
+func (v *validator) validateObject(s *JSONSchema, obj map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.issue(path, "required", fmt.Sprintf("missing required property %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "/" + escapePointer(name)
+		if prop, ok := s.Properties[name]; ok {
+			v.validate(prop, obj[name], propPath, 0)
+		} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			v.issue(propPath, "additionalProperties", fmt.Sprintf("property %q is not allowed", name))
+		}
+	}
+}
+
+// resolve looks up a local "#/$defs/name" reference in the root schema.
+func (v *validator) resolve(ref string) (*JSONSchema, bool) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	def, ok := v.root.Defs[name]
+	return def, ok && def != nil
+}
+
+// normalizeJSON converts a Go value into its generic JSON form
+// (float64, string, bool, nil, []any, map[string]any).
+func normalizeJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// hasJSONType reports whether a normalized value has the given JSON type.
+func hasJSONType(value any, typ string) bool {
+	switch typ {
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeOf(value) == typ
+	}
+}
+
+// jsonTypeOf returns the JSON type name of a normalized value.
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// containsJSON reports whether list contains a value equal by JSON encoding.
+func containsJSON(list []any, value any) bool {
+	for _, item := range list {
+		if jsonEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
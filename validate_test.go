@@ -0,0 +1,121 @@
+package tooladapter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema_ValidateValue(t *testing.T) {
+	min, max := 1.0, 10.0
+	minLen, maxLen := 2, 4
+	maxItems := 2
+	closed := false
+
+	schema := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"count": {Type: "integer", Minimum: &min, Maximum: &max},
+			"code":  {Type: "string", MinLength: &minLen, MaxLength: &maxLen, Pattern: "^[A-Z]+$"},
+			"unit":  {Type: "string", Enum: []any{"c", "f"}},
+			"kind":  {Const: "fixed"},
+			"tags":  {Type: "array", Items: &JSONSchema{Type: "string"}, MaxItems: &maxItems},
+			"place": {Ref: "#/$defs/place"},
+			"login": {Type: "string", Pattern: "^(?!admin)[a-z]+$"},
+		},
+		Required:             []string{"count"},
+		AdditionalProperties: &closed,
+		Defs: map[string]*JSONSchema{
+			"place": {Type: "object", Properties: map[string]*JSONSchema{"city": {Type: "string"}}, Required: []string{"city"}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  []string
+	}{
+		{"valid", map[string]any{"count": 3, "code": "AB", "unit": "c", "kind": "fixed", "tags": []string{"x"}, "place": map[string]any{"city": "Paris"}}, nil},
+		{"not an object", "x", []string{"type at /"}},
+		{"missing required", map[string]any{}, []string{"required at /"}},
+		{"not an integer", map[string]any{"count": 1.5}, []string{"type at /count"}},
+		{"below minimum", map[string]any{"count": 0}, []string{"minimum at /count"}},
+		{"above maximum", map[string]any{"count": 11}, []string{"maximum at /count"}},
+		{"string checks", map[string]any{"count": 1, "code": "abcde"}, []string{"maxLength at /code", "pattern at /code"}},
+		{"unassertable pattern", map[string]any{"count": 1, "login": "admin"}, nil},
+		{"too short", map[string]any{"count": 1, "code": "A"}, []string{"minLength at /code"}},
+		{"enum", map[string]any{"count": 1, "unit": "k"}, []string{"enum at /unit"}},
+		{"const", map[string]any{"count": 1, "kind": "other"}, []string{"const at /kind"}},
+		{"array items", map[string]any{"count": 1, "tags": []any{"a", 2}}, []string{"type at /tags/1"}},
+		{"too many items", map[string]any{"count": 1, "tags": []any{"a", "b", "c"}}, []string{"maxItems at /tags"}},
+		{"ref", map[string]any{"count": 1, "place": map[string]any{}}, []string{"required at /place"}},
+		{"additional property", map[string]any{"count": 1, "extra": true}, []string{"additionalProperties at /extra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := schema.ValidateValue(tt.value)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("ValidateValue() = %v, want %v", issues, tt.want)
+			}
+			for i, issue := range issues {
+				path := issue.Path
+				if path == "" {
+					path = "/"
+				}
+				if got := issue.Keyword + " at " + path; got != tt.want[i] {
+					t.Errorf("issue %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJSONSchema_ValidateValue_Combinators(t *testing.T) {
+	schema := &JSONSchema{
+		AnyOf: []*JSONSchema{{Type: "string"}, {Type: "number"}},
+		OneOf: []*JSONSchema{{Type: "integer"}, {Type: "number"}, {Type: "string"}},
+		Not:   &JSONSchema{Const: "forbidden"},
+	}
+
+	tests := []struct {
+		value any
+		want  []string
+	}{
+		{"ok", nil},
+		{1.5, nil},
+		{true, []string{"anyOf", "oneOf"}},
+		{2, []string{"oneOf"}},
+		{"forbidden", []string{"not"}},
+	}
+
+	for _, tt := range tests {
+		issues := schema.ValidateValue(tt.value)
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Keyword)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ValidateValue(%v) keywords = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestJSONSchema_ValidateValue_RefErrors(t *testing.T) {
+	cyclic := &JSONSchema{
+		Ref:  "#/$defs/a",
+		Defs: map[string]*JSONSchema{"a": {Ref: "#/$defs/a"}},
+	}
+	if issues := cyclic.ValidateValue(1); len(issues) == 0 {
+		t.Error("ValidateValue() with cyclic reference = none, want issue")
+	}
+
+	external := &JSONSchema{Ref: "https://example.com/schema.json"}
+	if issues := external.ValidateValue(1); len(issues) != 1 || issues[0].Keyword != "$ref" {
+		t.Errorf("ValidateValue() with external reference = %v, want $ref issue", issues)
+	}
+
+	var nilSchema *JSONSchema
+	if issues := nilSchema.ValidateValue(1); issues != nil {
+		t.Errorf("nil ValidateValue() = %v, want nil", issues)
+	}
+}